@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/creachadair/jrpc2/jhttp"
+	"github.com/go-chi/chi/middleware"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+)
+
+type AdminHandlerParams struct {
+	DatabaseBackuper          methods.DatabaseBackuper
+	IngestionCheckpointReader db.IngestionCheckpointReader
+	LedgerReader              db.LedgerReader
+	StoreStatsGetter          methods.StoreStatsGetter
+	SnapshotRestorer          methods.SnapshotRestorer
+	CacheRefresher            methods.CacheRefresher
+	Logger                    *log.Entry
+}
+
+func logAdminHandlers(logger *log.Entry, m handler.Map) handler.Map {
+	logged := handler.Map{}
+	for endpoint, h := range m {
+		// create copy of h, so it can be used in closure below
+		h := h
+		logged[endpoint] = handler.New(func(ctx context.Context, r *jrpc2.Request) (interface{}, error) {
+			reqID := strconv.FormatUint(middleware.NextRequestID(), 10)
+			logRequest(logger, reqID, r)
+			startTime := time.Now()
+			result, err := h(ctx, r)
+			status := "ok"
+			if err != nil {
+				status = "error"
+			}
+			logResponse(logger, reqID, time.Since(startTime), status, result)
+			return result, err
+		})
+	}
+	return logged
+}
+
+// restrictSourceIPs only lets through the requests coming from an address in the allowlist,
+// rejecting (and logging) the others. An empty allowlist lets all the requests through.
+func restrictSourceIPs(logger *log.Entry, allowlist []netip.Prefix, next http.Handler) http.Handler {
+	if len(allowlist) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		addr, err := netip.ParseAddr(host)
+		if err == nil {
+			addr = addr.Unmap()
+			for _, prefix := range allowlist {
+				if prefix.Contains(addr) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		logger.WithField("remote_addr", r.RemoteAddr).Warn("rejected admin request from a source IP not in the allowlist")
+		http.Error(w, fmt.Sprintf("source IP %s is not allowed to call admin methods", host), http.StatusForbidden)
+	})
+}
+
+// NewAdminJSONRPCHandler constructs a Handler serving the administrative JSON RPC methods.
+// It must only be exposed through the admin endpoint.
+func NewAdminJSONRPCHandler(cfg *config.Config, params AdminHandlerParams) (Handler, error) {
+	allowlist, err := config.ParseIPAllowlist(cfg.AdminAllowedIPs)
+	if err != nil {
+		return Handler{}, fmt.Errorf("invalid admin allowed IPs: %w", err)
+	}
+	bridgeOptions := jhttp.BridgeOptions{
+		Server: &jrpc2.ServerOptions{
+			Logger: func(text string) { params.Logger.Debug(text) },
+		},
+	}
+	handlers := handler.Map{
+		"backupDatabase": methods.NewBackupDatabaseHandler(
+			params.Logger, params.DatabaseBackuper, cfg.AdminBackupDirectory),
+		"getIngestionCheckpoint": methods.NewGetIngestionCheckpointHandler(
+			params.IngestionCheckpointReader, params.LedgerReader),
+		"getStoreStats": methods.NewGetStoreStatsHandler(params.StoreStatsGetter),
+		"restoreFromSnapshot": methods.NewRestoreFromSnapshotHandler(
+			params.Logger, params.SnapshotRestorer, params.LedgerReader, cfg.AdminBackupDirectory),
+		"refreshCaches": methods.NewRefreshCachesHandler(params.Logger, params.CacheRefresher),
+	}
+	bridge := jhttp.NewBridge(logAdminHandlers(params.Logger, handlers), &bridgeOptions)
+	return Handler{
+		bridge:  bridge,
+		logger:  params.Logger,
+		Handler: restrictSourceIPs(params.Logger, allowlist, http.MaxBytesHandler(bridge, maxHTTPRequestSize)),
+	}, nil
+}
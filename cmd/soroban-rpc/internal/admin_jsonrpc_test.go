@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+)
+
+func TestRestrictSourceIPs(t *testing.T) {
+	allowlist, err := config.ParseIPAllowlist([]string{"127.0.0.1", "10.1.0.0/16", "fd00::/8"})
+	require.NoError(t, err)
+	handler := restrictSourceIPs(log.DefaultLogger, allowlist, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for remoteAddr, allowed := range map[string]bool{
+		"127.0.0.1:1234":          true,
+		"10.1.200.3:1234":         true,
+		"[::ffff:10.1.0.1]:1234":  true,
+		"[fd12::1]:1234":          true,
+		"127.0.0.2:1234":          false,
+		"10.2.0.1:1234":           false,
+		"[::1]:1234":              false,
+		"not-an-address":          false,
+		"[fe80::1%eth0]:1234":     false,
+		"192.168.1.1:1234":        false,
+		"[2001:db8::1]:1234":      false,
+		"255.255.255.255:1234":    false,
+		"[::ffff:127.0.0.1]:1234": true,
+	} {
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		request.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if allowed {
+			assert.Equal(t, http.StatusOK, recorder.Code, remoteAddr)
+		} else {
+			assert.Equal(t, http.StatusForbidden, recorder.Code, remoteAddr)
+			assert.Contains(t, recorder.Body.String(), "is not allowed to call admin methods", remoteAddr)
+		}
+	}
+}
+
+func TestRestrictSourceIPsEmptyAllowlist(t *testing.T) {
+	handler := restrictSourceIPs(log.DefaultLogger, nil, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+	request.RemoteAddr = "192.168.1.1:1234"
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestParseIPAllowlistInvalid(t *testing.T) {
+	_, err := config.ParseIPAllowlist([]string{"10.0.0.0/33"})
+	require.ErrorContains(t, err, "invalid CIDR range")
+	_, err = config.ParseIPAllowlist([]string{"localhost"})
+	require.ErrorContains(t, err, "invalid IP address")
+}
@@ -20,56 +20,112 @@ type Config struct {
 	CaptiveCoreConfigPath  string
 	CaptiveCoreHTTPPort    uint
 
-	Endpoint                                       string
-	AdminEndpoint                                  string
-	CheckpointFrequency                            uint32
-	CoreRequestTimeout                             time.Duration
-	DefaultEventsLimit                             uint
-	DefaultTransactionsLimit                       uint
-	EventLedgerRetentionWindow                     uint32
-	FriendbotURL                                   string
-	HistoryArchiveURLs                             []string
-	HistoryArchiveUserAgent                        string
-	IngestionTimeout                               time.Duration
-	LogFormat                                      LogFormat
-	LogLevel                                       logrus.Level
-	MaxEventsLimit                                 uint
-	MaxTransactionsLimit                           uint
-	MaxHealthyLedgerLatency                        time.Duration
-	NetworkPassphrase                              string
-	PreflightWorkerCount                           uint
-	PreflightWorkerQueueSize                       uint
-	PreflightEnableDebug                           bool
-	SQLiteDBPath                                   string
-	HistoryRetentionWindow                         uint32
-	TransactionLedgerRetentionWindow               uint32
-	SorobanFeeStatsLedgerRetentionWindow           uint32
-	ClassicFeeStatsLedgerRetentionWindow           uint32
-	RequestBacklogGlobalQueueLimit                 uint
-	RequestBacklogGetHealthQueueLimit              uint
-	RequestBacklogGetEventsQueueLimit              uint
-	RequestBacklogGetNetworkQueueLimit             uint
-	RequestBacklogGetVersionInfoQueueLimit         uint
-	RequestBacklogGetLatestLedgerQueueLimit        uint
-	RequestBacklogGetLedgerEntriesQueueLimit       uint
-	RequestBacklogGetTransactionQueueLimit         uint
-	RequestBacklogGetTransactionsQueueLimit        uint
-	RequestBacklogSendTransactionQueueLimit        uint
-	RequestBacklogSimulateTransactionQueueLimit    uint
-	RequestBacklogGetFeeStatsTransactionQueueLimit uint
-	RequestExecutionWarningThreshold               time.Duration
-	MaxRequestExecutionDuration                    time.Duration
-	MaxGetHealthExecutionDuration                  time.Duration
-	MaxGetEventsExecutionDuration                  time.Duration
-	MaxGetNetworkExecutionDuration                 time.Duration
-	MaxGetVersionInfoExecutionDuration             time.Duration
-	MaxGetLatestLedgerExecutionDuration            time.Duration
-	MaxGetLedgerEntriesExecutionDuration           time.Duration
-	MaxGetTransactionExecutionDuration             time.Duration
-	MaxGetTransactionsExecutionDuration            time.Duration
-	MaxSendTransactionExecutionDuration            time.Duration
-	MaxSimulateTransactionExecutionDuration        time.Duration
-	MaxGetFeeStatsExecutionDuration                time.Duration
+	Endpoint                                           string
+	AdminEndpoint                                      string
+	AdminBackupDirectory                               string
+	AdminAllowedIPs                                    []string
+	CheckpointFrequency                                uint32
+	CoreRequestTimeout                                 time.Duration
+	DefaultEventsLimit                                 uint
+	DefaultTransactionsLimit                           uint
+	MaxTransactionsResponseSize                        uint
+	MaxTransactionHashesStreamLedgers                  uint32
+	MaxLargestTransactionsLedgers                      uint32
+	MaxConcurrentStreams                               uint
+	MaxStreamLedgersPerSecond                          uint
+	DefaultXDRFormat                                   string
+	EmptySlices                                        string
+	EventLedgerRetentionWindow                         uint32
+	FriendbotURL                                       string
+	HistoryArchiveURLs                                 []string
+	DisabledMethods                                    []string
+	MethodAliases                                      []string
+	MethodCacheTTLs                                    []string
+	HistoryArchiveUserAgent                            string
+	IngestionTimeout                                   time.Duration
+	LogFormat                                          LogFormat
+	LogLevel                                           logrus.Level
+	MaxEventsLimit                                     uint
+	MaxTransactionsLimit                               uint
+	MaxEventsPerTransaction                            uint
+	MaxHealthyLedgerLatency                            time.Duration
+	MaxTransactionStoreLag                             uint32
+	NetworkPassphrase                                  string
+	PreflightWorkerCount                               uint
+	PreflightWorkerQueueSize                           uint
+	JSONConversionWorkerCount                          uint
+	TransactionsByHashConcurrency                      uint
+	JSONConversionTimeout                              time.Duration
+	PreflightEnableDebug                               bool
+	SQLiteDBPath                                       string
+	DBLedgerCodec                                      string
+	LedgerStreamPrefetchChunkSize                      uint
+	DBCircuitBreakerThreshold                          uint
+	DBCircuitBreakerCooldown                           time.Duration
+	DBMaxOpenConnections                               uint
+	TransactionHashFilterCapacity                      uint
+	TransactionHashFilterFalsePositiveRate             float64
+	TransactionDenylistPath                            string
+	HistoryRetentionWindow                             uint32
+	HistoryRetentionPolicy                             string
+	HistoryRetentionPeriod                             time.Duration
+	HistoryRetentionMaxDBSize                          uint64
+	HistoryRetentionMinWindow                          uint32
+	MinProtocolVersion                                 uint32
+	TransactionLedgerRetentionWindow                   uint32
+	SorobanFeeStatsLedgerRetentionWindow               uint32
+	ClassicFeeStatsLedgerRetentionWindow               uint32
+	RequestBacklogGlobalQueueLimit                     uint
+	RequestBacklogGetHealthQueueLimit                  uint
+	RequestBacklogGetEventsQueueLimit                  uint
+	RequestBacklogGetNetworkQueueLimit                 uint
+	RequestBacklogGetVersionInfoQueueLimit             uint
+	RequestBacklogGetLatestLedgerQueueLimit            uint
+	RequestBacklogGetLedgerEntriesQueueLimit           uint
+	RequestBacklogGetTransactionQueueLimit             uint
+	RequestBacklogGetTransactionsQueueLimit            uint
+	RequestBacklogGetTransactionsByMemoQueueLimit      uint
+	RequestBacklogSendTransactionQueueLimit            uint
+	RequestBacklogSimulateTransactionQueueLimit        uint
+	RequestBacklogGetFeeStatsTransactionQueueLimit     uint
+	RequestBacklogGetRetentionStatusQueueLimit         uint
+	RequestBacklogDiffLedgerHeadersQueueLimit          uint
+	RequestBacklogGetContractCreationQueueLimit        uint
+	RequestBacklogGetLargestTransactionsQueueLimit     uint
+	RequestBacklogComputeTransactionHashQueueLimit     uint
+	RequestBacklogGetTransactionsByLedgerKeyQueueLimit uint
+	RequestBacklogGetContractActivityQueueLimit        uint
+	RequestBacklogGetLedgerCloseTimesQueueLimit        uint
+	RequestBacklogGetTransactionsByHashQueueLimit      uint
+	RequestBacklogGetLedgerQueueLimit                  uint
+	RequestBacklogGetMethodsQueueLimit                 uint
+	RequestExecutionWarningThreshold                   time.Duration
+	MaxRequestExecutionDuration                        time.Duration
+	MaxGetHealthExecutionDuration                      time.Duration
+	MaxGetEventsExecutionDuration                      time.Duration
+	MaxGetNetworkExecutionDuration                     time.Duration
+	MaxGetVersionInfoExecutionDuration                 time.Duration
+	MaxGetLatestLedgerExecutionDuration                time.Duration
+	GetLatestLedgerMaxWait                             time.Duration
+	LedgerCloseTimeTarget                              time.Duration
+	MaxGetLedgerEntriesExecutionDuration               time.Duration
+	MaxGetTransactionExecutionDuration                 time.Duration
+	MaxGetTransactionsExecutionDuration                time.Duration
+	MaxGetTransactionsByMemoExecutionDuration          time.Duration
+	MaxSendTransactionExecutionDuration                time.Duration
+	MaxSimulateTransactionExecutionDuration            time.Duration
+	MaxGetFeeStatsExecutionDuration                    time.Duration
+	MaxGetRetentionStatusExecutionDuration             time.Duration
+	MaxDiffLedgerHeadersExecutionDuration              time.Duration
+	MaxGetContractCreationExecutionDuration            time.Duration
+	MaxGetLargestTransactionsExecutionDuration         time.Duration
+	MaxComputeTransactionHashExecutionDuration         time.Duration
+	MaxGetTransactionsByLedgerKeyExecutionDuration     time.Duration
+	MaxGetContractActivityExecutionDuration            time.Duration
+	MaxGetLedgerCloseTimesExecutionDuration            time.Duration
+	MaxGetTransactionsByHashExecutionDuration          time.Duration
+	MaxGetLedgerExecutionDuration                      time.Duration
+	MaxGetMethodsExecutionDuration                     time.Duration
 
 	// We memoize these, so they bind to pflags correctly
 	optionsCache *Options
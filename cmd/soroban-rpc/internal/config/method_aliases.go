@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseMethodAliases parses a list of method aliases, formatted as alias=method
+// (e.g. "get_transaction=getTransaction"), into a map from the aliases to their methods.
+func ParseMethodAliases(entries []string) (map[string]string, error) {
+	aliases := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		alias, method, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		alias, method = strings.TrimSpace(alias), strings.TrimSpace(method)
+		if !ok || alias == "" || method == "" {
+			return nil, fmt.Errorf("invalid method alias %q, expected alias=method", entry)
+		}
+		if _, ok := aliases[alias]; ok {
+			return nil, fmt.Errorf("duplicate method alias %q", alias)
+		}
+		aliases[alias] = method
+	}
+	return aliases, nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMethodAliases(t *testing.T) {
+	aliases, err := ParseMethodAliases([]string{"get_transaction=getTransaction", " getTx = getTransaction "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"get_transaction": "getTransaction",
+		"getTx":           "getTransaction",
+	}, aliases)
+
+	for _, entries := range [][]string{
+		{"getTransaction"},
+		{"=getTransaction"},
+		{"getTx="},
+		{"getTx=getTransaction", "getTx=getLedgerEntries"},
+	} {
+		_, err = ParseMethodAliases(entries)
+		assert.Error(t, err, entries)
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseMethodCacheTTLs parses a list of method result cache TTLs, formatted as method=ttl
+// (e.g. "getVersionInfo=1m"), into a map from the methods to their (positive) TTLs.
+func ParseMethodCacheTTLs(entries []string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		method, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		method, value = strings.TrimSpace(method), strings.TrimSpace(value)
+		if !ok || method == "" || value == "" {
+			return nil, fmt.Errorf("invalid method cache TTL %q, expected method=ttl", entry)
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid method cache TTL %q: %w", entry, err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("invalid method cache TTL %q, the TTL must be positive", entry)
+		}
+		if _, ok := ttls[method]; ok {
+			return nil, fmt.Errorf("duplicate method cache TTL of %q", method)
+		}
+		ttls[method] = ttl
+	}
+	return ttls, nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMethodCacheTTLs(t *testing.T) {
+	ttls, err := ParseMethodCacheTTLs([]string{"getVersionInfo=1m", " getFeeStats = 5s "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"getVersionInfo": time.Minute,
+		"getFeeStats":    5 * time.Second,
+	}, ttls)
+
+	for _, entries := range [][]string{
+		{"getFeeStats"},
+		{"=5s"},
+		{"getFeeStats="},
+		{"getFeeStats=5"},
+		{"getFeeStats=0s"},
+		{"getFeeStats=-1s"},
+		{"getFeeStats=5s", "getFeeStats=1m"},
+	} {
+		_, err = ParseMethodCacheTTLs(entries)
+		assert.Error(t, err, entries)
+	}
+}
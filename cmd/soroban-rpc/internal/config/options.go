@@ -2,6 +2,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -20,6 +21,11 @@ const (
 	OneDayOfLedgers   = 17280
 	SevenDayOfLedgers = OneDayOfLedgers * 7
 
+	// RetentionPolicySequence retains a fixed number of ledgers (history-retention-window).
+	RetentionPolicySequence = "sequence"
+	// RetentionPolicyTime retains the ledgers closed within a period of time (history-retention-period).
+	RetentionPolicyTime = "time"
+
 	defaultHTTPEndpoint = "localhost:8000"
 )
 
@@ -58,6 +64,24 @@ func (cfg *Config) options() Options {
 			Usage:     "Admin endpoint to listen and serve on. WARNING: this should not be accessible from the Internet and does not use TLS. \"\" (default) disables the admin server",
 			ConfigKey: &cfg.AdminEndpoint,
 		},
+		{
+			Name: "admin-backup-dir",
+			Usage: "Directory in which the backupDatabase admin method is allowed to write database backups, and " +
+				"from which the restoreFromSnapshot admin method is allowed to read them. \"\" (default) disables both",
+			ConfigKey: &cfg.AdminBackupDirectory,
+		},
+		{
+			Name: "admin-allowed-ips",
+			Usage: "comma-separated list of IP addresses and CIDR ranges (e.g. 127.0.0.1,10.0.0.0/8) allowed to call " +
+				"the admin methods. Requests from other addresses are rejected. \"\" (default) allows all the addresses",
+			ConfigKey: &cfg.AdminAllowedIPs,
+			Validate: func(_ *Option) error {
+				if _, err := ParseIPAllowlist(cfg.AdminAllowedIPs); err != nil {
+					return fmt.Errorf("invalid admin-allowed-ips: %w", err)
+				}
+				return nil
+			},
+		},
 		{
 			Name:      "stellar-core-url",
 			Usage:     "URL used to query Stellar Core (local captive core by default)",
@@ -185,6 +209,38 @@ func (cfg *Config) options() Options {
 			ConfigKey: &cfg.HistoryArchiveURLs,
 			Validate:  required,
 		},
+		{
+			Name:      "disabled-methods",
+			Usage:     "comma-separated list of JSON-RPC methods which must not be served (e.g. on public endpoints). Unknown methods are rejected at startup",
+			ConfigKey: &cfg.DisabledMethods,
+		},
+		{
+			Name: "method-aliases",
+			Usage: "comma-separated list of alternate JSON-RPC method names, formatted as alias=method (e.g. " +
+				"get_transaction=getTransaction), easing the migration of clients of other implementations. " +
+				"The calls of aliases are logged",
+			ConfigKey: &cfg.MethodAliases,
+			Validate: func(_ *Option) error {
+				if _, err := ParseMethodAliases(cfg.MethodAliases); err != nil {
+					return fmt.Errorf("invalid method-aliases: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "method-cache-ttls",
+			Usage: "comma-separated list of the methods whose results are cached, with the time to live of their " +
+				"results, formatted as method=ttl (e.g. getVersionInfo=1m). The results of the methods following " +
+				"the latest ledger (getLatestLedger, getFeeStats and getLedger) are also dropped when a ledger is " +
+				"ingested. Only getVersionInfo, getLatestLedger, getFeeStats and getLedger can be cached",
+			ConfigKey: &cfg.MethodCacheTTLs,
+			Validate: func(_ *Option) error {
+				if _, err := ParseMethodCacheTTLs(cfg.MethodCacheTTLs); err != nil {
+					return fmt.Errorf("invalid method-cache-ttls: %w", err)
+				}
+				return nil
+			},
+		},
 		{
 			Name:      "friendbot-url",
 			Usage:     "The friendbot URL to be returned by getNetwork endpoint",
@@ -202,6 +258,68 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.SQLiteDBPath,
 			DefaultValue: "soroban_rpc.sqlite",
 		},
+		{
+			Name: "db-ledger-codec",
+			Usage: "encoding of the ledgers stored in the database: \"xdr\" or \"zstd\" (zstd-compressed XDR, which " +
+				"shrinks the database at the cost of some CPU). It can only be changed while the database has no ledgers",
+			ConfigKey:    &cfg.DBLedgerCodec,
+			DefaultValue: "xdr",
+		},
+		{
+			Name: "ledger-stream-prefetch-chunk-size",
+			Usage: "Number of ledgers of the chunks read and decoded ahead of their processing when scanning the " +
+				"stored ledgers at startup. At most two chunks are held in memory. 0 disables the prefetching",
+			ConfigKey:    &cfg.LedgerStreamPrefetchChunkSize,
+			DefaultValue: uint(0),
+		},
+		{
+			Name: "transaction-hash-filter-capacity",
+			Usage: "Number of transaction hashes held by the in-memory filter consulted before looking up transactions " +
+				"by hash (e.g. in getTransaction), so that the lookups of transactions which aren't stored don't query " +
+				"the database. It should cover the transactions of the history retention window. 0 disables the filter",
+			ConfigKey:    &cfg.TransactionHashFilterCapacity,
+			DefaultValue: uint(0),
+		},
+		{
+			Name: "transaction-hash-filter-false-positive-rate",
+			Usage: "False positive rate of the transaction hash filter (see transaction-hash-filter-capacity). " +
+				"Lower rates use more memory",
+			ConfigKey:    &cfg.TransactionHashFilterFalsePositiveRate,
+			DefaultValue: 0.01,
+			Validate: func(_ *Option) error {
+				if rate := cfg.TransactionHashFilterFalsePositiveRate; rate <= 0 || rate >= 1 {
+					return errors.New("transaction-hash-filter-false-positive-rate must be between 0 and 1 (exclusive)")
+				}
+				return nil
+			},
+		},
+		{
+			Name: "db-circuit-breaker-threshold",
+			Usage: "Number of consecutive failed (or timed out) database reads after which JSON-RPC methods stop " +
+				"reading from the database for db-circuit-breaker-cooldown. 0 disables the circuit breaker",
+			ConfigKey:    &cfg.DBCircuitBreakerThreshold,
+			DefaultValue: uint(10),
+		},
+		{
+			Name:         "db-circuit-breaker-cooldown",
+			Usage:        "Time after which a read is let through to probe whether the database recovered, once the circuit breaker opened",
+			ConfigKey:    &cfg.DBCircuitBreakerCooldown,
+			DefaultValue: 10 * time.Second,
+		},
+		{
+			Name: "db-max-open-connections",
+			Usage: "Maximum number of open database connections, shared by ingestion and the JSON-RPC methods. " +
+				"Database accesses beyond it wait for a connection to be released (see the db wait metrics). " +
+				"0 means unlimited",
+			ConfigKey:    &cfg.DBMaxOpenConnections,
+			DefaultValue: uint(64),
+		},
+		{
+			Name: "transaction-denylist-path",
+			Usage: "Path to a file listing transactions (one hex-encoded hash per line) which must not be served " +
+				"by getTransaction and getTransactions. The file is reloaded on SIGHUP. Disabled if empty",
+			ConfigKey: &cfg.TransactionDenylistPath,
+		},
 		{
 			Name:         "ingestion-timeout",
 			Usage:        "Ingestion Timeout when bootstrapping data (checkpoint and in-memory initialization) and preparing ledger reads",
@@ -224,6 +342,56 @@ func (cfg *Config) options() Options {
 			DefaultValue: uint32(OneDayOfLedgers),
 			Validate:     positive,
 		},
+		{
+			Name: "history-retention-policy",
+			Usage: "how the stored history is trimmed: \"sequence\" keeps the ledgers within history-retention-window " +
+				"and \"time\" keeps the ledgers which closed within history-retention-period",
+			ConfigKey:    &cfg.HistoryRetentionPolicy,
+			DefaultValue: RetentionPolicySequence,
+			Validate: func(_ *Option) error {
+				switch cfg.HistoryRetentionPolicy {
+				case RetentionPolicySequence:
+					return nil
+				case RetentionPolicyTime:
+					if cfg.HistoryRetentionPeriod < time.Second {
+						return errors.New("history-retention-period must be at least one second")
+					}
+					return nil
+				default:
+					return fmt.Errorf("invalid history-retention-policy %q (expected %q or %q)",
+						cfg.HistoryRetentionPolicy, RetentionPolicySequence, RetentionPolicyTime)
+				}
+			},
+		},
+		{
+			Name:         "history-retention-period",
+			Usage:        "history retention period, used by the \"time\" history-retention-policy",
+			ConfigKey:    &cfg.HistoryRetentionPeriod,
+			DefaultValue: 7 * 24 * time.Hour,
+		},
+		{
+			Name: "history-retention-max-db-size",
+			Usage: "maximum size (in bytes) of the data stored in the database, in addition to the " +
+				"history-retention-policy: the oldest ledgers, transactions and events are trimmed when it's " +
+				"exceeded (the ledger entries count towards it, but are never trimmed). 0 disables the limit",
+			ConfigKey:    &cfg.HistoryRetentionMaxDBSize,
+			DefaultValue: uint64(0),
+		},
+		{
+			Name: "history-retention-min-window",
+			Usage: "minimum number of ledgers retained when trimming to history-retention-max-db-size: getHealth " +
+				"fails instead of trimming past it while the database exceeds its maximum size",
+			ConfigKey:    &cfg.HistoryRetentionMinWindow,
+			DefaultValue: uint32(OneDayOfLedgers / 24),
+			Validate:     positive,
+		},
+		{
+			Name: "min-protocol-version",
+			Usage: "minimum protocol version of the ingested ledgers: ingestion stops on the ledgers of a lower " +
+				"protocol version (e.g. when pointed at the wrong network), which are logged and counted. 0 disables the guard",
+			ConfigKey:    &cfg.MinProtocolVersion,
+			DefaultValue: uint32(0),
+		},
 		// TODO: remove
 		{
 			Name: "event-retention-window",
@@ -304,6 +472,66 @@ func (cfg *Config) options() Options {
 				return nil
 			},
 		},
+		{
+			Name: "max-transactions-response-size",
+			Usage: "Maximum size (in bytes) of the transactions of a getTransactions response. Larger pages are cut " +
+				"short of their limit and flagged with sizeLimited, their cursor pointing to the last returned " +
+				"transaction so that the next page resumes after it. 0 means unlimited",
+			ConfigKey:    &cfg.MaxTransactionsResponseSize,
+			DefaultValue: uint(0),
+		},
+		{
+			Name: "max-transaction-hashes-stream-ledgers",
+			Usage: "Maximum number of ledgers whose transaction hashes can be streamed by a single request to the " +
+				"/transactions/hashes HTTP endpoint",
+			ConfigKey:    &cfg.MaxTransactionHashesStreamLedgers,
+			DefaultValue: uint32(17280),
+			Validate:     positive,
+		},
+		{
+			Name: "max-largest-transactions-ledgers",
+			Usage: "Maximum number of ledgers whose transactions can be ranked by a single getLargestTransactions " +
+				"request. All the transactions of the range are decoded",
+			ConfigKey:    &cfg.MaxLargestTransactionsLedgers,
+			DefaultValue: uint32(1000),
+			Validate:     positive,
+		},
+		{
+			Name: "max-concurrent-streams",
+			Usage: "Maximum number of concurrent streams (e.g. of the /transactions/hashes HTTP endpoint). " +
+				"Further streams are rejected with a 503 status",
+			ConfigKey:    &cfg.MaxConcurrentStreams,
+			DefaultValue: uint(10),
+			Validate:     positive,
+		},
+		{
+			Name: "max-stream-ledgers-per-second",
+			Usage: "Maximum number of ledgers emitted per second by each stream (e.g. of the /transactions/hashes " +
+				"HTTP endpoint), to cap the disk I/O of full history scans. 0 means unlimited",
+			ConfigKey:    &cfg.MaxStreamLedgersPerSecond,
+			DefaultValue: uint(0),
+		},
+		{
+			Name: "max-events-per-transaction",
+			Usage: "Maximum number of diagnostic events returned per transaction by getTransaction and getTransactions. " +
+				"Transactions with more events return the first ones and flag the truncation. 0 means unlimited",
+			ConfigKey:    &cfg.MaxEventsPerTransaction,
+			DefaultValue: uint(0),
+		},
+		{
+			Name: "default-xdr-format",
+			Usage: "Format (base64 or json) of the XDR values in the responses to requests which don't set xdrFormat. " +
+				"Defaults to base64",
+			ConfigKey: &cfg.DefaultXDRFormat,
+		},
+		{
+			Name: "empty-slices",
+			Usage: "How the empty lists of the responses (the diagnostic event lists, e.g. diagnosticEventsXdr, " +
+				"the event topics and the events, transactions and ledger entries of getEvents, getTransactions " +
+				"and getLedgerEntries) are rendered: omit (the default) leaves the fields out, empty renders " +
+				"them as [] and null as null",
+			ConfigKey: &cfg.EmptySlices,
+		},
 		{
 			Name: "max-healthy-ledger-latency",
 			Usage: "maximum ledger latency (i.e. time elapsed since the last known ledger closing time) considered to be healthy" +
@@ -311,6 +539,13 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.MaxHealthyLedgerLatency,
 			DefaultValue: 30 * time.Second,
 		},
+		{
+			Name: "max-transaction-store-lag",
+			Usage: "maximum number of ledgers whose transactions can be missing from the transaction store " +
+				"(i.e. ledgers ingested without indexing their transactions) before getHealth reports the node as unhealthy",
+			ConfigKey:    &cfg.MaxTransactionStoreLag,
+			DefaultValue: uint32(10),
+		},
 		{
 			Name:         "preflight-worker-count",
 			Usage:        "Number of workers (read goroutines) used to compute preflights for the simulateTransaction endpoint. Defaults to the number of CPUs.",
@@ -325,6 +560,43 @@ func (cfg *Config) options() Options {
 			DefaultValue: uint(runtime.NumCPU()),
 			Validate:     positive,
 		},
+		{
+			Name: "json-conversion-worker-count",
+			Usage: "Number of workers used to convert the transactions of the getTransactions and getTransactionsByMemo " +
+				"responses to JSON, when requested through xdrFormat. Defaults to the number of CPUs.",
+			ConfigKey:    &cfg.JSONConversionWorkerCount,
+			DefaultValue: uint(runtime.NumCPU()),
+			Validate:     positive,
+		},
+		{
+			Name: "transactions-by-hash-concurrency",
+			Usage: "Number of transactions of a getTransactionsByHash request looked up concurrently. It can't " +
+				"exceed db-max-open-connections, since each lookup holds a database connection. 1 looks them up " +
+				"sequentially, from a read snapshot of the database",
+			ConfigKey:    &cfg.TransactionsByHashConcurrency,
+			DefaultValue: uint(4),
+			Validate: func(option *Option) error {
+				if err := positive(option); err != nil {
+					return err
+				}
+				if cfg.DBMaxOpenConnections > 0 && cfg.TransactionsByHashConcurrency > cfg.DBMaxOpenConnections {
+					return fmt.Errorf(
+						"transactions-by-hash-concurrency (%d) can't exceed db-max-open-connections (%d)",
+						cfg.TransactionsByHashConcurrency,
+						cfg.DBMaxOpenConnections,
+					)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "json-conversion-timeout",
+			Usage: "Maximum duration of the JSON conversion of a single transaction of the getTransactions and " +
+				"getTransactionsByMemo responses. The transactions whose conversion times out are returned with a " +
+				"conversionError instead of their JSON fields. 0 disables the timeout",
+			ConfigKey:    &cfg.JSONConversionTimeout,
+			DefaultValue: time.Second,
+		},
 		{
 			Name:         "preflight-enable-debug",
 			Usage:        "Enable debug information in preflighting (provides more detailed errors). It should not be enabled in production deployments.",
@@ -394,6 +666,13 @@ func (cfg *Config) options() Options {
 			DefaultValue: uint(1000),
 			Validate:     positive,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-transactions-by-memo-queue-limit"),
+			Usage:        "Maximum number of outstanding GetTransactionsByMemo requests",
+			ConfigKey:    &cfg.RequestBacklogGetTransactionsByMemoQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("request-backlog-send-transaction-queue-limit"),
 			Usage:        "Maximum number of outstanding SendTransaction requests",
@@ -415,6 +694,83 @@ func (cfg *Config) options() Options {
 			DefaultValue: uint(100),
 			Validate:     positive,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-retention-status-queue-limit"),
+			Usage:        "Maximum number of outstanding GetRetentionStatus requests",
+			ConfigKey:    &cfg.RequestBacklogGetRetentionStatusQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-diff-ledger-headers-queue-limit"),
+			Usage:        "Maximum number of outstanding DiffLedgerHeaders requests",
+			ConfigKey:    &cfg.RequestBacklogDiffLedgerHeadersQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-contract-creation-queue-limit"),
+			Usage:        "Maximum number of outstanding GetContractCreation requests",
+			ConfigKey:    &cfg.RequestBacklogGetContractCreationQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-largest-transactions-queue-limit"),
+			Usage:        "Maximum number of outstanding GetLargestTransactions requests",
+			ConfigKey:    &cfg.RequestBacklogGetLargestTransactionsQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-compute-transaction-hash-queue-limit"),
+			Usage:        "Maximum number of outstanding ComputeTransactionHash requests",
+			ConfigKey:    &cfg.RequestBacklogComputeTransactionHashQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-transactions-by-ledger-key-queue-limit"),
+			Usage:        "Maximum number of outstanding GetTransactionsByLedgerKey requests",
+			ConfigKey:    &cfg.RequestBacklogGetTransactionsByLedgerKeyQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-contract-activity-queue-limit"),
+			Usage:        "Maximum number of outstanding GetContractActivity requests",
+			ConfigKey:    &cfg.RequestBacklogGetContractActivityQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-ledger-close-times-queue-limit"),
+			Usage:        "Maximum number of outstanding GetLedgerCloseTimes requests",
+			ConfigKey:    &cfg.RequestBacklogGetLedgerCloseTimesQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-transactions-by-hash-queue-limit"),
+			Usage:        "Maximum number of outstanding GetTransactionsByHash requests",
+			ConfigKey:    &cfg.RequestBacklogGetTransactionsByHashQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-ledger-queue-limit"),
+			Usage:        "Maximum number of outstanding GetLedger requests",
+			ConfigKey:    &cfg.RequestBacklogGetLedgerQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-methods-queue-limit"),
+			Usage:        "Maximum number of outstanding GetMethods requests",
+			ConfigKey:    &cfg.RequestBacklogGetMethodsQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("request-execution-warning-threshold"),
 			Usage:        "The request execution warning threshold is the predetermined maximum duration of time that a request can take to be processed before a warning would be generated",
@@ -457,6 +813,36 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.MaxGetLatestLedgerExecutionDuration,
 			DefaultValue: 5 * time.Second,
 		},
+		{
+			Name: "get-latest-ledger-max-wait",
+			Usage: "maximum duration for which a getLatestLedger request with a minLedger waits for the ingestion " +
+				"of a later ledger, which must be lower than max-get-latest-ledger-execution-duration",
+			ConfigKey:    &cfg.GetLatestLedgerMaxWait,
+			DefaultValue: 4 * time.Second,
+			Validate: func(_ *Option) error {
+				if cfg.GetLatestLedgerMaxWait >= cfg.MaxGetLatestLedgerExecutionDuration {
+					return fmt.Errorf(
+						"get-latest-ledger-max-wait (%v) must be lower than max-get-latest-ledger-execution-duration (%v)",
+						cfg.GetLatestLedgerMaxWait,
+						cfg.MaxGetLatestLedgerExecutionDuration,
+					)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "ledger-close-time-target",
+			Usage: "target interval between the close times of consecutive ledgers, from which getLedger " +
+				"computes the close time drift of a ledger",
+			ConfigKey:    &cfg.LedgerCloseTimeTarget,
+			DefaultValue: 5 * time.Second,
+			Validate: func(_ *Option) error {
+				if cfg.LedgerCloseTimeTarget <= 0 {
+					return fmt.Errorf("ledger-close-time-target (%v) must be positive", cfg.LedgerCloseTimeTarget)
+				}
+				return nil
+			},
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("max-get_ledger-entries-execution-duration"),
 			Usage:        "The maximum duration of time allowed for processing a getLedgerEntries request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
@@ -475,6 +861,12 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.MaxGetTransactionsExecutionDuration,
 			DefaultValue: 5 * time.Second,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-transactions-by-memo-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getTransactionsByMemo request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetTransactionsByMemoExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("max-send-transaction-execution-duration"),
 			Usage:        "The maximum duration of time allowed for processing a sendTransaction request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
@@ -493,6 +885,72 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.MaxGetFeeStatsExecutionDuration,
 			DefaultValue: 5 * time.Second,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-retention-status-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getRetentionStatus request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetRetentionStatusExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-diff-ledger-headers-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a diffLedgerHeaders request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxDiffLedgerHeadersExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-contract-creation-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getContractCreation request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetContractCreationExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-largest-transactions-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getLargestTransactions request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetLargestTransactionsExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-compute-transaction-hash-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a computeTransactionHash request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxComputeTransactionHashExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-transactions-by-ledger-key-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getTransactionsByLedgerKey request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetTransactionsByLedgerKeyExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-contract-activity-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getContractActivity request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetContractActivityExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-ledger-close-times-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getLedgerCloseTimes request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetLedgerCloseTimesExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-transactions-by-hash-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getTransactionsByHash request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetTransactionsByHashExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-ledger-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getLedger request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetLedgerExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-methods-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getMethods request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetMethodsExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
 	}
 	return *cfg.optionsCache
 }
@@ -115,6 +115,10 @@ func TestRoundTrip(t *testing.T) {
 			*v = 42
 		case *uint32:
 			*v = 32
+		case *uint64:
+			*v = 64
+		case *float64:
+			*v = 0.05
 		case *time.Duration:
 			*v = 5 * time.Second
 		case *[]string:
@@ -30,7 +30,10 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingest"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/txdenylist"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
 )
 
@@ -50,12 +53,22 @@ type Daemon struct {
 	core                *ledgerbackend.CaptiveStellarCore
 	coreClient          *CoreClientWithMetrics
 	ingestService       *ingest.Service
+	ingestionWindow     *ingestionwindow.IngestionWindow
+	transactionDenylist *txdenylist.Denylist
 	db                  *db.DB
+	// dbCircuitBreaker guards the database reads of the JSON-RPC methods and HTTP endpoints. A nil
+	// circuit breaker lets all the reads through.
+	dbCircuitBreaker *db.CircuitBreaker
+	// maxDBSizeStatus reports the database exceeding its maximum size, when one is configured.
+	maxDBSizeStatus     *db.MaxDBSizeStatus
 	jsonRPCHandler      *internal.Handler
+	adminRPCHandler     *internal.Handler
 	logger              *supportlog.Entry
 	preflightWorkerPool *preflight.WorkerPool
 	listener            net.Listener
 	server              *http.Server
+	streams             *methods.StreamRegistry
+	snapshotRestorer    *db.SnapshotRestorer
 	adminListener       net.Listener
 	adminServer         *http.Server
 	closeOnce           sync.Once
@@ -84,6 +97,11 @@ func (d *Daemon) close() {
 	defer shutdownRelease()
 	var closeErrors []error
 
+	// the streams would otherwise keep their connections (and the database) busy past the shutdown
+	if err := d.streams.Drain(shutdownCtx); err != nil {
+		d.logger.WithError(err).Error("error draining the streams")
+		closeErrors = append(closeErrors, err)
+	}
 	if err := d.server.Shutdown(shutdownCtx); err != nil {
 		d.logger.WithError(err).Error("error during Soroban JSON RPC server Shutdown")
 		closeErrors = append(closeErrors, err)
@@ -104,6 +122,9 @@ func (d *Daemon) close() {
 		closeErrors = append(closeErrors, err)
 	}
 	d.jsonRPCHandler.Close()
+	if d.adminRPCHandler != nil {
+		d.adminRPCHandler.Close()
+	}
 	if err := d.db.Close(); err != nil {
 		d.logger.WithError(err).Error("Error closing db")
 		closeErrors = append(closeErrors, err)
@@ -151,6 +172,12 @@ func newCaptiveCore(cfg *config.Config, logger *supportlog.Entry) (*ledgerbacken
 
 func MustNew(cfg *config.Config, logger *supportlog.Entry) *Daemon {
 	logger = setupLogger(cfg, logger)
+	if err := methods.IsValidFormat(cfg.DefaultXDRFormat); err != nil {
+		logger.WithError(err).Fatal("invalid default-xdr-format")
+	}
+	if err := methods.IsValidEmptySlicesMode(cfg.EmptySlices); err != nil {
+		logger.WithError(err).Fatal("invalid empty-slices")
+	}
 	core := mustCreateCaptiveCore(cfg, logger)
 	historyArchive := mustCreateHistoryArchive(cfg, logger)
 	metricsRegistry := prometheus.NewRegistry()
@@ -162,12 +189,22 @@ func MustNew(cfg *config.Config, logger *supportlog.Entry) *Daemon {
 		done:            make(chan struct{}),
 		metricsRegistry: metricsRegistry,
 		coreClient:      newCoreClientWithMetrics(createStellarCoreClient(cfg), metricsRegistry),
+		ingestionWindow: ingestionwindow.NewIngestionWindow(ingestionwindow.DefaultRetention),
 	}
+	daemon.transactionDenylist = mustLoadTransactionDenylist(cfg, logger)
 
 	feewindows := daemon.mustInitializeStorage(cfg)
+	daemon.mustEnableTransactionHashFilter(cfg)
 
-	daemon.ingestService = createIngestService(cfg, logger, daemon, feewindows, historyArchive)
+	readWriter := newReadWriter(cfg, logger, daemon)
+	// restored ledgers are ingested by the fee windows, like the ledgers of the ingestion
+	daemon.snapshotRestorer = db.NewSnapshotRestorer(readWriter, daemon.db, feewindows.IngestFees)
+	daemon.ingestService = createIngestService(cfg, logger, daemon, readWriter, feewindows, historyArchive)
 	daemon.preflightWorkerPool = createPreflightWorkerPool(cfg, logger, daemon)
+	if cfg.DBCircuitBreakerThreshold > 0 {
+		daemon.dbCircuitBreaker = db.NewCircuitBreaker(daemon, cfg.DBCircuitBreakerThreshold,
+			cfg.DBCircuitBreakerCooldown, util.RealClock{})
+	}
 	daemon.jsonRPCHandler = createJSONRPCHandler(cfg, logger, daemon, feewindows)
 
 	daemon.setupHTTPServers(cfg)
@@ -220,13 +257,44 @@ func mustCreateHistoryArchive(cfg *config.Config, logger *supportlog.Entry) *his
 }
 
 func mustOpenDatabase(cfg *config.Config, logger *supportlog.Entry, metricsRegistry *prometheus.Registry) *db.DB {
-	dbConn, err := db.OpenSQLiteDBWithPrometheusMetrics(cfg.SQLiteDBPath, prometheusNamespace, "db", metricsRegistry)
+	dbConn, err := db.OpenSQLiteDBWithPrometheusMetrics(cfg.SQLiteDBPath, cfg.DBLedgerCodec,
+		int(cfg.DBMaxOpenConnections), prometheusNamespace, "db", metricsRegistry)
 	if err != nil {
 		logger.WithError(err).Fatal("could not open database")
 	}
+	missingIndexes, err := db.MissingIndexes(context.Background(), dbConn)
+	if err != nil {
+		logger.WithError(err).Warn("could not check database indexes")
+	} else if len(missingIndexes) > 0 {
+		logger.WithField("indexes", missingIndexes).
+			Warn("database indexes are missing (was the database restored manually?), queries may be slow")
+	}
 	return dbConn
 }
 
+func mustLoadTransactionDenylist(cfg *config.Config, logger *supportlog.Entry) *txdenylist.Denylist {
+	if cfg.TransactionDenylistPath == "" {
+		return nil
+	}
+	denylist, err := txdenylist.NewDenylist(cfg.TransactionDenylistPath, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("could not load transaction denylist")
+	}
+	logger.WithField("transactions", denylist.Len()).Info("loaded transaction denylist")
+	return denylist
+}
+
+func (d *Daemon) reloadTransactionDenylist() {
+	if d.transactionDenylist == nil {
+		return
+	}
+	if err := d.transactionDenylist.Reload(); err != nil {
+		d.logger.WithError(err).Error("could not reload transaction denylist, keeping the current one")
+		return
+	}
+	d.logger.WithField("transactions", d.transactionDenylist.Len()).Info("reloaded transaction denylist")
+}
+
 func createStellarCoreClient(cfg *config.Config) stellarcore.Client {
 	return stellarcore.Client{
 		URL:  cfg.StellarCoreURL,
@@ -234,7 +302,40 @@ func createStellarCoreClient(cfg *config.Config) stellarcore.Client {
 	}
 }
 
-func createIngestService(cfg *config.Config, logger *supportlog.Entry, daemon *Daemon,
+// newReadWriter returns a database read-writer applying the configured retention policy, maximum
+// database size and minimum protocol version.
+func newReadWriter(cfg *config.Config, logger *supportlog.Entry, daemon *Daemon) db.ReadWriter {
+	var options []db.ReadWriterOption
+	if cfg.HistoryRetentionMaxDBSize > 0 {
+		daemon.maxDBSizeStatus = db.NewMaxDBSizeStatus(cfg.HistoryRetentionMaxDBSize)
+		options = append(options, db.WithMaxDBSize(daemon.maxDBSizeStatus, cfg.HistoryRetentionMinWindow))
+	}
+	if cfg.MinProtocolVersion > 0 {
+		options = append(options, db.WithMinProtocolVersion(cfg.MinProtocolVersion))
+	}
+	if cfg.HistoryRetentionPolicy == config.RetentionPolicyTime {
+		return db.NewReadWriterWithRetentionPeriod(
+			logger,
+			daemon.db,
+			daemon,
+			maxLedgerEntryWriteBatchSize,
+			cfg.HistoryRetentionPeriod,
+			cfg.NetworkPassphrase,
+			options...,
+		)
+	}
+	return db.NewReadWriter(
+		logger,
+		daemon.db,
+		daemon,
+		maxLedgerEntryWriteBatchSize,
+		cfg.HistoryRetentionWindow,
+		cfg.NetworkPassphrase,
+		options...,
+	)
+}
+
+func createIngestService(cfg *config.Config, logger *supportlog.Entry, daemon *Daemon, readWriter db.ReadWriter,
 	feewindows *feewindow.FeeWindows, historyArchive *historyarchive.ArchiveInterface,
 ) *ingest.Service {
 	onIngestionRetry := func(err error, _ time.Duration) {
@@ -242,15 +343,8 @@ func createIngestService(cfg *config.Config, logger *supportlog.Entry, daemon *D
 	}
 
 	return ingest.NewService(ingest.Config{
-		Logger: logger,
-		DB: db.NewReadWriter(
-			logger,
-			daemon.db,
-			daemon,
-			maxLedgerEntryWriteBatchSize,
-			cfg.HistoryRetentionWindow,
-			cfg.NetworkPassphrase,
-		),
+		Logger:            logger,
+		DB:                readWriter,
 		NetworkPassPhrase: cfg.NetworkPassphrase,
 		Archive:           *historyArchive,
 		LedgerBackend:     daemon.core,
@@ -258,6 +352,7 @@ func createIngestService(cfg *config.Config, logger *supportlog.Entry, daemon *D
 		OnIngestionRetry:  onIngestionRetry,
 		Daemon:            daemon,
 		FeeWindows:        feewindows,
+		IngestionWindow:   daemon.ingestionWindow,
 	})
 }
 
@@ -279,16 +374,46 @@ func createPreflightWorkerPool(cfg *config.Config, logger *supportlog.Entry, dae
 func createJSONRPCHandler(cfg *config.Config, logger *supportlog.Entry, daemon *Daemon,
 	feewindows *feewindow.FeeWindows,
 ) *internal.Handler {
-	rpcHandler := internal.NewJSONRPCHandler(cfg, internal.HandlerParams{
-		Daemon:            daemon,
-		FeeStatWindows:    feewindows,
-		Logger:            logger,
-		LedgerReader:      db.NewLedgerReader(daemon.db),
-		LedgerEntryReader: db.NewLedgerEntryReader(daemon.db),
-		TransactionReader: db.NewTransactionReader(logger, daemon.db, cfg.NetworkPassphrase),
-		EventReader:       db.NewEventReader(logger, daemon.db, cfg.NetworkPassphrase),
-		PreflightGetter:   daemon.preflightWorkerPool,
+	circuitBreaker := daemon.dbCircuitBreaker
+	var maxDBSizeChecker methods.MaxDBSizeChecker
+	if daemon.maxDBSizeStatus != nil {
+		maxDBSizeChecker = daemon.maxDBSizeStatus
+	}
+	ledgerReader := circuitBreaker.WrapLedgerReader(db.NewLedgerReader(daemon.db))
+	rpcHandler, err := internal.NewJSONRPCHandler(cfg, internal.HandlerParams{
+		Daemon:              daemon,
+		FeeStatWindows:      feewindows,
+		IngestionWindow:     daemon.ingestionWindow,
+		Logger:              logger,
+		LedgerReader:        daemon.transactionDenylist.LedgerReader(ledgerReader, cfg.NetworkPassphrase),
+		IndexedLedgerReader: ledgerReader,
+		LedgerEntryReader:   circuitBreaker.WrapLedgerEntryReader(db.NewLedgerEntryReader(daemon.db)),
+		TransactionReader: circuitBreaker.WrapTransactionReader(
+			db.NewTransactionReader(logger, daemon.db, cfg.NetworkPassphrase)),
+		TransactionMemoReader: circuitBreaker.WrapTransactionMemoReader(
+			db.NewTransactionMemoReader(logger, daemon.db, cfg.NetworkPassphrase)),
+		TransactionFilterReader: circuitBreaker.WrapTransactionFilterReader(
+			db.NewTransactionFilterReader(daemon.db)),
+		ContractCreationReader: circuitBreaker.WrapContractCreationReader(db.NewContractCreationReader(daemon.db)),
+		LedgerKeyTransactionReader: circuitBreaker.WrapLedgerKeyTransactionReader(
+			db.NewLedgerKeyTransactionReader(daemon.db)),
+		ContractActivityReader: circuitBreaker.WrapContractActivityReader(
+			db.NewContractActivityReader(daemon.db)),
+		LedgerCloseTimeReader: circuitBreaker.WrapLedgerCloseTimeReader(
+			db.NewLedgerCloseTimeReader(daemon.db)),
+		EventReader:      circuitBreaker.WrapEventReader(db.NewEventReader(logger, daemon.db, cfg.NetworkPassphrase)),
+		DBCircuitBreaker: circuitBreaker,
+		PreflightGetter:  daemon.preflightWorkerPool,
+
+		TransactionStoreLagChecker: db.NewTransactionStoreLagChecker(daemon, daemon.db),
+		MaxDBSizeChecker:           maxDBSizeChecker,
+
+		TransactionDenylist: daemon.transactionDenylist,
+		ReadSnapshotter:     daemon.db,
 	})
+	if err != nil {
+		logger.WithError(err).Fatal("invalid disabled-methods")
+	}
 	return &rpcHandler
 }
 
@@ -298,8 +423,17 @@ func (d *Daemon) setupHTTPServers(cfg *config.Config) {
 	if err != nil {
 		d.logger.WithError(err).WithField("endpoint", cfg.Endpoint).Fatal("cannot listen on endpoint")
 	}
+	transactionReader := d.transactionDenylist.TransactionReader(
+		d.dbCircuitBreaker.WrapTransactionReader(db.NewTransactionReader(d.logger, d.db, cfg.NetworkPassphrase)))
+	ledgerReader := d.transactionDenylist.LedgerReader(d.dbCircuitBreaker.WrapLedgerReader(db.NewLedgerReader(d.db)),
+		cfg.NetworkPassphrase)
+	d.streams = methods.NewStreamRegistry(cfg.MaxConcurrentStreams, cfg.MaxStreamLedgersPerSecond,
+		util.RealClock{})
 	d.server = &http.Server{
-		Handler:     createHTTPHandler(d.logger, d.jsonRPCHandler),
+		Handler: createHTTPHandler(d.logger, d.jsonRPCHandler, transactionReader, ledgerReader,
+			d.transactionDenylist.TransactionHashReader(
+				d.dbCircuitBreaker.WrapTransactionHashReader(db.NewTransactionHashReader(d.db))),
+			cfg.MaxTransactionHashesStreamLedgers, d.streams),
 		ReadTimeout: defaultReadTimeout,
 	}
 
@@ -308,15 +442,64 @@ func (d *Daemon) setupHTTPServers(cfg *config.Config) {
 	}
 }
 
-func createHTTPHandler(logger *supportlog.Entry, jsonRPCHandler *internal.Handler) http.Handler {
+func createHTTPHandler(logger *supportlog.Entry, jsonRPCHandler *internal.Handler,
+	transactionReader db.TransactionReader, ledgerReader db.LedgerReader,
+	transactionHashReader db.TransactionHashReader, maxTransactionHashesStreamLedgers uint32,
+	streams *methods.StreamRegistry,
+) http.Handler {
 	httpHandler := supporthttp.NewAPIMux(logger)
 	httpHandler.Handle("/", jsonRPCHandler)
+	// the endpoints serve the data of JSON-RPC methods, whose limits they share (and they are
+	// disabled along with them)
+	for _, endpoint := range []struct {
+		path      string
+		method    string
+		name      string
+		handler   http.Handler
+		streaming bool
+	}{
+		{
+			path: methods.TransactionEnvelopePath, method: "getTransaction", name: "transaction_envelope_http",
+			handler: methods.NewGetTransactionEnvelopeHTTPHandler(logger, transactionReader),
+		},
+		{
+			path: methods.TransactionMetaPath, method: "getTransaction", name: "transaction_meta_http",
+			handler: methods.NewGetTransactionMetaHTTPHandler(logger, transactionReader),
+		},
+		{
+			path: methods.LedgerMetaPath, method: "getLedger", name: "ledger_meta_http",
+			handler: methods.NewGetLedgerMetaHTTPHandler(logger, ledgerReader),
+		},
+		{
+			path: methods.TransactionHashesPath, method: "getTransactions", name: "transaction_hashes_http",
+			handler: methods.NewTransactionHashesHTTPHandler(logger, ledgerReader, transactionHashReader,
+				maxTransactionHashesStreamLedgers, streams),
+			streaming: true,
+		},
+	} {
+		if handler, ok := jsonRPCHandler.WrapHTTPEndpoint(endpoint.method, endpoint.name, endpoint.handler,
+			endpoint.streaming); ok {
+			httpHandler.Method(http.MethodGet, endpoint.path, handler)
+		}
+	}
 	return httpHandler
 }
 
 func (d *Daemon) setupAdminServer(cfg *config.Config) {
-	var err error
-	adminMux := createAdminMux(d.logger, d.metricsRegistry)
+	adminRPCHandler, err := internal.NewAdminJSONRPCHandler(cfg, internal.AdminHandlerParams{
+		DatabaseBackuper:          d.db,
+		IngestionCheckpointReader: d.db,
+		LedgerReader:              db.NewLedgerReader(d.db),
+		StoreStatsGetter:          d.db,
+		SnapshotRestorer:          ingestionStoppingRestorer{d},
+		CacheRefresher:            d.db,
+		Logger:                    d.logger,
+	})
+	if err != nil {
+		d.logger.WithError(err).Fatal("could not create the admin JSON-RPC handler")
+	}
+	d.adminRPCHandler = &adminRPCHandler
+	adminMux := createAdminMux(d.logger, d.metricsRegistry, d.adminRPCHandler)
 	d.adminListener, err = net.Listen("tcp", cfg.AdminEndpoint)
 	if err != nil {
 		d.logger.WithError(err).WithField("endpoint", cfg.AdminEndpoint).Fatal("cannot listen on admin endpoint")
@@ -324,8 +507,27 @@ func (d *Daemon) setupAdminServer(cfg *config.Config) {
 	d.adminServer = &http.Server{Handler: adminMux} //nolint:gosec
 }
 
-func createAdminMux(logger *supportlog.Entry, metricsRegistry *prometheus.Registry) *chi.Mux {
+// ingestionStoppingRestorer restores snapshots with the ingestion stopped, so that the
+// restoration doesn't race with it. Ingestion then resumes after the latest restored ledger.
+type ingestionStoppingRestorer struct {
+	daemon *Daemon
+}
+
+func (r ingestionStoppingRestorer) RestoreFromSnapshot(ctx context.Context, path string) (db.LedgerSeqRange, error) {
+	var restored db.LedgerSeqRange
+	err := r.daemon.ingestService.WithIngestionStopped(func() error {
+		var err error
+		restored, err = r.daemon.snapshotRestorer.RestoreFromSnapshot(ctx, path)
+		return err
+	})
+	return restored, err
+}
+
+func createAdminMux(logger *supportlog.Entry, metricsRegistry *prometheus.Registry,
+	adminRPCHandler http.Handler,
+) *chi.Mux {
 	adminMux := supporthttp.NewMux(logger)
+	adminMux.Handle("/", adminRPCHandler)
 	adminMux.HandleFunc("/debug/pprof/", pprof.Index)
 	adminMux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
 	adminMux.HandleFunc("/debug/pprof/profile", pprof.Profile)
@@ -369,7 +571,7 @@ func (d *Daemon) mustInitializeStorage(cfg *config.Config) *feewindow.FeeWindows
 	// 3. Apply all migrations, including fee stat analysis.
 	//
 	var initialSeq, currentSeq uint32
-	err = db.NewLedgerReader(d.db).StreamLedgerRange(
+	err = db.NewLedgerReaderWithPrefetch(d.db, cfg.LedgerStreamPrefetchChunkSize).StreamLedgerRange(
 		readTxMetaCtx,
 		ledgerSeqRange.First,
 		ledgerSeqRange.Last,
@@ -412,6 +614,18 @@ func (d *Daemon) mustInitializeStorage(cfg *config.Config) *feewindow.FeeWindows
 	return feeWindows
 }
 
+func (d *Daemon) mustEnableTransactionHashFilter(cfg *config.Config) {
+	if cfg.TransactionHashFilterCapacity == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.IngestionTimeout)
+	defer cancel()
+	filter := db.NewTransactionHashFilter(cfg.TransactionHashFilterCapacity, cfg.TransactionHashFilterFalsePositiveRate)
+	if err := d.db.EnableTransactionHashFilter(ctx, filter, prometheusNamespace, d.metricsRegistry); err != nil {
+		d.logger.WithError(err).Fatal("could not enable the transaction hash filter")
+	}
+}
+
 func (d *Daemon) buildMigrations(ctx context.Context, cfg *config.Config, retentionRange db.LedgerSeqRange,
 	feeWindows *feewindow.FeeWindows,
 ) db.MultiMigration {
@@ -477,11 +691,19 @@ func (d *Daemon) Run() {
 	// to return to idle and then shut down.
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
-
-	select {
-	case <-signals:
-		d.Close()
-	case <-d.done:
-		return
+	// SIGHUP reloads the reloadable configuration (i.e. the transaction denylist)
+	reloadSignals := make(chan os.Signal, 1)
+	signal.Notify(reloadSignals, syscall.SIGHUP)
+
+	for {
+		select {
+		case <-reloadSignals:
+			d.reloadTransactionDenylist()
+		case <-signals:
+			d.Close()
+			return
+		case <-d.done:
+			return
+		}
 	}
 }
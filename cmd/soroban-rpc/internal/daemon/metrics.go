@@ -17,6 +17,7 @@ import (
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrfailures"
 )
 
 func (d *Daemon) registerMetrics() {
@@ -42,6 +43,7 @@ func (d *Daemon) registerMetrics() {
 	d.metricsRegistry.MustRegister(collectors.NewGoCollector())
 	d.metricsRegistry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	d.metricsRegistry.MustRegister(buildInfoGauge)
+	d.metricsRegistry.MustRegister(xdrfailures.Collector())
 }
 
 func (d *Daemon) MetricsRegistry() *prometheus.Registry {
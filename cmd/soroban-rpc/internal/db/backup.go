@@ -0,0 +1,22 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Backup writes a consistent, compacted copy of the database to path using
+// VACUUM INTO. The copy is taken within a single read transaction, so
+// ingestion can carry on while it runs. The target file must not exist.
+// It returns the size of the produced file.
+func (d *DB) Backup(ctx context.Context, path string) (int64, error) {
+	if _, err := d.ExecRaw(ctx, "VACUUM INTO ?", path); err != nil {
+		return 0, fmt.Errorf("could not back up database to %s: %w", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
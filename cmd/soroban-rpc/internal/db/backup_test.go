@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestBackup(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+	for i := uint32(1); i <= 5; i++ {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		ledger := createLedger(i)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	backupPath := path.Join(t.TempDir(), "backup.sqlite")
+	size, err := db.Backup(ctx, backupPath)
+	require.NoError(t, err)
+	assert.Positive(t, size)
+
+	// backing up onto an existing file fails
+	_, err = db.Backup(ctx, backupPath)
+	require.Error(t, err)
+
+	backup, err := OpenSQLiteDB(backupPath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, backup.Close()) }()
+
+	expected, err := NewLedgerReader(db).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	actual, err := NewLedgerReader(backup).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	assertLedgerRange(t, NewLedgerReader(backup), 1, 5)
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// CacheState is the state held by the in-memory caches of the database.
+type CacheState struct {
+	// LatestLedgerSequence and LatestLedgerCloseTime are the cached latest ledger (0 if not cached yet).
+	LatestLedgerSequence  uint32
+	LatestLedgerCloseTime int64
+	// LedgerEntries is the number of cached ledger entries (the config setting entries).
+	LedgerEntries int
+}
+
+func (c *dbCache) state() CacheState {
+	return CacheState{
+		LatestLedgerSequence:  c.latestLedgerSeq,
+		LatestLedgerCloseTime: c.latestLedgerCloseTime,
+		LedgerEntries:         len(c.ledgerEntries.entries),
+	}
+}
+
+// RefreshCaches reloads the cached latest ledger and ledger entries from the database, returning
+// the state of the caches before and after the refresh. The caches are locked for the duration of
+// the refresh, which makes it atomic with respect to the ingestion commits (which update the caches
+// while holding the same lock) and to the readers. The transaction hash filter isn't refreshed,
+// since it is only rebuilt at startup.
+func (d *DB) RefreshCaches(ctx context.Context) (CacheState, CacheState, error) {
+	d.cache.Lock()
+	defer d.cache.Unlock()
+	before := d.cache.state()
+
+	var sequences []uint32
+	query := sq.Select("sequence").From(ledgerCloseMetaTableName).OrderBy("sequence DESC").Limit(1)
+	if err := d.Select(ctx, &sequences, query); err != nil {
+		return before, before, fmt.Errorf("could not query the latest ledger: %w", err)
+	}
+	var latestLedgerSeq uint32
+	var latestLedgerCloseTime int64
+	if len(sequences) > 0 {
+		ledger, found, err := NewLedgerReader(d).GetLedger(ctx, sequences[0])
+		if err != nil {
+			return before, before, fmt.Errorf("could not get the latest ledger: %w", err)
+		}
+		if !found {
+			return before, before, errors.New("the latest ledger was removed during the refresh")
+		}
+		latestLedgerSeq = ledger.LedgerSequence()
+		latestLedgerCloseTime = ledger.LedgerCloseTime()
+	}
+
+	keys := make([]string, 0, len(d.cache.ledgerEntries.entries))
+	for key := range d.cache.ledgerEntries.entries {
+		keys = append(keys, key)
+	}
+	entries := make(map[string]string, len(keys))
+	if len(keys) > 0 {
+		var rows []struct {
+			Key   string `db:"key"`
+			Entry string `db:"entry"`
+		}
+		query := sq.Select("key", "entry").From(ledgerEntriesTableName).Where(sq.Eq{"key": keys})
+		if err := d.Select(ctx, &rows, query); err != nil {
+			return before, before, fmt.Errorf("could not query the cached ledger entries: %w", err)
+		}
+		for _, row := range rows {
+			entries[row.Key] = row.Entry
+		}
+	}
+
+	// only update the caches once all the queries succeeded
+	d.cache.latestLedgerSeq = latestLedgerSeq
+	d.cache.latestLedgerCloseTime = latestLedgerCloseTime
+	// the entries are updated in place, since the pending write transactions reference the map
+	for _, key := range keys {
+		if entry, ok := entries[key]; ok {
+			d.cache.ledgerEntries.entries[key] = entry
+		} else {
+			delete(d.cache.ledgerEntries.entries, key)
+		}
+	}
+	return before, d.cache.state(), nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+func TestRefreshCaches(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := makeReadWriter(db, 150, 15)
+
+	maxSize := xdr.Uint32(1024)
+	configEntry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeConfigSetting,
+			ConfigSetting: &xdr.ConfigSettingEntry{
+				ConfigSettingId:      xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+				ContractMaxSizeBytes: &maxSize,
+			},
+		},
+	}
+	for seq := uint32(1); seq <= 3; seq++ {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		if seq == 1 {
+			require.NoError(t, tx.LedgerEntryWriter().UpsertLedgerEntry(configEntry))
+		}
+		ledger := createLedger(seq)
+		ledger.V1.LedgerHeader.Header.ScpValue.CloseTime = xdr.TimePoint(100 + seq)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	before, after, err := db.RefreshCaches(ctx)
+	require.NoError(t, err)
+	require.Equal(t, CacheState{LatestLedgerSequence: 3, LatestLedgerCloseTime: 103, LedgerEntries: 1}, before)
+	require.Equal(t, before, after)
+
+	// make the caches stale
+	db.cache.Lock()
+	db.cache.latestLedgerSeq = 1
+	db.cache.latestLedgerCloseTime = 101
+	var cachedKey string
+	for key := range db.cache.ledgerEntries.entries {
+		cachedKey = key
+	}
+	cachedEntry := db.cache.ledgerEntries.entries[cachedKey]
+	db.cache.ledgerEntries.entries[cachedKey] = "stale"
+	db.cache.ledgerEntries.entries["removed"] = "stale"
+	db.cache.Unlock()
+
+	ledgerRange, err := NewLedgerReader(db).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), ledgerRange.LastLedger.Sequence)
+
+	before, after, err = db.RefreshCaches(ctx)
+	require.NoError(t, err)
+	require.Equal(t, CacheState{LatestLedgerSequence: 1, LatestLedgerCloseTime: 101, LedgerEntries: 2}, before)
+	require.Equal(t, CacheState{LatestLedgerSequence: 3, LatestLedgerCloseTime: 103, LedgerEntries: 1}, after)
+	require.Equal(t, map[string]string{cachedKey: cachedEntry}, db.cache.ledgerEntries.entries)
+
+	ledgerRange, err = NewLedgerReader(db).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), ledgerRange.LastLedger.Sequence)
+	require.Equal(t, int64(103), ledgerRange.LastLedger.CloseTime)
+}
@@ -0,0 +1,545 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+// ErrCircuitBreakerOpen is returned instead of reading from the database while the
+// circuit breaker is open.
+var ErrCircuitBreakerOpen = errors.New("service unavailable: database reads are suspended after repeated failures")
+
+type CircuitBreakerState int
+
+const (
+	// CircuitBreakerClosed lets all the reads through.
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerHalfOpen lets a single read through, to probe whether the database recovered.
+	CircuitBreakerHalfOpen
+	// CircuitBreakerOpen rejects all the reads until the cooldown elapses.
+	CircuitBreakerOpen
+)
+
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case CircuitBreakerClosed:
+		return "closed"
+	case CircuitBreakerHalfOpen:
+		return "half-open"
+	case CircuitBreakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker guards the database read path. After threshold consecutive read
+// failures (including timeouts) it opens, rejecting reads with ErrCircuitBreakerOpen
+// for the cooldown period. It then lets a single probe read through: the breaker
+// closes if the probe succeeds and opens again otherwise.
+//
+// Reads which are cancelled by the client or which don't find what they look for
+// (e.g. ErrNoTransaction) are not failures.
+type CircuitBreaker struct {
+	threshold uint
+	cooldown  time.Duration
+	clock     util.Clock
+
+	lock                sync.Mutex
+	state               CircuitBreakerState
+	consecutiveFailures uint
+	openedAt            time.Time
+	probing             bool
+
+	stateMetric    prometheus.Gauge
+	rejectedMetric prometheus.Counter
+}
+
+// NewCircuitBreaker creates a circuit breaker opening after threshold consecutive
+// failures. A threshold of 0 disables the circuit breaker, as does using a nil *CircuitBreaker.
+func NewCircuitBreaker(daemon interfaces.Daemon, threshold uint, cooldown time.Duration,
+	clock util.Clock,
+) *CircuitBreaker {
+	stateMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: daemon.MetricsNamespace(), Subsystem: "db",
+		Name: "circuit_breaker_state",
+		Help: "state of the database read circuit breaker (0: closed, 1: half-open, 2: open)",
+	})
+	rejectedMetric := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: daemon.MetricsNamespace(), Subsystem: "db",
+		Name: "circuit_breaker_rejected_reads",
+		Help: "number of database reads rejected by the open circuit breaker",
+	})
+	daemon.MetricsRegistry().MustRegister(stateMetric, rejectedMetric)
+
+	return &CircuitBreaker{
+		threshold:      threshold,
+		cooldown:       cooldown,
+		clock:          clock,
+		stateMetric:    stateMetric,
+		rejectedMetric: rejectedMetric,
+	}
+}
+
+// State returns the current state of the circuit breaker.
+func (b *CircuitBreaker) State() CircuitBreakerState {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
+
+func (b *CircuitBreaker) setState(state CircuitBreakerState) {
+	b.state = state
+	b.stateMetric.Set(float64(state))
+}
+
+// allow reports whether a read can go ahead and, if so, whether it is the probe read.
+func (b *CircuitBreaker) allow() (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.state == CircuitBreakerOpen {
+		if b.clock.Now().Sub(b.openedAt) < b.cooldown {
+			b.rejectedMetric.Inc()
+			return false, ErrCircuitBreakerOpen
+		}
+		b.setState(CircuitBreakerHalfOpen)
+	}
+	if b.state == CircuitBreakerHalfOpen {
+		if b.probing {
+			b.rejectedMetric.Inc()
+			return false, ErrCircuitBreakerOpen
+		}
+		b.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+func isReadFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, ErrNoTransaction) &&
+		!errors.Is(err, ErrEmptyDB)
+}
+
+func (b *CircuitBreaker) record(probe bool, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if probe {
+		b.probing = false
+	}
+	if !isReadFailure(err) {
+		b.consecutiveFailures = 0
+		// a cancelled probe tells nothing about the database, so let another read probe it
+		if probe && !errors.Is(err, context.Canceled) {
+			b.setState(CircuitBreakerClosed)
+		}
+		return
+	}
+	b.consecutiveFailures++
+	if probe || (b.state == CircuitBreakerClosed && b.consecutiveFailures >= b.threshold) {
+		b.openedAt = b.clock.Now()
+		b.setState(CircuitBreakerOpen)
+	}
+}
+
+// run performs the read f through the circuit breaker.
+func (b *CircuitBreaker) run(f func() error) error {
+	if b == nil || b.threshold == 0 {
+		return f()
+	}
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+	err = f()
+	b.record(probe, err)
+	return err
+}
+
+// runStream streams ledgers to f through the circuit breaker. Errors returned by f
+// are not read failures.
+func (b *CircuitBreaker) runStream(f StreamLedgerFn, stream func(StreamLedgerFn) error) error {
+	var callbackErr error
+	var streamErr error
+	err := b.run(func() error {
+		streamErr = stream(func(ledger xdr.LedgerCloseMeta) error {
+			callbackErr = f(ledger)
+			return callbackErr
+		})
+		if callbackErr != nil && errors.Is(streamErr, callbackErr) {
+			return nil
+		}
+		return streamErr
+	})
+	if err != nil {
+		return err
+	}
+	return streamErr
+}
+
+// WrapLedgerReader returns a LedgerReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapLedgerReader(reader LedgerReader) LedgerReader {
+	return circuitBreakerLedgerReader{reader: reader, breaker: b}
+}
+
+// WrapLedgerEntryReader returns a LedgerEntryReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapLedgerEntryReader(reader LedgerEntryReader) LedgerEntryReader {
+	return circuitBreakerLedgerEntryReader{reader: reader, breaker: b}
+}
+
+// WrapTransactionReader returns a TransactionReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapTransactionReader(reader TransactionReader) TransactionReader {
+	return circuitBreakerTransactionReader{reader: reader, breaker: b}
+}
+
+// WrapTransactionMemoReader returns a TransactionMemoReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapTransactionMemoReader(reader TransactionMemoReader) TransactionMemoReader {
+	return circuitBreakerTransactionMemoReader{reader: reader, breaker: b}
+}
+
+// WrapTransactionFilterReader returns a TransactionFilterReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapTransactionFilterReader(reader TransactionFilterReader) TransactionFilterReader {
+	return circuitBreakerTransactionFilterReader{reader: reader, breaker: b}
+}
+
+// WrapLedgerKeyTransactionReader returns a LedgerKeyTransactionReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapLedgerKeyTransactionReader(reader LedgerKeyTransactionReader) LedgerKeyTransactionReader {
+	return circuitBreakerLedgerKeyTransactionReader{reader: reader, breaker: b}
+}
+
+// WrapContractActivityReader returns a ContractActivityReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapContractActivityReader(reader ContractActivityReader) ContractActivityReader {
+	return circuitBreakerContractActivityReader{reader: reader, breaker: b}
+}
+
+// WrapLedgerCloseTimeReader returns a LedgerCloseTimeReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapLedgerCloseTimeReader(reader LedgerCloseTimeReader) LedgerCloseTimeReader {
+	return circuitBreakerLedgerCloseTimeReader{reader: reader, breaker: b}
+}
+
+// WrapContractCreationReader returns a ContractCreationReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapContractCreationReader(reader ContractCreationReader) ContractCreationReader {
+	return circuitBreakerContractCreationReader{reader: reader, breaker: b}
+}
+
+// WrapTransactionHashReader returns a TransactionHashReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapTransactionHashReader(reader TransactionHashReader) TransactionHashReader {
+	return circuitBreakerTransactionHashReader{reader: reader, breaker: b}
+}
+
+// WrapEventReader returns an EventReader whose reads go through the circuit breaker.
+func (b *CircuitBreaker) WrapEventReader(reader EventReader) EventReader {
+	return circuitBreakerEventReader{reader: reader, breaker: b}
+}
+
+type circuitBreakerLedgerReader struct {
+	reader  LedgerReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerLedgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
+	var (
+		ledger xdr.LedgerCloseMeta
+		found  bool
+	)
+	err := r.breaker.run(func() error {
+		var err error
+		ledger, found, err = r.reader.GetLedger(ctx, sequence)
+		return err
+	})
+	return ledger, found, err
+}
+
+func (r circuitBreakerLedgerReader) StreamAllLedgers(ctx context.Context, f StreamLedgerFn) error {
+	return r.breaker.runStream(f, func(f StreamLedgerFn) error {
+		return r.reader.StreamAllLedgers(ctx, f)
+	})
+}
+
+func (r circuitBreakerLedgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
+	var ledgerRange ledgerbucketwindow.LedgerRange
+	err := r.breaker.run(func() error {
+		var err error
+		ledgerRange, err = r.reader.GetLedgerRange(ctx)
+		return err
+	})
+	return ledgerRange, err
+}
+
+func (r circuitBreakerLedgerReader) StreamLedgerRange(ctx context.Context, startLedger uint32, endLedger uint32,
+	f StreamLedgerFn,
+) error {
+	return r.breaker.runStream(f, func(f StreamLedgerFn) error {
+		return r.reader.StreamLedgerRange(ctx, startLedger, endLedger, f)
+	})
+}
+
+type circuitBreakerLedgerEntryReader struct {
+	reader  LedgerEntryReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerLedgerEntryReader) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	var sequence uint32
+	err := r.breaker.run(func() error {
+		var err error
+		sequence, err = r.reader.GetLatestLedgerSequence(ctx)
+		return err
+	})
+	return sequence, err
+}
+
+func (r circuitBreakerLedgerEntryReader) NewTx(ctx context.Context, cacheTx bool) (LedgerEntryReadTx, error) {
+	var tx LedgerEntryReadTx
+	err := r.breaker.run(func() error {
+		var err error
+		tx, err = r.reader.NewTx(ctx, cacheTx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return circuitBreakerLedgerEntryReadTx{tx: tx, breaker: r.breaker}, nil
+}
+
+type circuitBreakerLedgerEntryReadTx struct {
+	tx      LedgerEntryReadTx
+	breaker *CircuitBreaker
+}
+
+func (t circuitBreakerLedgerEntryReadTx) GetLatestLedgerSequence() (uint32, error) {
+	var sequence uint32
+	err := t.breaker.run(func() error {
+		var err error
+		sequence, err = t.tx.GetLatestLedgerSequence()
+		return err
+	})
+	return sequence, err
+}
+
+func (t circuitBreakerLedgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKeyAndEntry, error) {
+	var entries []LedgerKeyAndEntry
+	err := t.breaker.run(func() error {
+		var err error
+		entries, err = t.tx.GetLedgerEntries(keys...)
+		return err
+	})
+	return entries, err
+}
+
+func (t circuitBreakerLedgerEntryReadTx) Done() error {
+	return t.tx.Done()
+}
+
+type circuitBreakerTransactionReader struct {
+	reader  TransactionReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerTransactionReader) GetTransaction(ctx context.Context, hash xdr.Hash) (Transaction, error) {
+	var tx Transaction
+	err := r.breaker.run(func() error {
+		var err error
+		tx, err = r.reader.GetTransaction(ctx, hash)
+		return err
+	})
+	return tx, err
+}
+
+func (r circuitBreakerTransactionReader) GetTransactionByResultHash(ctx context.Context, resultHash xdr.Hash) (
+	Transaction, error,
+) {
+	var tx Transaction
+	err := r.breaker.run(func() error {
+		var err error
+		tx, err = r.reader.GetTransactionByResultHash(ctx, resultHash)
+		return err
+	})
+	return tx, err
+}
+
+func (r circuitBreakerTransactionReader) CountTransactions(ctx context.Context, startLedger uint32,
+	endLedger uint32,
+) (uint64, error) {
+	var count uint64
+	err := r.breaker.run(func() error {
+		var err error
+		count, err = r.reader.CountTransactions(ctx, startLedger, endLedger)
+		return err
+	})
+	return count, err
+}
+
+type circuitBreakerTransactionMemoReader struct {
+	reader  TransactionMemoReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerTransactionMemoReader) GetTransactionsByMemo(ctx context.Context, memoType xdr.MemoType,
+	memoValue []byte, startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	var locations []TransactionLocation
+	err := r.breaker.run(func() error {
+		var err error
+		locations, err = r.reader.GetTransactionsByMemo(ctx, memoType, memoValue, startLedger, startOrder,
+			endLedger, limit)
+		return err
+	})
+	return locations, err
+}
+
+type circuitBreakerTransactionFilterReader struct {
+	reader  TransactionFilterReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerTransactionFilterReader) GetTransactionsBySourceAccount(ctx context.Context,
+	account xdr.AccountId, startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	var locations []TransactionLocation
+	err := r.breaker.run(func() error {
+		var err error
+		locations, err = r.reader.GetTransactionsBySourceAccount(ctx, account, startLedger, startOrder,
+			endLedger, limit)
+		return err
+	})
+	return locations, err
+}
+
+func (r circuitBreakerTransactionFilterReader) GetTransactionsByOperationType(ctx context.Context,
+	operationType xdr.OperationType, startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	var locations []TransactionLocation
+	err := r.breaker.run(func() error {
+		var err error
+		locations, err = r.reader.GetTransactionsByOperationType(ctx, operationType, startLedger, startOrder,
+			endLedger, limit)
+		return err
+	})
+	return locations, err
+}
+
+type circuitBreakerLedgerKeyTransactionReader struct {
+	reader  LedgerKeyTransactionReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerLedgerKeyTransactionReader) GetTransactionsByLedgerKey(ctx context.Context,
+	key xdr.LedgerKey, startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	var locations []TransactionLocation
+	err := r.breaker.run(func() error {
+		var err error
+		locations, err = r.reader.GetTransactionsByLedgerKey(ctx, key, startLedger, startOrder, endLedger, limit)
+		return err
+	})
+	return locations, err
+}
+
+type circuitBreakerContractActivityReader struct {
+	reader  ContractActivityReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerContractActivityReader) GetContractActivity(ctx context.Context, startLedger, endLedger uint32,
+	limit uint,
+) ([]ContractActivity, error) {
+	var activity []ContractActivity
+	err := r.breaker.run(func() error {
+		var err error
+		activity, err = r.reader.GetContractActivity(ctx, startLedger, endLedger, limit)
+		return err
+	})
+	return activity, err
+}
+
+type circuitBreakerLedgerCloseTimeReader struct {
+	reader  LedgerCloseTimeReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerLedgerCloseTimeReader) GetLedgerCloseTimes(ctx context.Context, startLedger, endLedger,
+	stride uint32,
+) ([]LedgerCloseTime, error) {
+	var closeTimes []LedgerCloseTime
+	err := r.breaker.run(func() error {
+		var err error
+		closeTimes, err = r.reader.GetLedgerCloseTimes(ctx, startLedger, endLedger, stride)
+		return err
+	})
+	return closeTimes, err
+}
+
+type circuitBreakerContractCreationReader struct {
+	reader  ContractCreationReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerContractCreationReader) GetContractCreation(ctx context.Context, contractID xdr.Hash,
+) (ContractCreation, bool, error) {
+	var creation ContractCreation
+	var found bool
+	err := r.breaker.run(func() error {
+		var err error
+		creation, found, err = r.reader.GetContractCreation(ctx, contractID)
+		return err
+	})
+	return creation, found, err
+}
+
+type circuitBreakerEventReader struct {
+	reader  EventReader
+	breaker *CircuitBreaker
+}
+
+func (r circuitBreakerEventReader) GetEvents(
+	ctx context.Context,
+	cursorRange CursorRange,
+	contractIDs [][]byte,
+	topics NestedTopicArray,
+	eventTypes []int,
+	f ScanFunction,
+) error {
+	return r.breaker.run(func() error {
+		return r.reader.GetEvents(ctx, cursorRange, contractIDs, topics, eventTypes, f)
+	})
+}
+
+type circuitBreakerTransactionHashReader struct {
+	reader  TransactionHashReader
+	breaker *CircuitBreaker
+}
+
+// StreamTransactionHashes streams the hashes through the circuit breaker. Errors returned by f
+// are not read failures.
+func (r circuitBreakerTransactionHashReader) StreamTransactionHashes(ctx context.Context,
+	startLedger, endLedger uint32, f StreamTransactionHashFn,
+) error {
+	var callbackErr error
+	var streamErr error
+	err := r.breaker.run(func() error {
+		streamErr = r.reader.StreamTransactionHashes(ctx, startLedger, endLedger, func(hash TransactionHash) error {
+			callbackErr = f(hash)
+			return callbackErr
+		})
+		if callbackErr != nil && errors.Is(streamErr, callbackErr) {
+			return nil
+		}
+		return streamErr
+	})
+	if err != nil {
+		return err
+	}
+	return streamErr
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+type fakeTransactionReader struct {
+	calls int
+	err   error
+}
+
+func (r *fakeTransactionReader) GetTransaction(context.Context, xdr.Hash) (Transaction, error) {
+	r.calls++
+	return Transaction{}, r.err
+}
+
+func (r *fakeTransactionReader) GetTransactionByResultHash(context.Context, xdr.Hash) (Transaction, error) {
+	r.calls++
+	return Transaction{}, r.err
+}
+
+func (r *fakeTransactionReader) CountTransactions(context.Context, uint32, uint32) (uint64, error) {
+	r.calls++
+	return 0, r.err
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	clock := util.NewManualClock(time.Unix(1000, 0))
+	breaker := NewCircuitBreaker(interfaces.MakeNoOpDeamon(), 3, 10*time.Second, clock)
+	fake := &fakeTransactionReader{}
+	reader := breaker.WrapTransactionReader(fake)
+	dbErr := errors.New("database is locked")
+
+	// misses, cancellations and interleaved successes don't open the breaker
+	fake.err = ErrNoTransaction
+	for range 5 {
+		_, err := reader.GetTransaction(ctx, xdr.Hash{})
+		require.ErrorIs(t, err, ErrNoTransaction)
+	}
+	fake.err = context.Canceled
+	for range 5 {
+		_, err := reader.GetTransaction(ctx, xdr.Hash{})
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	for _, err := range []error{dbErr, dbErr, nil, dbErr, dbErr} {
+		fake.err = err
+		_, _ = reader.GetTransaction(ctx, xdr.Hash{})
+	}
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+
+	// the third consecutive failure (timeouts included) opens it
+	fake.err = context.DeadlineExceeded
+	_, err := reader.GetTransaction(ctx, xdr.Hash{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, CircuitBreakerOpen, breaker.State())
+
+	// while open, reads are rejected without reaching the database
+	calls := fake.calls
+	_, err = reader.GetTransaction(ctx, xdr.Hash{})
+	require.ErrorIs(t, err, ErrCircuitBreakerOpen)
+	assert.Equal(t, calls, fake.calls)
+
+	// after the cooldown, a failing probe opens it again
+	clock.Advance(10 * time.Second)
+	fake.err = dbErr
+	_, err = reader.GetTransaction(ctx, xdr.Hash{})
+	require.ErrorIs(t, err, dbErr)
+	assert.Equal(t, calls+1, fake.calls)
+	assert.Equal(t, CircuitBreakerOpen, breaker.State())
+	_, err = reader.GetTransaction(ctx, xdr.Hash{})
+	require.ErrorIs(t, err, ErrCircuitBreakerOpen)
+
+	// and a successful probe closes it
+	clock.Advance(10 * time.Second)
+	fake.err = nil
+	_, err = reader.GetTransaction(ctx, xdr.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+	_, err = reader.GetTransaction(ctx, xdr.Hash{})
+	require.NoError(t, err)
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	clock := util.NewManualClock(time.Unix(1000, 0))
+	breaker := NewCircuitBreaker(interfaces.MakeNoOpDeamon(), 1, time.Second, clock)
+	dbErr := errors.New("disk I/O error")
+	require.ErrorIs(t, breaker.run(func() error { return dbErr }), dbErr)
+	require.Equal(t, CircuitBreakerOpen, breaker.State())
+
+	clock.Advance(time.Second)
+	// only the probe goes through while half-open
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	probeErr := make(chan error)
+	go func() {
+		probeErr <- breaker.run(func() error {
+			close(probing)
+			<-release
+			return dbErr
+		})
+	}()
+	<-probing
+	assert.Equal(t, CircuitBreakerHalfOpen, breaker.State())
+	require.ErrorIs(t, breaker.run(func() error {
+		t.Fatal("concurrent read must be rejected while probing")
+		return nil
+	}), ErrCircuitBreakerOpen)
+	close(release)
+	require.ErrorIs(t, <-probeErr, dbErr)
+	assert.Equal(t, CircuitBreakerOpen, breaker.State())
+
+	// a cancelled probe leaves the breaker half-open for the next read to probe
+	clock.Advance(time.Second)
+	require.ErrorIs(t, breaker.run(func() error { return context.Canceled }), context.Canceled)
+	assert.Equal(t, CircuitBreakerHalfOpen, breaker.State())
+	require.NoError(t, breaker.run(func() error { return nil }))
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+}
+
+func TestCircuitBreakerStreamCallbackErrors(t *testing.T) {
+	clock := util.NewManualClock(time.Unix(1000, 0))
+	breaker := NewCircuitBreaker(interfaces.MakeNoOpDeamon(), 1, time.Second, clock)
+	callbackErr := errors.New("stop")
+	err := breaker.runStream(
+		func(xdr.LedgerCloseMeta) error { return callbackErr },
+		func(f StreamLedgerFn) error { return f(xdr.LedgerCloseMeta{}) },
+	)
+	require.ErrorIs(t, err, callbackErr)
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+}
+
+type fakeTransactionHashReader struct {
+	err error
+}
+
+func (r fakeTransactionHashReader) StreamTransactionHashes(_ context.Context, _, _ uint32,
+	f StreamTransactionHashFn,
+) error {
+	if r.err != nil {
+		return r.err
+	}
+	return f(TransactionHash{})
+}
+
+func TestCircuitBreakerTransactionHashReader(t *testing.T) {
+	ctx := context.Background()
+	clock := util.NewManualClock(time.Unix(1000, 0))
+	breaker := NewCircuitBreaker(interfaces.MakeNoOpDeamon(), 1, time.Second, clock)
+
+	// callback errors aren't read failures
+	callbackErr := errors.New("client went away")
+	reader := breaker.WrapTransactionHashReader(fakeTransactionHashReader{})
+	err := reader.StreamTransactionHashes(ctx, 1, 2, func(TransactionHash) error { return callbackErr })
+	require.ErrorIs(t, err, callbackErr)
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+
+	dbErr := errors.New("database is locked")
+	reader = breaker.WrapTransactionHashReader(fakeTransactionHashReader{err: dbErr})
+	err = reader.StreamTransactionHashes(ctx, 1, 2, func(TransactionHash) error { return nil })
+	require.ErrorIs(t, err, dbErr)
+	assert.Equal(t, CircuitBreakerOpen, breaker.State())
+	err = reader.StreamTransactionHashes(ctx, 1, 2, func(TransactionHash) error { return nil })
+	require.ErrorIs(t, err, ErrCircuitBreakerOpen)
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	breaker := NewCircuitBreaker(interfaces.MakeNoOpDeamon(), 0, time.Second, util.RealClock{})
+	dbErr := errors.New("database is locked")
+	for range 10 {
+		require.ErrorIs(t, breaker.run(func() error { return dbErr }), dbErr)
+	}
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+}
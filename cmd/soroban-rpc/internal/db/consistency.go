@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+var errFoundMissingTransactions = errors.New("found a ledger with missing transactions")
+
+// TransactionStoreLagChecker detects the transaction store lagging behind the ledger
+// store, e.g. because ledgers keep being ingested while their transactions aren't indexed.
+type TransactionStoreLagChecker struct {
+	db        *DB
+	lagMetric prometheus.Gauge
+
+	lock sync.Mutex
+	// consistentUpTo is the latest ledger known to have all its transactions indexed.
+	// Stored ledgers don't change, so it saves scanning the same empty ledgers again.
+	consistentUpTo uint32
+}
+
+func NewTransactionStoreLagChecker(daemon interfaces.Daemon, db *DB) *TransactionStoreLagChecker {
+	lagMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: daemon.MetricsNamespace(), Subsystem: "db",
+		Name: "transaction_store_lag_ledgers",
+		Help: "number of ledgers since the oldest stored ledger whose transactions are missing from the transaction store",
+	})
+	daemon.MetricsRegistry().MustRegister(lagMetric)
+	return &TransactionStoreLagChecker{db: db, lagMetric: lagMetric}
+}
+
+func (c *TransactionStoreLagChecker) maxSequence(ctx context.Context, column string, table string) (uint32, error) {
+	var sequences []uint32
+	query := sq.Select(fmt.Sprintf("COALESCE(MAX(%s), 0)", column)).From(table)
+	if err := c.db.Select(ctx, &sequences, query); err != nil {
+		return 0, fmt.Errorf("could not query the latest ledger of table %q: %w", table, err)
+	}
+	return sequences[0], nil
+}
+
+// Lag returns the number of ledgers from the first stored ledger whose transactions are missing
+// from the transaction store to the latest stored ledger (0 if the stores are consistent).
+//
+// The latest ledger of the transaction store alone isn't enough, since ledgers can be empty:
+// the ledgers following it are scanned for transactions.
+func (c *TransactionStoreLagChecker) Lag(ctx context.Context) (uint32, error) {
+	latestLedger, err := c.maxSequence(ctx, "sequence", ledgerCloseMetaTableName)
+	if err != nil {
+		return 0, err
+	}
+	latestTxLedger, err := c.maxSequence(ctx, "ledger_sequence", transactionTableName)
+	if err != nil {
+		return 0, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	start := max(latestTxLedger, c.consistentUpTo) + 1
+	var firstMissing uint32
+	if start <= latestLedger {
+		err = NewLedgerReader(c.db).StreamLedgerRange(ctx, start, latestLedger,
+			func(ledger xdr.LedgerCloseMeta) error {
+				if ledger.CountTransactions() > 0 {
+					firstMissing = ledger.LedgerSequence()
+					return errFoundMissingTransactions
+				}
+				return nil
+			})
+		if err != nil && !errors.Is(err, errFoundMissingTransactions) {
+			return 0, fmt.Errorf("could not scan ledgers: %w", err)
+		}
+	}
+
+	var lag uint32
+	if firstMissing == 0 {
+		c.consistentUpTo = max(c.consistentUpTo, latestLedger)
+	} else {
+		c.consistentUpTo = max(c.consistentUpTo, firstMissing-1)
+		lag = latestLedger - firstMissing + 1
+	}
+	c.lagMetric.Set(float64(lag))
+	return lag, nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestTransactionStoreLag(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 100, passphrase)
+	checker := NewTransactionStoreLagChecker(interfaces.MakeNoOpDeamon(), db)
+
+	ingest := func(lcm xdr.LedgerCloseMeta, indexTransactions bool) {
+		write, err := writer.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+		if indexTransactions {
+			require.NoError(t, write.TransactionWriter().InsertTransactions(lcm))
+		}
+		require.NoError(t, write.Commit(lcm))
+	}
+	requireLag := func(expected uint32) {
+		lag, err := checker.Lag(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, lag)
+	}
+
+	requireLag(0)
+
+	// empty ledgers following the latest indexed transaction aren't a lag
+	ingest(txMeta(1, true), true)
+	ingest(createLedger(102), true)
+	ingest(createLedger(103), true)
+	requireLag(0)
+
+	// ledger 104 has a transaction which isn't indexed
+	ingest(txMeta(4, true), false)
+	requireLag(1)
+	ingest(createLedger(105), true)
+	requireLag(2)
+
+	// once indexed, the stores are consistent again
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, write.TransactionWriter().InsertTransactions(txMeta(4, true)))
+	require.NoError(t, write.Commit(createLedger(105)))
+	requireLag(0)
+}
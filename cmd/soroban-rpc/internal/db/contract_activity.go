@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/xdr"
+)
+
+// ContractActivity is the activity of a contract over a range of ledgers, as recorded by the
+// events it emitted.
+type ContractActivity struct {
+	ContractID xdr.Hash
+	// TransactionCount is the number of (successful) transactions in which the contract
+	// emitted events.
+	TransactionCount uint32
+	// EventCount is the number of events emitted by the contract.
+	EventCount uint32
+}
+
+// ContractActivityReader aggregates the activity of contracts.
+type ContractActivityReader interface {
+	// GetContractActivity returns the activity of the (at most limit) most active contracts
+	// between the start and end ledgers (inclusive), ordered by decreasing transaction count
+	// and then event count.
+	GetContractActivity(ctx context.Context, startLedger, endLedger uint32, limit uint) ([]ContractActivity, error)
+}
+
+func NewContractActivityReader(db *DB) ContractActivityReader {
+	return contractActivityReader{db: db}
+}
+
+type contractActivityReader struct {
+	db *DB
+}
+
+func (r contractActivityReader) GetContractActivity(ctx context.Context, startLedger, endLedger uint32,
+	limit uint,
+) ([]ContractActivity, error) {
+	// the events are identified by their cursor, which sorts by ledger first
+	query := sq.Select("contract_id", "COUNT(DISTINCT transaction_hash) AS transactions", "COUNT(*) AS events").
+		From(eventTableName).
+		Where(sq.GtOrEq{"id": Cursor{Ledger: startLedger}.String()}).
+		Where(sq.Lt{"id": Cursor{Ledger: endLedger + 1}.String()}).
+		Where(sq.NotEq{"contract_id": nil}).
+		GroupBy("contract_id").
+		OrderBy("transactions DESC", "events DESC", "contract_id ASC").
+		Limit(uint64(limit))
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not query contract activity: %w", err)
+	}
+	defer rows.Close()
+	var activity []ContractActivity
+	for rows.Next() {
+		var contract ContractActivity
+		var contractID []byte
+		if err := rows.Scan(&contractID, &contract.TransactionCount, &contract.EventCount); err != nil {
+			return nil, err
+		}
+		copy(contract.ContractID[:], contractID)
+		activity = append(activity, contract)
+	}
+	return activity, rows.Err()
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestGetContractActivity(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	a, b, c := xdr.Hash{1}, xdr.Hash{2}, xdr.Hash{3}
+	counter := xdr.ScSymbol("COUNTER")
+	value := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}
+	txMeta := func(contractIDs ...xdr.Hash) xdr.TransactionMeta {
+		var events []xdr.ContractEvent
+		for _, contractID := range contractIDs {
+			events = append(events, contractEvent(contractID, xdr.ScVec{value}, value))
+		}
+		return transactionMetaWithEvents(events...)
+	}
+
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+	for _, ledger := range []xdr.LedgerCloseMeta{
+		ledgerCloseMetaWithEvents(1, 100, txMeta(a, a, b), txMeta(a)),
+		ledgerCloseMetaWithEvents(2, 200, txMeta(b), txMeta(c, c, c)),
+		ledgerCloseMetaWithEvents(3, 300, txMeta(a)),
+	} {
+		write, err := writer.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, write.EventWriter().InsertEvents(ledger))
+		require.NoError(t, write.Commit(ledger))
+	}
+
+	reader := NewContractActivityReader(db)
+	activity, err := reader.GetContractActivity(ctx, 1, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []ContractActivity{
+		{ContractID: a, TransactionCount: 2, EventCount: 3},
+		{ContractID: b, TransactionCount: 2, EventCount: 2},
+		{ContractID: c, TransactionCount: 1, EventCount: 3},
+	}, activity)
+
+	// the most active contracts come first
+	activity, err = reader.GetContractActivity(ctx, 1, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []ContractActivity{
+		{ContractID: a, TransactionCount: 2, EventCount: 3},
+		{ContractID: b, TransactionCount: 2, EventCount: 2},
+	}, activity)
+
+	// ties on the transaction count are broken by the event count, then the contract ID
+	activity, err = reader.GetContractActivity(ctx, 2, 3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []ContractActivity{
+		{ContractID: c, TransactionCount: 1, EventCount: 3},
+		{ContractID: a, TransactionCount: 1, EventCount: 1},
+		{ContractID: b, TransactionCount: 1, EventCount: 1},
+	}, activity)
+
+	activity, err = reader.GetContractActivity(ctx, 4, 10, 10)
+	require.NoError(t, err)
+	assert.Empty(t, activity)
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+const contractCreationTableName = "contract_creations"
+
+// ContractCreation locates the transaction which created a contract.
+type ContractCreation struct {
+	TransactionLocation
+	TransactionHash xdr.Hash
+	// LedgerCloseTime is the unix timestamp of the ledger which included the transaction.
+	LedgerCloseTime int64
+}
+
+// ContractCreationReader looks the creation of contracts up.
+type ContractCreationReader interface {
+	// GetContractCreation returns the transaction which created the given contract, if it is stored.
+	GetContractCreation(ctx context.Context, contractID xdr.Hash) (ContractCreation, bool, error)
+}
+
+func NewContractCreationReader(db *DB) ContractCreationReader {
+	return contractCreationReader{db: db}
+}
+
+type contractCreationReader struct {
+	db *DB
+}
+
+func (r contractCreationReader) GetContractCreation(ctx context.Context, contractID xdr.Hash,
+) (ContractCreation, bool, error) {
+	query := sq.Select("c.transaction_hash", "c.ledger_sequence", "c.application_order", "l.close_time").
+		From(contractCreationTableName + " c").
+		Join(ledgerCloseMetaTableName + " l ON l.sequence = c.ledger_sequence").
+		Where(sq.Eq{"c.contract_id": contractID[:]})
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return ContractCreation{}, false, fmt.Errorf("could not query contract creation: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return ContractCreation{}, false, rows.Err()
+	}
+	var creation ContractCreation
+	var hash []byte
+	if err := rows.Scan(&hash, &creation.Ledger, &creation.ApplicationOrder, &creation.LedgerCloseTime); err != nil {
+		return ContractCreation{}, false, err
+	}
+	copy(creation.TransactionHash[:], hash)
+	return creation, true, nil
+}
+
+// CreatedContractIDs returns the IDs of the contracts created by the transaction with the given
+// meta, in order of appearance in the meta. Contracts are identified by the creation of their
+// instance entry, so the contracts created by other contracts (e.g. factories) are included as
+// well as the ones created by the CreateContract host functions.
+func CreatedContractIDs(meta xdr.TransactionMeta) ([]xdr.Hash, error) {
+	changes, err := (&ingest.LedgerTransaction{UnsafeMeta: meta}).GetChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []xdr.Hash
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeContractData || change.Pre != nil || change.Post == nil {
+			continue
+		}
+		data := change.Post.Data.MustContractData()
+		if data.Key.Type != xdr.ScValTypeScvLedgerKeyContractInstance || data.Contract.ContractId == nil {
+			continue
+		}
+		ids = append(ids, *data.Contract.ContractId)
+	}
+	return ids, nil
+}
+
+// insertContractCreations records the contracts created by the given transactions of a ledger.
+func insertContractCreations(runner sq.BaseRunner, ledgerSeq uint32, txs []ingest.LedgerTransaction) error {
+	// a contract is only created once, but ignore conflicts so that reingesting a ledger
+	// (e.g. after a migration) never fails
+	query := sq.Insert(contractCreationTableName).
+		Options("OR IGNORE").
+		Columns("contract_id", "transaction_hash", "ledger_sequence", "application_order")
+	var count int
+	for _, tx := range txs {
+		if !tx.Result.Successful() {
+			continue
+		}
+		ids, err := CreatedContractIDs(tx.UnsafeMeta)
+		if err != nil {
+			return fmt.Errorf("could not get the contracts created by tx %d: %w", tx.Index, err)
+		}
+		for _, id := range ids {
+			query = query.Values(id[:], tx.Result.TransactionHash[:], ledgerSeq, tx.Index)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	_, err := query.RunWith(runner).Exec()
+	return err
+}
+
+// contractCreationMigration fills in the contract creations of the ledgers stored before
+// the contract_creations table was added.
+type contractCreationMigration struct {
+	ledgerSeqRange LedgerSeqRange
+	passphrase     string
+	stmtCache      *sq.StmtCache
+}
+
+func (m *contractCreationMigration) ApplicableRange() LedgerSeqRange {
+	return m.ledgerSeqRange
+}
+
+func (m *contractCreationMigration) Apply(_ context.Context, meta xdr.LedgerCloseMeta) error {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(m.passphrase, meta)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction reader for ledger %d: %w", meta.LedgerSequence(), err)
+	}
+	txs := make([]ingest.LedgerTransaction, 0, meta.CountTransactions())
+	for i := range meta.CountTransactions() {
+		tx, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("failed reading tx %d: %w", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	return insertContractCreations(m.stmtCache, meta.LedgerSequence(), txs)
+}
+
+func newContractCreationMigration(
+	_ context.Context,
+	_ *log.Entry,
+	passphrase string,
+	ledgerSeqRange LedgerSeqRange,
+) migrationApplierFactory {
+	return migrationApplierFactoryF(func(db *DB) (MigrationApplier, error) {
+		return &contractCreationMigration{
+			ledgerSeqRange: ledgerSeqRange,
+			passphrase:     passphrase,
+			stmtCache:      sq.NewStmtCache(db.GetTx()),
+		}, nil
+	})
+}
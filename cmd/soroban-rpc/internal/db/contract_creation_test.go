@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func txMetaWithContractCreation(acctSeq uint32, successful bool, contractID xdr.Hash) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, successful)
+	instance := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract: xdr.ScAddress{
+					Type:       xdr.ScAddressTypeScAddressTypeContract,
+					ContractId: &contractID,
+				},
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+	}
+	meta.V1.TxProcessing[0].TxApplyProcessing.V3.Operations = []xdr.OperationMeta{{
+		Changes: xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &instance},
+		},
+	}}
+	return meta
+}
+
+func TestGetContractCreation(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 3, passphrase)
+	ledgers := []xdr.LedgerCloseMeta{
+		txMetaWithContractCreation(1, true, xdr.Hash{1}),
+		txMetaWithContractCreation(2, true, xdr.Hash{2}),
+		// failed transactions don't create contracts
+		txMetaWithContractCreation(3, false, xdr.Hash{3}),
+		txMeta(4, true),
+	}
+	for _, ledger := range ledgers[:3] {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	reader := NewContractCreationReader(db)
+	assertCreation := func(contractID xdr.Hash, acctSeq uint32) {
+		creation, found, err := reader.GetContractCreation(ctx, contractID)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, ContractCreation{
+			TransactionLocation: TransactionLocation{Ledger: acctSeq + 100, ApplicationOrder: 1},
+			TransactionHash:     txHash(acctSeq),
+			LedgerCloseTime:     ledgerCloseTime(acctSeq + 100),
+		}, creation)
+	}
+	assertNotFound := func(contractID xdr.Hash) {
+		_, found, err := reader.GetContractCreation(ctx, contractID)
+		require.NoError(t, err)
+		assert.False(t, found)
+	}
+	assertCreation(xdr.Hash{1}, 1)
+	assertCreation(xdr.Hash{2}, 2)
+	assertNotFound(xdr.Hash{3})
+	assertNotFound(xdr.Hash{4})
+
+	// simulate ledgers stored before the contract_creations table was added
+	_, err := db.ExecRaw(ctx, "DELETE FROM contract_creations")
+	require.NoError(t, err)
+	assertNotFound(xdr.Hash{1})
+
+	require.NoError(t, db.Begin(ctx))
+	migration, err := newContractCreationMigration(ctx, logger, passphrase, LedgerSeqRange{First: 101, Last: 103}).
+		New(db)
+	require.NoError(t, err)
+	require.NoError(t, NewLedgerReader(db).StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		return migration.Apply(ctx, ledger)
+	}))
+	require.NoError(t, db.Commit())
+	assertCreation(xdr.Hash{1}, 1)
+	assertCreation(xdr.Hash{2}, 2)
+
+	// the creations are trimmed along with the transactions
+	tx, err := rw.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerWriter().InsertLedger(ledgers[3]))
+	require.NoError(t, tx.TransactionWriter().InsertTransactions(ledgers[3]))
+	require.NoError(t, tx.Commit(ledgers[3]))
+	assertNotFound(xdr.Hash{1})
+	assertCreation(xdr.Hash{2}, 2)
+}
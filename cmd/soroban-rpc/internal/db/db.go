@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	_ "github.com/mattn/go-sqlite3"
@@ -29,11 +31,15 @@ var ErrEmptyDB = errors.New("DB is empty")
 
 const (
 	metaTableName = "metadata"
+	// maxDBSizeTrimChunk is the number of ledgers trimmed at a time when the database exceeds
+	// its maximum size.
+	maxDBSizeTrimChunk = 64
 )
 
 type ReadWriter interface {
 	NewTx(ctx context.Context) (WriteTx, error)
 	GetLatestLedgerSequence(ctx context.Context) (uint32, error)
+	IngestionCheckpointReader
 }
 
 type WriteTx interface {
@@ -56,9 +62,13 @@ type dbCache struct {
 type DB struct {
 	db.SessionInterface
 	cache *dbCache
+	// codec encodes the stored ledgers
+	codec LedgerCloseMetaCodec
+	// txHashFilter, if set, filters the lookups of transactions by hash
+	txHashFilter *TransactionHashFilter
 }
 
-func openSQLiteDB(dbFilePath string) (*db.Session, error) {
+func openSQLiteDB(dbFilePath string, ledgerCodec string) (*db.Session, LedgerCloseMetaCodec, error) {
 	// 1. Use Write-Ahead Logging (WAL).
 	// 2. Disable WAL auto-checkpointing (we will do the checkpointing ourselves with wal_checkpoint pragmas
 	//    after every write transaction).
@@ -66,34 +76,71 @@ func openSQLiteDB(dbFilePath string) (*db.Session, error) {
 	session, err := db.Open("sqlite3",
 		fmt.Sprintf("file:%s?_journal_mode=WAL&_wal_autocheckpoint=0&_synchronous=NORMAL", dbFilePath))
 	if err != nil {
-		return nil, fmt.Errorf("open failed: %w", err)
+		return nil, nil, fmt.Errorf("open failed: %w", err)
 	}
 
 	if err = runSQLMigrations(session.DB.DB, "sqlite3"); err != nil {
 		_ = session.Close()
-		return nil, fmt.Errorf("could not run SQL migrations: %w", err)
+		return nil, nil, fmt.Errorf("could not run SQL migrations: %w", err)
 	}
-	return session, nil
+	codec, err := setupLedgerCodec(context.Background(), session, ledgerCodec)
+	if err != nil {
+		_ = session.Close()
+		return nil, nil, err
+	}
+	return session, codec, nil
 }
 
-func OpenSQLiteDBWithPrometheusMetrics(dbFilePath string, namespace string, sub db.Subservice,
-	registry *prometheus.Registry,
+// OpenSQLiteDBWithPrometheusMetrics opens the database, storing its ledgers with the given
+// codec (see NewLedgerCloseMetaCodec) and holding up to maxOpenConnections connections (0
+// means unlimited). The requests for a connection beyond the limit wait for one to be
+// released, which is reported by the wait metrics of the connection pool.
+func OpenSQLiteDBWithPrometheusMetrics(dbFilePath string, ledgerCodec string, maxOpenConnections int,
+	namespace string, sub db.Subservice, registry *prometheus.Registry,
 ) (*DB, error) {
-	session, err := openSQLiteDB(dbFilePath)
+	session, codec, err := openSQLiteDB(dbFilePath, ledgerCodec)
 	if err != nil {
 		return nil, err
 	}
+	if maxOpenConnections > 0 {
+		session.DB.SetMaxOpenConns(maxOpenConnections)
+		// keep the connections around, rather than reopening them on every burst of requests
+		session.DB.SetMaxIdleConns(maxOpenConnections)
+	}
+	registry.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "db",
+			Name:        "connection_pool_utilization",
+			Help:        "ratio of the maximum open connections in use (0 when the connections are unlimited)",
+			ConstLabels: prometheus.Labels{"subservice": string(sub)},
+		},
+		func() float64 {
+			stats := session.DB.Stats()
+			if stats.MaxOpenConnections == 0 {
+				return 0
+			}
+			return float64(stats.InUse) / float64(stats.MaxOpenConnections)
+		},
+	))
 	result := DB{
 		SessionInterface: db.RegisterMetrics(session, namespace, sub, registry),
 		cache: &dbCache{
 			ledgerEntries: newTransactionalCache(),
 		},
+		codec: codec,
 	}
 	return &result, nil
 }
 
 func OpenSQLiteDB(dbFilePath string) (*DB, error) {
-	session, err := openSQLiteDB(dbFilePath)
+	return OpenSQLiteDBWithLedgerCodec(dbFilePath, LedgerCodecXDR)
+}
+
+// OpenSQLiteDBWithLedgerCodec is like OpenSQLiteDB, but the ledgers are stored with the
+// given codec (see NewLedgerCloseMetaCodec).
+func OpenSQLiteDBWithLedgerCodec(dbFilePath string, ledgerCodec string) (*DB, error) {
+	session, codec, err := openSQLiteDB(dbFilePath, ledgerCodec)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +149,7 @@ func OpenSQLiteDB(dbFilePath string) (*DB, error) {
 		cache: &dbCache{
 			ledgerEntries: newTransactionalCache(),
 		},
+		codec: codec,
 	}
 	return &result, nil
 }
@@ -169,6 +217,8 @@ func getLatestLedgerSequence(ctx context.Context, ledgerReader LedgerReader, cac
 
 type ReadWriterMetrics struct {
 	TxIngestDuration, TxCount prometheus.Observer
+	ReorgsDetected            prometheus.Counter
+	ProtocolVersionRejections prometheus.Counter
 }
 
 type readWriter struct {
@@ -176,15 +226,84 @@ type readWriter struct {
 	db                    *DB
 	maxBatchSize          int
 	ledgerRetentionWindow uint32
-	passphrase            string
+	ledgerRetentionPeriod time.Duration
+	maxDBSize             uint64
+	// minDBSizeRetentionWindow is the number of ledgers retained regardless of maxDBSize.
+	minDBSizeRetentionWindow uint32
+	maxDBSizeStatus          *MaxDBSizeStatus
+	minProtocolVersion       uint32
+	passphrase               string
 
 	metrics ReadWriterMetrics
 }
 
+// ReadWriterOption configures the read-writers returned by NewReadWriter.
+type ReadWriterOption func(rw *readWriter)
+
+// NewReadWriterWithRetentionPeriod is like NewReadWriter, but the database only retains
+// the ledgers which closed within the retention period before the latest ledger,
+// regardless of their number.
+func NewReadWriterWithRetentionPeriod(
+	log *log.Entry,
+	db *DB,
+	daemon interfaces.Daemon,
+	maxBatchSize int,
+	ledgerRetentionPeriod time.Duration,
+	networkPassphrase string,
+	options ...ReadWriterOption,
+) ReadWriter {
+	withRetentionPeriod := func(rw *readWriter) {
+		rw.ledgerRetentionPeriod = ledgerRetentionPeriod
+	}
+	return NewReadWriter(log, db, daemon, maxBatchSize, 0, networkPassphrase,
+		append([]ReadWriterOption{withRetentionPeriod}, options...)...)
+}
+
+// MaxDBSizeStatus tells whether the database exceeds its maximum size (see WithMaxDBSize)
+// with no ledgers left to trim, as of the latest committed ledger.
+type MaxDBSizeStatus struct {
+	maxDBSize uint64
+	// exceededSize is the used size of the database while it exceeds maxDBSize, 0 otherwise.
+	exceededSize atomic.Uint64
+}
+
+// NewMaxDBSizeStatus returns the status of a database capped to maxDBSize bytes, to be passed
+// to WithMaxDBSize.
+func NewMaxDBSizeStatus(maxDBSize uint64) *MaxDBSizeStatus {
+	return &MaxDBSizeStatus{maxDBSize: maxDBSize}
+}
+
+// Exceeded returns the used size of the database and its maximum size, if it exceeds it with
+// the minimum retention window retained.
+func (s *MaxDBSizeStatus) Exceeded() (uint64, uint64, bool) {
+	size := s.exceededSize.Load()
+	return size, s.maxDBSize, size > 0
+}
+
+// WithMaxDBSize makes the read-writer, in addition to its retention window or period, trim
+// the oldest ledgers (along with their transactions and events) whenever the data stored in
+// the database exceeds the maximum size of status. At least minRetentionWindow ledgers are
+// retained: status reports the database exceeding its maximum size with only them left.
+func WithMaxDBSize(status *MaxDBSizeStatus, minRetentionWindow uint32) ReadWriterOption {
+	return func(rw *readWriter) {
+		rw.maxDBSize = status.maxDBSize
+		rw.minDBSizeRetentionWindow = max(minRetentionWindow, 1)
+		rw.maxDBSizeStatus = status
+	}
+}
+
+// WithMinProtocolVersion makes the read-writer reject the ledgers of a protocol version below
+// minProtocolVersion, e.g. when the node is pointed at the wrong network.
+func WithMinProtocolVersion(minProtocolVersion uint32) ReadWriterOption {
+	return func(rw *readWriter) {
+		rw.minProtocolVersion = minProtocolVersion
+	}
+}
+
 // NewReadWriter constructs a new readWriter instance and configures the size of
 // ledger entry batches when writing ledger entries and the retention window for
 // how many historical ledgers are recorded in the database, hooking up metrics
-// for various DB ops.
+// for various DB ops. The options (e.g. WithMaxDBSize) are applied in order.
 func NewReadWriter(
 	log *log.Entry,
 	db *DB,
@@ -192,6 +311,7 @@ func NewReadWriter(
 	maxBatchSize int,
 	ledgerRetentionWindow uint32,
 	networkPassphrase string,
+	options ...ReadWriterOption,
 ) ReadWriter {
 	// a metric for measuring latency of transaction store operations
 	txDurationMetric := prometheus.NewSummaryVec(prometheus.SummaryOpts{
@@ -209,19 +329,38 @@ func NewReadWriter(
 		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}, //nolint:mnd
 	})
 
-	daemon.MetricsRegistry().MustRegister(txDurationMetric, txCountMetric)
+	reorgsDetectedMetric := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: daemon.MetricsNamespace(), Subsystem: "ledgers",
+		Name: "reorgs_detected",
+		Help: "number of stored ledgers replaced by a ledger with the same sequence and different content",
+	})
 
-	return &readWriter{
+	protocolVersionRejectionsMetric := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: daemon.MetricsNamespace(), Subsystem: "ledgers",
+		Name: "protocol_version_rejections_total",
+		Help: "number of ledgers rejected for having a protocol version below the minimum protocol version",
+	})
+
+	daemon.MetricsRegistry().MustRegister(txDurationMetric, txCountMetric, reorgsDetectedMetric,
+		protocolVersionRejectionsMetric)
+
+	rw := &readWriter{
 		log:                   log,
 		db:                    db,
 		maxBatchSize:          maxBatchSize,
 		ledgerRetentionWindow: ledgerRetentionWindow,
 		passphrase:            networkPassphrase,
 		metrics: ReadWriterMetrics{
-			TxIngestDuration: txDurationMetric.With(prometheus.Labels{"operation": "ingest"}),
-			TxCount:          txCountMetric,
+			TxIngestDuration:          txDurationMetric.With(prometheus.Labels{"operation": "ingest"}),
+			TxCount:                   txCountMetric,
+			ReorgsDetected:            reorgsDetectedMetric,
+			ProtocolVersionRejections: protocolVersionRejectionsMetric,
 		},
 	}
+	for _, option := range options {
+		option(rw)
+	}
+	return rw
 }
 
 func (rw *readWriter) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
@@ -240,13 +379,27 @@ func (rw *readWriter) NewTx(ctx context.Context) (WriteTx, error) {
 		globalCache: db.cache,
 		postCommit: func() error {
 			// TODO: this is sqlite-only, it shouldn't be here
-			_, err := db.ExecRaw(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+			// A passive checkpoint doesn't wait for the readers (e.g. the read snapshots of the
+			// requests), it only checkpoints the frames they don't need. The remaining frames are
+			// checkpointed after the following commits.
+			_, err := db.ExecRaw(ctx, "PRAGMA wal_checkpoint(PASSIVE)")
 			return err
 		},
-		tx:                    txSession,
-		stmtCache:             stmtCache,
-		ledgerRetentionWindow: rw.ledgerRetentionWindow,
-		ledgerWriter:          ledgerWriter{stmtCache: stmtCache},
+		tx:                       txSession,
+		stmtCache:                stmtCache,
+		ledgerRetentionWindow:    rw.ledgerRetentionWindow,
+		ledgerRetentionPeriod:    rw.ledgerRetentionPeriod,
+		maxDBSize:                rw.maxDBSize,
+		minDBSizeRetentionWindow: rw.minDBSizeRetentionWindow,
+		maxDBSizeStatus:          rw.maxDBSizeStatus,
+		ledgerWriter: ledgerWriter{
+			log:                       rw.log,
+			codec:                     db.codec,
+			stmtCache:                 stmtCache,
+			reorgsDetected:            rw.metrics.ReorgsDetected,
+			minProtocolVersion:        rw.minProtocolVersion,
+			protocolVersionRejections: rw.metrics.ProtocolVersionRejections,
+		},
 		ledgerEntryWriter: ledgerEntryWriter{
 			stmtCache:               stmtCache,
 			buffer:                  xdr.NewEncodingBuffer(),
@@ -257,9 +410,11 @@ func (rw *readWriter) NewTx(ctx context.Context) (WriteTx, error) {
 		txWriter: transactionHandler{
 			log:        rw.log,
 			db:         txSession,
+			codec:      db.codec,
 			stmtCache:  stmtCache,
 			passphrase: rw.passphrase,
 		},
+		txHashFilter: db.txHashFilter,
 		eventWriter: eventHandler{
 			log:        rw.log,
 			db:         txSession,
@@ -267,6 +422,9 @@ func (rw *readWriter) NewTx(ctx context.Context) (WriteTx, error) {
 			passphrase: rw.passphrase,
 		},
 	}
+	if db.txHashFilter != nil {
+		writer.txWriter.hashFilterUpdate = &hashFilterUpdate{}
+	}
 	writer.txWriter.RegisterMetrics(
 		rw.metrics.TxIngestDuration,
 		rw.metrics.TxCount)
@@ -283,7 +441,14 @@ type writeTx struct {
 	ledgerWriter          ledgerWriter
 	txWriter              transactionHandler
 	eventWriter           eventHandler
+	txHashFilter          *TransactionHashFilter
 	ledgerRetentionWindow uint32
+	// ledgerRetentionPeriod, if set, replaces ledgerRetentionWindow with time-based retention.
+	ledgerRetentionPeriod time.Duration
+	// maxDBSize, if set, caps the size of the data stored in the database (see WithMaxDBSize).
+	maxDBSize                uint64
+	minDBSizeRetentionWindow uint32
+	maxDBSizeStatus          *MaxDBSizeStatus
 }
 
 func (w writeTx) LedgerEntryWriter() LedgerEntryWriter {
@@ -302,6 +467,77 @@ func (w writeTx) EventWriter() EventWriter {
 	return &w.eventWriter
 }
 
+// trimLedgersByTime removes the ledgers which closed before the retention period,
+// returning the number of ledgers retained so that the rest of the data can be
+// trimmed accordingly.
+func (w writeTx) trimLedgersByTime(ledgerSeq uint32, ledgerCloseTime int64) (uint32, error) {
+	cutoff := ledgerCloseTime - int64(w.ledgerRetentionPeriod/time.Second)
+	if err := w.ledgerWriter.trimLedgersByTime(cutoff); err != nil {
+		return 0, err
+	}
+	first, err := w.ledgerWriter.firstLedgerSequence()
+	if errors.Is(err, ErrEmptyDB) || (err == nil && first > ledgerSeq) {
+		// no ledgers are retained
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return ledgerSeq + 1 - first, nil
+}
+
+// usedDBSize returns the size (in bytes) of the pages of the database in use, as seen by the
+// transaction. The pages freed by trimming are reused before the database file grows again.
+func (w writeTx) usedDBSize() (uint64, error) {
+	pragmas := []string{"page_count", "freelist_count", "page_size"}
+	values := make([]uint64, len(pragmas))
+	for i, pragma := range pragmas {
+		if err := w.stmtCache.QueryRow("PRAGMA " + pragma).Scan(&values[i]); err != nil {
+			return 0, fmt.Errorf("could not read %s: %w", pragma, err)
+		}
+	}
+	return (values[0] - values[1]) * values[2], nil
+}
+
+// trimToMaxDBSize trims the oldest ledgers, their transactions and their events in chunks of
+// maxDBSizeTrimChunk ledgers until the used size of the database is within maxDBSize. The
+// minimum retention window is always retained, since the ledger entries (which are never
+// trimmed) may exceed maxDBSize on their own: the database exceeding its maximum size is then
+// logged and reported through the max DB size status instead.
+func (w writeTx) trimToMaxDBSize(ledgerSeq uint32) error {
+	for {
+		size, err := w.usedDBSize()
+		if err != nil {
+			return err
+		}
+		if size <= w.maxDBSize {
+			w.maxDBSizeStatus.exceededSize.Store(0)
+			return nil
+		}
+		first, err := w.ledgerWriter.firstLedgerSequence()
+		if err != nil && !errors.Is(err, ErrEmptyDB) {
+			return err
+		}
+		if errors.Is(err, ErrEmptyDB) || first > ledgerSeq || ledgerSeq+1-first <= w.minDBSizeRetentionWindow {
+			w.ledgerWriter.log.WithField("size", size).WithField("max_size", w.maxDBSize).
+				WithField("min_retention_window", w.minDBSizeRetentionWindow).
+				Error("the database exceeds its maximum size with its minimum retention window retained")
+			w.maxDBSizeStatus.exceededSize.Store(size)
+			return nil
+		}
+		retentionWindow := ledgerSeq + 1 - first
+		retentionWindow -= min(retentionWindow-w.minDBSizeRetentionWindow, maxDBSizeTrimChunk)
+		if err := w.ledgerWriter.trimLedgers(ledgerSeq, retentionWindow); err != nil {
+			return err
+		}
+		if err := w.txWriter.trimTransactions(ledgerSeq, retentionWindow); err != nil {
+			return err
+		}
+		if err := w.eventWriter.trimEvents(ledgerSeq, retentionWindow); err != nil {
+			return err
+		}
+	}
+}
+
 func (w writeTx) Commit(ledgerCloseMeta xdr.LedgerCloseMeta) error {
 	ledgerSeq := ledgerCloseMeta.LedgerSequence()
 	ledgerCloseTime := ledgerCloseMeta.LedgerCloseTime()
@@ -310,14 +546,32 @@ func (w writeTx) Commit(ledgerCloseMeta xdr.LedgerCloseMeta) error {
 		return err
 	}
 
-	if err := w.ledgerWriter.trimLedgers(ledgerSeq, w.ledgerRetentionWindow); err != nil {
+	retentionWindow := w.ledgerRetentionWindow
+	if w.ledgerRetentionPeriod > 0 {
+		var err error
+		if retentionWindow, err = w.trimLedgersByTime(ledgerSeq, ledgerCloseTime); err != nil {
+			return err
+		}
+	} else if err := w.ledgerWriter.trimLedgers(ledgerSeq, retentionWindow); err != nil {
 		return err
 	}
-	if err := w.txWriter.trimTransactions(ledgerSeq, w.ledgerRetentionWindow); err != nil {
+	if err := w.txWriter.trimTransactions(ledgerSeq, retentionWindow); err != nil {
 		return err
 	}
 
-	if err := w.eventWriter.trimEvents(ledgerSeq, w.ledgerRetentionWindow); err != nil {
+	if err := w.eventWriter.trimEvents(ledgerSeq, retentionWindow); err != nil {
+		return err
+	}
+
+	if w.maxDBSize > 0 {
+		if err := w.trimToMaxDBSize(ledgerSeq); err != nil {
+			return err
+		}
+	}
+
+	// Record the ledger as ingested within the same transaction, so that the
+	// checkpoint only moves forward once all of its data is committed.
+	if err := setIngestionCheckpoint(w.stmtCache, ledgerSeq); err != nil {
 		return err
 	}
 
@@ -333,6 +587,9 @@ func (w writeTx) Commit(ledgerCloseMeta xdr.LedgerCloseMeta) error {
 		w.globalCache.latestLedgerSeq = ledgerSeq
 		w.globalCache.latestLedgerCloseTime = ledgerCloseTime
 		w.ledgerEntryWriter.ledgerEntryCacheWriteTx.commit()
+		if w.txHashFilter != nil {
+			w.txWriter.hashFilterUpdate.apply(w.txHashFilter)
+		}
 		return nil
 	}
 	if err := commitAndUpdateCache(); err != nil {
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryInterruptedOnCancellation(t *testing.T) {
+	db := NewTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	// counting a billion rows takes minutes, cancelling the context must interrupt it
+	start := time.Now()
+	var count int64
+	err := db.GetRaw(ctx, &count,
+		"WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c LIMIT 1000000000) SELECT count(*) FROM c")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// the connection remains usable
+	require.NoError(t, db.GetRaw(context.Background(), &count, "SELECT 1"))
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMaxOpenConnections(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	db, err := OpenSQLiteDBWithPrometheusMetrics(path.Join(t.TempDir(), "db.sqlite"), LedgerCodecXDR, 2,
+		"soroban_rpc", "db", registry)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+	metric := func(name string) float64 {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == name {
+				metric := family.GetMetric()[0]
+				if metric.GetGauge() != nil {
+					return metric.GetGauge().GetValue()
+				}
+				return metric.GetCounter().GetValue()
+			}
+		}
+		t.Fatalf("metric %s not found", name)
+		return 0
+	}
+
+	// the read snapshots hold both connections
+	_, release1, err := db.WithReadSnapshot(ctx)
+	require.NoError(t, err)
+	_, release2, err := db.WithReadSnapshot(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, metric("soroban_rpc_db_connection_pool_utilization"), 0)
+
+	// other reads queue for a connection until one is released
+	done := make(chan error)
+	go func() {
+		var count int
+		done <- db.GetRaw(ctx, &count, "SELECT 1")
+	}()
+	select {
+	case <-done:
+		t.Fatal("the read didn't wait for a connection")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release1()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the read wasn't given the released connection")
+	}
+	release2()
+
+	assert.InDelta(t, 1, metric("soroban_rpc_db_wait_count_total"), 0)
+	assert.Greater(t, metric("soroban_rpc_db_wait_duration_seconds_total"), 0.04)
+	assert.InDelta(t, 0, metric("soroban_rpc_db_connection_pool_utilization"), 0)
+	assert.InDelta(t, 2, metric("soroban_rpc_db_max_open_connections"), 0)
+}
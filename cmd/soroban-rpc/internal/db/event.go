@@ -14,6 +14,8 @@ import (
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrfailures"
 )
 
 const (
@@ -59,7 +61,18 @@ func (eventHandler *eventHandler) InsertEvents(lcm xdr.LedgerCloseMeta) error {
 
 	if eventHandler.stmtCache == nil {
 		return errors.New("EventWriter incorrectly initialized without stmtCache")
-	} else if txCount == 0 {
+	}
+	// the ledger may be re-ingested or replaced (see ledgerWriter.InsertLedger)
+	_, err := sq.StatementBuilder.
+		RunWith(eventHandler.stmtCache).
+		Delete(eventTableName).
+		Where(sq.GtOrEq{"id": Cursor{Ledger: lcm.LedgerSequence()}.String()}).
+		Where(sq.Lt{"id": Cursor{Ledger: lcm.LedgerSequence() + 1}.String()}).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if txCount == 0 {
 		return nil
 	}
 
@@ -181,22 +194,8 @@ func (eventHandler *eventHandler) trimEvents(latestLedgerSeq uint32, retentionWi
 	return err
 }
 
-// GetEvents applies f on all the events occurring in the given range with specified contract IDs if provided.
-// The events are returned in sorted ascending Cursor order.
-// If f returns false, the scan terminates early (f will not be applied on
-// remaining events in the range).
-//
-//nolint:funlen,cyclop
-func (eventHandler *eventHandler) GetEvents(
-	ctx context.Context,
-	cursorRange CursorRange,
-	contractIDs [][]byte,
-	topics NestedTopicArray,
-	eventTypes []int,
-	f ScanFunction,
-) error {
-	start := time.Now()
-
+func eventsQuery(cursorRange CursorRange, contractIDs [][]byte, topics NestedTopicArray, eventTypes []int,
+) sq.SelectBuilder {
 	rowQ := sq.
 		Select(" id", "event_data", "transaction_hash", "ledger_close_time").
 		From(eventTableName).
@@ -223,6 +222,26 @@ func (eventHandler *eventHandler) GetEvents(
 			rowQ = rowQ.Where(orConditions)
 		}
 	}
+	return rowQ
+}
+
+// GetEvents applies f on all the events occurring in the given range with specified contract IDs if provided.
+// The events are returned in sorted ascending Cursor order.
+// If f returns false, the scan terminates early (f will not be applied on
+// remaining events in the range).
+//
+//nolint:funlen,cyclop
+func (eventHandler *eventHandler) GetEvents(
+	ctx context.Context,
+	cursorRange CursorRange,
+	contractIDs [][]byte,
+	topics NestedTopicArray,
+	eventTypes []int,
+	f ScanFunction,
+) error {
+	start := time.Now()
+
+	rowQ := eventsQuery(cursorRange, contractIDs, topics, eventTypes)
 
 	encodedContractIDs := make([]string, 0, len(contractIDs))
 	for _, contractID := range contractIDs {
@@ -276,6 +295,7 @@ func (eventHandler *eventHandler) GetEvents(
 		var eventXDR xdr.DiagnosticEvent
 		err = xdr.SafeUnmarshal(eventData, &eventXDR)
 		if err != nil {
+			xdrfailures.Record(xdrfailures.EventDecode, "DiagnosticEvent")
 			return errors.Join(err, errors.New("failed to decode event"))
 		}
 		txHash := xdr.Hash(transactionHash)
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/support/db"
+)
+
+// expectedIndexes maps the name of every index created by the SQL migrations
+// to the table it belongs to.
+var expectedIndexes = map[string]string{
+	"index_ledger_sequence":                           transactionTableName,
+	"idx_contract_id":                                 eventTableName,
+	"idx_topic1":                                      eventTableName,
+	"idx_transactions_source_account":                 transactionTableName,
+	"idx_transaction_operation_types_ledger_sequence": transactionOperationTypeTableName,
+	"idx_ledger_close_meta_close_time":                ledgerCloseMetaTableName,
+	"idx_transactions_memo":                           transactionTableName,
+	"idx_contract_creations_ledger_sequence":          contractCreationTableName,
+	"idx_ledger_key_transactions_ledger_sequence":     ledgerKeyTransactionTableName,
+	"idx_transactions_result_hash":                    transactionTableName,
+}
+
+// IndexInfo describes an index present in the database.
+type IndexInfo struct {
+	Name  string `db:"name" json:"name"`
+	Table string `db:"tbl_name" json:"table"`
+}
+
+// GetIndexes returns the (explicitly created) indexes present in the database.
+func GetIndexes(ctx context.Context, q db.SessionInterface) ([]IndexInfo, error) {
+	query := sq.Select("name", "tbl_name").
+		From("sqlite_master").
+		Where(sq.Eq{"type": "index"}).
+		Where(sq.NotEq{"sql": nil}).
+		OrderBy("name")
+	var indexes []IndexInfo
+	if err := q.Select(ctx, &indexes, query); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// MissingIndexes returns the names of the indexes expected by the query
+// patterns of the RPC server which are not present in the database (e.g. after
+// restoring it manually).
+func MissingIndexes(ctx context.Context, q db.SessionInterface) ([]string, error) {
+	indexes, err := GetIndexes(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]struct{}, len(indexes))
+	for _, index := range indexes {
+		present[index.Name] = struct{}{}
+	}
+	var missing []string
+	for name := range expectedIndexes {
+		if _, ok := present[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+func explainQueryPlan(t *testing.T, db *DB, query sq.Sqlizer) string {
+	sql, args, err := query.ToSql()
+	require.NoError(t, err)
+	var plan []struct {
+		ID      int    `db:"id"`
+		Parent  int    `db:"parent"`
+		NotUsed int    `db:"notused"`
+		Detail  string `db:"detail"`
+	}
+	require.NoError(t, db.SelectRaw(context.Background(), &plan, "EXPLAIN QUERY PLAN "+sql, args...))
+	details := make([]string, 0, len(plan))
+	for _, row := range plan {
+		details = append(details, row.Detail)
+	}
+	return strings.Join(details, "\n")
+}
+
+// TestQueriesUseIndexes checks the query plans of the queries built by the readers and writers.
+func TestQueriesUseIndexes(t *testing.T) {
+	db := NewTestDB(t)
+	cursorRange := CursorRange{Start: Cursor{Ledger: 1}, End: Cursor{Ledger: 10}}
+	account := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	for _, testCase := range []struct {
+		query sq.Sqlizer
+		index string
+	}{
+		{countTransactionsQuery(1, 10), "index_ledger_sequence"},
+		{eventsQuery(cursorRange, [][]byte{{0}}, nil, nil), "idx_contract_id"},
+		{eventsQuery(cursorRange, nil, NestedTopicArray{{{0}}}, nil), "idx_topic1"},
+		{trimLedgersByTimeQuery(100), "idx_ledger_close_meta_close_time"},
+		{transactionsByMemoQuery(xdr.MemoTypeMemoText, []byte("memo"), 1, 1, 10, 10), "idx_transactions_memo"},
+		{transactionWhereQuery(sq.Eq{"t.result_hash": []byte{0}}), "idx_transactions_result_hash"},
+		{transactionsBySourceAccountQuery(account, 1, 1, 10, 10), "idx_transactions_source_account"},
+		{transactionsByOperationTypeQuery(xdr.OperationTypeInvokeHostFunction, 1, 1, 10, 10), "PRIMARY KEY"},
+		{trimTransactionTableQuery(transactionTableName, 10), "index_ledger_sequence"},
+		{trimTransactionTableQuery(contractCreationTableName, 10), "idx_contract_creations_ledger_sequence"},
+		{trimTransactionTableQuery(ledgerKeyTransactionTableName, 10), "idx_ledger_key_transactions_ledger_sequence"},
+		{
+			trimTransactionTableQuery(transactionOperationTypeTableName, 10),
+			"idx_transaction_operation_types_ledger_sequence",
+		},
+	} {
+		sql, _, err := testCase.query.ToSql()
+		require.NoError(t, err)
+		require.Contains(t, explainQueryPlan(t, db, testCase.query), testCase.index, sql)
+	}
+}
+
+func TestMissingIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestDB(t)
+
+	missing, err := MissingIndexes(ctx, db)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	indexes, err := GetIndexes(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, indexes, len(expectedIndexes))
+
+	_, err = db.ExecRaw(ctx, "DROP INDEX idx_topic1")
+	require.NoError(t, err)
+	missing, err = MissingIndexes(ctx, db)
+	require.NoError(t, err)
+	require.Equal(t, []string{"idx_topic1"}, missing)
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/support/db"
+)
+
+// ingestionCheckpointMetaKey is the metadata key holding the sequence of the last
+// ledger whose ingestion was committed.
+const ingestionCheckpointMetaKey = "IngestionCheckpoint"
+
+type IngestionCheckpointReader interface {
+	// GetIngestionCheckpoint returns the sequence of the last ledger whose ingestion was
+	// committed, or ErrEmptyDB if no ledger was committed since the checkpoint was introduced.
+	GetIngestionCheckpoint(ctx context.Context) (uint32, error)
+}
+
+func getIngestionCheckpoint(ctx context.Context, q db.SessionInterface) (uint32, error) {
+	value, err := getMetaValue(ctx, q, ingestionCheckpointMetaKey)
+	if err != nil {
+		return 0, err
+	}
+	sequence, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(sequence), nil
+}
+
+func setIngestionCheckpoint(stmtCache *sq.StmtCache, sequence uint32) error {
+	_, err := sq.Replace(metaTableName).
+		Values(ingestionCheckpointMetaKey, strconv.FormatUint(uint64(sequence), 10)).
+		RunWith(stmtCache).
+		Exec()
+	return err
+}
+
+func (d *DB) GetIngestionCheckpoint(ctx context.Context) (uint32, error) {
+	return getIngestionCheckpoint(ctx, d)
+}
+
+func (rw *readWriter) GetIngestionCheckpoint(ctx context.Context) (uint32, error) {
+	return getIngestionCheckpoint(ctx, rw.db)
+}
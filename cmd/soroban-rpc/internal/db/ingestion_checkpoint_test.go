@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestIngestionCheckpoint(t *testing.T) {
+	dbPath := path.Join(t.TempDir(), "db.sqlite")
+	db, err := OpenSQLiteDB(dbPath)
+	require.NoError(t, err)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+
+	_, err = rw.GetIngestionCheckpoint(ctx)
+	require.ErrorIs(t, err, ErrEmptyDB)
+
+	for i := uint32(1); i <= 3; i++ {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		ledger := createLedger(i)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+	checkpoint, err := rw.GetIngestionCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), checkpoint)
+
+	// the checkpoint doesn't move if the ledger isn't committed
+	tx, err := rw.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerWriter().InsertLedger(createLedger(4)))
+	require.NoError(t, tx.Rollback())
+	checkpoint, err = rw.GetIngestionCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), checkpoint)
+
+	// the checkpoint survives reopening the database
+	require.NoError(t, db.Close())
+	db, err = OpenSQLiteDB(dbPath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+	checkpoint, err = db.GetIngestionCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), checkpoint)
+	assertLedgerRange(t, NewLedgerReader(db), 1, 3)
+}
@@ -1,11 +1,17 @@
 package db
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
@@ -15,6 +21,10 @@ const (
 	ledgerCloseMetaTableName = "ledger_close_meta"
 )
 
+// ErrProtocolVersionTooLow is returned when inserting a ledger of a protocol version below
+// the minimum protocol version (see WithMinProtocolVersion).
+var ErrProtocolVersionTooLow = errors.New("ledger protocol version below the minimum protocol version")
+
 type StreamLedgerFn func(xdr.LedgerCloseMeta) error
 
 type LedgerReader interface {
@@ -30,25 +40,52 @@ type LedgerWriter interface {
 
 type ledgerReader struct {
 	db *DB
+	// prefetchChunkSize, if not zero, is the number of ledgers of the chunks read ahead of the
+	// callback when streaming ledgers (see streamLedgersPrefetched)
+	prefetchChunkSize uint
 }
 
 func NewLedgerReader(db *DB) LedgerReader {
 	return ledgerReader{db: db}
 }
 
-// StreamAllLedgers runs f over all the ledgers in the database (until f errors or signals it's done).
-func (r ledgerReader) StreamAllLedgers(ctx context.Context, f StreamLedgerFn) error {
-	sql := sq.Select("meta").From(ledgerCloseMetaTableName).OrderBy("sequence asc")
-	q, err := r.db.Query(ctx, sql)
+// NewLedgerReaderWithPrefetch is like NewLedgerReader, but the streamed ledgers are read and
+// decoded in chunks of chunkSize ledgers, concurrently with the callback. A zero chunkSize
+// disables the prefetching.
+func NewLedgerReaderWithPrefetch(db *DB, chunkSize uint) LedgerReader {
+	return ledgerReader{db: db, prefetchChunkSize: chunkSize}
+}
+
+// decodeLedgers decodes the stored (encoded) ledgers.
+func (r ledgerReader) decodeLedgers(encoded [][]byte) ([]xdr.LedgerCloseMeta, error) {
+	ledgers := make([]xdr.LedgerCloseMeta, len(encoded))
+	for i, data := range encoded {
+		if err := r.db.codec.Decode(data, &ledgers[i]); err != nil {
+			return nil, fmt.Errorf("could not decode stored ledger: %w", err)
+		}
+	}
+	return ledgers, nil
+}
+
+// streamLedgers runs f over the ledgers returned by the query.
+func (r ledgerReader) streamLedgers(ctx context.Context, query sq.SelectBuilder, f StreamLedgerFn) error {
+	if r.prefetchChunkSize > 0 {
+		return r.streamLedgersPrefetched(ctx, query, f)
+	}
+	q, err := r.db.Query(ctx, query)
 	if err != nil {
 		return err
 	}
 	defer q.Close()
 	for q.Next() {
-		var closeMeta xdr.LedgerCloseMeta
-		if err = q.Scan(&closeMeta); err != nil {
+		var encoded []byte
+		if err = q.Scan(&encoded); err != nil {
 			return err
 		}
+		var closeMeta xdr.LedgerCloseMeta
+		if err = r.db.codec.Decode(encoded, &closeMeta); err != nil {
+			return fmt.Errorf("could not decode stored ledger: %w", err)
+		}
 		if err = f(closeMeta); err != nil {
 			return err
 		}
@@ -56,6 +93,84 @@ func (r ledgerReader) StreamAllLedgers(ctx context.Context, f StreamLedgerFn) er
 	return q.Err()
 }
 
+// streamLedgersPrefetched is like streamLedgers, but the ledgers are read and decoded in chunks
+// by another goroutine, so that the next chunk is prepared while f processes the current one.
+// The handoff is unbuffered, which bounds the memory to two chunks.
+func (r ledgerReader) streamLedgersPrefetched(ctx context.Context, query sq.SelectBuilder, f StreamLedgerFn) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chunks := make(chan []xdr.LedgerCloseMeta)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(chunks)
+		readErr <- r.readLedgerChunks(ctx, query, chunks)
+	}()
+
+	for chunk := range chunks {
+		for _, ledger := range chunk {
+			if err := f(ledger); err != nil {
+				// stop the reader and wait for it to release the query
+				cancel()
+				for range chunks {
+				}
+				return err
+			}
+		}
+	}
+	return <-readErr
+}
+
+// readLedgerChunks sends the ledgers returned by the query to chunks, in chunks of
+// prefetchChunkSize ledgers, until ctx is done.
+func (r ledgerReader) readLedgerChunks(ctx context.Context, query sq.SelectBuilder,
+	chunks chan<- []xdr.LedgerCloseMeta,
+) error {
+	q, err := r.db.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer q.Close()
+	send := func(chunk []xdr.LedgerCloseMeta) error {
+		select {
+		case chunks <- chunk:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	chunk := make([]xdr.LedgerCloseMeta, 0, r.prefetchChunkSize)
+	for q.Next() {
+		var encoded []byte
+		if err = q.Scan(&encoded); err != nil {
+			return err
+		}
+		var closeMeta xdr.LedgerCloseMeta
+		if err = r.db.codec.Decode(encoded, &closeMeta); err != nil {
+			return fmt.Errorf("could not decode stored ledger: %w", err)
+		}
+		chunk = append(chunk, closeMeta)
+		if uint(len(chunk)) == r.prefetchChunkSize {
+			if err = send(chunk); err != nil {
+				return err
+			}
+			chunk = make([]xdr.LedgerCloseMeta, 0, r.prefetchChunkSize)
+		}
+	}
+	if err = q.Err(); err != nil {
+		return err
+	}
+	if len(chunk) > 0 {
+		return send(chunk)
+	}
+	return nil
+}
+
+// StreamAllLedgers runs f over all the ledgers in the database (until f errors or signals it's done).
+func (r ledgerReader) StreamAllLedgers(ctx context.Context, f StreamLedgerFn) error {
+	sql := sq.Select("meta").From(ledgerCloseMetaTableName).OrderBy("sequence asc")
+	return r.streamLedgers(ctx, sql, f)
+}
+
 // StreamLedgerRange runs f over inclusive (startLedger, endLedger) (until f errors or signals it's done).
 func (r ledgerReader) StreamLedgerRange(
 	ctx context.Context,
@@ -67,29 +182,18 @@ func (r ledgerReader) StreamLedgerRange(
 		Where(sq.GtOrEq{"sequence": startLedger}).
 		Where(sq.LtOrEq{"sequence": endLedger}).
 		OrderBy("sequence asc")
-
-	q, err := r.db.Query(ctx, sql)
-	if err != nil {
-		return err
-	}
-	defer q.Close()
-	for q.Next() {
-		var closeMeta xdr.LedgerCloseMeta
-		if err = q.Scan(&closeMeta); err != nil {
-			return err
-		}
-		if err = f(closeMeta); err != nil {
-			return err
-		}
-	}
-	return q.Err()
+	return r.streamLedgers(ctx, sql, f)
 }
 
 // GetLedger fetches a single ledger from the db.
 func (r ledgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
 	sql := sq.Select("meta").From(ledgerCloseMetaTableName).Where(sq.Eq{"sequence": sequence})
-	var results []xdr.LedgerCloseMeta
-	if err := r.db.Select(ctx, &results, sql); err != nil {
+	var encoded [][]byte
+	if err := r.db.Select(ctx, &encoded, sql); err != nil {
+		return xdr.LedgerCloseMeta{}, false, err
+	}
+	results, err := r.decodeLedgers(encoded)
+	if err != nil {
 		return xdr.LedgerCloseMeta{}, false, err
 	}
 	switch len(results) {
@@ -111,16 +215,21 @@ func (r ledgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.Le
 	r.db.cache.RUnlock()
 
 	// Make use of the cached latest ledger seq and close time to query only the oldest ledger details.
-	if latestLedgerSeqCache != 0 {
+	// The cache can be ahead of read snapshots, which query both.
+	if latestLedgerSeqCache != 0 && !r.db.inReadSnapshot(ctx) {
 		query := sq.Select("meta").
 			From(ledgerCloseMetaTableName).
 			Where(
 				fmt.Sprintf("sequence = (SELECT MIN(sequence) FROM %s)", ledgerCloseMetaTableName),
 			)
-		var lcm []xdr.LedgerCloseMeta
-		if err := r.db.Select(ctx, &lcm, query); err != nil {
+		var encoded [][]byte
+		if err := r.db.Select(ctx, &encoded, query); err != nil {
 			return ledgerbucketwindow.LedgerRange{}, fmt.Errorf("couldn't query ledger range: %w", err)
 		}
+		lcm, err := r.decodeLedgers(encoded)
+		if err != nil {
+			return ledgerbucketwindow.LedgerRange{}, err
+		}
 
 		if len(lcm) == 0 {
 			return ledgerbucketwindow.LedgerRange{}, ErrEmptyDB
@@ -145,10 +254,14 @@ func (r ledgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.Le
 			sq.Expr("lcm.sequence = (?)", sq.Select("MAX(sequence)").From(ledgerCloseMetaTableName)),
 		}).OrderBy("lcm.sequence ASC")
 
-	var lcms []xdr.LedgerCloseMeta
-	if err := r.db.Select(ctx, &lcms, query); err != nil {
+	var encoded [][]byte
+	if err := r.db.Select(ctx, &encoded, query); err != nil {
 		return ledgerbucketwindow.LedgerRange{}, fmt.Errorf("couldn't query ledger range: %w", err)
 	}
+	lcms, err := r.decodeLedgers(encoded)
+	if err != nil {
+		return ledgerbucketwindow.LedgerRange{}, err
+	}
 
 	if len(lcms) == 0 {
 		return ledgerbucketwindow.LedgerRange{}, ErrEmptyDB
@@ -167,7 +280,13 @@ func (r ledgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.Le
 }
 
 type ledgerWriter struct {
-	stmtCache *sq.StmtCache
+	log            *log.Entry
+	codec          LedgerCloseMetaCodec
+	stmtCache      *sq.StmtCache
+	reorgsDetected prometheus.Counter
+	// minProtocolVersion, if set, is the minimum protocol version of the inserted ledgers
+	minProtocolVersion        uint32
+	protocolVersionRejections prometheus.Counter
 }
 
 // trimLedgers removes all ledgers which fall outside the retention window.
@@ -184,11 +303,203 @@ func (l ledgerWriter) trimLedgers(latestLedgerSeq uint32, retentionWindow uint32
 	return err
 }
 
+// trimLedgersByTime removes all ledgers which closed before the cutoff (unix timestamp).
+func (l ledgerWriter) trimLedgersByTime(cutoff int64) error {
+	_, err := trimLedgersByTimeQuery(cutoff).RunWith(l.stmtCache).Exec()
+	return err
+}
+
+func trimLedgersByTimeQuery(cutoff int64) sq.DeleteBuilder {
+	return sq.Delete(ledgerCloseMetaTableName).Where(sq.Lt{"close_time": cutoff})
+}
+
+// firstLedgerSequence returns the sequence of the oldest stored ledger.
+func (l ledgerWriter) firstLedgerSequence() (uint32, error) {
+	var sequence sql.NullInt64
+	err := sq.StatementBuilder.
+		RunWith(l.stmtCache).
+		Select("MIN(sequence)").
+		From(ledgerCloseMetaTableName).
+		QueryRow().
+		Scan(&sequence)
+	if err != nil {
+		return 0, err
+	}
+	if !sequence.Valid {
+		return 0, ErrEmptyDB
+	}
+	return uint32(sequence.Int64), nil
+}
+
 // InsertLedger inserts a ledger in the db.
+//
+// Re-inserting an already stored ledger is a no-op if its hash is unchanged, which
+// is compared against the hash column without decoding the stored ledger.
+// Otherwise, the stored ledger is replaced and the replacement is logged and
+// counted as a reorg, since it points to inconsistent upstream data.
 func (l ledgerWriter) InsertLedger(ledger xdr.LedgerCloseMeta) error {
+	if err := l.checkProtocolVersion(ledger); err != nil {
+		return err
+	}
+	var storedHash []byte
+	err := sq.StatementBuilder.RunWith(l.stmtCache).
+		Select("hash").
+		From(ledgerCloseMetaTableName).
+		Where(sq.Eq{"sequence": ledger.LedgerSequence()}).
+		QueryRow().
+		Scan(&storedHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	exists := err == nil
+	if exists && storedHash == nil {
+		// the ledger was stored before the hash column was added
+		if storedHash, err = l.decodeStoredLedgerHash(ledger.LedgerSequence()); err != nil {
+			return err
+		}
+	}
+	hash := ledger.LedgerHash()
+	if exists && bytes.Equal(storedHash, hash[:]) {
+		return nil
+	}
+	encoded, err := l.codec.Encode(ledger)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err = sq.StatementBuilder.RunWith(l.stmtCache).
+			Insert(ledgerCloseMetaTableName).
+			Columns("sequence", "meta", "close_time", "hash").
+			Values(ledger.LedgerSequence(), encoded, ledger.LedgerCloseTime(), hash[:]).
+			Exec()
+		return err
+	}
+	return l.replaceLedger(storedHash, encoded, ledger)
+}
+
+// decodeStoredLedgerHash returns the hash of a stored ledger, decoded from its meta.
+func (l ledgerWriter) decodeStoredLedgerHash(sequence uint32) ([]byte, error) {
+	var encoded []byte
+	err := sq.StatementBuilder.RunWith(l.stmtCache).
+		Select("meta").
+		From(ledgerCloseMetaTableName).
+		Where(sq.Eq{"sequence": sequence}).
+		QueryRow().
+		Scan(&encoded)
+	if err != nil {
+		return nil, err
+	}
+	var stored xdr.LedgerCloseMeta
+	if err := l.codec.Decode(encoded, &stored); err != nil {
+		return nil, fmt.Errorf("could not decode stored ledger %d: %w", sequence, err)
+	}
+	hash := stored.LedgerHash()
+	return hash[:], nil
+}
+
+// checkProtocolVersion rejects the ledgers of a protocol version below the minimum protocol version.
+func (l ledgerWriter) checkProtocolVersion(ledger xdr.LedgerCloseMeta) error {
+	version := ledger.ProtocolVersion()
+	if l.minProtocolVersion == 0 || version >= l.minProtocolVersion {
+		return nil
+	}
+	if l.protocolVersionRejections != nil {
+		l.protocolVersionRejections.Inc()
+	}
+	l.log.WithField("sequence", ledger.LedgerSequence()).
+		WithField("protocol_version", version).
+		WithField("min_protocol_version", l.minProtocolVersion).
+		Error("rejected ledger with a protocol version below the minimum protocol version")
+	return fmt.Errorf("%w: ledger %d has protocol version %d (minimum %d)", ErrProtocolVersionTooLow,
+		ledger.LedgerSequence(), version, l.minProtocolVersion)
+}
+
+// replaceLedger replaces a stored ledger by a ledger of the same sequence and a different hash.
+func (l ledgerWriter) replaceLedger(previousHash []byte, encoded []byte, ledger xdr.LedgerCloseMeta) error {
+	l.log.WithFields(log.F{
+		"sequence":      ledger.LedgerSequence(),
+		"previous_hash": hex.EncodeToString(previousHash),
+		"new_hash":      ledger.LedgerHash().HexString(),
+	}).Warn("replacing stored ledger with different content (reorg detected)")
+	if l.reorgsDetected != nil {
+		l.reorgsDetected.Inc()
+	}
+
+	hash := ledger.LedgerHash()
 	_, err := sq.StatementBuilder.RunWith(l.stmtCache).
-		Insert(ledgerCloseMetaTableName).
-		Values(ledger.LedgerSequence(), ledger).
+		Update(ledgerCloseMetaTableName).
+		Set("meta", encoded).
+		Set("close_time", ledger.LedgerCloseTime()).
+		Set("hash", hash[:]).
+		Where(sq.Eq{"sequence": ledger.LedgerSequence()}).
 		Exec()
 	return err
 }
+
+// ledgerCloseTimeMigration fills in the close time of the ledgers stored before
+// the close_time column was added.
+type ledgerCloseTimeMigration struct {
+	ledgerSeqRange LedgerSeqRange
+	stmtCache      *sq.StmtCache
+}
+
+func (m *ledgerCloseTimeMigration) ApplicableRange() LedgerSeqRange {
+	return m.ledgerSeqRange
+}
+
+func (m *ledgerCloseTimeMigration) Apply(_ context.Context, meta xdr.LedgerCloseMeta) error {
+	_, err := sq.StatementBuilder.RunWith(m.stmtCache).
+		Update(ledgerCloseMetaTableName).
+		Set("close_time", meta.LedgerCloseTime()).
+		Where(sq.Eq{"sequence": meta.LedgerSequence(), "close_time": 0}).
+		Exec()
+	return err
+}
+
+func newLedgerCloseTimeMigration(
+	_ context.Context,
+	_ *log.Entry,
+	_ string,
+	ledgerSeqRange LedgerSeqRange,
+) migrationApplierFactory {
+	return migrationApplierFactoryF(func(db *DB) (MigrationApplier, error) {
+		return &ledgerCloseTimeMigration{
+			ledgerSeqRange: ledgerSeqRange,
+			stmtCache:      sq.NewStmtCache(db.GetTx()),
+		}, nil
+	})
+}
+
+// ledgerHashMigration fills in the hash of the ledgers stored before the hash column was added.
+type ledgerHashMigration struct {
+	ledgerSeqRange LedgerSeqRange
+	stmtCache      *sq.StmtCache
+}
+
+func (m *ledgerHashMigration) ApplicableRange() LedgerSeqRange {
+	return m.ledgerSeqRange
+}
+
+func (m *ledgerHashMigration) Apply(_ context.Context, meta xdr.LedgerCloseMeta) error {
+	hash := meta.LedgerHash()
+	_, err := sq.StatementBuilder.RunWith(m.stmtCache).
+		Update(ledgerCloseMetaTableName).
+		Set("hash", hash[:]).
+		Where(sq.And{sq.Eq{"sequence": meta.LedgerSequence()}, sq.Eq{"hash": nil}}).
+		Exec()
+	return err
+}
+
+func newLedgerHashMigration(
+	_ context.Context,
+	_ *log.Entry,
+	_ string,
+	ledgerSeqRange LedgerSeqRange,
+) migrationApplierFactory {
+	return migrationApplierFactoryF(func(db *DB) (MigrationApplier, error) {
+		return &ledgerHashMigration{
+			ledgerSeqRange: ledgerSeqRange,
+			stmtCache:      sq.NewStmtCache(db.GetTx()),
+		}, nil
+	})
+}
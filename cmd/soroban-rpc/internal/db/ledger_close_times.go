@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// LedgerCloseTime is the close time of a ledger.
+type LedgerCloseTime struct {
+	Sequence  uint32 `db:"sequence"`
+	CloseTime int64  `db:"close_time"`
+}
+
+// LedgerCloseTimeReader reads the close times of the stored ledgers.
+type LedgerCloseTimeReader interface {
+	// GetLedgerCloseTimes returns the close times of every stride-th ledger between the start
+	// and end ledgers (inclusive), starting at the start ledger, in ledger order. The close
+	// times are read from the close_time column, so the ledgers aren't decoded.
+	GetLedgerCloseTimes(ctx context.Context, startLedger, endLedger, stride uint32) ([]LedgerCloseTime, error)
+}
+
+func NewLedgerCloseTimeReader(db *DB) LedgerCloseTimeReader {
+	return ledgerCloseTimeReader{db: db}
+}
+
+type ledgerCloseTimeReader struct {
+	db *DB
+}
+
+func (r ledgerCloseTimeReader) GetLedgerCloseTimes(ctx context.Context, startLedger, endLedger, stride uint32,
+) ([]LedgerCloseTime, error) {
+	if stride == 0 {
+		return nil, errors.New("stride must be positive")
+	}
+	query := sq.Select("sequence", "close_time").
+		From(ledgerCloseMetaTableName).
+		Where(sq.GtOrEq{"sequence": startLedger}).
+		Where(sq.LtOrEq{"sequence": endLedger}).
+		Where(sq.Expr("(sequence - ?) % ? = 0", startLedger, stride)).
+		OrderBy("sequence ASC")
+	var closeTimes []LedgerCloseTime
+	if err := r.db.Select(ctx, &closeTimes, query); err != nil {
+		return nil, fmt.Errorf("could not query ledger close times: %w", err)
+	}
+	return closeTimes, nil
+}
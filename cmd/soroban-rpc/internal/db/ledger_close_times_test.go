@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestGetLedgerCloseTimes(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 100, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	var ledger xdr.LedgerCloseMeta
+	for i := uint32(1); i <= 10; i++ {
+		ledger = createLedger(i)
+		ledger.V1.LedgerHeader.Header.ScpValue.CloseTime = xdr.TimePoint(1000 + 5*i)
+		require.NoError(t, write.LedgerWriter().InsertLedger(ledger))
+	}
+	require.NoError(t, write.Commit(ledger))
+
+	reader := NewLedgerCloseTimeReader(db)
+	closeTimes, err := reader.GetLedgerCloseTimes(ctx, 2, 9, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerCloseTime{{2, 1010}, {5, 1025}, {8, 1040}}, closeTimes)
+
+	closeTimes, err = reader.GetLedgerCloseTimes(ctx, 9, 20, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerCloseTime{{9, 1045}, {10, 1050}}, closeTimes)
+
+	// the stride can exceed the range
+	closeTimes, err = reader.GetLedgerCloseTimes(ctx, 3, 10, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerCloseTime{{3, 1015}}, closeTimes)
+
+	_, err = reader.GetLedgerCloseTimes(ctx, 1, 10, 0)
+	require.ErrorContains(t, err, "stride must be positive")
+}
+
+func benchmarkLedgerCloseTimes(b *testing.B, closeTimes func(ctx context.Context, db *DB, start, end uint32) int) {
+	db := NewTestDB(b)
+	lcms := make([]xdr.LedgerCloseMeta, 0, 10_000)
+	for i := range cap(lcms) {
+		lcms = append(lcms, txMeta(uint32(1234+i), true))
+	}
+	ingestTransactions(b, db, lcms)
+	start, end := lcms[0].LedgerSequence(), lcms[len(lcms)-1].LedgerSequence()
+
+	b.ResetTimer()
+	for range b.N {
+		require.Equal(b, len(lcms)/10, closeTimes(context.TODO(), db, start, end))
+	}
+}
+
+func BenchmarkGetLedgerCloseTimes(b *testing.B) {
+	benchmarkLedgerCloseTimes(b, func(ctx context.Context, db *DB, start, end uint32) int {
+		closeTimes, err := NewLedgerCloseTimeReader(db).GetLedgerCloseTimes(ctx, start, end, 10)
+		require.NoError(b, err)
+		return len(closeTimes)
+	})
+}
+
+// BenchmarkGetLedgerCloseTimesFromLedgers gets the same close times by decoding the stored ledgers.
+func BenchmarkGetLedgerCloseTimesFromLedgers(b *testing.B) {
+	benchmarkLedgerCloseTimes(b, func(ctx context.Context, db *DB, start, end uint32) int {
+		var closeTimes []LedgerCloseTime
+		require.NoError(b, NewLedgerReader(db).StreamLedgerRange(ctx, start, end, func(lcm xdr.LedgerCloseMeta) error {
+			if (lcm.LedgerSequence()-start)%10 == 0 {
+				closeTimes = append(closeTimes, LedgerCloseTime{lcm.LedgerSequence(), lcm.LedgerCloseTime()})
+			}
+			return nil
+		}))
+		return len(closeTimes)
+	})
+}
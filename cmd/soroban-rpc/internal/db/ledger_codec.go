@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrfailures"
+)
+
+const (
+	// LedgerCodecXDR stores the ledgers as plain XDR. It is the default.
+	LedgerCodecXDR = "xdr"
+	// LedgerCodecZstd stores the ledgers as zstd-compressed XDR.
+	LedgerCodecZstd = "zstd"
+
+	// ledgerCodecMetaKey records the codec of the ledgers stored in a database.
+	ledgerCodecMetaKey = "LedgerCloseMetaCodec"
+)
+
+// LedgerCloseMetaCodec encodes the ledgers stored in the ledger_close_meta table.
+type LedgerCloseMetaCodec interface {
+	// Name identifies the codec in the database, which must always be opened with the same codec.
+	Name() string
+	Encode(ledger xdr.LedgerCloseMeta) ([]byte, error)
+	Decode(data []byte, ledger *xdr.LedgerCloseMeta) error
+}
+
+// NewLedgerCloseMetaCodec returns the codec with the given name (LedgerCodecXDR or LedgerCodecZstd).
+func NewLedgerCloseMetaCodec(name string) (LedgerCloseMetaCodec, error) {
+	switch name {
+	case "", LedgerCodecXDR:
+		return xdrLedgerCodec{}, nil
+	case LedgerCodecZstd:
+		encoder, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, err
+		}
+		decoder, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		return zstdLedgerCodec{encoder: encoder, decoder: decoder}, nil
+	default:
+		return nil, fmt.Errorf("unknown ledger codec %q (expected %q or %q)", name, LedgerCodecXDR, LedgerCodecZstd)
+	}
+}
+
+type xdrLedgerCodec struct{}
+
+func (xdrLedgerCodec) Name() string {
+	return LedgerCodecXDR
+}
+
+func (xdrLedgerCodec) Encode(ledger xdr.LedgerCloseMeta) ([]byte, error) {
+	return ledger.MarshalBinary()
+}
+
+func (xdrLedgerCodec) Decode(data []byte, ledger *xdr.LedgerCloseMeta) error {
+	if err := xdr.SafeUnmarshal(data, ledger); err != nil {
+		xdrfailures.Record(xdrfailures.LedgerMetaDecode, "LedgerCloseMeta")
+		return err
+	}
+	return nil
+}
+
+// zstdLedgerCodec compresses the XDR of the ledgers. The encoder and decoder are used
+// through EncodeAll and DecodeAll, which are safe for concurrent use.
+type zstdLedgerCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func (zstdLedgerCodec) Name() string {
+	return LedgerCodecZstd
+}
+
+func (c zstdLedgerCodec) Encode(ledger xdr.LedgerCloseMeta) ([]byte, error) {
+	encoded, err := ledger.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return c.encoder.EncodeAll(encoded, nil), nil
+}
+
+func (c zstdLedgerCodec) Decode(data []byte, ledger *xdr.LedgerCloseMeta) error {
+	decoded, err := c.decoder.DecodeAll(data, nil)
+	if err != nil {
+		xdrfailures.Record(xdrfailures.LedgerMetaDecode, "LedgerCloseMeta")
+		return fmt.Errorf("could not decompress ledger: %w", err)
+	}
+	if err := xdr.SafeUnmarshal(decoded, ledger); err != nil {
+		xdrfailures.Record(xdrfailures.LedgerMetaDecode, "LedgerCloseMeta")
+		return err
+	}
+	return nil
+}
+
+// setupLedgerCodec returns the codec with the given name, after checking it against the codec
+// recorded in the database. The codec can only change while the database has no ledgers.
+func setupLedgerCodec(ctx context.Context, session db.SessionInterface, name string) (LedgerCloseMetaCodec, error) {
+	codec, err := NewLedgerCloseMetaCodec(name)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := getMetaValue(ctx, session, ledgerCodecMetaKey)
+	if errors.Is(err, ErrEmptyDB) {
+		// the databases predating the codecs store plain XDR
+		recorded = LedgerCodecXDR
+	} else if err != nil {
+		return nil, fmt.Errorf("could not get the ledger codec of the database: %w", err)
+	}
+
+	if recorded != codec.Name() {
+		var sequences []uint32
+		query := sq.Select("sequence").From(ledgerCloseMetaTableName).Limit(1)
+		if err := session.Select(ctx, &sequences, query); err != nil {
+			return nil, fmt.Errorf("could not query ledgers: %w", err)
+		}
+		if len(sequences) > 0 {
+			return nil, fmt.Errorf("the database stores ledgers with the %q codec, it can't be opened with the %q codec",
+				recorded, codec.Name())
+		}
+	}
+	query := sq.Replace(metaTableName).Values(ledgerCodecMetaKey, codec.Name())
+	if _, err := session.Exec(ctx, query); err != nil {
+		return nil, fmt.Errorf("could not record the ledger codec of the database: %w", err)
+	}
+	return codec, nil
+}
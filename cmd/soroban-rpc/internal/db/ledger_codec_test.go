@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrfailures"
+)
+
+func TestLedgerCloseMetaCodecRoundTrip(t *testing.T) {
+	ledger := txMeta(1, true)
+	expected, err := ledger.MarshalBinary()
+	require.NoError(t, err)
+	for _, name := range []string{LedgerCodecXDR, LedgerCodecZstd} {
+		t.Run(name, func(t *testing.T) {
+			codec, err := NewLedgerCloseMetaCodec(name)
+			require.NoError(t, err)
+			assert.Equal(t, name, codec.Name())
+
+			encoded, err := codec.Encode(ledger)
+			require.NoError(t, err)
+			var decoded xdr.LedgerCloseMeta
+			require.NoError(t, codec.Decode(encoded, &decoded))
+			actual, err := decoded.MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	_, err = NewLedgerCloseMetaCodec("gzip")
+	require.Error(t, err)
+}
+
+// xdrFailures returns the number of failures of the given operation recorded by xdrfailures.
+func xdrFailures(t *testing.T, operation string) float64 {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(xdrfailures.Collector())
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	var count float64
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "operation" && label.GetValue() == operation {
+					count += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return count
+}
+
+func TestLedgerCloseMetaCodecDecodeFailuresAreCounted(t *testing.T) {
+	for _, name := range []string{LedgerCodecXDR, LedgerCodecZstd} {
+		t.Run(name, func(t *testing.T) {
+			codec, err := NewLedgerCloseMetaCodec(name)
+			require.NoError(t, err)
+			before := xdrFailures(t, xdrfailures.LedgerMetaDecode)
+			var decoded xdr.LedgerCloseMeta
+			require.Error(t, codec.Decode([]byte("corrupted"), &decoded))
+			assert.Equal(t, before+1, xdrFailures(t, xdrfailures.LedgerMetaDecode))
+		})
+	}
+}
+
+func TestZstdLedgerCodecDatabase(t *testing.T) {
+	ctx := context.Background()
+	dbPath := path.Join(t.TempDir(), "db.sqlite")
+	db, err := OpenSQLiteDBWithLedgerCodec(dbPath, LedgerCodecZstd)
+	require.NoError(t, err)
+
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+	ledgers := []xdr.LedgerCloseMeta{txMeta(1, true), txMeta(2, false)}
+	for _, ledger := range ledgers {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	// the ledgers are stored compressed
+	var stored []byte
+	require.NoError(t, db.GetRaw(ctx, &stored, "SELECT meta FROM ledger_close_meta WHERE sequence = ?",
+		ledgers[0].LedgerSequence()))
+	uncompressed, err := ledgers[0].MarshalBinary()
+	require.NoError(t, err)
+	assert.NotEqual(t, uncompressed, stored)
+
+	reader := NewLedgerReader(db)
+	ledger, found, err := reader.GetLedger(ctx, ledgers[1].LedgerSequence())
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, ledgers[1].LedgerHash(), ledger.LedgerHash())
+	ledgerRange, err := reader.GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ledgers[0].LedgerSequence(), ledgerRange.FirstLedger.Sequence)
+	var streamed []uint32
+	require.NoError(t, reader.StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		streamed = append(streamed, ledger.LedgerSequence())
+		return nil
+	}))
+	assert.Equal(t, []uint32{101, 102}, streamed)
+	tx, err := NewTransactionReader(logger, db, passphrase).GetTransaction(ctx, txHash(2))
+	require.NoError(t, err)
+	assert.False(t, tx.Successful)
+
+	// re-inserting an unchanged ledger is a no-op
+	writeTx, err := rw.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, writeTx.LedgerWriter().InsertLedger(ledgers[0]))
+	require.NoError(t, writeTx.Commit(ledgers[1]))
+	require.NoError(t, db.Close())
+
+	// the database records its codec
+	_, err = OpenSQLiteDB(dbPath)
+	require.ErrorContains(t, err, `the database stores ledgers with the "zstd" codec`)
+	db, err = OpenSQLiteDBWithLedgerCodec(dbPath, LedgerCodecZstd)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestLedgerCodecOfEmptyDatabaseCanChange(t *testing.T) {
+	dbPath := path.Join(t.TempDir(), "db.sqlite")
+	db, err := OpenSQLiteDB(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	db, err = OpenSQLiteDBWithLedgerCodec(dbPath, LedgerCodecZstd)
+	require.NoError(t, err)
+	assert.Equal(t, LedgerCodecZstd, db.codec.Name())
+	require.NoError(t, db.Close())
+}
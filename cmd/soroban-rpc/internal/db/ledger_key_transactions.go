@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ledgerKeyTransactionTableName = "ledger_key_transactions"
+	// ledgerKeyTransactionInsertChunkSize is the number of rows inserted per statement into the
+	// ledger key index, keeping the statements (3 parameters per row) within the SQLite limit on
+	// the number of parameters, which is 999 in older versions.
+	ledgerKeyTransactionInsertChunkSize = 300
+)
+
+// LedgerKeyTransactionReader looks transactions up by the ledger entries they changed.
+type LedgerKeyTransactionReader interface {
+	// GetTransactionsByLedgerKey returns the locations of (at most limit) transactions which
+	// created, updated or removed the entry with the given key, in application order, starting at
+	// the transaction with application order startOrder of ledger startLedger and ending at
+	// ledger endLedger (inclusive).
+	GetTransactionsByLedgerKey(ctx context.Context, key xdr.LedgerKey,
+		startLedger uint32, startOrder int32, endLedger uint32, limit uint) ([]TransactionLocation, error)
+}
+
+func NewLedgerKeyTransactionReader(db *DB) LedgerKeyTransactionReader {
+	return ledgerKeyTransactionReader{db: db}
+}
+
+type ledgerKeyTransactionReader struct {
+	db *DB
+}
+
+// hashLedgerKey returns how a ledger key is stored in the ledger key index.
+func hashLedgerKey(key xdr.LedgerKey) ([]byte, error) {
+	encoded, err := key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(encoded)
+	return hash[:], nil
+}
+
+func (r ledgerKeyTransactionReader) GetTransactionsByLedgerKey(ctx context.Context, key xdr.LedgerKey,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	keyHash, err := hashLedgerKey(key)
+	if err != nil {
+		return nil, err
+	}
+	query := transactionLocationsQuery(ledgerKeyTransactionTableName, sq.Eq{"key_hash": keyHash},
+		startLedger, startOrder, endLedger, limit)
+	var locations []TransactionLocation
+	if err := r.db.Select(ctx, &locations, query); err != nil {
+		return nil, fmt.Errorf("could not query transactions by ledger key: %w", err)
+	}
+	return locations, nil
+}
+
+// ChangedLedgerKeys returns the keys of the ledger entries created, updated or removed by the
+// transaction with the given meta (excluding its fee processing), without duplicates and in order
+// of first appearance in the meta.
+func ChangedLedgerKeys(meta xdr.TransactionMeta) ([]xdr.LedgerKey, error) {
+	changes, err := (&ingest.LedgerTransaction{UnsafeMeta: meta}).GetChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []xdr.LedgerKey
+	seen := map[string]struct{}{}
+	for _, change := range changes {
+		entry := change.Post
+		if entry == nil {
+			entry = change.Pre
+		}
+		if entry == nil {
+			continue
+		}
+		key, err := entry.LedgerKey()
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[encoded]; ok {
+			continue
+		}
+		seen[encoded] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// insertLedgerKeyTransactions indexes the transactions of a ledger by the ledger entries they
+// changed, in chunks of ledgerKeyTransactionInsertChunkSize rows.
+func insertLedgerKeyTransactions(runner sq.BaseRunner, ledgerSeq uint32, txs []ingest.LedgerTransaction) error {
+	newQuery := func() sq.InsertBuilder {
+		// ignore conflicts so that reingesting a ledger (e.g. after a migration) never fails
+		return sq.Insert(ledgerKeyTransactionTableName).
+			Options("OR IGNORE").
+			Columns("key_hash", "ledger_sequence", "application_order")
+	}
+	query := newQuery()
+	var count int
+	for _, tx := range txs {
+		keys, err := ChangedLedgerKeys(tx.UnsafeMeta)
+		if err != nil {
+			return fmt.Errorf("could not get the ledger entries changed by tx %d: %w", tx.Index, err)
+		}
+		for _, key := range keys {
+			keyHash, err := hashLedgerKey(key)
+			if err != nil {
+				return err
+			}
+			query = query.Values(keyHash, ledgerSeq, tx.Index)
+			count++
+			if count == ledgerKeyTransactionInsertChunkSize {
+				if _, err := query.RunWith(runner).Exec(); err != nil {
+					return err
+				}
+				query = newQuery()
+				count = 0
+			}
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	_, err := query.RunWith(runner).Exec()
+	return err
+}
+
+// ledgerKeyTransactionMigration fills in the ledger key index of the ledgers stored before
+// the ledger_key_transactions table was added.
+type ledgerKeyTransactionMigration struct {
+	ledgerSeqRange LedgerSeqRange
+	passphrase     string
+	stmtCache      *sq.StmtCache
+}
+
+func (m *ledgerKeyTransactionMigration) ApplicableRange() LedgerSeqRange {
+	return m.ledgerSeqRange
+}
+
+func (m *ledgerKeyTransactionMigration) Apply(_ context.Context, meta xdr.LedgerCloseMeta) error {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(m.passphrase, meta)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction reader for ledger %d: %w", meta.LedgerSequence(), err)
+	}
+	txs := make([]ingest.LedgerTransaction, 0, meta.CountTransactions())
+	for i := range meta.CountTransactions() {
+		tx, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("failed reading tx %d: %w", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	return insertLedgerKeyTransactions(m.stmtCache, meta.LedgerSequence(), txs)
+}
+
+func newLedgerKeyTransactionMigration(
+	_ context.Context,
+	_ *log.Entry,
+	passphrase string,
+	ledgerSeqRange LedgerSeqRange,
+) migrationApplierFactory {
+	return migrationApplierFactoryF(func(db *DB) (MigrationApplier, error) {
+		return &ledgerKeyTransactionMigration{
+			ledgerSeqRange: ledgerSeqRange,
+			passphrase:     passphrase,
+			stmtCache:      sq.NewStmtCache(db.GetTx()),
+		}, nil
+	})
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func contractInstanceEntry(contractID xdr.Hash, value uint32) xdr.LedgerEntry {
+	val := xdr.Uint32(value)
+	return xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract: xdr.ScAddress{
+					Type:       xdr.ScAddressTypeScAddressTypeContract,
+					ContractId: &contractID,
+				},
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &val},
+			},
+		},
+	}
+}
+
+func txMetaWithChanges(acctSeq uint32, changes ...xdr.LedgerEntryChange) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, true)
+	meta.V1.TxProcessing[0].TxApplyProcessing.V3.Operations = []xdr.OperationMeta{{Changes: changes}}
+	return meta
+}
+
+func TestGetTransactionsByLedgerKey(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 4, passphrase)
+	before, after, other := contractInstanceEntry(xdr.Hash{1}, 1), contractInstanceEntry(xdr.Hash{1}, 2),
+		contractInstanceEntry(xdr.Hash{2}, 1)
+	key, err := before.LedgerKey()
+	require.NoError(t, err)
+	otherKey, err := other.LedgerKey()
+	require.NoError(t, err)
+	ledgers := []xdr.LedgerCloseMeta{
+		txMetaWithChanges(1, xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &before}),
+		txMetaWithChanges(2,
+			xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &before},
+			xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &after},
+			xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &other}),
+		txMetaWithChanges(3, xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &other}),
+		txMetaWithChanges(4,
+			xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &after},
+			xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &key}),
+		txMeta(5, true),
+	}
+	for _, ledger := range ledgers[:4] {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	reader := NewLedgerKeyTransactionReader(db)
+	assertLocations := func(expected []TransactionLocation) {
+		locations, err := reader.GetTransactionsByLedgerKey(ctx, key, 101, 1, 104, 10)
+		require.NoError(t, err)
+		assert.Equal(t, expected, locations)
+	}
+	assertLocations([]TransactionLocation{
+		{Ledger: 101, ApplicationOrder: 1}, {Ledger: 102, ApplicationOrder: 1}, {Ledger: 104, ApplicationOrder: 1},
+	})
+
+	locations, err := reader.GetTransactionsByLedgerKey(ctx, key, 101, 1, 104, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 101, ApplicationOrder: 1}}, locations)
+	locations, err = reader.GetTransactionsByLedgerKey(ctx, key, 101, 2, 103, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 102, ApplicationOrder: 1}}, locations)
+	locations, err = reader.GetTransactionsByLedgerKey(ctx, otherKey, 101, 1, 104, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 102, ApplicationOrder: 1}, {Ledger: 103, ApplicationOrder: 1}},
+		locations)
+
+	// simulate ledgers stored before the ledger_key_transactions table was added
+	_, err = db.ExecRaw(ctx, "DELETE FROM ledger_key_transactions")
+	require.NoError(t, err)
+	assertLocations(nil)
+
+	require.NoError(t, db.Begin(ctx))
+	migration, err := newLedgerKeyTransactionMigration(ctx, logger, passphrase, LedgerSeqRange{First: 101, Last: 104}).
+		New(db)
+	require.NoError(t, err)
+	require.NoError(t, NewLedgerReader(db).StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		return migration.Apply(ctx, ledger)
+	}))
+	require.NoError(t, db.Commit())
+	assertLocations([]TransactionLocation{
+		{Ledger: 101, ApplicationOrder: 1}, {Ledger: 102, ApplicationOrder: 1}, {Ledger: 104, ApplicationOrder: 1},
+	})
+
+	// the index is trimmed along with the transactions
+	tx, err := rw.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerWriter().InsertLedger(ledgers[4]))
+	require.NoError(t, tx.TransactionWriter().InsertTransactions(ledgers[4]))
+	require.NoError(t, tx.Commit(ledgers[4]))
+	assertLocations([]TransactionLocation{{Ledger: 102, ApplicationOrder: 1}, {Ledger: 104, ApplicationOrder: 1}})
+}
+
+func TestInsertLedgerKeyTransactionsInChunks(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 4, passphrase)
+	// index more rows than fit in a single insert statement
+	var changes []xdr.LedgerEntryChange
+	var keys []xdr.LedgerKey
+	for i := range 2*ledgerKeyTransactionInsertChunkSize + 1 {
+		entry := contractInstanceEntry(xdr.Hash{byte(i), byte(i >> 8)}, 1)
+		changes = append(changes, xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &entry})
+		key, err := entry.LedgerKey()
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	ledger := txMetaWithChanges(1, changes...)
+	tx, err := rw.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+	require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+	require.NoError(t, tx.Commit(ledger))
+
+	reader := NewLedgerKeyTransactionReader(db)
+	for _, key := range []xdr.LedgerKey{keys[0], keys[ledgerKeyTransactionInsertChunkSize], keys[len(keys)-1]} {
+		locations, err := reader.GetTransactionsByLedgerKey(ctx, key, 101, 1, 101, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []TransactionLocation{{Ledger: 101, ApplicationOrder: 1}}, locations)
+	}
+	var count int
+	require.NoError(t, db.GetRaw(ctx, &count, "SELECT COUNT(*) FROM ledger_key_transactions"))
+	assert.Equal(t, len(keys), count)
+}
+
+func TestChangedLedgerKeys(t *testing.T) {
+	before, after := contractInstanceEntry(xdr.Hash{1}, 1), contractInstanceEntry(xdr.Hash{1}, 2)
+	other := contractInstanceEntry(xdr.Hash{2}, 1)
+	meta := txMetaWithChanges(1,
+		xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &before},
+		xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &after},
+		xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &other})
+	keys, err := ChangedLedgerKeys(meta.V1.TxProcessing[0].TxApplyProcessing)
+	require.NoError(t, err)
+	key, err := before.LedgerKey()
+	require.NoError(t, err)
+	otherKey, err := other.LedgerKey()
+	require.NoError(t, err)
+	assert.Equal(t, []xdr.LedgerKey{key, otherKey}, keys)
+}
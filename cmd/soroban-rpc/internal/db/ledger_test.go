@@ -2,9 +2,13 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -110,6 +114,238 @@ func TestLedgers(t *testing.T) {
 	assertLedgerRange(t, reader, 8, 12)
 }
 
+func TestLedgerReplacement(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+	reorgsDetected := rw.(*readWriter).metrics.ReorgsDetected
+
+	insert := func(ledger xdr.LedgerCloseMeta) {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	insert(createLedger(1))
+	// re-inserting the same content is a no-op
+	insert(createLedger(1))
+	assert.Zero(t, testutil.ToFloat64(reorgsDetected))
+	assertLedgerRange(t, NewLedgerReader(db), 1, 1)
+
+	// different content replaces the stored ledger
+	replacement := createLedger(1)
+	replacement.V1.LedgerHeader.Hash = xdr.Hash{0xff}
+	insert(replacement)
+	assert.InDelta(t, 1, testutil.ToFloat64(reorgsDetected), 0)
+
+	ledger, exists, err := NewLedgerReader(db).GetLedger(ctx, 1)
+	require.NoError(t, err)
+	require.True(t, exists)
+	assert.Equal(t, xdr.Hash{0xff}, ledger.LedgerHash())
+	var storedHash []byte
+	require.NoError(t, db.GetRaw(ctx, &storedHash, "SELECT hash FROM ledger_close_meta WHERE sequence = 1"))
+	assert.Equal(t, replacement.LedgerHash(), xdr.Hash(storedHash))
+
+	// the stored ledger is decoded if it was stored before the hash column was added
+	_, err = db.ExecRaw(ctx, "UPDATE ledger_close_meta SET hash = NULL")
+	require.NoError(t, err)
+	insert(replacement)
+	assert.InDelta(t, 1, testutil.ToFloat64(reorgsDetected), 0)
+	insert(createLedger(1))
+	assert.InDelta(t, 2, testutil.ToFloat64(reorgsDetected), 0)
+	require.NoError(t, db.GetRaw(ctx, &storedHash, "SELECT hash FROM ledger_close_meta WHERE sequence = 1"))
+	assert.Equal(t, createLedger(1).LedgerHash(), xdr.Hash(storedHash))
+}
+
+func TestLedgerRetentionPeriod(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriterWithRetentionPeriod(logger, db, interfaces.MakeNoOpDeamon(), 150, 350*time.Second, passphrase)
+
+	// ledgers close every 100 seconds
+	for i := uint32(1); i <= 10; i++ {
+		ledger := createLedger(i)
+		ledger.V1.LedgerHeader.Header.ScpValue.CloseTime = xdr.TimePoint(100 * i)
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	// the latest ledger closed at 1000, so the ledgers which closed before 650 are trimmed
+	reader := NewLedgerReader(db)
+	ledgerRange, err := reader.GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(7), ledgerRange.FirstLedger.Sequence)
+	assert.Equal(t, int64(700), ledgerRange.FirstLedger.CloseTime)
+	assert.Equal(t, uint32(10), ledgerRange.LastLedger.Sequence)
+	_, exists, err := reader.GetLedger(ctx, 6)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestLedgerRetentionMaxDBSize(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	usedSize := func() uint64 {
+		var pageCount, freelistCount, pageSize uint64
+		require.NoError(t, db.GetRaw(ctx, &pageCount, "PRAGMA page_count"))
+		require.NoError(t, db.GetRaw(ctx, &freelistCount, "PRAGMA freelist_count"))
+		require.NoError(t, db.GetRaw(ctx, &pageSize, "PRAGMA page_size"))
+		return (pageCount - freelistCount) * pageSize
+	}
+	// each ledger takes about 8KB, through its evicted keys
+	ingest := func(rw ReadWriter, sequence uint32) {
+		ledger := createLedger(sequence)
+		for i := 0; i < 200; i++ {
+			var key xdr.LedgerKey
+			require.NoError(t, key.SetContractCode(xdr.Hash{byte(sequence), byte(sequence >> 8), byte(i)}))
+			ledger.V1.EvictedTemporaryLedgerKeys = append(ledger.V1.EvictedTemporaryLedgerKeys, key)
+		}
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 1_000_000, passphrase)
+	ingest(rw, 1)
+	maxSize := usedSize() + 1024*1024
+	status := NewMaxDBSizeStatus(maxSize)
+	sizeCapped := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 1_000_000, passphrase,
+		WithMaxDBSize(status, 1))
+	for i := uint32(2); i <= 300; i++ {
+		ingest(sizeCapped, i)
+		require.LessOrEqual(t, usedSize(), maxSize)
+		_, _, exceeded := status.Exceeded()
+		require.False(t, exceeded)
+	}
+
+	// the oldest ledgers are trimmed to keep the database within its maximum size
+	ledgerRange, err := NewLedgerReader(db).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(300), ledgerRange.LastLedger.Sequence)
+	assert.Greater(t, ledgerRange.FirstLedger.Sequence, uint32(1))
+	assert.Less(t, ledgerRange.FirstLedger.Sequence, uint32(300-maxDBSizeTrimChunk), "too many ledgers trimmed")
+
+	// the minimum retention window is retained regardless of the maximum size, which is reported instead
+	status = NewMaxDBSizeStatus(1)
+	sizeCapped = NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 1_000_000, passphrase,
+		WithMaxDBSize(status, 10))
+	ingest(sizeCapped, 301)
+	ledgerRange, err = NewLedgerReader(db).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(292), ledgerRange.FirstLedger.Sequence)
+	assert.Equal(t, uint32(301), ledgerRange.LastLedger.Sequence)
+	size, reportedMaxSize, exceeded := status.Exceeded()
+	assert.True(t, exceeded)
+	assert.Equal(t, usedSize(), size)
+	assert.Equal(t, uint64(1), reportedMaxSize)
+}
+
+func TestLedgerMinProtocolVersion(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase, WithMinProtocolVersion(21))
+	insert := func(sequence uint32, protocolVersion uint32) error {
+		ledger := createLedger(sequence)
+		ledger.V1.LedgerHeader.Header.LedgerVersion = xdr.Uint32(protocolVersion)
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		if err := tx.LedgerWriter().InsertLedger(ledger); err != nil {
+			require.NoError(t, tx.Rollback())
+			return err
+		}
+		return tx.Commit(ledger)
+	}
+
+	require.NoError(t, insert(1, 21))
+	require.NoError(t, insert(2, 22))
+	err := insert(3, 20)
+	require.ErrorIs(t, err, ErrProtocolVersionTooLow)
+	assert.InDelta(t, 1, testutil.ToFloat64(rw.(*readWriter).metrics.ProtocolVersionRejections), 0)
+
+	reader := NewLedgerReader(db)
+	_, found, err := reader.GetLedger(ctx, 3)
+	require.NoError(t, err)
+	require.False(t, found)
+	ledgerRange, err := reader.GetLedgerRange(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), ledgerRange.LastLedger.Sequence)
+}
+
+func TestLedgerCloseTimeMigration(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+	for i := uint32(1); i <= 5; i++ {
+		ledger := createLedger(i)
+		ledger.V1.LedgerHeader.Header.ScpValue.CloseTime = xdr.TimePoint(100 * i)
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+	// simulate ledgers stored before the close_time column was added
+	_, err := db.ExecRaw(ctx, "UPDATE ledger_close_meta SET close_time = 0 WHERE sequence >= 2")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Begin(ctx))
+	// the migration applies to the range supplied by MultiMigration
+	migration, err := newLedgerCloseTimeMigration(ctx, logger, passphrase, LedgerSeqRange{First: 1, Last: 4}).New(db)
+	require.NoError(t, err)
+	assert.Equal(t, LedgerSeqRange{First: 1, Last: 4}, migration.ApplicableRange())
+	require.NoError(t, NewLedgerReader(db).StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		if !migration.ApplicableRange().IsLedgerIncluded(ledger.LedgerSequence()) {
+			return nil
+		}
+		return migration.Apply(ctx, ledger)
+	}))
+	require.NoError(t, db.Commit())
+
+	var closeTimes []int64
+	require.NoError(t, db.SelectRaw(ctx, &closeTimes, "SELECT close_time FROM ledger_close_meta ORDER BY sequence"))
+	assert.Equal(t, []int64{100, 200, 300, 400, 0}, closeTimes)
+}
+
+func TestLedgerHashMigration(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+	for i := uint32(1); i <= 5; i++ {
+		ledger := createLedger(i)
+		ledger.V1.LedgerHeader.Hash = xdr.Hash{byte(i)}
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+	// simulate ledgers stored before the hash column was added
+	_, err := db.ExecRaw(ctx, "UPDATE ledger_close_meta SET hash = NULL")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Begin(ctx))
+	migration, err := newLedgerHashMigration(ctx, logger, passphrase, LedgerSeqRange{First: 2, Last: 5}).New(db)
+	require.NoError(t, err)
+	require.NoError(t, NewLedgerReader(db).StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		if !migration.ApplicableRange().IsLedgerIncluded(ledger.LedgerSequence()) {
+			return nil
+		}
+		return migration.Apply(ctx, ledger)
+	}))
+	require.NoError(t, db.Commit())
+
+	var hashes [][]byte
+	require.NoError(t, db.SelectRaw(ctx, &hashes, "SELECT hash FROM ledger_close_meta ORDER BY sequence"))
+	require.Len(t, hashes, 5)
+	// the ledgers out of the range of the migration are left as they are
+	assert.Nil(t, hashes[0])
+	for i, hash := range hashes[1:] {
+		assert.Equal(t, xdr.Hash{byte(i + 2)}, xdr.Hash(hash))
+	}
+}
+
 func TestGetLedgerRange_NonEmptyDB(t *testing.T) {
 	db := NewTestDB(t)
 	ctx := context.TODO()
@@ -212,6 +448,79 @@ func BenchmarkGetLedgerRange(b *testing.B) {
 	}
 }
 
+func TestStreamLedgers_Prefetch(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 100, passphrase)
+	for i := uint32(1); i <= 10; i++ {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(createLedger(i)))
+		require.NoError(t, tx.Commit(createLedger(i)))
+	}
+
+	errStop := errors.New("stop")
+	for _, chunkSize := range []uint{0, 1, 3, 10, 20} {
+		reader := NewLedgerReaderWithPrefetch(db, chunkSize)
+		var sequences []uint32
+		collect := func(ledger xdr.LedgerCloseMeta) error {
+			sequences = append(sequences, ledger.LedgerSequence())
+			return nil
+		}
+		require.NoError(t, reader.StreamAllLedgers(ctx, collect))
+		assert.Equal(t, []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, sequences, "chunk size %d", chunkSize)
+
+		sequences = nil
+		require.NoError(t, reader.StreamLedgerRange(ctx, 3, 7, collect))
+		assert.Equal(t, []uint32{3, 4, 5, 6, 7}, sequences, "chunk size %d", chunkSize)
+
+		// the stream stops at the first error of the callback
+		sequences = nil
+		err := reader.StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+			if ledger.LedgerSequence() == 5 {
+				return errStop
+			}
+			return collect(ledger)
+		})
+		require.ErrorIs(t, err, errStop)
+		assert.Equal(t, []uint32{1, 2, 3, 4}, sequences, "chunk size %d", chunkSize)
+	}
+}
+
+func BenchmarkStreamAllLedgers(b *testing.B) {
+	db := NewTestDB(b)
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 1_000_000, passphrase)
+	write, err := writer.NewTx(context.TODO())
+	require.NoError(b, err)
+	lcms := make([]xdr.LedgerCloseMeta, 0, 10_000)
+	for i := range cap(lcms) {
+		lcms = append(lcms, txMeta(uint32(1234+i), i%2 == 0))
+	}
+	ledgerW := write.LedgerWriter()
+	for _, lcm := range lcms {
+		require.NoError(b, ledgerW.InsertLedger(lcm))
+	}
+	require.NoError(b, write.Commit(lcms[len(lcms)-1]))
+
+	for _, chunkSize := range []uint{0, 100, 1000} {
+		name := "serial"
+		if chunkSize > 0 {
+			name = fmt.Sprintf("prefetch-%d", chunkSize)
+		}
+		b.Run(name, func(b *testing.B) {
+			reader := NewLedgerReaderWithPrefetch(db, chunkSize)
+			for range b.N {
+				// re-encoding the ledgers stands for the processing of a full scan (e.g. the migrations)
+				err := reader.StreamAllLedgers(context.TODO(), func(ledger xdr.LedgerCloseMeta) error {
+					_, err := ledger.MarshalBinary()
+					return err
+				})
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
 func NewTestDB(tb testing.TB) *DB {
 	tmp := tb.TempDir()
 	dbPath := path.Join(tmp, "db.sqlite")
@@ -11,6 +11,8 @@ import (
 
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrfailures"
 )
 
 const (
@@ -136,6 +138,9 @@ func (l ledgerEntryWriter) flush() error {
 }
 
 type ledgerEntryReadTx struct {
+	// ctx is the context the transaction was created with. It is used by all the
+	// queries, so that cancelling it interrupts a running query.
+	ctx                    context.Context //nolint:containedctx
 	globalCache            *dbCache
 	stmtCache              *sq.StmtCache
 	latestLedgerSeqCache   uint32
@@ -149,7 +154,7 @@ func (l *ledgerEntryReadTx) GetLatestLedgerSequence() (uint32, error) {
 	if l.latestLedgerSeqCache != 0 {
 		return l.latestLedgerSeqCache, nil
 	}
-	latestLedgerSeq, err := getLatestLedgerSequence(context.Background(), l.ledgerReader, l.globalCache)
+	latestLedgerSeq, err := getLatestLedgerSequence(l.ctx, l.ledgerReader, l.globalCache)
 	if err == nil {
 		l.latestLedgerSeqCache = latestLedgerSeq
 	}
@@ -184,7 +189,7 @@ func (l *ledgerEntryReadTx) getRawLedgerEntries(keys ...string) (map[string]stri
 		builder = builder.RunWith(l.tx.GetTx())
 	}
 	sql := builder.Select("key", "entry").From(ledgerEntriesTableName).Where(sq.Eq{"key": keysToQueryInDB})
-	q, err := sql.Query()
+	q, err := sql.QueryContext(l.ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -321,6 +326,7 @@ func (l *ledgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKey
 		}
 		var entry xdr.LedgerEntry
 		if err := xdr.SafeUnmarshal([]byte(encodedEntry), &entry); err != nil {
+			xdrfailures.Record(xdrfailures.LedgerEntryDecode, "LedgerEntry")
 			return nil, fmt.Errorf("cannot decode ledger entry from DB: %w", err)
 		}
 		if k2e.encodedTTLKey == nil {
@@ -334,6 +340,7 @@ func (l *ledgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKey
 		}
 		var ttlEntry xdr.LedgerEntry
 		if err := xdr.SafeUnmarshal([]byte(encodedTTLEntry), &ttlEntry); err != nil {
+			xdrfailures.Record(xdrfailures.LedgerEntryDecode, "LedgerEntry")
 			return nil, fmt.Errorf("cannot decode TTL ledger entry from DB: %w", err)
 		}
 		liveUntilSeq := uint32(ttlEntry.Data.Ttl.LiveUntilLedgerSeq)
@@ -371,6 +378,7 @@ func (r ledgerEntryReader) NewTx(ctx context.Context, cacheTx bool) (LedgerEntry
 		return nil, fmt.Errorf("failed to begin read transaction: %w", err)
 	}
 	tx := &ledgerEntryReadTx{
+		ctx:                  ctx,
 		globalCache:          r.db.cache,
 		latestLedgerSeqCache: r.db.cache.latestLedgerSeq,
 		tx:                   txSession,
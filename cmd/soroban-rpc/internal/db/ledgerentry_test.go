@@ -197,6 +197,36 @@ func verifyDeletedEntry(t *testing.T, db *DB, key xdr.LedgerKey, ledgerSequence
 	assert.Equal(t, ledgerSequence, obtainedLedgerSequence)
 }
 
+func TestLedgerEntryReadTxCancellation(t *testing.T) {
+	db := NewTestDB(t)
+	key, entry := getContractDataLedgerEntry(t, createTestContractDataEntry())
+	writer := makeReadWriter(db, 150, 15)
+	tx, err := writer.NewTx(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerEntryWriter().UpsertLedgerEntry(entry))
+	ttlKey, err := entryKeyToTTLEntryKey(key)
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerEntryWriter().UpsertLedgerEntry(getTTLLedgerEntry(ttlKey)))
+	require.NoError(t, tx.Commit(createLedger(1)))
+
+	for _, cacheTx := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		readTx, err := NewLedgerEntryReader(db).NewTx(ctx, cacheTx)
+		require.NoError(t, err)
+		entries, err := readTx.GetLedgerEntries(key)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		// queries issued after the request context is cancelled are interrupted
+		cancel()
+		otherKey, _ := getContractDataLedgerEntry(t, createTestContractDataEntry())
+		otherKey.ContractData.Durability = xdr.ContractDataDurabilityTemporary
+		_, err = readTx.GetLedgerEntries(otherKey)
+		require.Error(t, err)
+		_ = readTx.Done()
+	}
+}
+
 func TestDeleteNonExistentLedgerEmpty(t *testing.T) {
 	db := NewTestDB(t)
 
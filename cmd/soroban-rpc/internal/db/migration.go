@@ -10,8 +10,15 @@ import (
 )
 
 const (
-	transactionsMigrationName = "TransactionsTable"
-	eventsMigrationName       = "EventsTable"
+	transactionsMigrationName       = "TransactionsTable"
+	eventsMigrationName             = "EventsTable"
+	ledgerCloseTimeMigrationName    = "LedgerCloseTimeColumn"
+	transactionMemoMigrationName    = "TransactionMemoColumns"
+	contractCreationsMigrationName  = "ContractCreationsTable"
+	ledgerKeysMigrationName         = "LedgerKeyTransactionsTable"
+	resultHashMigrationName         = "TransactionResultHashColumn"
+	transactionFiltersMigrationName = "TransactionFilters"
+	ledgerHashMigrationName         = "LedgerHashColumn"
 )
 
 type LedgerSeqRange struct {
@@ -208,8 +215,15 @@ func BuildMigrations(
 	// Add new DB migrations here:
 	//
 	currentMigrations := map[string]migrationApplierF{
-		transactionsMigrationName: newTransactionTableMigration,
-		eventsMigrationName:       newEventTableMigration,
+		transactionsMigrationName:       newTransactionTableMigration,
+		eventsMigrationName:             newEventTableMigration,
+		ledgerCloseTimeMigrationName:    newLedgerCloseTimeMigration,
+		transactionMemoMigrationName:    newTransactionMemoMigration,
+		contractCreationsMigrationName:  newContractCreationMigration,
+		ledgerKeysMigrationName:         newLedgerKeyTransactionMigration,
+		resultHashMigrationName:         newTransactionResultHashMigration,
+		transactionFiltersMigrationName: newTransactionFiltersMigration,
+		ledgerHashMigrationName:         newLedgerHashMigration,
 	}
 
 	migrations := make([]Migration, 0, len(currentMigrations))
@@ -77,6 +77,29 @@ func (txn *MockTransactionHandler) GetTransaction(_ context.Context, hash xdr.Ha
 	return itx, err
 }
 
+func (txn *MockTransactionHandler) GetTransactionByResultHash(ctx context.Context, resultHash xdr.Hash) (
+	Transaction, error,
+) {
+	for _, tx := range txn.txs {
+		if hash, err := HashTransactionResult(tx.Result.Result); err == nil && hash == resultHash {
+			return txn.GetTransaction(ctx, tx.Result.TransactionHash)
+		}
+	}
+	return Transaction{}, ErrNoTransaction
+}
+
+func (txn *MockTransactionHandler) CountTransactions(_ context.Context, startLedger uint32, endLedger uint32) (
+	uint64, error,
+) {
+	var count uint64
+	for sequence, lcm := range txn.ledgerSeqToMeta {
+		if sequence >= startLedger && sequence <= endLedger {
+			count += uint64(lcm.CountTransactions())
+		}
+	}
+	return count, nil
+}
+
 func (txn *MockTransactionHandler) RegisterMetrics(_, _ prometheus.Observer) {}
 
 type MockLedgerReader struct {
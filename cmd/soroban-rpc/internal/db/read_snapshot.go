@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/support/db"
+)
+
+// ReadSnapshotter opens read snapshots of the database.
+type ReadSnapshotter interface {
+	// WithReadSnapshot returns a context in which all the reads of the database see the same
+	// consistent view, regardless of the ledgers ingested or trimmed concurrently, and a
+	// function releasing the snapshot once the reads are done.
+	WithReadSnapshot(ctx context.Context) (context.Context, func(), error)
+}
+
+// readSnapshotKey is the context key of the read snapshots (see DB.WithReadSnapshot).
+type readSnapshotKey struct{}
+
+type readSnapshot struct {
+	db      *DB
+	session db.SessionInterface
+}
+
+// WithReadSnapshot opens a read transaction, which sees the database as of its first read in
+// WAL mode: the snapshot isn't pinned before the reads need it. The snapshot must be released
+// promptly, since it holds a connection and the WAL frames written after it can't be
+// checkpointed until then.
+func (d *DB) WithReadSnapshot(ctx context.Context) (context.Context, func(), error) {
+	session := d.SessionInterface.Clone()
+	if err := session.Begin(ctx); err != nil {
+		return ctx, nil, err
+	}
+	release := func() {
+		_ = session.Rollback()
+	}
+	return context.WithValue(ctx, readSnapshotKey{}, readSnapshot{db: d, session: session}), release, nil
+}
+
+// session returns the session serving the reads in the context: its read snapshot of the
+// database, if any.
+func (d *DB) session(ctx context.Context) db.SessionInterface {
+	if snapshot, ok := ctx.Value(readSnapshotKey{}).(readSnapshot); ok && snapshot.db == d {
+		return snapshot.session
+	}
+	return d.SessionInterface
+}
+
+// inReadSnapshot tells whether the reads in the context see a read snapshot of the database.
+func (d *DB) inReadSnapshot(ctx context.Context) bool {
+	snapshot, ok := ctx.Value(readSnapshotKey{}).(readSnapshot)
+	return ok && snapshot.db == d
+}
+
+func (d *DB) Get(ctx context.Context, dest interface{}, query sq.Sqlizer) error {
+	return d.session(ctx).Get(ctx, dest, query)
+}
+
+func (d *DB) GetRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.session(ctx).GetRaw(ctx, dest, query, args...)
+}
+
+func (d *DB) Select(ctx context.Context, dest interface{}, query sq.Sqlizer) error {
+	return d.session(ctx).Select(ctx, dest, query)
+}
+
+func (d *DB) SelectRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.session(ctx).SelectRaw(ctx, dest, query, args...)
+}
+
+func (d *DB) Query(ctx context.Context, query sq.Sqlizer) (*db.Rows, error) {
+	return d.session(ctx).Query(ctx, query)
+}
+
+func (d *DB) QueryRaw(ctx context.Context, query string, args ...interface{}) (*db.Rows, error) {
+	return d.session(ctx).QueryRaw(ctx, query, args...)
+}
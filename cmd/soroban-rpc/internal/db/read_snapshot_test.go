@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+// ingestLedger is like ingestTransactions, but can be called outside of the test goroutine.
+func ingestLedger(ctx context.Context, db *DB, lcm xdr.LedgerCloseMeta) error {
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 1_000_000, passphrase)
+	write, err := writer.NewTx(ctx)
+	if err != nil {
+		return err
+	}
+	if err := write.LedgerWriter().InsertLedger(lcm); err != nil {
+		return err
+	}
+	if err := write.TransactionWriter().InsertTransactions(lcm); err != nil {
+		return err
+	}
+	return write.Commit(lcm)
+}
+
+func TestReadSnapshotConcurrentTrim(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestDB(t)
+	ingestTransactions(t, db, []xdr.LedgerCloseMeta{txMeta(1, true), txMeta(2, true), txMeta(3, true)})
+	ledgerReader := NewLedgerReader(db)
+	txReader := NewTransactionReader(log.DefaultLogger, db, passphrase)
+
+	snapshotCtx, release, err := db.WithReadSnapshot(ctx)
+	require.NoError(t, err)
+	ledgerRange, err := ledgerReader.GetLedgerRange(snapshotCtx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), ledgerRange.FirstLedger.Sequence)
+
+	// trim the oldest ledger and ingest a new one in the middle of the reads
+	_, err = db.Exec(ctx, sq.Delete(ledgerCloseMetaTableName).Where(sq.Eq{"sequence": 101}))
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, sq.Delete(transactionTableName).Where(sq.Eq{"ledger_sequence": 101}))
+	require.NoError(t, err)
+	require.NoError(t, ingestLedger(ctx, db, txMeta(4, true)))
+
+	// the snapshot still sees the trimmed ledger, and not the new one
+	tx, err := txReader.GetTransaction(snapshotCtx, txHash(1))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), tx.Ledger.Sequence)
+	_, err = txReader.GetTransaction(snapshotCtx, txHash(4))
+	require.ErrorIs(t, err, ErrNoTransaction)
+	ledgerRange, err = ledgerReader.GetLedgerRange(snapshotCtx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), ledgerRange.FirstLedger.Sequence)
+	assert.Equal(t, uint32(103), ledgerRange.LastLedger.Sequence)
+
+	// whereas the reads outside of the snapshot see the trim
+	_, err = txReader.GetTransaction(ctx, txHash(1))
+	require.ErrorIs(t, err, ErrNoTransaction)
+	release()
+	ledgerRange, err = ledgerReader.GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(102), ledgerRange.FirstLedger.Sequence)
+	assert.Equal(t, uint32(104), ledgerRange.LastLedger.Sequence)
+}
+
+func TestReadSnapshotIngestionLatency(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestDB(t)
+	ingestTransactions(t, db, []xdr.LedgerCloseMeta{txMeta(1, true)})
+	ledgerReader := NewLedgerReader(db)
+
+	// long-lived readers, e.g. slow requests
+	for range 3 {
+		snapshotCtx, release, err := db.WithReadSnapshot(ctx)
+		require.NoError(t, err)
+		defer release()
+		_, err = ledgerReader.GetLedgerRange(snapshotCtx)
+		require.NoError(t, err)
+	}
+
+	// the ingestion doesn't wait for the readers (i.e. for the busy timeout of the checkpoints)
+	for i := uint32(2); i <= 5; i++ {
+		start := time.Now()
+		require.NoError(t, ingestLedger(ctx, db, txMeta(i, true)))
+		assert.Less(t, time.Since(start), time.Second, "ingesting ledger %d", i+100)
+	}
+	ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(105), ledgerRange.LastLedger.Sequence)
+}
+
+func TestReadSnapshotCheckpointProgress(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestDB(t)
+	ingestTransactions(t, db, []xdr.LedgerCloseMeta{txMeta(1, true)})
+	ledgerReader := NewLedgerReader(db)
+	checkpoint := func() (int, int) {
+		var result struct {
+			Busy         int `db:"busy"`
+			Log          int `db:"log"`
+			Checkpointed int `db:"checkpointed"`
+		}
+		require.NoError(t, db.GetRaw(ctx, &result, "PRAGMA wal_checkpoint(PASSIVE)"))
+		assert.Zero(t, result.Busy)
+		return result.Log, result.Checkpointed
+	}
+	openSnapshot := func() func() {
+		snapshotCtx, release, err := db.WithReadSnapshot(ctx)
+		require.NoError(t, err)
+		// the snapshot is pinned by its first read
+		_, err = ledgerReader.GetLedgerRange(snapshotCtx)
+		require.NoError(t, err)
+		return release
+	}
+
+	// requests keep overlapping snapshots open while ledgers are ingested
+	release := openSnapshot()
+	for i := uint32(2); i <= 4; i++ {
+		require.NoError(t, ingestLedger(ctx, db, txMeta(i, true)))
+	}
+	pinnedFrames, _ := checkpoint()
+	require.Positive(t, pinnedFrames)
+	releaseNext := openSnapshot()
+	release()
+
+	// the frames written before the oldest open snapshot are checkpointed
+	for i := uint32(5); i <= 6; i++ {
+		require.NoError(t, ingestLedger(ctx, db, txMeta(i, true)))
+		frames, checkpointed := checkpoint()
+		assert.GreaterOrEqual(t, checkpointed, pinnedFrames)
+		assert.Less(t, checkpointed, frames)
+	}
+
+	// and the whole WAL once the snapshots are released
+	releaseNext()
+	require.NoError(t, ingestLedger(ctx, db, txMeta(7, true)))
+	frames, checkpointed := checkpoint()
+	assert.Equal(t, frames, checkpointed)
+}
@@ -0,0 +1,249 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/xdr"
+)
+
+// snapshotLedgerEntriesBatchSize is the number of ledger entries read from a snapshot at once.
+const snapshotLedgerEntriesBatchSize = 1000
+
+// ErrInvalidSnapshot is returned when a snapshot can't be restored, e.g. because its ledgers
+// aren't contiguous.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// ErrDatabaseNotEmpty is returned when restoring a snapshot into a database which already
+// stores ledgers or ledger entries.
+var ErrDatabaseNotEmpty = errors.New("snapshots can only be restored into an empty database")
+
+// SnapshotRestorer bootstraps an empty database from snapshots: database backups (see
+// DB.Backup), e.g. of another node. Their ledger entries are copied, and their ledgers are
+// replayed into the database like ingested ones, so that the transactions and events are
+// indexed too, which is much faster than catching up ledger by ledger. It is meant for the
+// provisioning of nodes, and ingestion must be stopped while restoring.
+type SnapshotRestorer struct {
+	lock       sync.Mutex
+	readWriter ReadWriter
+	db         *DB
+	// onLedger, if set, is called with every restored ledger, e.g. to fill in-memory caches.
+	onLedger func(xdr.LedgerCloseMeta) error
+}
+
+// NewSnapshotRestorer returns a restorer writing into db through readWriter. onLedger, if
+// set, is called with every restored ledger.
+func NewSnapshotRestorer(readWriter ReadWriter, db *DB, onLedger func(xdr.LedgerCloseMeta) error) *SnapshotRestorer {
+	return &SnapshotRestorer{
+		readWriter: readWriter,
+		db:         db,
+		onLedger:   onLedger,
+	}
+}
+
+// openSnapshot opens the snapshot at path read-only, decoding its ledgers with the codec
+// recorded in it.
+func openSnapshot(ctx context.Context, path string) (*DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	session, err := db.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return nil, fmt.Errorf("open failed: %w", err)
+	}
+	codecName, err := getMetaValue(ctx, session, ledgerCodecMetaKey)
+	if errors.Is(err, ErrEmptyDB) {
+		// the databases predating the codecs store plain XDR
+		codecName = LedgerCodecXDR
+	} else if err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("%w: could not get its ledger codec: %w", ErrInvalidSnapshot, err)
+	}
+	codec, err := NewLedgerCloseMetaCodec(codecName)
+	if err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	return &DB{
+		SessionInterface: session,
+		cache:            &dbCache{ledgerEntries: newTransactionalCache()},
+		codec:            codec,
+	}, nil
+}
+
+// snapshotRange returns the range of the ledgers of a snapshot, checking that they're contiguous.
+func snapshotRange(ctx context.Context, snapshot *DB) (LedgerSeqRange, error) {
+	ledgerRange, err := NewLedgerReader(snapshot).GetLedgerRange(ctx)
+	if errors.Is(err, ErrEmptyDB) {
+		return LedgerSeqRange{}, fmt.Errorf("%w: it has no ledgers", ErrInvalidSnapshot)
+	} else if err != nil {
+		return LedgerSeqRange{}, err
+	}
+	first, last := ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence
+	var counts []uint32
+	if err := snapshot.Select(ctx, &counts, sq.Select("COUNT(*)").From(ledgerCloseMetaTableName)); err != nil {
+		return LedgerSeqRange{}, err
+	}
+	if len(counts) != 1 || counts[0] != last-first+1 {
+		return LedgerSeqRange{}, fmt.Errorf("%w: its ledgers [%d, %d] aren't contiguous", ErrInvalidSnapshot,
+			first, last)
+	}
+	return LedgerSeqRange{First: first, Last: last}, nil
+}
+
+// checkEmpty returns ErrDatabaseNotEmpty unless the database stores no ledgers, no ledger
+// entries and no ingestion checkpoint.
+func (r *SnapshotRestorer) checkEmpty(ctx context.Context) error {
+	_, err := NewLedgerReader(r.db).GetLedgerRange(ctx)
+	if err == nil {
+		return ErrDatabaseNotEmpty
+	} else if !errors.Is(err, ErrEmptyDB) {
+		return err
+	}
+	_, err = r.db.GetIngestionCheckpoint(ctx)
+	if err == nil {
+		return ErrDatabaseNotEmpty
+	} else if !errors.Is(err, ErrEmptyDB) {
+		return err
+	}
+	var keys []string
+	if err := r.db.Select(ctx, &keys, sq.Select("key").From(ledgerEntriesTableName).Limit(1)); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return ErrDatabaseNotEmpty
+	}
+	return nil
+}
+
+// RestoreFromSnapshot restores the snapshot at path into the database, which must be empty,
+// returning the range of the restored ledgers. The ledger entries and the ledgers are written
+// within a single write transaction, so that the latest ledger and the ingestion checkpoint
+// only advance along with the ledger entries. Ingestion must be stopped meanwhile, and resumed
+// after the latest restored ledger.
+func (r *SnapshotRestorer) RestoreFromSnapshot(ctx context.Context, path string) (LedgerSeqRange, error) {
+	if !r.lock.TryLock() {
+		return LedgerSeqRange{}, errors.New("a snapshot is being restored already")
+	}
+	defer r.lock.Unlock()
+
+	snapshot, err := openSnapshot(ctx, path)
+	if err != nil {
+		return LedgerSeqRange{}, err
+	}
+	defer snapshot.Close()
+	restored, err := snapshotRange(ctx, snapshot)
+	if err != nil {
+		return LedgerSeqRange{}, err
+	}
+	// the ledger entries of the snapshot must be those of its latest ledger
+	checkpoint, err := snapshot.GetIngestionCheckpoint(ctx)
+	switch {
+	case errors.Is(err, ErrEmptyDB):
+		// the databases predating the checkpoint commit the ledger entries along with the ledgers
+	case err != nil:
+		return LedgerSeqRange{}, err
+	case checkpoint != restored.Last:
+		return LedgerSeqRange{}, fmt.Errorf("%w: its ingestion checkpoint %d isn't its latest ledger %d",
+			ErrInvalidSnapshot, checkpoint, restored.Last)
+	}
+	if err := r.checkEmpty(ctx); err != nil {
+		return LedgerSeqRange{}, err
+	}
+
+	tx, err := r.readWriter.NewTx(ctx)
+	if err != nil {
+		return LedgerSeqRange{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := restoreLedgerEntries(ctx, snapshot, tx.LedgerEntryWriter()); err != nil {
+		return LedgerSeqRange{}, fmt.Errorf("could not restore the ledger entries: %w", err)
+	}
+	latest, err := r.restoreLedgers(ctx, NewLedgerReader(snapshot), tx, restored)
+	if err != nil {
+		return LedgerSeqRange{}, fmt.Errorf("could not restore ledgers [%d, %d]: %w", restored.First,
+			restored.Last, err)
+	}
+	if err := tx.Commit(latest); err != nil {
+		return LedgerSeqRange{}, err
+	}
+	return restored, nil
+}
+
+// restoreLedgerEntries copies the ledger entries of the snapshot, in batches of keys.
+func restoreLedgerEntries(ctx context.Context, snapshot *DB, writer LedgerEntryWriter) error {
+	type keyAndEntry struct {
+		Key   string `db:"key"`
+		Entry string `db:"entry"`
+	}
+	var lastKey *string
+	for {
+		query := sq.Select("key", "entry").From(ledgerEntriesTableName).
+			OrderBy("key").
+			Limit(snapshotLedgerEntriesBatchSize)
+		if lastKey != nil {
+			query = query.Where(sq.Gt{"key": *lastKey})
+		}
+		var batch []keyAndEntry
+		if err := snapshot.Select(ctx, &batch, query); err != nil {
+			return err
+		}
+		for _, row := range batch {
+			var entry xdr.LedgerEntry
+			if err := xdr.SafeUnmarshal([]byte(row.Entry), &entry); err != nil {
+				return fmt.Errorf("%w: could not decode a ledger entry: %w", ErrInvalidSnapshot, err)
+			}
+			if err := writer.UpsertLedgerEntry(entry); err != nil {
+				return err
+			}
+		}
+		if len(batch) < snapshotLedgerEntriesBatchSize {
+			return nil
+		}
+		lastKey = &batch[len(batch)-1].Key
+	}
+}
+
+// restoreLedgers replays the ledgers of the snapshot within tx, returning the latest one.
+func (r *SnapshotRestorer) restoreLedgers(ctx context.Context, snapshotReader LedgerReader, tx WriteTx,
+	ledgers LedgerSeqRange,
+) (xdr.LedgerCloseMeta, error) {
+	expected := ledgers.First
+	var latest xdr.LedgerCloseMeta
+	err := snapshotReader.StreamLedgerRange(ctx, ledgers.First, ledgers.Last, func(ledger xdr.LedgerCloseMeta) error {
+		if ledger.LedgerSequence() != expected {
+			return fmt.Errorf("%w: ledger %d follows ledger %d", ErrInvalidSnapshot, ledger.LedgerSequence(),
+				expected-1)
+		}
+		expected++
+		if err := tx.LedgerWriter().InsertLedger(ledger); err != nil {
+			return err
+		}
+		if err := tx.TransactionWriter().InsertTransactions(ledger); err != nil {
+			return err
+		}
+		if err := tx.EventWriter().InsertEvents(ledger); err != nil {
+			return err
+		}
+		if r.onLedger != nil {
+			if err := r.onLedger(ledger); err != nil {
+				return err
+			}
+		}
+		latest = ledger
+		return nil
+	})
+	if err != nil {
+		return xdr.LedgerCloseMeta{}, err
+	}
+	if expected != ledgers.Last+1 {
+		return xdr.LedgerCloseMeta{}, fmt.Errorf("%w: ledger %d is missing", ErrInvalidSnapshot, expected)
+	}
+	return latest, nil
+}
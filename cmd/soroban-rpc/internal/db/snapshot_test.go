@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+// backupLedgers returns the path of a snapshot storing the given ledgers.
+func backupLedgers(t *testing.T, lcms []xdr.LedgerCloseMeta) string {
+	return backupLedgersAndEntries(t, lcms, nil)
+}
+
+// backupLedgersAndEntries returns the path of a snapshot storing the given ledgers and
+// ledger entries.
+func backupLedgersAndEntries(t *testing.T, lcms []xdr.LedgerCloseMeta, entries []xdr.LedgerEntry) string {
+	db := NewTestDB(t)
+	ingestLedgers(t, db, lcms, entries)
+	snapshotPath := path.Join(t.TempDir(), "snapshot.sqlite")
+	_, err := db.Backup(context.Background(), snapshotPath)
+	require.NoError(t, err)
+	return snapshotPath
+}
+
+func assertStoredRange(t *testing.T, db *DB, first, last uint32) {
+	ledgerRange, err := NewLedgerReader(db).GetLedgerRange(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, ledgerRange.FirstLedger.Sequence)
+	assert.Equal(t, last, ledgerRange.LastLedger.Sequence)
+}
+
+// ingestLedgers writes the ledgers, with their transactions and events, and the ledger
+// entries like ingestion does.
+func ingestLedgers(t *testing.T, db *DB, lcms []xdr.LedgerCloseMeta, entries []xdr.LedgerEntry) {
+	rw := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 1_000_000, passphrase)
+	tx, err := rw.NewTx(context.Background())
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, tx.LedgerEntryWriter().UpsertLedgerEntry(entry))
+	}
+	for _, lcm := range lcms {
+		require.NoError(t, tx.LedgerWriter().InsertLedger(lcm))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(lcm))
+		require.NoError(t, tx.EventWriter().InsertEvents(lcm))
+	}
+	require.NoError(t, tx.Commit(lcms[len(lcms)-1]))
+}
+
+func newTestSnapshotRestorer(db *DB, onLedger func(xdr.LedgerCloseMeta) error) *SnapshotRestorer {
+	rw := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 1_000_000, passphrase)
+	return NewSnapshotRestorer(rw, db, onLedger)
+}
+
+// contractDataEntries returns the keys of count distinct contract data entries, and the
+// entries followed by their TTL entries.
+func contractDataEntries(t *testing.T, count int) ([]xdr.LedgerKey, []xdr.LedgerEntry) {
+	keys := make([]xdr.LedgerKey, 0, count)
+	entries := make([]xdr.LedgerEntry, count, 2*count)
+	for i := 0; i < count; i++ {
+		data := createTestContractDataEntry()
+		index := xdr.Uint32(i)
+		data.Key = xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &index}
+		key, entry := getContractDataLedgerEntry(t, data)
+		ttlKey, err := entryKeyToTTLEntryKey(key)
+		require.NoError(t, err)
+		keys = append(keys, key)
+		entries[i] = entry
+		entries = append(entries, getTTLLedgerEntry(ttlKey))
+	}
+	return keys, entries
+}
+
+func TestRestoreFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	var lcms []xdr.LedgerCloseMeta
+	for i := uint32(1); i <= 5; i++ {
+		lcms = append(lcms, txMeta(i, true))
+	}
+	snapshotPath := backupLedgers(t, lcms)
+
+	// bootstrapping an empty database
+	db := NewTestDB(t)
+	var ingested []uint32
+	restorer := newTestSnapshotRestorer(db, func(lcm xdr.LedgerCloseMeta) error {
+		ingested = append(ingested, lcm.LedgerSequence())
+		return nil
+	})
+	restored, err := restorer.RestoreFromSnapshot(ctx, snapshotPath)
+	require.NoError(t, err)
+	assert.Equal(t, LedgerSeqRange{First: 101, Last: 105}, restored)
+	assert.Equal(t, []uint32{101, 102, 103, 104, 105}, ingested)
+	assertStoredRange(t, db, 101, 105)
+	ledgerRange, err := NewLedgerReader(db).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ledgerCloseTime(105), ledgerRange.LastLedger.CloseTime)
+	checkpoint, err := db.GetIngestionCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(105), checkpoint)
+	tx, err := NewTransactionReader(log.DefaultLogger, db, passphrase).GetTransaction(ctx, txHash(3))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(103), tx.Ledger.Sequence)
+
+	// the database isn't empty anymore
+	_, err = restorer.RestoreFromSnapshot(ctx, snapshotPath)
+	require.ErrorIs(t, err, ErrDatabaseNotEmpty)
+
+	// a database with ledgers can't be extended
+	db = NewTestDB(t)
+	ingestTransactions(t, db, lcms[:3])
+	_, err = newTestSnapshotRestorer(db, nil).RestoreFromSnapshot(ctx, snapshotPath)
+	require.ErrorIs(t, err, ErrDatabaseNotEmpty)
+	assertStoredRange(t, db, 101, 103)
+
+	// neither can a database with ledger entries only, e.g. filled from a checkpoint
+	db = NewTestDB(t)
+	_, entries := contractDataEntries(t, 1)
+	rw := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 1_000_000, passphrase)
+	writeTx, err := rw.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, writeTx.LedgerEntryWriter().UpsertLedgerEntry(entries[0]))
+	require.NoError(t, writeTx.Commit(txMeta(0, true)))
+	_, err = db.Exec(ctx, sq.Delete(metaTableName).Where(sq.Eq{"key": ingestionCheckpointMetaKey}))
+	require.NoError(t, err)
+	_, err = newTestSnapshotRestorer(db, nil).RestoreFromSnapshot(ctx, snapshotPath)
+	require.ErrorIs(t, err, ErrDatabaseNotEmpty)
+
+	_, err = newTestSnapshotRestorer(db, nil).RestoreFromSnapshot(ctx, path.Join(t.TempDir(), "missing.sqlite"))
+	require.Error(t, err)
+}
+
+func TestRestoreFromSnapshotNotContiguous(t *testing.T) {
+	ctx := context.Background()
+	source := NewTestDB(t)
+	ingestTransactions(t, source, []xdr.LedgerCloseMeta{txMeta(1, true), txMeta(2, true), txMeta(3, true)})
+	_, err := source.Exec(ctx, sq.Delete(ledgerCloseMetaTableName).Where(sq.Eq{"sequence": 102}))
+	require.NoError(t, err)
+	snapshotPath := path.Join(t.TempDir(), "snapshot.sqlite")
+	_, err = source.Backup(ctx, snapshotPath)
+	require.NoError(t, err)
+
+	db := NewTestDB(t)
+	_, err = newTestSnapshotRestorer(db, nil).RestoreFromSnapshot(ctx, snapshotPath)
+	require.ErrorIs(t, err, ErrInvalidSnapshot)
+	_, err = NewLedgerReader(db).GetLedgerRange(ctx)
+	require.ErrorIs(t, err, ErrEmptyDB)
+}
+
+func TestRestoreFromSnapshotLedgerEntries(t *testing.T) {
+	ctx := context.Background()
+	// more entries than restored at once
+	keys, entries := contractDataEntries(t, snapshotLedgerEntriesBatchSize+10)
+	snapshotPath := backupLedgersAndEntries(t,
+		[]xdr.LedgerCloseMeta{txMeta(1, true), txMeta(2, true), txMeta(3, true)}, entries)
+
+	db := NewTestDB(t)
+	restored, err := newTestSnapshotRestorer(db, nil).RestoreFromSnapshot(ctx, snapshotPath)
+	require.NoError(t, err)
+	assert.Equal(t, LedgerSeqRange{First: 101, Last: 103}, restored)
+	for i, key := range keys {
+		present, entry, latest, _ := getLedgerEntryAndLatestLedgerSequence(t, db, key)
+		require.True(t, present)
+		assert.Equal(t, entries[i], entry)
+		assert.Equal(t, uint32(103), latest)
+	}
+}
+
+func TestRestoreFromSnapshotThenIngest(t *testing.T) {
+	ctx := context.Background()
+	keys, entries := contractDataEntries(t, 2)
+	snapshotPath := backupLedgersAndEntries(t,
+		[]xdr.LedgerCloseMeta{txMeta(1, true), txMeta(2, true)}, entries)
+
+	db := NewTestDB(t)
+	_, err := newTestSnapshotRestorer(db, nil).RestoreFromSnapshot(ctx, snapshotPath)
+	require.NoError(t, err)
+
+	// ingestion resumes after the latest restored ledger, updating a restored entry
+	updated := entries[0]
+	updatedData := *updated.Data.ContractData
+	seven := xdr.Uint32(7)
+	updatedData.Val = xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &seven}
+	updated.Data.ContractData = &updatedData
+	updated.LastModifiedLedgerSeq = 103
+	ingestLedgers(t, db, []xdr.LedgerCloseMeta{txMeta(3, true)}, []xdr.LedgerEntry{updated})
+
+	assertStoredRange(t, db, 101, 103)
+	checkpoint, err := db.GetIngestionCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(103), checkpoint)
+	present, entry, latest, _ := getLedgerEntryAndLatestLedgerSequence(t, db, keys[0])
+	require.True(t, present)
+	assert.Equal(t, updated, entry)
+	assert.Equal(t, uint32(103), latest)
+	present, entry, _, _ = getLedgerEntryAndLatestLedgerSequence(t, db, keys[1])
+	require.True(t, present)
+	assert.Equal(t, entries[1], entry)
+	tx, err := NewTransactionReader(log.DefaultLogger, db, passphrase).GetTransaction(ctx, txHash(3))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(103), tx.Ledger.Sequence)
+}
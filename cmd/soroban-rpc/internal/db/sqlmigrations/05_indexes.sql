@@ -0,0 +1,26 @@
+-- +migrate Up
+
+-- source account of the transactions (of the inner transaction for fee bumps), backing the
+-- lookup of transactions by source account. It is filled in for the transactions stored before
+-- this migration by the TransactionFilters data migration.
+ALTER TABLE transactions ADD COLUMN source_account BLOB;
+CREATE INDEX IF NOT EXISTS idx_transactions_source_account
+    ON transactions (source_account, ledger_sequence, application_order);
+
+-- operation types of the transactions, backing the lookup of transactions by operation type.
+-- It is filled in for the ledgers stored before this migration by the TransactionFilters data
+-- migration.
+CREATE TABLE transaction_operation_types (
+    operation_type INTEGER NOT NULL,
+    ledger_sequence INTEGER NOT NULL,
+    application_order INTEGER NOT NULL,
+    PRIMARY KEY (operation_type, ledger_sequence, application_order)
+) WITHOUT ROWID;
+CREATE INDEX IF NOT EXISTS idx_transaction_operation_types_ledger_sequence
+    ON transaction_operation_types (ledger_sequence);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transaction_operation_types_ledger_sequence;
+DROP TABLE transaction_operation_types;
+DROP INDEX IF EXISTS idx_transactions_source_account;
+ALTER TABLE transactions DROP COLUMN source_account;
@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- close time of the ledgers, backing time-based retention. It is filled in for the
+-- ledgers stored before this migration by the LedgerCloseTimeColumn data migration.
+ALTER TABLE ledger_close_meta ADD COLUMN close_time INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_ledger_close_meta_close_time ON ledger_close_meta (close_time);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_ledger_close_meta_close_time;
+ALTER TABLE ledger_close_meta DROP COLUMN close_time;
@@ -0,0 +1,14 @@
+-- +migrate Up
+
+-- memo of the transactions (of the inner transaction for fee bumps), backing the lookup of
+-- transactions by memo. It is filled in for the transactions stored before this migration
+-- by the TransactionMemoColumns data migration.
+ALTER TABLE transactions ADD COLUMN memo_type INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN memo_value BLOB;
+CREATE INDEX IF NOT EXISTS idx_transactions_memo
+    ON transactions (memo_type, memo_value, ledger_sequence, application_order);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_memo;
+ALTER TABLE transactions DROP COLUMN memo_value;
+ALTER TABLE transactions DROP COLUMN memo_type;
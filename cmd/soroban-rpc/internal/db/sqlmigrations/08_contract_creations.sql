@@ -0,0 +1,16 @@
+-- +migrate Up
+
+-- transactions which created contracts, backing the lookup of the creation of contracts.
+-- It is filled in for the ledgers stored before this migration by the ContractCreationsTable
+-- data migration.
+CREATE TABLE contract_creations (
+    contract_id BLOB NOT NULL PRIMARY KEY,
+    transaction_hash BLOB NOT NULL,
+    ledger_sequence INTEGER NOT NULL,
+    application_order INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_contract_creations_ledger_sequence ON contract_creations (ledger_sequence);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_contract_creations_ledger_sequence;
+DROP TABLE contract_creations;
@@ -0,0 +1,19 @@
+-- +migrate Up
+
+-- transactions which created, updated or removed each ledger entry, backing the lookup of
+-- transactions by ledger key. Keys are stored as the SHA-256 hash of their XDR, which bounds
+-- the size of the rows regardless of the size of the keys (e.g. of contract data). It is
+-- filled in for the ledgers stored before this migration by the LedgerKeyTransactionsTable
+-- data migration.
+CREATE TABLE ledger_key_transactions (
+    key_hash BLOB NOT NULL,
+    ledger_sequence INTEGER NOT NULL,
+    application_order INTEGER NOT NULL,
+    PRIMARY KEY (key_hash, ledger_sequence, application_order)
+) WITHOUT ROWID;
+CREATE INDEX IF NOT EXISTS idx_ledger_key_transactions_ledger_sequence
+    ON ledger_key_transactions (ledger_sequence);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_ledger_key_transactions_ledger_sequence;
+DROP TABLE ledger_key_transactions;
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- SHA-256 hash of the TransactionResult XDR of the transactions, backing the lookup of
+-- transactions by result hash. It is filled in for the transactions stored before this
+-- migration by the TransactionResultHashColumn data migration.
+ALTER TABLE transactions ADD COLUMN result_hash BLOB;
+CREATE INDEX IF NOT EXISTS idx_transactions_result_hash ON transactions (result_hash);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_result_hash;
+ALTER TABLE transactions DROP COLUMN result_hash;
@@ -0,0 +1,9 @@
+-- +migrate Up
+
+-- hash of the ledgers, against which the ledgers ingested again are compared to detect reorgs
+-- without decoding the stored ledgers. It is filled in for the ledgers stored before this
+-- migration by the LedgerHashColumn data migration.
+ALTER TABLE ledger_close_meta ADD COLUMN hash BLOB;
+
+-- +migrate Down
+ALTER TABLE ledger_close_meta DROP COLUMN hash;
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// StoreStats describes the disk usage of the database.
+type StoreStats struct {
+	// TotalSize is the size in bytes of the used database pages.
+	TotalSize int64
+	// LedgerCloseMetaSize is the size in bytes of the ledger_close_meta table. It is
+	// only available if SQLite was built with the dbstat virtual table.
+	LedgerCloseMetaSize *int64
+	// StoredLedgers is the number of ledgers in the database.
+	StoredLedgers uint32
+	// Indexes are the indexes present in the database.
+	Indexes []IndexInfo
+}
+
+func (d *DB) pragmaInt(ctx context.Context, pragma string) (int64, error) {
+	var value int64
+	if err := d.GetRaw(ctx, &value, "PRAGMA "+pragma); err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+// tableSize returns the size in bytes of the given table using the dbstat virtual
+// table. It returns false if dbstat isn't available.
+func (d *DB) tableSize(ctx context.Context, table string) (int64, bool, error) {
+	query := sq.Select("COALESCE(SUM(pgsize), 0)").From("dbstat").Where(sq.Eq{"name": table})
+	var size int64
+	if err := d.Get(ctx, &size, query); err != nil {
+		if strings.Contains(err.Error(), "no such table: dbstat") {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return size, true, nil
+}
+
+// GetStoreStats returns the disk usage of the database. It only relies on
+// metadata (page counts), so it is cheap to compute regardless of the database size.
+func (d *DB) GetStoreStats(ctx context.Context) (StoreStats, error) {
+	var stats StoreStats
+	pageSize, err := d.pragmaInt(ctx, "page_size")
+	if err != nil {
+		return StoreStats{}, err
+	}
+	pageCount, err := d.pragmaInt(ctx, "page_count")
+	if err != nil {
+		return StoreStats{}, err
+	}
+	freePages, err := d.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return StoreStats{}, err
+	}
+	stats.TotalSize = (pageCount - freePages) * pageSize
+
+	size, ok, err := d.tableSize(ctx, ledgerCloseMetaTableName)
+	if err != nil {
+		return StoreStats{}, err
+	}
+	if ok {
+		stats.LedgerCloseMetaSize = &size
+	}
+
+	ledgerRange, err := NewLedgerReader(d).GetLedgerRange(ctx)
+	if err != nil && !errors.Is(err, ErrEmptyDB) {
+		return StoreStats{}, err
+	}
+	if err == nil {
+		stats.StoredLedgers = ledgerRange.LastLedger.Sequence - ledgerRange.FirstLedger.Sequence + 1
+	}
+
+	if stats.Indexes, err = GetIndexes(ctx, d); err != nil {
+		return StoreStats{}, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestGetStoreStats(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+
+	stats, err := db.GetStoreStats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.StoredLedgers)
+	emptySize := stats.TotalSize
+	assert.Positive(t, emptySize)
+	assert.Len(t, stats.Indexes, len(expectedIndexes))
+
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+	for i := uint32(1); i <= 10; i++ {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		ledger := createLedger(i)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	stats, err = db.GetStoreStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), stats.StoredLedgers)
+	assert.GreaterOrEqual(t, stats.TotalSize, emptySize)
+	if stats.LedgerCloseMetaSize != nil {
+		assert.Positive(t, *stats.LedgerCloseMetaSize)
+		assert.LessOrEqual(t, *stats.LedgerCloseMetaSize, stats.TotalSize)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrfailures"
 )
 
 const (
@@ -45,19 +46,30 @@ type TransactionWriter interface {
 // TransactionReader provides all the public ways to read from the DB.
 type TransactionReader interface {
 	GetTransaction(ctx context.Context, hash xdr.Hash) (Transaction, error)
+	// GetTransactionByResultHash looks a transaction up by the hash of its result
+	// (see HashTransactionResult), returning ErrNoTransaction if there is none.
+	GetTransactionByResultHash(ctx context.Context, resultHash xdr.Hash) (Transaction, error)
+	// CountTransactions returns the number of transactions in the ledgers from startLedger
+	// to endLedger (inclusive).
+	CountTransactions(ctx context.Context, startLedger uint32, endLedger uint32) (uint64, error)
 }
 
 type transactionHandler struct {
 	log        *log.Entry
 	db         db.SessionInterface
+	codec      LedgerCloseMetaCodec
 	stmtCache  *sq.StmtCache
 	passphrase string
+	// hashFilter, if set, is consulted before looking up transactions
+	hashFilter *TransactionHashFilter
+	// hashFilterUpdate, if set, accumulates the ingested hashes and trims for the hash filter
+	hashFilterUpdate *hashFilterUpdate
 
 	ingestMetric, countMetric prometheus.Observer
 }
 
-func NewTransactionReader(log *log.Entry, db db.SessionInterface, passphrase string) TransactionReader {
-	return &transactionHandler{log: log, db: db, passphrase: passphrase}
+func NewTransactionReader(log *log.Entry, db *DB, passphrase string) TransactionReader {
+	return &transactionHandler{log: log, db: db, codec: db.codec, passphrase: passphrase, hashFilter: db.txHashFilter}
 }
 
 func (txn *transactionHandler) InsertTransactions(lcm xdr.LedgerCloseMeta) error {
@@ -76,7 +88,12 @@ func (txn *transactionHandler) InsertTransactions(lcm xdr.LedgerCloseMeta) error
 
 	if txn.stmtCache == nil {
 		return errors.New("TransactionWriter incorrectly initialized without stmtCache")
-	} else if txCount == 0 {
+	}
+	// the ledger may be re-ingested or replaced (see ledgerWriter.InsertLedger)
+	if err := deleteLedgerTransactions(txn.stmtCache, lcm.LedgerSequence()); err != nil {
+		return err
+	}
+	if txCount == 0 {
 		return nil
 	}
 
@@ -90,11 +107,13 @@ func (txn *transactionHandler) InsertTransactions(lcm xdr.LedgerCloseMeta) error
 	}
 
 	transactions := make(map[xdr.Hash]ingest.LedgerTransaction, txCount)
+	txs := make([]ingest.LedgerTransaction, 0, txCount)
 	for i := range txCount {
 		tx, err := reader.Read()
 		if err != nil {
 			return fmt.Errorf("failed reading tx %d: %w", i, err)
 		}
+		txs = append(txs, tx)
 
 		// For fee-bump transactions, we store lookup entries for both the outer
 		// and inner hashes.
@@ -105,11 +124,33 @@ func (txn *transactionHandler) InsertTransactions(lcm xdr.LedgerCloseMeta) error
 	}
 
 	query := sq.Insert(transactionTableName).
-		Columns("hash", "ledger_sequence", "application_order")
+		Columns("hash", "ledger_sequence", "application_order", "memo_type", "memo_value", "result_hash",
+			"source_account")
+	hashes := make([]xdr.Hash, 0, len(transactions))
 	for hash, tx := range transactions {
-		query = query.Values(hash[:], lcm.LedgerSequence(), tx.Index)
+		memoType, memoValue := EncodeMemo(tx.Envelope.Memo())
+		resultHash, err := HashTransactionResult(tx.Result.Result)
+		if err != nil {
+			return fmt.Errorf("could not hash the result of tx %d: %w", tx.Index, err)
+		}
+		query = query.Values(hash[:], lcm.LedgerSequence(), tx.Index, memoType, memoValue, resultHash[:],
+			encodeSourceAccount(tx.Envelope.SourceAccount()))
+		hashes = append(hashes, hash)
+	}
+	if txn.hashFilterUpdate != nil {
+		txn.hashFilterUpdate.ledgers = append(txn.hashFilterUpdate.ledgers, lcm.LedgerSequence())
+		txn.hashFilterUpdate.hashes = append(txn.hashFilterUpdate.hashes, hashes)
+	}
+	if _, err = query.RunWith(txn.stmtCache).Exec(); err != nil {
+		return err
 	}
-	_, err = query.RunWith(txn.stmtCache).Exec()
+	if err = insertContractCreations(txn.stmtCache, lcm.LedgerSequence(), txs); err != nil {
+		return err
+	}
+	if err = insertTransactionOperationTypes(txn.stmtCache, lcm.LedgerSequence(), txs); err != nil {
+		return err
+	}
+	err = insertLedgerKeyTransactions(txn.stmtCache, lcm.LedgerSequence(), txs)
 
 	L.WithField("duration", time.Since(start)).
 		Debugf("Ingested %d transaction lookups", len(transactions))
@@ -117,6 +158,29 @@ func (txn *transactionHandler) InsertTransactions(lcm xdr.LedgerCloseMeta) error
 	return err
 }
 
+// transactionTableNames are the tables storing the transactions and their index rows, by ledger.
+var transactionTableNames = []string{
+	transactionTableName,
+	contractCreationTableName,
+	ledgerKeyTransactionTableName,
+	transactionOperationTypeTableName,
+}
+
+// deleteLedgerTransactions removes the transactions of a ledger, along with their index rows.
+func deleteLedgerTransactions(runner sq.BaseRunner, ledgerSeq uint32) error {
+	for _, table := range transactionTableNames {
+		_, err := sq.StatementBuilder.
+			RunWith(runner).
+			Delete(table).
+			Where(sq.Eq{"ledger_sequence": ledgerSeq}).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (txn *transactionHandler) RegisterMetrics(ingest, count prometheus.Observer) {
 	txn.ingestMetric = ingest
 	txn.countMetric = count
@@ -129,12 +193,20 @@ func (txn *transactionHandler) trimTransactions(latestLedgerSeq uint32, retentio
 	}
 
 	cutoff := latestLedgerSeq + 1 - retentionWindow
-	_, err := sq.StatementBuilder.
-		RunWith(txn.stmtCache).
-		Delete(transactionTableName).
-		Where(sq.Lt{"ledger_sequence": cutoff}).
-		Exec()
-	return err
+	for _, table := range transactionTableNames {
+		if _, err := trimTransactionTableQuery(table, cutoff).RunWith(txn.stmtCache).Exec(); err != nil {
+			return err
+		}
+	}
+	if txn.hashFilterUpdate != nil {
+		txn.hashFilterUpdate.cutoff = cutoff
+	}
+	return nil
+}
+
+// trimTransactionTableQuery deletes the rows of one of transactionTableNames before the cutoff ledger.
+func trimTransactionTableQuery(table string, cutoff uint32) sq.DeleteBuilder {
+	return sq.Delete(table).Where(sq.Lt{"ledger_sequence": cutoff})
 }
 
 // GetTransaction conforms to the interface in
@@ -149,7 +221,29 @@ func (txn *transactionHandler) GetTransaction(ctx context.Context, hash xdr.Hash
 	start := time.Now()
 	tx := Transaction{}
 
+	filterPositive := false
+	if txn.hashFilter != nil {
+		mayContain, conclusive := txn.hashFilter.lookup(hash)
+		switch {
+		case mayContain:
+			filterPositive = true
+		case conclusive:
+			txn.hashFilter.recordLookup(hashFilterNegative)
+			return tx, ErrNoTransaction
+		default:
+			txn.hashFilter.recordLookup(hashFilterInconclusive)
+		}
+	}
+
 	lcm, ingestTx, err := txn.getTransactionByHash(ctx, hash)
+	// positive lookups are always verified against the database
+	if filterPositive {
+		if errors.Is(err, ErrNoTransaction) {
+			txn.hashFilter.recordLookup(hashFilterFalsePositive)
+		} else if err == nil {
+			txn.hashFilter.recordLookup(hashFilterPositive)
+		}
+	}
 	if err != nil {
 		return tx, err
 	}
@@ -166,6 +260,26 @@ func (txn *transactionHandler) GetTransaction(ctx context.Context, hash xdr.Hash
 	return tx, nil
 }
 
+// CountTransactions counts the transactions through the ledger sequence index of the
+// transactions table, without reading the ledgers.
+func (txn *transactionHandler) CountTransactions(ctx context.Context, startLedger uint32, endLedger uint32) (
+	uint64, error,
+) {
+	var count []uint64
+	if err := txn.db.Select(ctx, &count, countTransactionsQuery(startLedger, endLedger)); err != nil {
+		return 0, fmt.Errorf("could not count transactions in ledgers [%d, %d]: %w", startLedger, endLedger, err)
+	}
+	return count[0], nil
+}
+
+func countTransactionsQuery(startLedger uint32, endLedger uint32) sq.SelectBuilder {
+	return sq.
+		Select("COUNT(*)").
+		From(transactionTableName).
+		Where(sq.GtOrEq{"ledger_sequence": startLedger}).
+		Where(sq.LtOrEq{"ledger_sequence": endLedger})
+}
+
 // getTransactionByHash actually performs the DB ops to cross-reference a
 // transaction hash with a particular set of ledger close meta and parses out
 // the relevant transaction efficiently by leveraging the `application_order` db
@@ -174,26 +288,32 @@ func (txn *transactionHandler) GetTransaction(ctx context.Context, hash xdr.Hash
 // Note: Caller must do input sanitization on the hash.
 func (txn *transactionHandler) getTransactionByHash(ctx context.Context, hash xdr.Hash) (
 	xdr.LedgerCloseMeta, ingest.LedgerTransaction, error,
+) {
+	return txn.getTransactionWhere(ctx, sq.Eq{"t.hash": hash[:]}, "txhash "+hex.EncodeToString(hash[:]))
+}
+
+// getTransactionWhere reads the (first) transaction matching the given condition on the
+// transactions table, which is described by description in the errors.
+func (txn *transactionHandler) getTransactionWhere(ctx context.Context, condition sq.Eq, description string) (
+	xdr.LedgerCloseMeta, ingest.LedgerTransaction, error,
 ) {
 	var rows []struct {
-		TxIndex int                 `db:"application_order"`
-		Lcm     xdr.LedgerCloseMeta `db:"meta"`
+		TxIndex int    `db:"application_order"`
+		Meta    []byte `db:"meta"`
 	}
-	rowQ := sq.
-		Select("t.application_order", "lcm.meta").
-		From(transactionTableName + " t").
-		Join(ledgerCloseMetaTableName + " lcm ON (t.ledger_sequence = lcm.sequence)").
-		Where(sq.Eq{"t.hash": hash[:]}).
-		Limit(1)
-
-	if err := txn.db.Select(ctx, &rows, rowQ); err != nil {
+	if err := txn.db.Select(ctx, &rows, transactionWhereQuery(condition)); err != nil {
 		return xdr.LedgerCloseMeta{}, ingest.LedgerTransaction{},
-			fmt.Errorf("db read failed for txhash %s: %w", hex.EncodeToString(hash[:]), err)
+			fmt.Errorf("db read failed for %s: %w", description, err)
 	} else if len(rows) < 1 {
 		return xdr.LedgerCloseMeta{}, ingest.LedgerTransaction{}, ErrNoTransaction
 	}
 
-	txIndex, lcm := rows[0].TxIndex, rows[0].Lcm
+	txIndex := rows[0].TxIndex
+	var lcm xdr.LedgerCloseMeta
+	if err := txn.codec.Decode(rows[0].Meta, &lcm); err != nil {
+		return xdr.LedgerCloseMeta{}, ingest.LedgerTransaction{},
+			fmt.Errorf("could not decode ledger of %s: %w", description, err)
+	}
 	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(txn.passphrase, lcm)
 	if err != nil {
 		return lcm, ingest.LedgerTransaction{},
@@ -202,14 +322,24 @@ func (txn *transactionHandler) getTransactionByHash(ctx context.Context, hash xd
 	err = reader.Seek(txIndex - 1)
 	if err != nil {
 		return lcm, ingest.LedgerTransaction{},
-			fmt.Errorf("failed to index to tx %d in ledger %d (txhash=%s): %w",
-				txIndex, lcm.LedgerSequence(), hash, err)
+			fmt.Errorf("failed to index to tx %d in ledger %d (%s): %w",
+				txIndex, lcm.LedgerSequence(), description, err)
 	}
 
 	ledgerTx, err := reader.Read()
 	return lcm, ledgerTx, err
 }
 
+func transactionWhereQuery(condition sq.Eq) sq.SelectBuilder {
+	return sq.
+		Select("t.application_order", "lcm.meta").
+		From(transactionTableName+" t").
+		Join(ledgerCloseMetaTableName+" lcm ON (t.ledger_sequence = lcm.sequence)").
+		Where(condition).
+		OrderBy("t.ledger_sequence ASC", "t.application_order ASC").
+		Limit(1)
+}
+
 func ParseTransaction(lcm xdr.LedgerCloseMeta, ingestTx ingest.LedgerTransaction) (Transaction, error) {
 	var tx Transaction
 	var err error
@@ -227,12 +357,15 @@ func ParseTransaction(lcm xdr.LedgerCloseMeta, ingestTx ingest.LedgerTransaction
 	tx.TransactionHash = ingestTx.Result.TransactionHash.HexString()
 
 	if tx.Result, err = ingestTx.Result.Result.MarshalBinary(); err != nil {
+		xdrfailures.Record(xdrfailures.TransactionEncode, "TransactionResult")
 		return tx, fmt.Errorf("couldn't encode transaction Result: %w", err)
 	}
 	if tx.Meta, err = ingestTx.UnsafeMeta.MarshalBinary(); err != nil {
+		xdrfailures.Record(xdrfailures.TransactionEncode, "TransactionMeta")
 		return tx, fmt.Errorf("couldn't encode transaction UnsafeMeta: %w", err)
 	}
 	if tx.Envelope, err = ingestTx.Envelope.MarshalBinary(); err != nil {
+		xdrfailures.Record(xdrfailures.TransactionEncode, "TransactionEnvelope")
 		return tx, fmt.Errorf("couldn't encode transaction Envelope: %w", err)
 	}
 	if events, diagErr := ingestTx.GetDiagnosticEvents(); diagErr == nil {
@@ -240,6 +373,7 @@ func ParseTransaction(lcm xdr.LedgerCloseMeta, ingestTx ingest.LedgerTransaction
 		for i, event := range events {
 			bytes, ierr := event.MarshalBinary()
 			if ierr != nil {
+				xdrfailures.Record(xdrfailures.TransactionEncode, "DiagnosticEvent")
 				return tx, fmt.Errorf("couldn't encode transaction DiagnosticEvent %d: %w", i, ierr)
 			}
 			tx.Events = append(tx.Events, bytes)
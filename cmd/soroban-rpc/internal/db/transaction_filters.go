@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+const transactionOperationTypeTableName = "transaction_operation_types"
+
+// TransactionFilterReader looks transactions up by source account and by operation type.
+type TransactionFilterReader interface {
+	// GetTransactionsBySourceAccount returns the locations of (at most limit) transactions with the
+	// given source account (that of the inner transaction for fee bumps), in application order,
+	// starting at the transaction with application order startOrder of ledger startLedger and ending
+	// at ledger endLedger (inclusive).
+	GetTransactionsBySourceAccount(ctx context.Context, account xdr.AccountId,
+		startLedger uint32, startOrder int32, endLedger uint32, limit uint) ([]TransactionLocation, error)
+	// GetTransactionsByOperationType returns the locations of (at most limit) transactions with at
+	// least one operation of the given type, in application order, starting at the transaction with
+	// application order startOrder of ledger startLedger and ending at ledger endLedger (inclusive).
+	GetTransactionsByOperationType(ctx context.Context, operationType xdr.OperationType,
+		startLedger uint32, startOrder int32, endLedger uint32, limit uint) ([]TransactionLocation, error)
+}
+
+func NewTransactionFilterReader(db *DB) TransactionFilterReader {
+	return transactionFilterReader{db: db}
+}
+
+type transactionFilterReader struct {
+	db *DB
+}
+
+// encodeSourceAccount returns how a source account is stored: the ed25519 key of the account,
+// without the ID of muxed accounts.
+func encodeSourceAccount(account xdr.MuxedAccount) []byte {
+	key := account.ToAccountId().MustEd25519()
+	return key[:]
+}
+
+func transactionsBySourceAccountQuery(account xdr.AccountId,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) sq.SelectBuilder {
+	key := account.MustEd25519()
+	// fee bump transactions are stored under both their inner and outer hashes
+	return transactionLocationsQuery(transactionTableName, sq.Eq{"source_account": key[:]},
+		startLedger, startOrder, endLedger, limit).
+		Distinct()
+}
+
+func transactionsByOperationTypeQuery(operationType xdr.OperationType,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) sq.SelectBuilder {
+	return transactionLocationsQuery(transactionOperationTypeTableName, sq.Eq{"operation_type": operationType},
+		startLedger, startOrder, endLedger, limit)
+}
+
+func (r transactionFilterReader) GetTransactionsBySourceAccount(ctx context.Context, account xdr.AccountId,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	query := transactionsBySourceAccountQuery(account, startLedger, startOrder, endLedger, limit)
+	var locations []TransactionLocation
+	if err := r.db.Select(ctx, &locations, query); err != nil {
+		return nil, fmt.Errorf("could not query transactions by source account: %w", err)
+	}
+	return locations, nil
+}
+
+func (r transactionFilterReader) GetTransactionsByOperationType(ctx context.Context,
+	operationType xdr.OperationType, startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	query := transactionsByOperationTypeQuery(operationType, startLedger, startOrder, endLedger, limit)
+	var locations []TransactionLocation
+	if err := r.db.Select(ctx, &locations, query); err != nil {
+		return nil, fmt.Errorf("could not query transactions by operation type: %w", err)
+	}
+	return locations, nil
+}
+
+// insertTransactionOperationTypes indexes the transactions of a ledger by the types of their
+// operations.
+func insertTransactionOperationTypes(runner sq.BaseRunner, ledgerSeq uint32, txs []ingest.LedgerTransaction) error {
+	// ignore conflicts so that reingesting a ledger (e.g. after a migration) never fails
+	query := sq.Insert(transactionOperationTypeTableName).
+		Options("OR IGNORE").
+		Columns("operation_type", "ledger_sequence", "application_order")
+	var count int
+	for _, tx := range txs {
+		seen := map[xdr.OperationType]struct{}{}
+		for _, op := range tx.Envelope.Operations() {
+			if _, ok := seen[op.Body.Type]; ok {
+				continue
+			}
+			seen[op.Body.Type] = struct{}{}
+			query = query.Values(op.Body.Type, ledgerSeq, tx.Index)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	_, err := query.RunWith(runner).Exec()
+	return err
+}
+
+// transactionFiltersMigration fills in the source accounts and the operation types of the
+// transactions stored before they were indexed.
+type transactionFiltersMigration struct {
+	ledgerSeqRange LedgerSeqRange
+	passphrase     string
+	stmtCache      *sq.StmtCache
+}
+
+func (m *transactionFiltersMigration) ApplicableRange() LedgerSeqRange {
+	return m.ledgerSeqRange
+}
+
+func (m *transactionFiltersMigration) Apply(_ context.Context, meta xdr.LedgerCloseMeta) error {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(m.passphrase, meta)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction reader for ledger %d: %w", meta.LedgerSequence(), err)
+	}
+	txs := make([]ingest.LedgerTransaction, 0, meta.CountTransactions())
+	for i := range meta.CountTransactions() {
+		tx, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("failed reading tx %d: %w", i, err)
+		}
+		txs = append(txs, tx)
+		_, err = sq.StatementBuilder.RunWith(m.stmtCache).
+			Update(transactionTableName).
+			Set("source_account", encodeSourceAccount(tx.Envelope.SourceAccount())).
+			Where(sq.Eq{"ledger_sequence": meta.LedgerSequence(), "application_order": tx.Index}).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return insertTransactionOperationTypes(m.stmtCache, meta.LedgerSequence(), txs)
+}
+
+func newTransactionFiltersMigration(
+	_ context.Context,
+	_ *log.Entry,
+	passphrase string,
+	ledgerSeqRange LedgerSeqRange,
+) migrationApplierFactory {
+	return migrationApplierFactoryF(func(db *DB) (MigrationApplier, error) {
+		return &transactionFiltersMigration{
+			ledgerSeqRange: ledgerSeqRange,
+			passphrase:     passphrase,
+			stmtCache:      sq.NewStmtCache(db.GetTx()),
+		}, nil
+	})
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+// txMetaWithOperations returns the ledger of txMeta with the given source account and operations.
+func txMetaWithOperations(acctSeq uint32, source xdr.MuxedAccount, opTypes ...xdr.OperationType) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, true)
+	envelope := &(*meta.V1.TxSet.V1TxSet.Phases[0].V0Components)[0].TxsMaybeDiscountedFee.Txs[0]
+	envelope.V1.Tx.SourceAccount = source
+	for _, opType := range opTypes {
+		envelope.V1.Tx.Operations = append(envelope.V1.Tx.Operations, xdr.Operation{
+			Body: xdr.OperationBody{Type: opType},
+		})
+	}
+	hash, err := network.HashTransactionInEnvelope(*envelope, passphrase)
+	if err != nil {
+		panic(err)
+	}
+	meta.V1.TxProcessing[0].Result.TransactionHash = hash
+	return meta
+}
+
+func TestGetTransactionsBySourceAccountAndOperationType(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 4, passphrase)
+	account := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	other := xdr.MustAddress("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ")
+	muxed, err := xdr.MuxedAccountFromAccountId(account.Address(), 1)
+	require.NoError(t, err)
+	ledgers := []xdr.LedgerCloseMeta{
+		txMetaWithOperations(1, account.ToMuxedAccount(), xdr.OperationTypeInflation),
+		txMetaWithOperations(2, other.ToMuxedAccount(),
+			xdr.OperationTypeInflation, xdr.OperationTypeEndSponsoringFutureReserves, xdr.OperationTypeInflation),
+		txMetaWithOperations(3, muxed, xdr.OperationTypeEndSponsoringFutureReserves),
+		txMetaWithOperations(4, other.ToMuxedAccount()),
+		txMetaWithOperations(5, other.ToMuxedAccount()),
+	}
+	for _, ledger := range ledgers[:3] {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	reader := NewTransactionFilterReader(db)
+	assertLocations := func(expectedBySource, expectedByType []TransactionLocation) {
+		locations, err := reader.GetTransactionsBySourceAccount(ctx, account, 101, 1, 104, 10)
+		require.NoError(t, err)
+		assert.Equal(t, expectedBySource, locations)
+		locations, err = reader.GetTransactionsByOperationType(ctx, xdr.OperationTypeInflation, 101, 1, 104, 10)
+		require.NoError(t, err)
+		assert.Equal(t, expectedByType, locations)
+	}
+	// muxed source accounts are indexed under their account
+	assertLocations(
+		[]TransactionLocation{{Ledger: 101, ApplicationOrder: 1}, {Ledger: 103, ApplicationOrder: 1}},
+		[]TransactionLocation{{Ledger: 101, ApplicationOrder: 1}, {Ledger: 102, ApplicationOrder: 1}},
+	)
+
+	locations, err := reader.GetTransactionsBySourceAccount(ctx, account, 101, 2, 104, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 103, ApplicationOrder: 1}}, locations)
+	locations, err = reader.GetTransactionsByOperationType(ctx, xdr.OperationTypeEndSponsoringFutureReserves,
+		101, 1, 104, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 102, ApplicationOrder: 1}}, locations)
+
+	// simulate transactions stored before their source accounts and operation types were indexed
+	_, err = db.ExecRaw(ctx, "UPDATE transactions SET source_account = NULL")
+	require.NoError(t, err)
+	_, err = db.ExecRaw(ctx, "DELETE FROM transaction_operation_types")
+	require.NoError(t, err)
+	assertLocations(nil, nil)
+
+	require.NoError(t, db.Begin(ctx))
+	migration, err := newTransactionFiltersMigration(ctx, logger, passphrase, LedgerSeqRange{First: 101, Last: 103}).
+		New(db)
+	require.NoError(t, err)
+	require.NoError(t, NewLedgerReader(db).StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		return migration.Apply(ctx, ledger)
+	}))
+	require.NoError(t, db.Commit())
+	assertLocations(
+		[]TransactionLocation{{Ledger: 101, ApplicationOrder: 1}, {Ledger: 103, ApplicationOrder: 1}},
+		[]TransactionLocation{{Ledger: 101, ApplicationOrder: 1}, {Ledger: 102, ApplicationOrder: 1}},
+	)
+
+	// the index rows are trimmed along with the transactions
+	for _, ledger := range ledgers[3:] {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+	assertLocations(
+		[]TransactionLocation{{Ledger: 103, ApplicationOrder: 1}},
+		[]TransactionLocation{{Ledger: 102, ApplicationOrder: 1}},
+	)
+}
@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/go/xdr"
+)
+
+// Results of the transaction hash filter lookups (the labels of the lookup metric).
+const (
+	hashFilterNegative      = "negative"
+	hashFilterPositive      = "positive"
+	hashFilterFalsePositive = "false_positive"
+	hashFilterInconclusive  = "inconclusive"
+)
+
+// TransactionHashFilter is a bounded in-memory bloom filter of the hashes of the stored
+// transactions, consulted before querying the transactions table, so that lookups of
+// transactions which aren't stored (e.g. while polling for the confirmation of a recently
+// submitted transaction) don't hit the database.
+//
+// Bloom filters have false positives but no false negatives, so positive lookups are always
+// verified against the database. To stay bounded, the filter is split into two generations:
+// once the current generation is full, the previous one is discarded. The filter then no longer
+// covers the ledgers of the discarded generation, and its negative lookups are inconclusive
+// (i.e. verified against the database) until those ledgers are trimmed from the database.
+type TransactionHashFilter struct {
+	lock sync.RWMutex
+	// generationCapacity is the number of hashes of each generation
+	generationCapacity uint
+	bitCount           uint64
+	hashCount          uint64
+	current, previous  *hashFilterGeneration
+	// trimmedBefore is the ledger before which all the transactions were trimmed
+	trimmedBefore uint32
+
+	lookups *prometheus.CounterVec
+}
+
+type hashFilterGeneration struct {
+	bits []uint64
+	size uint
+	// firstLedger is the first ledger whose transactions are all in the generation (or its successor)
+	firstLedger uint32
+}
+
+// NewTransactionHashFilter returns an empty filter holding up to capacity hashes with the given
+// false positive rate. The filter assumes that the database doesn't store transactions yet, see
+// EnableTransactionHashFilter.
+func NewTransactionHashFilter(capacity uint, falsePositiveRate float64) *TransactionHashFilter {
+	generationCapacity := max(capacity/2, 1)
+	// the optimal sizing of bloom filters, see https://en.wikipedia.org/wiki/Bloom_filter
+	bitCount := uint64(math.Ceil(-float64(generationCapacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	bitCount = max(bitCount, 64)
+	hashCount := uint64(math.Round(float64(bitCount) / float64(generationCapacity) * math.Ln2))
+	f := &TransactionHashFilter{
+		generationCapacity: generationCapacity,
+		bitCount:           bitCount,
+		hashCount:          max(hashCount, 1),
+	}
+	f.previous = f.newGeneration(0)
+	f.current = f.newGeneration(0)
+	return f
+}
+
+func (f *TransactionHashFilter) newGeneration(firstLedger uint32) *hashFilterGeneration {
+	return &hashFilterGeneration{
+		bits:        make([]uint64, (f.bitCount+63)/64),
+		firstLedger: firstLedger,
+	}
+}
+
+// bitIndexes calls fn with the bit indexes of a hash. Transaction hashes are uniformly
+// distributed, so they are used directly for double hashing.
+func (f *TransactionHashFilter) bitIndexes(hash xdr.Hash, fn func(index uint64) bool) {
+	h1 := binary.LittleEndian.Uint64(hash[0:8])
+	h2 := binary.LittleEndian.Uint64(hash[8:16]) | 1
+	for i := range f.hashCount {
+		if !fn((h1 + i*h2) % f.bitCount) {
+			return
+		}
+	}
+}
+
+func (g *hashFilterGeneration) add(f *TransactionHashFilter, hash xdr.Hash) {
+	f.bitIndexes(hash, func(index uint64) bool {
+		g.bits[index/64] |= 1 << (index % 64)
+		return true
+	})
+	g.size++
+}
+
+func (g *hashFilterGeneration) mayContain(f *TransactionHashFilter, hash xdr.Hash) bool {
+	contained := true
+	f.bitIndexes(hash, func(index uint64) bool {
+		contained = g.bits[index/64]&(1<<(index%64)) != 0
+		return contained
+	})
+	return contained
+}
+
+// add records the transaction hashes of a ledger. The hashes of a ledger are added
+// to the same generation, so that the generations cover whole ledgers.
+func (f *TransactionHashFilter) add(ledgerSeq uint32, hashes []xdr.Hash) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.current.size > 0 && f.current.size+uint(len(hashes)) > f.generationCapacity {
+		f.previous = f.current
+		f.current = f.newGeneration(ledgerSeq)
+	}
+	for _, hash := range hashes {
+		f.current.add(f, hash)
+	}
+}
+
+// trimmed records that the transactions of the ledgers before cutoff were trimmed.
+func (f *TransactionHashFilter) trimmed(cutoff uint32) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.trimmedBefore = max(f.trimmedBefore, cutoff)
+}
+
+// lookup returns whether the transaction may be stored, and whether a negative result is
+// conclusive (i.e. the filter covers all the stored transactions).
+func (f *TransactionHashFilter) lookup(hash xdr.Hash) (bool, bool) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if f.current.mayContain(f, hash) || f.previous.mayContain(f, hash) {
+		return true, true
+	}
+	return false, f.previous.firstLedger <= f.trimmedBefore
+}
+
+func (f *TransactionHashFilter) recordLookup(result string) {
+	if f.lookups != nil {
+		f.lookups.With(prometheus.Labels{"result": result}).Inc()
+	}
+}
+
+// load adds the hashes of the newest stored transactions, up to a generation.
+func (f *TransactionHashFilter) load(ctx context.Context, db *DB) error {
+	var rows []struct {
+		Hash   []byte `db:"hash"`
+		Ledger uint32 `db:"ledger_sequence"`
+	}
+	query := sq.Select("hash", "ledger_sequence").
+		From(transactionTableName).
+		OrderBy("ledger_sequence DESC").
+		Limit(uint64(f.generationCapacity))
+	if err := db.Select(ctx, &rows, query); err != nil {
+		return fmt.Errorf("could not load the transaction hashes: %w", err)
+	}
+	slices.Reverse(rows)
+
+	var ledgerSeq uint32
+	var hashes []xdr.Hash
+	for _, row := range rows {
+		if row.Ledger != ledgerSeq && len(hashes) > 0 {
+			f.add(ledgerSeq, hashes)
+			hashes = hashes[:0]
+		}
+		ledgerSeq = row.Ledger
+		var hash xdr.Hash
+		copy(hash[:], row.Hash)
+		hashes = append(hashes, hash)
+	}
+	if len(hashes) > 0 {
+		f.add(ledgerSeq, hashes)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if uint(len(rows)) == f.generationCapacity {
+		// the oldest loaded ledger may only be partially loaded
+		f.current.firstLedger = rows[0].Ledger + 1
+	}
+	// the loaded hashes become the previous generation
+	f.previous, f.current = f.current, f.newGeneration(ledgerSeq+1)
+	return nil
+}
+
+// EnableTransactionHashFilter loads the hashes of the stored transactions in the filter,
+// which is then updated by the ingestion and consulted by the transaction readers of the
+// database. It must be called before ingesting ledgers and creating transaction readers.
+func (db *DB) EnableTransactionHashFilter(ctx context.Context, filter *TransactionHashFilter,
+	namespace string, registry *prometheus.Registry,
+) error {
+	if err := filter.load(ctx, db); err != nil {
+		return err
+	}
+	filter.lookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "transactions",
+		Name: "hash_filter_lookups_total",
+		Help: "transaction lookups by hash filter result (negative lookups don't query the database)",
+	}, []string{"result"})
+	registry.MustRegister(filter.lookups)
+	db.txHashFilter = filter
+	return nil
+}
+
+// hashFilterUpdate accumulates the changes of a write transaction, which are applied
+// to the filter once the transaction is committed.
+type hashFilterUpdate struct {
+	ledgers []uint32
+	hashes  [][]xdr.Hash
+	cutoff  uint32
+}
+
+// apply updates the filter. Until then, the newly committed transactions may be reported
+// as not found, as if they were looked up right before the commit.
+func (u *hashFilterUpdate) apply(f *TransactionHashFilter) {
+	for i, ledgerSeq := range u.ledgers {
+		f.add(ledgerSeq, u.hashes[i])
+	}
+	if u.cutoff > 0 {
+		f.trimmed(u.cutoff)
+	}
+}
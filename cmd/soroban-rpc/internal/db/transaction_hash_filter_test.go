@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestTransactionHashFilterGenerations(t *testing.T) {
+	filter := NewTransactionHashFilter(4, 0.01)
+	hashes := []xdr.Hash{txHash(1), txHash(2), txHash(3), txHash(4), txHash(5)}
+
+	filter.add(10, hashes[:2])
+	filter.add(11, hashes[2:3])
+	mayContain, _ := filter.lookup(hashes[0])
+	assert.True(t, mayContain)
+	_, conclusive := filter.lookup(txHash(100))
+	assert.True(t, conclusive)
+
+	// the first generation is discarded, so the hashes of ledger 10 are no longer covered
+	filter.add(12, hashes[3:5])
+	for _, hash := range hashes[2:] {
+		mayContain, _ := filter.lookup(hash)
+		assert.True(t, mayContain)
+	}
+	mayContain, conclusive = filter.lookup(txHash(100))
+	assert.False(t, mayContain)
+	assert.False(t, conclusive)
+
+	// until ledger 10 is trimmed
+	filter.trimmed(11)
+	_, conclusive = filter.lookup(txHash(100))
+	assert.True(t, conclusive)
+}
+
+func TestTransactionHashFilter(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	logger := log.DefaultLogger
+	writer := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+	ingest := func(lcms ...xdr.LedgerCloseMeta) {
+		write, err := writer.NewTx(ctx)
+		require.NoError(t, err)
+		for _, lcm := range lcms {
+			require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+			require.NoError(t, write.TransactionWriter().InsertTransactions(lcm))
+		}
+		require.NoError(t, write.Commit(lcms[len(lcms)-1]))
+	}
+
+	// the transactions stored before enabling the filter are loaded
+	ingest(txMeta(1, true), txMeta(2, true))
+	filter := NewTransactionHashFilter(100, 0.01)
+	require.NoError(t, db.EnableTransactionHashFilter(ctx, filter, "test", prometheus.NewRegistry()))
+	ingest(txMeta(3, true))
+
+	reader := NewTransactionReader(logger, db, passphrase)
+	for _, acctSeq := range []uint32{1, 2, 3} {
+		_, err := reader.GetTransaction(ctx, txHash(acctSeq))
+		require.NoError(t, err)
+	}
+	assert.InDelta(t, 3, testutil.ToFloat64(filter.lookups.WithLabelValues(hashFilterPositive)), 0)
+
+	// negative lookups don't query the database
+	_, err := reader.GetTransaction(ctx, txHash(4))
+	require.ErrorIs(t, err, ErrNoTransaction)
+	assert.InDelta(t, 1, testutil.ToFloat64(filter.lookups.WithLabelValues(hashFilterNegative)), 0)
+
+	// positive lookups are verified against the database
+	filter.add(200, []xdr.Hash{txHash(5)})
+	_, err = reader.GetTransaction(ctx, txHash(5))
+	require.ErrorIs(t, err, ErrNoTransaction)
+	assert.InDelta(t, 1, testutil.ToFloat64(filter.lookups.WithLabelValues(hashFilterFalsePositive)), 0)
+}
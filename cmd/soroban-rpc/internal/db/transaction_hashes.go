@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionHash is the hash of a stored transaction, along with its location.
+type TransactionHash struct {
+	TransactionLocation
+	Hash xdr.Hash
+}
+
+type StreamTransactionHashFn func(TransactionHash) error
+
+// TransactionHashReader streams the hashes of the stored transactions.
+type TransactionHashReader interface {
+	// StreamTransactionHashes runs f over the hashes of the transactions of the ledgers from
+	// startLedger to endLedger (inclusive), in application order, until f errors. Fee-bump
+	// transactions are stored under both their outer and inner hashes, so f is called for both.
+	StreamTransactionHashes(ctx context.Context, startLedger, endLedger uint32, f StreamTransactionHashFn) error
+}
+
+func NewTransactionHashReader(db *DB) TransactionHashReader {
+	return &transactionHandler{db: db, codec: db.codec}
+}
+
+// StreamTransactionHashes only reads the transactions table, without decoding the ledgers.
+func (txn *transactionHandler) StreamTransactionHashes(ctx context.Context, startLedger, endLedger uint32,
+	f StreamTransactionHashFn,
+) error {
+	query := sq.Select("ledger_sequence", "application_order", "hash").
+		From(transactionTableName).
+		Where(sq.GtOrEq{"ledger_sequence": startLedger}).
+		Where(sq.LtOrEq{"ledger_sequence": endLedger}).
+		OrderBy("ledger_sequence ASC", "application_order ASC", "hash ASC")
+	rows, err := txn.db.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var txHash TransactionHash
+		var hash []byte
+		if err := rows.Scan(&txHash.Ledger, &txHash.ApplicationOrder, &hash); err != nil {
+			return err
+		}
+		copy(txHash.Hash[:], hash)
+		if err := f(txHash); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
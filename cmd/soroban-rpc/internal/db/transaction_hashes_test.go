@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func ingestTransactions(t testing.TB, db *DB, lcms []xdr.LedgerCloseMeta) {
+	ctx := context.TODO()
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 100, 1_000_000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	ledgerW, txW := write.LedgerWriter(), write.TransactionWriter()
+	for _, lcm := range lcms {
+		require.NoError(t, ledgerW.InsertLedger(lcm))
+		require.NoError(t, txW.InsertTransactions(lcm))
+	}
+	require.NoError(t, write.Commit(lcms[len(lcms)-1]))
+}
+
+func TestStreamTransactionHashes(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	lcms := []xdr.LedgerCloseMeta{txMeta(1, true), txMeta(2, false), txMeta(3, true), txMeta(4, true)}
+	ingestTransactions(t, db, lcms)
+
+	var hashes []TransactionHash
+	err := NewTransactionHashReader(db).StreamTransactionHashes(ctx, lcms[1].LedgerSequence(), lcms[2].LedgerSequence(),
+		func(hash TransactionHash) error {
+			hashes = append(hashes, hash)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionHash{
+		{TransactionLocation{Ledger: lcms[1].LedgerSequence(), ApplicationOrder: 1}, txHash(2)},
+		{TransactionLocation{Ledger: lcms[2].LedgerSequence(), ApplicationOrder: 1}, txHash(3)},
+	}, hashes)
+}
+
+func benchmarkStreamHashes(b *testing.B, stream func(ctx context.Context, db *DB, start, end uint32) int) {
+	db := NewTestDB(b)
+	lcms := make([]xdr.LedgerCloseMeta, 0, 10_000)
+	for i := range cap(lcms) {
+		lcms = append(lcms, txMeta(uint32(1234+i), true))
+	}
+	ingestTransactions(b, db, lcms)
+	start, end := lcms[0].LedgerSequence(), lcms[len(lcms)-1].LedgerSequence()
+
+	b.ResetTimer()
+	for range b.N {
+		require.Equal(b, len(lcms), stream(context.TODO(), db, start, end))
+	}
+}
+
+func BenchmarkStreamTransactionHashes(b *testing.B) {
+	benchmarkStreamHashes(b, func(ctx context.Context, db *DB, start, end uint32) int {
+		count := 0
+		require.NoError(b, NewTransactionHashReader(db).StreamTransactionHashes(ctx, start, end,
+			func(TransactionHash) error {
+				count++
+				return nil
+			}))
+		return count
+	})
+}
+
+// BenchmarkStreamTransactionHashesFromLedgers gets the same hashes by decoding the stored ledgers.
+func BenchmarkStreamTransactionHashesFromLedgers(b *testing.B) {
+	benchmarkStreamHashes(b, func(ctx context.Context, db *DB, start, end uint32) int {
+		count := 0
+		require.NoError(b, NewLedgerReader(db).StreamLedgerRange(ctx, start, end, func(lcm xdr.LedgerCloseMeta) error {
+			reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(passphrase, lcm)
+			if err != nil {
+				return err
+			}
+			for range lcm.CountTransactions() {
+				if _, err := reader.Read(); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		}))
+		return count
+	})
+}
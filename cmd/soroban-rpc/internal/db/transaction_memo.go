@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionLocation locates a transaction in the stored ledgers.
+type TransactionLocation struct {
+	Ledger           uint32 `db:"ledger_sequence"`
+	ApplicationOrder int32  `db:"application_order"`
+}
+
+// TransactionMemoReader looks transactions up by memo.
+type TransactionMemoReader interface {
+	// GetTransactionsByMemo returns the locations of (at most limit) transactions with the given
+	// memo, in application order, starting at the transaction with application order startOrder
+	// of ledger startLedger and ending at ledger endLedger (inclusive).
+	GetTransactionsByMemo(ctx context.Context, memoType xdr.MemoType, memoValue []byte,
+		startLedger uint32, startOrder int32, endLedger uint32, limit uint) ([]TransactionLocation, error)
+}
+
+func NewTransactionMemoReader(log *log.Entry, db *DB, passphrase string) TransactionMemoReader {
+	return &transactionHandler{log: log, db: db, codec: db.codec, passphrase: passphrase}
+}
+
+// EncodeMemo returns how a memo is stored: its type and its value, which is the text of
+// text memos, the big-endian ID of ID memos and the hash of hash and return memos.
+func EncodeMemo(memo xdr.Memo) (xdr.MemoType, []byte) {
+	switch memo.Type {
+	case xdr.MemoTypeMemoText:
+		return memo.Type, []byte(memo.MustText())
+	case xdr.MemoTypeMemoId:
+		return memo.Type, binary.BigEndian.AppendUint64(nil, uint64(memo.MustId()))
+	case xdr.MemoTypeMemoHash:
+		hash := memo.MustHash()
+		return memo.Type, hash[:]
+	case xdr.MemoTypeMemoReturn:
+		hash := memo.MustRetHash()
+		return memo.Type, hash[:]
+	default:
+		return xdr.MemoTypeMemoNone, nil
+	}
+}
+
+// transactionLocationsQuery selects the locations of (at most limit) transactions of table matching
+// condition, in application order, starting at the transaction with application order startOrder of
+// ledger startLedger and ending at ledger endLedger (inclusive).
+func transactionLocationsQuery(table string, condition sq.Sqlizer,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) sq.SelectBuilder {
+	return sq.Select("ledger_sequence", "application_order").
+		From(table).
+		Where(condition).
+		Where(sq.Or{
+			sq.Gt{"ledger_sequence": startLedger},
+			sq.And{sq.Eq{"ledger_sequence": startLedger}, sq.GtOrEq{"application_order": startOrder}},
+		}).
+		Where(sq.LtOrEq{"ledger_sequence": endLedger}).
+		OrderBy("ledger_sequence ASC", "application_order ASC").
+		Limit(uint64(limit))
+}
+
+func transactionsByMemoQuery(memoType xdr.MemoType, memoValue []byte,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) sq.SelectBuilder {
+	// fee bump transactions are stored under both their inner and outer hashes
+	return transactionLocationsQuery(transactionTableName, sq.Eq{"memo_type": memoType, "memo_value": memoValue},
+		startLedger, startOrder, endLedger, limit).
+		Distinct()
+}
+
+func (txn *transactionHandler) GetTransactionsByMemo(ctx context.Context, memoType xdr.MemoType,
+	memoValue []byte, startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]TransactionLocation, error) {
+	query := transactionsByMemoQuery(memoType, memoValue, startLedger, startOrder, endLedger, limit)
+	var locations []TransactionLocation
+	if err := txn.db.Select(ctx, &locations, query); err != nil {
+		return nil, fmt.Errorf("could not query transactions by memo: %w", err)
+	}
+	return locations, nil
+}
+
+// transactionMemoMigration fills in the memos of the transactions stored before the memo
+// columns were added.
+type transactionMemoMigration struct {
+	ledgerSeqRange LedgerSeqRange
+	passphrase     string
+	stmtCache      *sq.StmtCache
+}
+
+func (m *transactionMemoMigration) ApplicableRange() LedgerSeqRange {
+	return m.ledgerSeqRange
+}
+
+func (m *transactionMemoMigration) Apply(_ context.Context, meta xdr.LedgerCloseMeta) error {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(m.passphrase, meta)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction reader for ledger %d: %w", meta.LedgerSequence(), err)
+	}
+	for i := range meta.CountTransactions() {
+		tx, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("failed reading tx %d: %w", i, err)
+		}
+		memoType, memoValue := EncodeMemo(tx.Envelope.Memo())
+		if memoType == xdr.MemoTypeMemoNone {
+			continue
+		}
+		_, err = sq.StatementBuilder.RunWith(m.stmtCache).
+			Update(transactionTableName).
+			Set("memo_type", memoType).
+			Set("memo_value", memoValue).
+			Where(sq.Eq{"ledger_sequence": meta.LedgerSequence(), "application_order": tx.Index}).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTransactionMemoMigration(
+	_ context.Context,
+	_ *log.Entry,
+	passphrase string,
+	ledgerSeqRange LedgerSeqRange,
+) migrationApplierFactory {
+	return migrationApplierFactoryF(func(db *DB) (MigrationApplier, error) {
+		return &transactionMemoMigration{
+			ledgerSeqRange: ledgerSeqRange,
+			passphrase:     passphrase,
+			stmtCache:      sq.NewStmtCache(db.GetTx()),
+		}, nil
+	})
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func txMetaWithMemo(acctSeq uint32, memo xdr.Memo) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, true)
+	components := *meta.V1.TxSet.V1TxSet.Phases[0].V0Components
+	envelope := &components[0].TxsMaybeDiscountedFee.Txs[0]
+	envelope.V1.Tx.Memo = memo
+	hash, err := network.HashTransactionInEnvelope(*envelope, passphrase)
+	if err != nil {
+		panic(err)
+	}
+	meta.V1.TxProcessing[0].Result.TransactionHash = hash
+	return meta
+}
+
+func TestGetTransactionsByMemo(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+	ledgers := []xdr.LedgerCloseMeta{
+		txMetaWithMemo(1, xdr.MemoText("deposit")),
+		txMetaWithMemo(2, xdr.MemoID(42)),
+		txMetaWithMemo(3, xdr.MemoText("deposit")),
+		txMeta(4, true),
+	}
+	for _, ledger := range ledgers {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	reader := NewTransactionMemoReader(logger, db, passphrase)
+	textType, text := EncodeMemo(xdr.MemoText("deposit"))
+	idType, id := EncodeMemo(xdr.MemoID(42))
+	assertLocations := func(expected []TransactionLocation) {
+		locations, err := reader.GetTransactionsByMemo(ctx, textType, text, 101, 1, 104, 10)
+		require.NoError(t, err)
+		assert.Equal(t, expected, locations)
+	}
+	assertLocations([]TransactionLocation{{Ledger: 101, ApplicationOrder: 1}, {Ledger: 103, ApplicationOrder: 1}})
+
+	locations, err := reader.GetTransactionsByMemo(ctx, textType, text, 101, 1, 104, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 101, ApplicationOrder: 1}}, locations)
+	locations, err = reader.GetTransactionsByMemo(ctx, textType, text, 101, 2, 104, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 103, ApplicationOrder: 1}}, locations)
+	locations, err = reader.GetTransactionsByMemo(ctx, textType, text, 101, 1, 102, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 101, ApplicationOrder: 1}}, locations)
+	locations, err = reader.GetTransactionsByMemo(ctx, idType, id, 101, 1, 104, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionLocation{{Ledger: 102, ApplicationOrder: 1}}, locations)
+
+	// simulate transactions stored before the memo columns were added
+	_, err = db.ExecRaw(ctx, "UPDATE transactions SET memo_type = 0, memo_value = NULL")
+	require.NoError(t, err)
+	assertLocations(nil)
+
+	require.NoError(t, db.Begin(ctx))
+	migration, err := newTransactionMemoMigration(ctx, logger, passphrase, LedgerSeqRange{First: 101, Last: 104}).
+		New(db)
+	require.NoError(t, err)
+	require.NoError(t, NewLedgerReader(db).StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		return migration.Apply(ctx, ledger)
+	}))
+	require.NoError(t, db.Commit())
+	assertLocations([]TransactionLocation{{Ledger: 101, ApplicationOrder: 1}, {Ledger: 103, ApplicationOrder: 1}})
+}
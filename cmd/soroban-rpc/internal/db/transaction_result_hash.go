@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+// HashTransactionResult returns the result hash of a transaction, by which it can be looked up:
+// the SHA-256 hash of the XDR of its TransactionResult (as returned in resultXdr by getTransaction).
+func HashTransactionResult(result xdr.TransactionResult) (xdr.Hash, error) {
+	encoded, err := result.MarshalBinary()
+	if err != nil {
+		return xdr.Hash{}, err
+	}
+	return sha256.Sum256(encoded), nil
+}
+
+// GetTransactionByResultHash looks a transaction up by its result hash (see HashTransactionResult).
+// If several stored transactions have the same result, the earliest one is returned.
+func (txn *transactionHandler) GetTransactionByResultHash(ctx context.Context, resultHash xdr.Hash) (
+	Transaction, error,
+) {
+	lcm, ingestTx, err := txn.getTransactionWhere(ctx, sq.Eq{"t.result_hash": resultHash[:]},
+		"result hash "+resultHash.HexString())
+	if err != nil {
+		return Transaction{}, err
+	}
+	return ParseTransaction(lcm, ingestTx)
+}
+
+// transactionResultHashMigration fills in the result hashes of the transactions stored before the
+// result_hash column was added.
+type transactionResultHashMigration struct {
+	ledgerSeqRange LedgerSeqRange
+	passphrase     string
+	stmtCache      *sq.StmtCache
+}
+
+func (m *transactionResultHashMigration) ApplicableRange() LedgerSeqRange {
+	return m.ledgerSeqRange
+}
+
+func (m *transactionResultHashMigration) Apply(_ context.Context, meta xdr.LedgerCloseMeta) error {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(m.passphrase, meta)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction reader for ledger %d: %w", meta.LedgerSequence(), err)
+	}
+	for i := range meta.CountTransactions() {
+		tx, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("failed reading tx %d: %w", i, err)
+		}
+		resultHash, err := HashTransactionResult(tx.Result.Result)
+		if err != nil {
+			return err
+		}
+		_, err = sq.StatementBuilder.RunWith(m.stmtCache).
+			Update(transactionTableName).
+			Set("result_hash", resultHash[:]).
+			Where(sq.Eq{"ledger_sequence": meta.LedgerSequence(), "application_order": tx.Index}).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTransactionResultHashMigration(
+	_ context.Context,
+	_ *log.Entry,
+	passphrase string,
+	ledgerSeqRange LedgerSeqRange,
+) migrationApplierFactory {
+	return migrationApplierFactoryF(func(db *DB) (MigrationApplier, error) {
+		return &transactionResultHashMigration{
+			ledgerSeqRange: ledgerSeqRange,
+			passphrase:     passphrase,
+			stmtCache:      sq.NewStmtCache(db.GetTx()),
+		}, nil
+	})
+}
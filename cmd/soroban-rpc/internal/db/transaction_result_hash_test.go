@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestGetTransactionByResultHash(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	rw := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 15, passphrase)
+	ledgers := []xdr.LedgerCloseMeta{txMeta(1, true), txMeta(2, false), txMeta(3, true)}
+	for _, ledger := range ledgers {
+		tx, err := rw.NewTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.TransactionWriter().InsertTransactions(ledger))
+		require.NoError(t, tx.Commit(ledger))
+	}
+
+	reader := NewTransactionReader(logger, db, passphrase)
+	successHash, err := HashTransactionResult(transactionResult(true))
+	require.NoError(t, err)
+	failureHash, err := HashTransactionResult(transactionResult(false))
+	require.NoError(t, err)
+	assertLedger := func(resultHash xdr.Hash, expected uint32) {
+		tx, err := reader.GetTransactionByResultHash(ctx, resultHash)
+		require.NoError(t, err)
+		assert.Equal(t, expected, tx.Ledger.Sequence)
+	}
+
+	// transactions with identical results resolve to the earliest one
+	assertLedger(successHash, 101)
+	assertLedger(failureHash, 102)
+	_, err = reader.GetTransactionByResultHash(ctx, xdr.Hash{1})
+	require.ErrorIs(t, err, ErrNoTransaction)
+
+	// simulate transactions stored before the result_hash column was added
+	_, err = db.ExecRaw(ctx, "UPDATE transactions SET result_hash = NULL")
+	require.NoError(t, err)
+	_, err = reader.GetTransactionByResultHash(ctx, failureHash)
+	require.ErrorIs(t, err, ErrNoTransaction)
+
+	require.NoError(t, db.Begin(ctx))
+	migration, err := newTransactionResultHashMigration(ctx, logger, passphrase, LedgerSeqRange{First: 101, Last: 103}).
+		New(db)
+	require.NoError(t, err)
+	require.NoError(t, NewLedgerReader(db).StreamAllLedgers(ctx, func(ledger xdr.LedgerCloseMeta) error {
+		return migration.Apply(ctx, ledger)
+	}))
+	require.NoError(t, db.Commit())
+	assertLedger(successHash, 101)
+	assertLedger(failureHash, 102)
+}
@@ -113,6 +113,63 @@ func TestTransactionFound(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, expectedEnvelope, tx.Envelope)
 	}
+
+	// the counts match the stored transactions
+	first, last := lcms[0].LedgerSequence(), lcms[len(lcms)-1].LedgerSequence()
+	count, err := reader.CountTransactions(ctx, first, last)
+	require.NoError(t, err)
+	assert.EqualValues(t, len(lcms), count)
+	count, err = reader.CountTransactions(ctx, first+1, first+1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	count, err = reader.CountTransactions(ctx, last+1, last+10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+func TestReingestTransactions(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	original := txMetaWithEvents(1)
+	ingestLedgers(t, db, []xdr.LedgerCloseMeta{original}, nil)
+
+	countEvents := func() (int, []xdr.Hash) {
+		var hashes []xdr.Hash
+		cursorRange := CursorRange{Start: Cursor{Ledger: 101}, End: Cursor{Ledger: 102}}
+		err := NewEventReader(logger, db, passphrase).GetEvents(ctx, cursorRange, nil, nil, nil,
+			func(_ xdr.DiagnosticEvent, _ Cursor, _ int64, txHash *xdr.Hash) bool {
+				hashes = append(hashes, *txHash)
+				return true
+			})
+		require.NoError(t, err)
+		return len(hashes), hashes
+	}
+
+	// re-ingesting the same ledger
+	ingestLedgers(t, db, []xdr.LedgerCloseMeta{original}, nil)
+	reader := NewTransactionReader(logger, db, passphrase)
+	tx, err := reader.GetTransaction(ctx, txHash(1))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), tx.Ledger.Sequence)
+	count, _ := countEvents()
+	assert.Equal(t, 1, count)
+
+	// replacing the ledger with a different transaction
+	replacement := txMetaWithEvents(2)
+	replacement.V1.LedgerHeader.Header.LedgerSeq = original.V1.LedgerHeader.Header.LedgerSeq
+	replacement.V1.LedgerHeader.Hash = xdr.Hash{0xff}
+	ingestLedgers(t, db, []xdr.LedgerCloseMeta{replacement}, nil)
+	_, err = reader.GetTransaction(ctx, txHash(1))
+	require.ErrorIs(t, err, ErrNoTransaction)
+	tx, err = reader.GetTransaction(ctx, txHash(2))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), tx.Ledger.Sequence)
+	count, hashes := countEvents()
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []xdr.Hash{txHash(2)}, hashes)
+	count64, err := reader.CountTransactions(ctx, 101, 101)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count64)
 }
 
 func BenchmarkTransactionFetch(b *testing.B) {
@@ -32,6 +32,11 @@ func (m *MockDB) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
 	return args.Get(0).(uint32), args.Error(1) //nolint:forcetypeassert
 }
 
+func (m *MockDB) GetIngestionCheckpoint(ctx context.Context) (uint32, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(uint32), args.Error(1) //nolint:forcetypeassert
+}
+
 type MockTx struct {
 	mock.Mock
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
 )
 
@@ -34,17 +35,20 @@ type Config struct {
 	Logger            *log.Entry
 	DB                db.ReadWriter
 	FeeWindows        *feewindow.FeeWindows
+	IngestionWindow   *ingestionwindow.IngestionWindow
 	NetworkPassPhrase string
 	Archive           historyarchive.ArchiveInterface
 	LedgerBackend     backends.LedgerBackend
 	Timeout           time.Duration
 	OnIngestionRetry  backoff.Notify
 	Daemon            interfaces.Daemon
+	// Clock defaults to the real clock if nil.
+	Clock util.Clock
 }
 
 func NewService(cfg Config) *Service {
 	service := newService(cfg)
-	startService(service, cfg)
+	startService(service)
 	return service
 }
 
@@ -77,13 +81,22 @@ func newService(cfg Config) *Service {
 		latestLedgerMetric,
 		ledgerStatsMetric)
 
+	clock := cfg.Clock
+	if clock == nil {
+		clock = util.RealClock{}
+	}
+
 	service := &Service{
 		logger:            cfg.Logger,
+		clock:             clock,
 		db:                cfg.DB,
 		feeWindows:        cfg.FeeWindows,
+		ingestionWindow:   cfg.IngestionWindow,
 		ledgerBackend:     cfg.LedgerBackend,
 		networkPassPhrase: cfg.NetworkPassPhrase,
 		timeout:           cfg.Timeout,
+		archive:           cfg.Archive,
+		onIngestionRetry:  cfg.OnIngestionRetry,
 		metrics: Metrics{
 			ingestionDurationMetric: ingestionDurationMetric,
 			latestLedgerMetric:      latestLedgerMetric,
@@ -94,11 +107,11 @@ func newService(cfg Config) *Service {
 	return service
 }
 
-func startService(service *Service, cfg Config) {
+func startService(service *Service) {
 	ctx, done := context.WithCancel(context.Background())
 	service.done = done
 	service.wg.Add(1)
-	panicGroup := util.UnrecoverablePanicGroup.Log(cfg.Logger)
+	panicGroup := util.UnrecoverablePanicGroup.Log(service.logger)
 	panicGroup.Go(func() {
 		defer service.wg.Done()
 		// Retry running ingestion every second for 5 seconds.
@@ -107,7 +120,7 @@ func startService(service *Service, cfg Config) {
 		contextBackoff := backoff.WithContext(constantBackoff, ctx)
 		err := backoff.RetryNotify(
 			func() error {
-				err := service.run(ctx, cfg.Archive)
+				err := service.run(ctx, service.archive)
 				if errors.Is(err, errEmptyArchives) {
 					// keep retrying until history archives are published
 					constantBackoff.Reset()
@@ -115,7 +128,7 @@ func startService(service *Service, cfg Config) {
 				return err
 			},
 			contextBackoff,
-			cfg.OnIngestionRetry)
+			service.onIngestionRetry)
 		if err != nil && !errors.Is(err, context.Canceled) {
 			service.logger.WithError(err).Fatal("could not run ingestion")
 		}
@@ -130,22 +143,47 @@ type Metrics struct {
 
 type Service struct {
 	logger            *log.Entry
+	clock             util.Clock
 	db                db.ReadWriter
 	feeWindows        *feewindow.FeeWindows
+	ingestionWindow   *ingestionwindow.IngestionWindow
 	ledgerBackend     backends.LedgerBackend
 	timeout           time.Duration
 	networkPassPhrase string
-	done              context.CancelFunc
-	wg                sync.WaitGroup
-	metrics           Metrics
+	archive           historyarchive.ArchiveInterface
+	onIngestionRetry  backoff.Notify
+	// lock serializes the stops of ingestion (see Close and WithIngestionStopped)
+	lock    sync.Mutex
+	closed  bool
+	done    context.CancelFunc
+	wg      sync.WaitGroup
+	metrics Metrics
 }
 
 func (s *Service) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
 	s.done()
 	s.wg.Wait()
 	return nil
 }
 
+// WithIngestionStopped stops ingestion, waiting for the ledger being ingested to be committed
+// or rolled back, and runs f, e.g. to write ledgers to the database without racing with
+// ingestion. Ingestion is then resumed after the latest ledger of the database.
+func (s *Service) WithIngestionStopped(f func() error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return errors.New("ingestion was closed")
+	}
+	s.done()
+	s.wg.Wait()
+	defer startService(s)
+	return f()
+}
+
 func (s *Service) run(ctx context.Context, archive historyarchive.ArchiveInterface) error {
 	// Create a ledger-entry baseline from a checkpoint if it wasn't done before
 	// (after that we will be adding deltas from txmeta ledger entry changes)
@@ -171,7 +209,7 @@ func (s *Service) maybeFillEntriesFromCheckpoint(ctx context.Context,
 ) (uint32, chan error, error) {
 	checkPointFillErr := make(chan error, 1)
 	// Skip creating a ledger-entry baseline if the DB was initialized
-	curLedgerSeq, err := s.db.GetLatestLedgerSequence(ctx)
+	curLedgerSeq, err := s.lastIngestedLedger(ctx)
 	if errors.Is(err, db.ErrEmptyDB) {
 		var checkpointLedger uint32
 		root, rootErr := archive.GetRootHAS()
@@ -204,6 +242,35 @@ func (s *Service) maybeFillEntriesFromCheckpoint(ctx context.Context,
 		s.ledgerBackend.PrepareRange(prepareRangeCtx, backends.UnboundedRange(nextLedgerSeq))
 }
 
+// lastIngestedLedger returns the ledger after which ingestion should resume, reconciling
+// the ingestion checkpoint with the latest stored ledger. It returns db.ErrEmptyDB if
+// nothing was ingested yet.
+func (s *Service) lastIngestedLedger(ctx context.Context) (uint32, error) {
+	checkpoint, checkpointErr := s.db.GetIngestionCheckpoint(ctx)
+	if checkpointErr != nil && !errors.Is(checkpointErr, db.ErrEmptyDB) {
+		return 0, checkpointErr
+	}
+	latestLedgerSeq, err := s.db.GetLatestLedgerSequence(ctx)
+	switch {
+	case errors.Is(checkpointErr, db.ErrEmptyDB):
+		// no checkpoint was recorded yet (e.g. the DB was created by an older version)
+		return latestLedgerSeq, err
+	case errors.Is(err, db.ErrEmptyDB):
+		// the ledger entries were committed at the checkpoint without storing any ledger,
+		// which happens right after creating the ledger-entry baseline
+		return checkpoint, nil
+	case err != nil:
+		return 0, err
+	case checkpoint != latestLedgerSeq:
+		// resume from the earliest of the two, so that no ledger is skipped
+		s.logger.WithField("checkpoint", checkpoint).
+			WithField("latestLedger", latestLedgerSeq).
+			Warn("ingestion checkpoint does not match the latest stored ledger, possibly due to a partial write")
+		return min(checkpoint, latestLedgerSeq), nil
+	}
+	return checkpoint, nil
+}
+
 func (s *Service) fillEntriesFromCheckpoint(ctx context.Context, archive historyarchive.ArchiveInterface,
 	checkpointLedger uint32,
 ) error {
@@ -320,6 +387,9 @@ func (s *Service) ingest(ctx context.Context, sequence uint32) error {
 		With(prometheus.Labels{"type": "total"}).
 		Observe(time.Since(startTime).Seconds())
 	s.metrics.latestLedgerMetric.Set(float64(sequence))
+	if s.ingestionWindow != nil {
+		s.ingestionWindow.AppendLedger(sequence, ledgerCloseMeta.LedgerCloseTime(), s.clock.Now())
+	}
 	return nil
 }
 
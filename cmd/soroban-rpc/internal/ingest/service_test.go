@@ -19,6 +19,7 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
 )
 
 type ErrorReadWriter struct{}
@@ -27,6 +28,10 @@ func (rw *ErrorReadWriter) GetLatestLedgerSequence(_ context.Context) (uint32, e
 	return 0, errors.New("could not get latest ledger sequence")
 }
 
+func (rw *ErrorReadWriter) GetIngestionCheckpoint(_ context.Context) (uint32, error) {
+	return 0, db.ErrEmptyDB
+}
+
 func (rw *ErrorReadWriter) NewTx(_ context.Context) (db.WriteTx, error) {
 	return nil, errors.New("could not create new tx")
 }
@@ -60,6 +65,41 @@ func TestRetryRunningIngestion(t *testing.T) {
 	require.ErrorContains(t, lastErr, "could not get latest ledger sequence")
 }
 
+func TestWithIngestionStopped(t *testing.T) {
+	retries := make(chan error, 10)
+	config := Config{
+		Logger:           supportlog.New(),
+		DB:               &ErrorReadWriter{},
+		Timeout:          time.Second,
+		OnIngestionRetry: func(err error, _ time.Duration) { retries <- err },
+		Daemon:           interfaces.MakeNoOpDeamon(),
+	}
+	service := NewService(config)
+	<-retries
+
+	err := service.WithIngestionStopped(func() error {
+		// the ingestion isn't retried while stopped
+		for len(retries) > 0 {
+			<-retries
+		}
+		time.Sleep(100 * time.Millisecond)
+		assert.Empty(t, retries)
+		return errors.New("restore failed")
+	})
+	require.ErrorContains(t, err, "restore failed")
+
+	// the ingestion is resumed
+	select {
+	case err := <-retries:
+		require.ErrorContains(t, err, "could not get latest ledger sequence")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the ingestion wasn't resumed")
+	}
+
+	require.NoError(t, service.Close())
+	require.Error(t, service.WithIngestionStopped(func() error { return nil }))
+}
+
 func TestIngestion(t *testing.T) {
 	ctx := context.Background()
 	mockDB, mockLedgerBackend, mockTx := setupMocks()
@@ -72,6 +112,43 @@ func TestIngestion(t *testing.T) {
 	require.NoError(t, service.ingest(ctx, sequence))
 
 	assertMockExpectations(t, mockDB, mockTx, mockLedgerBackend)
+	rate := service.ingestionWindow.Rate(time.Minute, time.Now())
+	assert.Equal(t, uint32(1), rate.LedgerCount)
+}
+
+func TestLastIngestedLedger(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name          string
+		checkpoint    uint32
+		checkpointErr error
+		latest        uint32
+		latestErr     error
+		expected      uint32
+		expectedErr   error
+	}{
+		{name: "consistent", checkpoint: 10, latest: 10, expected: 10},
+		{name: "no checkpoint", checkpointErr: db.ErrEmptyDB, latest: 10, expected: 10},
+		{name: "empty", checkpointErr: db.ErrEmptyDB, latestErr: db.ErrEmptyDB, expectedErr: db.ErrEmptyDB},
+		{name: "baseline only", checkpoint: 64, latestErr: db.ErrEmptyDB, expected: 64},
+		{name: "checkpoint behind", checkpoint: 9, latest: 10, expected: 9},
+		{name: "checkpoint ahead", checkpoint: 11, latest: 10, expected: 10},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mockLedgerBackend, _ := setupMocks()
+			service := setupService(mockDB, mockLedgerBackend)
+			mockDB.On("GetIngestionCheckpoint", ctx).Return(tc.checkpoint, tc.checkpointErr).Once()
+			mockDB.On("GetLatestLedgerSequence", ctx).Return(tc.latest, tc.latestErr).Once()
+
+			sequence, err := service.lastIngestedLedger(ctx)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sequence)
+		})
+	}
 }
 
 func setupMocks() (*MockDB, *ledgerbackend.MockDatabaseBackend, *MockTx) {
@@ -87,6 +164,7 @@ func setupService(mockDB *MockDB, mockLedgerBackend *ledgerbackend.MockDatabaseB
 		Logger:            supportlog.New(),
 		DB:                mockDB,
 		FeeWindows:        feewindow.NewFeeWindows(1, 1, network.TestNetworkPassphrase, nil),
+		IngestionWindow:   ingestionwindow.NewIngestionWindow(ingestionwindow.DefaultRetention),
 		LedgerBackend:     mockLedgerBackend,
 		Daemon:            daemon,
 		NetworkPassPhrase: network.TestNetworkPassphrase,
@@ -0,0 +1,126 @@
+package ingestionwindow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+// DefaultRetention is the number of ingestion timestamps kept by default,
+// (roughly) an hour worth of ledgers when following the network tip.
+const DefaultRetention = 720
+
+// IngestionRate summarizes how many ledgers were ingested over a recent window.
+type IngestionRate struct {
+	// Window is the period the rate was computed over. It can be shorter than
+	// the requested period if the window doesn't hold enough history.
+	Window time.Duration
+	// LedgerCount is the number of ledgers ingested within Window.
+	LedgerCount uint32
+	// LedgersPerMinute is LedgerCount normalized to a one-minute period.
+	LedgersPerMinute float64
+}
+
+// IngestionWindow keeps the wall-clock time at which each of the most recent
+// ledgers was ingested, so that the ingestion rate can be computed, and notifies
+// the waiters of new ledgers (see WaitForLedger).
+type IngestionWindow struct {
+	lock            sync.RWMutex
+	retentionWindow uint32
+	ingestedAt      *ledgerbucketwindow.LedgerBucketWindow[time.Time]
+	// newLedger is closed (and replaced) whenever a ledger is ingested
+	newLedger chan struct{}
+}
+
+func NewIngestionWindow(retentionWindow uint32) *IngestionWindow {
+	return &IngestionWindow{
+		retentionWindow: retentionWindow,
+		ingestedAt:      ledgerbucketwindow.NewLedgerBucketWindow[time.Time](retentionWindow),
+		newLedger:       make(chan struct{}),
+	}
+}
+
+// AppendLedger records that the ledger was ingested at the given time.
+func (w *IngestionWindow) AppendLedger(sequence uint32, closeTime int64, ingestedAt time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	bucket := ledgerbucketwindow.LedgerBucket[time.Time]{
+		LedgerSeq:            sequence,
+		LedgerCloseTimestamp: closeTime,
+		BucketContent:        ingestedAt,
+	}
+	if _, err := w.ingestedAt.Append(bucket); err != nil {
+		// The ledgers stopped being contiguous (e.g. ingestion was restarted
+		// from a different ledger), the previous timestamps are meaningless now.
+		w.ingestedAt = ledgerbucketwindow.NewLedgerBucketWindow[time.Time](w.retentionWindow)
+		_, _ = w.ingestedAt.Append(bucket)
+	}
+	close(w.newLedger)
+	w.newLedger = make(chan struct{})
+}
+
+// WaitForLedger blocks until a ledger later than sequence is ingested, returning
+// false if the context is done first.
+func (w *IngestionWindow) WaitForLedger(ctx context.Context, sequence uint32) bool {
+	for {
+		w.lock.RLock()
+		length := w.ingestedAt.Len()
+		ingested := length > 0 && w.ingestedAt.Get(length-1).LedgerSeq > sequence
+		newLedger := w.newLedger
+		w.lock.RUnlock()
+		if ingested {
+			return true
+		}
+		select {
+		case <-newLedger:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// LatestLedger returns the sequence of the latest ingested ledger, and false if no
+// ledger was ingested yet.
+func (w *IngestionWindow) LatestLedger() (uint32, bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	length := w.ingestedAt.Len()
+	if length == 0 {
+		return 0, false
+	}
+	return w.ingestedAt.Get(length - 1).LedgerSeq, true
+}
+
+// Rate computes the ingestion rate over the period preceding now.
+func (w *IngestionWindow) Rate(period time.Duration, now time.Time) IngestionRate {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	cutoff := now.Add(-period)
+	length := w.ingestedAt.Len()
+	var count uint32
+	for i := length; i > 0; i-- {
+		if w.ingestedAt.Get(i - 1).BucketContent.Before(cutoff) {
+			break
+		}
+		count++
+	}
+
+	window := period
+	if count > 0 && count == length && length == w.retentionWindow {
+		// All the retained ledgers fall inside the period, so older
+		// ingestions may have been evicted. Only account for what we know.
+		window = now.Sub(w.ingestedAt.Get(0).BucketContent)
+	}
+
+	rate := IngestionRate{
+		Window:      window,
+		LedgerCount: count,
+	}
+	if window > 0 {
+		rate.LedgersPerMinute = float64(count) / window.Minutes()
+	}
+	return rate
+}
@@ -0,0 +1,90 @@
+package ingestionwindow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestionRate(t *testing.T) {
+	window := NewIngestionWindow(100)
+	start := time.Unix(1_000_000, 0)
+
+	// nothing ingested yet
+	rate := window.Rate(time.Minute, start)
+	assert.Equal(t, IngestionRate{Window: time.Minute}, rate)
+
+	// one ledger every 5 seconds for 5 minutes
+	for i := range 60 {
+		window.AppendLedger(uint32(i+1), 0, start.Add(time.Duration(i)*5*time.Second))
+	}
+	now := start.Add(300 * time.Second)
+
+	rate = window.Rate(time.Minute, now)
+	assert.Equal(t, time.Minute, rate.Window)
+	assert.Equal(t, uint32(12), rate.LedgerCount)
+	assert.InDelta(t, 12.0, rate.LedgersPerMinute, 0.001)
+
+	rate = window.Rate(10*time.Minute, now)
+	assert.Equal(t, uint32(60), rate.LedgerCount)
+	assert.InDelta(t, 6.0, rate.LedgersPerMinute, 0.001)
+}
+
+func TestIngestionRateFullWindow(t *testing.T) {
+	window := NewIngestionWindow(10)
+	start := time.Unix(1_000_000, 0)
+
+	// catching up: 100 ledgers per second
+	for i := range 50 {
+		window.AppendLedger(uint32(i+1), 0, start.Add(time.Duration(i)*10*time.Millisecond))
+	}
+	now := start.Add(500 * time.Millisecond)
+
+	// only the last 10 ingestions are retained, the rate must be computed
+	// over the period they span rather than the requested one
+	rate := window.Rate(time.Minute, now)
+	require.Equal(t, uint32(10), rate.LedgerCount)
+	assert.Equal(t, 100*time.Millisecond, rate.Window)
+	assert.InDelta(t, 6000.0, rate.LedgersPerMinute, 0.001)
+}
+
+func TestIngestionWindowNonContiguous(t *testing.T) {
+	window := NewIngestionWindow(10)
+	start := time.Unix(1_000_000, 0)
+
+	window.AppendLedger(1, 0, start)
+	window.AppendLedger(2, 0, start.Add(time.Second))
+	// ingestion restarted at a later ledger
+	window.AppendLedger(10, 0, start.Add(2*time.Second))
+
+	rate := window.Rate(time.Minute, start.Add(3*time.Second))
+	assert.Equal(t, uint32(1), rate.LedgerCount)
+}
+
+func TestLatestLedger(t *testing.T) {
+	window := NewIngestionWindow(10)
+	_, ok := window.LatestLedger()
+	assert.False(t, ok)
+
+	window.AppendLedger(5, 0, time.Now())
+	window.AppendLedger(6, 0, time.Now())
+	latest, ok := window.LatestLedger()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(6), latest)
+}
+
+func TestWaitForLedger(t *testing.T) {
+	window := NewIngestionWindow(100)
+	window.AppendLedger(10, 0, time.Now())
+	assert.True(t, window.WaitForLedger(context.Background(), 9))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, window.WaitForLedger(ctx, 10))
+
+	go window.AppendLedger(11, 0, time.Now())
+	assert.True(t, window.WaitForLedger(context.Background(), 10))
+}
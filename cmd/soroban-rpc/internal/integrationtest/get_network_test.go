@@ -2,10 +2,13 @@ package integrationtest
 
 import (
 	"context"
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stellar/go/network"
+
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/integrationtest/infrastructure"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 )
@@ -22,5 +25,7 @@ func TestGetNetworkSucceeds(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, infrastructure.FriendbotURL, result.FriendbotURL)
 	assert.Equal(t, infrastructure.StandaloneNetworkPassphrase, result.Passphrase)
+	networkID := network.ID(infrastructure.StandaloneNetworkPassphrase)
+	assert.Equal(t, hex.EncodeToString(networkID[:]), result.NetworkID)
 	assert.GreaterOrEqual(t, result.ProtocolVersion, 20)
 }
@@ -19,4 +19,6 @@ func TestHealth(t *testing.T) {
 	assert.Greater(t, result.OldestLedger, uint32(0))
 	assert.Greater(t, result.LatestLedger, uint32(0))
 	assert.GreaterOrEqual(t, result.LatestLedger, result.OldestLedger)
+	require.NotNil(t, result.IngestionRate)
+	assert.Positive(t, result.IngestionRate.Since)
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -22,8 +24,11 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/network"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/txdenylist"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
 )
 
 const (
@@ -39,6 +44,11 @@ type Handler struct {
 	bridge jhttp.Bridge
 	logger *log.Entry
 	http.Handler
+
+	daemon interfaces.Daemon
+	// enabledMethods are the methods served by the handler, whose limits apply to the HTTP
+	// endpoints serving the same data (see WrapHTTPEndpoint)
+	enabledMethods map[string]rpcMethod
 }
 
 // Close closes all the resources held by the Handler instances.
@@ -51,13 +61,47 @@ func (h Handler) Close() {
 
 type HandlerParams struct {
 	FeeStatWindows    *feewindow.FeeWindows
+	IngestionWindow   *ingestionwindow.IngestionWindow
 	TransactionReader db.TransactionReader
-	EventReader       db.EventReader
-	LedgerEntryReader db.LedgerEntryReader
-	LedgerReader      db.LedgerReader
-	Logger            *log.Entry
-	PreflightGetter   methods.PreflightGetter
-	Daemon            interfaces.Daemon
+	// TransactionMemoReader serves getTransactionsByMemo.
+	TransactionMemoReader db.TransactionMemoReader
+	// TransactionFilterReader serves the filtered getTransactions requests.
+	TransactionFilterReader db.TransactionFilterReader
+	// ContractCreationReader serves getContractCreation.
+	ContractCreationReader db.ContractCreationReader
+	EventReader            db.EventReader
+	LedgerEntryReader      db.LedgerEntryReader
+	// LedgerReader strips the denied transactions from the ledgers it reads (see
+	// txdenylist.Denylist.LedgerReader).
+	LedgerReader db.LedgerReader
+	// IndexedLedgerReader, which defaults to LedgerReader, reads the ledgers as stored. It serves
+	// the methods reading the transactions of the ledgers by application order, at the locations
+	// of the indexes, which skip the denied transactions themselves.
+	IndexedLedgerReader db.LedgerReader
+	Logger              *log.Entry
+	PreflightGetter     methods.PreflightGetter
+	Daemon              interfaces.Daemon
+
+	// TransactionDenylist, if set, lists the transactions which must not be served.
+	TransactionDenylist *txdenylist.Denylist
+	// DBCircuitBreaker, if set, is the circuit breaker guarding the readers. Its state is reported by getHealth.
+	DBCircuitBreaker *db.CircuitBreaker
+	// TransactionStoreLagChecker, if set, is used by getHealth to detect the transaction store
+	// lagging behind the ledger store.
+	TransactionStoreLagChecker methods.TransactionStoreLagChecker
+	// MaxDBSizeChecker, if set, is used by getHealth to detect the database exceeding its maximum size.
+	MaxDBSizeChecker methods.MaxDBSizeChecker
+	// Clock defaults to the real clock if nil.
+	Clock util.Clock
+	// ReadSnapshotter, if set, serves the methods issuing several reads of the database from
+	// consistent read snapshots.
+	ReadSnapshotter db.ReadSnapshotter
+	// LedgerKeyTransactionReader serves getTransactionsByLedgerKey.
+	LedgerKeyTransactionReader db.LedgerKeyTransactionReader
+	// ContractActivityReader serves getContractActivity.
+	ContractActivityReader db.ContractActivityReader
+	// LedgerCloseTimeReader serves getLedgerCloseTimes.
+	LedgerCloseTimeReader db.LedgerCloseTimeReader
 }
 
 func decorateHandlers(daemon interfaces.Daemon, logger *log.Entry, m handler.Map) handler.Map {
@@ -133,8 +177,125 @@ func logResponse(logger *log.Entry, reqID string, duration time.Duration, status
 	}
 }
 
+type rpcMethod struct {
+	methodName           string
+	underlyingHandler    jrpc2.Handler
+	queueLimit           uint
+	longName             string
+	requestDurationLimit time.Duration
+	// acceptsFormat indicates whether the method accepts xdrFormat, in which case the
+	// configured default format applies to it
+	acceptsFormat bool
+	// readSnapshot indicates whether the method issues several reads of the database which
+	// must see the same ledgers (e.g. a transaction and the ledger it belongs to), and must then
+	// be served from a read snapshot. The methods which only read the ledger range along with
+	// their data don't need one, since they report the ledgers trimmed meanwhile as missing.
+	readSnapshot bool
+	// cacheable indicates whether the results of the method can be cached (see
+	// cfg.MethodCacheTTLs), and cachedPerLedger whether the cached results must be dropped
+	// when a ledger is ingested, since they follow the latest ledger
+	cacheable       bool
+	cachedPerLedger bool
+	// cacheKey, if set, derives the cache keys of the requests instead of
+	// methods.ParamsCacheKey (see methods.ResultCacheConfig.Key)
+	cacheKey func(request *jrpc2.Request) (string, error)
+}
+
+// withGetMethods adds the getMethods method to the given methods, returning the set of
+// disabled methods. It fails if any of the disabled methods is unknown.
+func withGetMethods(cfg *config.Config, handlers []rpcMethod) ([]rpcMethod, map[string]struct{}, error) {
+	getMethods := rpcMethod{
+		methodName:           "getMethods",
+		longName:             "get_methods",
+		queueLimit:           cfg.RequestBacklogGetMethodsQueueLimit,
+		requestDurationLimit: cfg.MaxGetMethodsExecutionDuration,
+	}
+	handlers = append(handlers, getMethods)
+
+	disabled := make(map[string]struct{}, len(cfg.DisabledMethods))
+	for _, name := range cfg.DisabledMethods {
+		disabled[name] = struct{}{}
+	}
+	infos := make([]methods.MethodInfo, 0, len(handlers))
+	known := make(map[string]struct{}, len(handlers))
+	for _, handler := range handlers {
+		_, isDisabled := disabled[handler.methodName]
+		infos = append(infos, methods.MethodInfo{Name: handler.methodName, Enabled: !isDisabled})
+		known[handler.methodName] = struct{}{}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	var unknown []string
+	for name := range disabled {
+		if _, ok := known[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, nil, fmt.Errorf("unknown disabled methods: %s", strings.Join(unknown, ", "))
+	}
+
+	handlers[len(handlers)-1].underlyingHandler = methods.NewGetMethodsHandler(infos)
+	return handlers, disabled, nil
+}
+
+// withMethodAliases registers the configured aliases of the methods in handlersMap, logging
+// their calls so that operators can track the clients still using them. The aliases of
+// disabled methods aren't registered either.
+func withMethodAliases(logger *log.Entry, cfg *config.Config, handlers []rpcMethod, handlersMap handler.Map) error {
+	aliases, err := config.ParseMethodAliases(cfg.MethodAliases)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]struct{}, len(handlers))
+	for _, handler := range handlers {
+		known[handler.methodName] = struct{}{}
+	}
+	for alias, method := range aliases {
+		if _, ok := known[alias]; ok {
+			return fmt.Errorf("method alias %q is the name of a method", alias)
+		}
+		if _, ok := known[method]; !ok {
+			return fmt.Errorf("method alias %q refers to unknown method %q", alias, method)
+		}
+		h, ok := handlersMap[method]
+		if !ok {
+			continue
+		}
+		aliasLogger := logger.WithFields(log.F{"alias": alias, "method": method})
+		handlersMap[alias] = func(ctx context.Context, r *jrpc2.Request) (interface{}, error) {
+			aliasLogger.Info("method called through an alias")
+			return h(ctx, r)
+		}
+	}
+	return nil
+}
+
+// resultCacheTTLs returns the configured result cache TTLs of the methods, failing if any of
+// them isn't a known cacheable method.
+func resultCacheTTLs(cfg *config.Config, handlers []rpcMethod) (map[string]time.Duration, error) {
+	ttls, err := config.ParseMethodCacheTTLs(cfg.MethodCacheTTLs)
+	if err != nil {
+		return nil, err
+	}
+	cacheable := make(map[string]bool, len(handlers))
+	for _, handler := range handlers {
+		cacheable[handler.methodName] = handler.cacheable
+	}
+	for method := range ttls {
+		isCacheable, ok := cacheable[method]
+		if !ok {
+			return nil, fmt.Errorf("method cache TTL refers to unknown method %q", method)
+		}
+		if !isCacheable {
+			return nil, fmt.Errorf("the results of method %q can't be cached", method)
+		}
+	}
+	return ttls, nil
+}
+
 // NewJSONRPCHandler constructs a Handler instance
-func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
+func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) (Handler, error) {
 	bridgeOptions := jhttp.BridgeOptions{
 		Server: &jrpc2.ServerOptions{
 			Logger: func(text string) { params.Logger.Debug(text) },
@@ -142,18 +303,25 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 	}
 
 	retentionWindow := cfg.HistoryRetentionWindow
+	clock := params.Clock
+	if clock == nil {
+		clock = util.RealClock{}
+	}
+	indexedLedgerReader := params.IndexedLedgerReader
+	if indexedLedgerReader == nil {
+		indexedLedgerReader = params.LedgerReader
+	}
 
-	handlers := []struct {
-		methodName           string
-		underlyingHandler    jrpc2.Handler
-		queueLimit           uint
-		longName             string
-		requestDurationLimit time.Duration
-	}{
+	// shared by the batch methods, so that the conversion workers bound their overall CPU usage
+	jsonConverter := methods.NewJSONConverter(cfg.JSONConversionWorkerCount, cfg.JSONConversionTimeout, clock)
+
+	handlers := []rpcMethod{
 		{
 			methodName: "getHealth",
 			underlyingHandler: methods.NewHealthCheck(
-				retentionWindow, params.LedgerReader, cfg.MaxHealthyLedgerLatency),
+				retentionWindow, params.LedgerReader, params.IngestionWindow, cfg.MaxHealthyLedgerLatency, clock,
+				params.DBCircuitBreaker, params.TransactionStoreLagChecker, cfg.MaxTransactionStoreLag,
+				params.MaxDBSizeChecker),
 			longName:             "get_health",
 			queueLimit:           cfg.RequestBacklogGetHealthQueueLimit,
 			requestDurationLimit: cfg.MaxGetHealthExecutionDuration,
@@ -169,6 +337,8 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			),
 
 			longName:             "get_events",
+			readSnapshot:         true,
+			acceptsFormat:        true,
 			queueLimit:           cfg.RequestBacklogGetEventsQueueLimit,
 			requestDurationLimit: cfg.MaxGetEventsExecutionDuration,
 		},
@@ -189,13 +359,18 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			underlyingHandler: methods.NewGetVersionInfoHandler(params.Logger, params.LedgerEntryReader,
 				params.LedgerReader, params.Daemon),
 			longName:             "get_version_info",
+			cacheable:            true,
 			queueLimit:           cfg.RequestBacklogGetVersionInfoQueueLimit,
 			requestDurationLimit: cfg.MaxGetVersionInfoExecutionDuration,
 		},
 		{
-			methodName:           "getLatestLedger",
-			underlyingHandler:    methods.NewGetLatestLedgerHandler(params.LedgerEntryReader, params.LedgerReader),
+			methodName: "getLatestLedger",
+			underlyingHandler: methods.NewGetLatestLedgerHandler(params.LedgerEntryReader, params.LedgerReader,
+				params.IngestionWindow, cfg.GetLatestLedgerMaxWait),
 			longName:             "get_latest_ledger",
+			cacheable:            true,
+			cachedPerLedger:      true,
+			cacheKey:             methods.GetLatestLedgerCacheKey,
 			queueLimit:           cfg.RequestBacklogGetLatestLedgerQueueLimit,
 			requestDurationLimit: cfg.MaxGetLatestLedgerExecutionDuration,
 		},
@@ -203,6 +378,7 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			methodName:           "getLedgerEntry",
 			underlyingHandler:    methods.NewGetLedgerEntryHandler(params.Logger, params.LedgerEntryReader),
 			longName:             "get_ledger_entry",
+			acceptsFormat:        true,
 			queueLimit:           cfg.RequestBacklogGetLedgerEntriesQueueLimit, // share with getLedgerEntries
 			requestDurationLimit: cfg.MaxGetLedgerEntriesExecutionDuration,
 		},
@@ -210,29 +386,50 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			methodName:           "getLedgerEntries",
 			underlyingHandler:    methods.NewGetLedgerEntriesHandler(params.Logger, params.LedgerEntryReader),
 			longName:             "get_ledger_entries",
+			acceptsFormat:        true,
 			queueLimit:           cfg.RequestBacklogGetLedgerEntriesQueueLimit,
 			requestDurationLimit: cfg.MaxGetLedgerEntriesExecutionDuration,
 		},
 		{
-			methodName:           "getTransaction",
-			underlyingHandler:    methods.NewGetTransactionHandler(params.Logger, params.TransactionReader, params.LedgerReader),
+			methodName: "getTransaction",
+			underlyingHandler: methods.NewGetTransactionHandler(params.Logger,
+				params.TransactionDenylist.TransactionReader(params.TransactionReader), indexedLedgerReader,
+				cfg.MaxEventsPerTransaction),
 			longName:             "get_transaction",
+			readSnapshot:         true,
+			acceptsFormat:        true,
 			queueLimit:           cfg.RequestBacklogGetTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxGetTransactionExecutionDuration,
 		},
 		{
 			methodName: "getTransactions",
-			underlyingHandler: methods.NewGetTransactionsHandler(params.Logger, params.LedgerReader,
-				cfg.MaxTransactionsLimit, cfg.DefaultTransactionsLimit, cfg.NetworkPassphrase),
+			underlyingHandler: methods.NewGetTransactionsHandler(params.Logger, indexedLedgerReader, params.TransactionReader,
+				cfg.MaxTransactionsLimit, cfg.DefaultTransactionsLimit, cfg.NetworkPassphrase, params.TransactionDenylist,
+				cfg.MaxEventsPerTransaction, jsonConverter, cfg.MaxTransactionsResponseSize,
+				params.TransactionFilterReader),
 			longName:             "get_transactions",
+			readSnapshot:         true,
+			acceptsFormat:        true,
 			queueLimit:           cfg.RequestBacklogGetTransactionsQueueLimit,
 			requestDurationLimit: cfg.MaxGetTransactionsExecutionDuration,
 		},
+		{
+			methodName: "getTransactionsByMemo",
+			underlyingHandler: methods.NewGetTransactionsByMemoHandler(params.Logger, indexedLedgerReader,
+				params.TransactionMemoReader, cfg.MaxTransactionsLimit, cfg.DefaultTransactionsLimit,
+				cfg.NetworkPassphrase, params.TransactionDenylist, cfg.MaxEventsPerTransaction, jsonConverter),
+			longName:             "get_transactions_by_memo",
+			readSnapshot:         true,
+			acceptsFormat:        true,
+			queueLimit:           cfg.RequestBacklogGetTransactionsByMemoQueueLimit,
+			requestDurationLimit: cfg.MaxGetTransactionsByMemoExecutionDuration,
+		},
 		{
 			methodName: "sendTransaction",
 			underlyingHandler: methods.NewSendTransactionHandler(
 				params.Daemon, params.Logger, params.LedgerReader, cfg.NetworkPassphrase),
 			longName:             "send_transaction",
+			acceptsFormat:        true,
 			queueLimit:           cfg.RequestBacklogSendTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxSendTransactionExecutionDuration,
 		},
@@ -242,6 +439,7 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 				params.Logger, params.LedgerEntryReader, params.LedgerReader,
 				params.Daemon, params.PreflightGetter),
 			longName:             "simulate_transaction",
+			acceptsFormat:        true,
 			queueLimit:           cfg.RequestBacklogSimulateTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxSimulateTransactionExecutionDuration,
 		},
@@ -249,12 +447,128 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			methodName:           "getFeeStats",
 			underlyingHandler:    methods.NewGetFeeStatsHandler(params.FeeStatWindows, params.LedgerReader, params.Logger),
 			longName:             "get_fee_stats",
+			cacheable:            true,
+			cachedPerLedger:      true,
 			queueLimit:           cfg.RequestBacklogGetFeeStatsTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxGetFeeStatsExecutionDuration,
 		},
+		{
+			methodName: "getRetentionStatus",
+			underlyingHandler: methods.NewGetRetentionStatusHandler(params.LedgerReader, methods.RetentionWindows{
+				History:         retentionWindow,
+				ClassicFeeStats: cfg.ClassicFeeStatsLedgerRetentionWindow,
+				SorobanFeeStats: cfg.SorobanFeeStatsLedgerRetentionWindow,
+			}),
+			longName:             "get_retention_status",
+			queueLimit:           cfg.RequestBacklogGetRetentionStatusQueueLimit,
+			requestDurationLimit: cfg.MaxGetRetentionStatusExecutionDuration,
+		},
+		{
+			methodName:           "diffLedgerHeaders",
+			underlyingHandler:    methods.NewDiffLedgerHeadersHandler(params.LedgerReader),
+			longName:             "diff_ledger_headers",
+			queueLimit:           cfg.RequestBacklogDiffLedgerHeadersQueueLimit,
+			requestDurationLimit: cfg.MaxDiffLedgerHeadersExecutionDuration,
+		},
+		{
+			methodName: "getContractCreation",
+			underlyingHandler: methods.NewGetContractCreationHandler(params.LedgerReader,
+				params.ContractCreationReader),
+			longName:             "get_contract_creation",
+			queueLimit:           cfg.RequestBacklogGetContractCreationQueueLimit,
+			requestDurationLimit: cfg.MaxGetContractCreationExecutionDuration,
+		},
+		{
+			methodName: "getLargestTransactions",
+			underlyingHandler: methods.NewGetLargestTransactionsHandler(params.LedgerReader,
+				cfg.NetworkPassphrase, cfg.MaxLargestTransactionsLedgers),
+			longName:             "get_largest_transactions",
+			queueLimit:           cfg.RequestBacklogGetLargestTransactionsQueueLimit,
+			requestDurationLimit: cfg.MaxGetLargestTransactionsExecutionDuration,
+		},
+		{
+			methodName:           "computeTransactionHash",
+			underlyingHandler:    methods.NewComputeTransactionHashHandler(cfg.NetworkPassphrase),
+			longName:             "compute_transaction_hash",
+			queueLimit:           cfg.RequestBacklogComputeTransactionHashQueueLimit,
+			requestDurationLimit: cfg.MaxComputeTransactionHashExecutionDuration,
+		},
+		{
+			methodName: "getTransactionsByLedgerKey",
+			underlyingHandler: methods.NewGetTransactionsByLedgerKeyHandler(params.Logger, indexedLedgerReader,
+				params.LedgerKeyTransactionReader, cfg.MaxTransactionsLimit, cfg.DefaultTransactionsLimit,
+				cfg.NetworkPassphrase, params.TransactionDenylist, cfg.MaxEventsPerTransaction, jsonConverter),
+			longName:             "get_transactions_by_ledger_key",
+			readSnapshot:         true,
+			acceptsFormat:        true,
+			queueLimit:           cfg.RequestBacklogGetTransactionsByLedgerKeyQueueLimit,
+			requestDurationLimit: cfg.MaxGetTransactionsByLedgerKeyExecutionDuration,
+		},
+		{
+			methodName:           "getContractActivity",
+			underlyingHandler:    methods.NewGetContractActivityHandler(params.LedgerReader, params.ContractActivityReader),
+			longName:             "get_contract_activity",
+			queueLimit:           cfg.RequestBacklogGetContractActivityQueueLimit,
+			requestDurationLimit: cfg.MaxGetContractActivityExecutionDuration,
+		},
+		{
+			methodName:           "getLedgerCloseTimes",
+			underlyingHandler:    methods.NewGetLedgerCloseTimesHandler(params.LedgerReader, params.LedgerCloseTimeReader),
+			longName:             "get_ledger_close_times",
+			queueLimit:           cfg.RequestBacklogGetLedgerCloseTimesQueueLimit,
+			requestDurationLimit: cfg.MaxGetLedgerCloseTimesExecutionDuration,
+		},
+		{
+			methodName: "getTransactionsByHash",
+			underlyingHandler: methods.NewGetTransactionsByHashHandler(params.Logger,
+				params.TransactionDenylist.TransactionReader(params.TransactionReader), params.LedgerReader,
+				cfg.MaxEventsPerTransaction, cfg.TransactionsByHashConcurrency),
+			longName: "get_transactions_by_hash",
+			// the reads of a read snapshot share its connection, which would serialize the
+			// concurrent lookups (each of which reads a single transaction)
+			readSnapshot:         cfg.TransactionsByHashConcurrency <= 1,
+			acceptsFormat:        true,
+			queueLimit:           cfg.RequestBacklogGetTransactionsByHashQueueLimit,
+			requestDurationLimit: cfg.MaxGetTransactionsByHashExecutionDuration,
+		},
+		{
+			methodName: "getLedger",
+			underlyingHandler: methods.NewGetLedgerHandler(
+				params.LedgerReader, params.LedgerCloseTimeReader, cfg.LedgerCloseTimeTarget),
+			longName:             "get_ledger",
+			acceptsFormat:        true,
+			cacheable:            true,
+			cachedPerLedger:      true,
+			queueLimit:           cfg.RequestBacklogGetLedgerQueueLimit,
+			requestDurationLimit: cfg.MaxGetLedgerExecutionDuration,
+		},
+	}
+	handlers, disabledMethods, err := withGetMethods(cfg, handlers)
+	if err != nil {
+		return Handler{}, err
 	}
+	cacheTTLs, err := resultCacheTTLs(cfg, handlers)
+	if err != nil {
+		return Handler{}, err
+	}
+	cacheHitsMetric := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: params.Daemon.MetricsNamespace(), Subsystem: "json_rpc",
+		Name: "result_cache_hits_total",
+		Help: "Number of JSON RPC requests served from the result cache",
+	}, []string{"endpoint"})
+	cacheMissesMetric := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: params.Daemon.MetricsNamespace(), Subsystem: "json_rpc",
+		Name: "result_cache_misses_total",
+		Help: "Number of JSON RPC requests of cached methods not served from the result cache",
+	}, []string{"endpoint"})
+	params.Daemon.MetricsRegistry().MustRegister(cacheHitsMetric, cacheMissesMetric)
 	handlersMap := handler.Map{}
+	enabledMethods := make(map[string]rpcMethod, len(handlers))
 	for _, handler := range handlers {
+		if _, ok := disabledMethods[handler.methodName]; ok {
+			continue
+		}
+		enabledMethods[handler.methodName] = handler
 		queueLimiterGaugeName := handler.longName + "_inflight_requests"
 		queueLimiterGaugeHelp := "Number of concurrenty in-flight " + handler.methodName + " requests"
 
@@ -263,8 +577,29 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			Name: queueLimiterGaugeName,
 			Help: queueLimiterGaugeHelp,
 		})
+		underlyingHandler := handler.underlyingHandler
+		if handler.acceptsFormat {
+			underlyingHandler = methods.WithDefaultFormat(underlyingHandler, cfg.DefaultXDRFormat)
+		}
+		underlyingHandler = methods.WithEmptySlices(underlyingHandler, cfg.EmptySlices)
+		if handler.readSnapshot {
+			underlyingHandler = methods.WithReadSnapshot(underlyingHandler, params.ReadSnapshotter, params.Logger)
+		}
+		if ttl, ok := cacheTTLs[handler.methodName]; ok {
+			cacheConfig := methods.ResultCacheConfig{
+				TTL:    ttl,
+				Key:    handler.cacheKey,
+				Clock:  clock,
+				Hits:   cacheHitsMetric.WithLabelValues(handler.methodName),
+				Misses: cacheMissesMetric.WithLabelValues(handler.methodName),
+			}
+			if handler.cachedPerLedger && params.IngestionWindow != nil {
+				cacheConfig.LatestLedger = params.IngestionWindow.LatestLedger
+			}
+			underlyingHandler = methods.WithResultCache(underlyingHandler, cacheConfig)
+		}
 		queueLimiter := network.MakeJrpcBacklogQueueLimiter(
-			handler.underlyingHandler,
+			underlyingHandler,
 			queueLimiterGauge,
 			uint64(handler.queueLimit),
 			params.Logger)
@@ -297,6 +632,9 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			params.Logger)
 		handlersMap[handler.methodName] = durationLimiter.Handle
 	}
+	if err := withMethodAliases(params.Logger, cfg, handlers, handlersMap); err != nil {
+		return Handler{}, err
+	}
 	bridge := jhttp.NewBridge(decorateHandlers(
 		params.Daemon,
 		params.Logger,
@@ -345,8 +683,56 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 	})
 
 	return Handler{
-		bridge:  bridge,
-		logger:  params.Logger,
-		Handler: corsMiddleware.Handler(handler),
+		bridge:         bridge,
+		logger:         params.Logger,
+		Handler:        corsMiddleware.Handler(handler),
+		daemon:         params.Daemon,
+		enabledMethods: enabledMethods,
+	}, nil
+}
+
+// WrapHTTPEndpoint applies the protections of a JSON-RPC method to an HTTP endpoint serving the
+// same data (e.g. the transaction envelopes of getTransaction), named name in its metrics. The
+// endpoint is disabled along with the method, in which case false is returned, and it's limited
+// to the request backlog and execution duration of the method. Streaming endpoints aren't
+// limited in duration, since the duration limiter buffers the responses; their streams are
+// bounded by the methods.StreamRegistry instead.
+func (h Handler) WrapHTTPEndpoint(method string, name string, endpoint http.Handler, streaming bool,
+) (http.Handler, bool) {
+	rpcMethod, ok := h.enabledMethods[method]
+	if !ok {
+		return nil, false
 	}
+	queueLimiterGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: h.daemon.MetricsNamespace(), Subsystem: "network",
+		Name: name + "_inflight_requests",
+		Help: "Number of concurrenty in-flight " + name + " requests",
+	})
+	h.daemon.MetricsRegistry().MustRegister(queueLimiterGauge)
+	wrapped := http.Handler(network.MakeHTTPBacklogQueueLimiter(endpoint, queueLimiterGauge,
+		uint64(rpcMethod.queueLimit), h.logger))
+	if streaming {
+		return wrapped, true
+	}
+
+	requestDurationWarnCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: h.daemon.MetricsNamespace(), Subsystem: "network",
+		Name: name + "_execution_threshold_warning",
+		Help: "The metric measures the count of " + name +
+			" requests that surpassed the warning threshold for execution time",
+	})
+	requestDurationLimitCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: h.daemon.MetricsNamespace(), Subsystem: "network",
+		Name: name + "_execution_threshold_limit",
+		Help: "The metric measures the count of " + name +
+			" requests that surpassed the limit threshold for execution time",
+	})
+	h.daemon.MetricsRegistry().MustRegister(requestDurationWarnCounter, requestDurationLimitCounter)
+	return network.MakeHTTPRequestDurationLimiter(
+		wrapped,
+		rpcMethod.requestDurationLimit/warningThresholdDenominator,
+		rpcMethod.requestDurationLimit,
+		requestDurationWarnCounter,
+		requestDurationLimitCounter,
+		h.logger), true
 }
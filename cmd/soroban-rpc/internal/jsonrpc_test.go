@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+)
+
+func TestWithGetMethods(t *testing.T) {
+	cfg := &config.Config{DisabledMethods: []string{"simulateTransaction"}}
+	handlers, disabled, err := withGetMethods(cfg, []rpcMethod{
+		{methodName: "simulateTransaction"},
+		{methodName: "getHealth"},
+	})
+	require.NoError(t, err)
+	require.Len(t, handlers, 3)
+	assert.Equal(t, "getMethods", handlers[2].methodName)
+	assert.Contains(t, disabled, "simulateTransaction")
+
+	result, err := handlers[2].underlyingHandler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, methods.GetMethodsResponse{Methods: []methods.MethodInfo{
+		{Name: "getHealth", Enabled: true},
+		{Name: "getMethods", Enabled: true},
+		{Name: "simulateTransaction", Enabled: false},
+	}}, result)
+}
+
+func TestWithGetMethodsUnknownMethod(t *testing.T) {
+	cfg := &config.Config{DisabledMethods: []string{"simulateTransactoin", "getHealth", "getLedger"}}
+	_, _, err := withGetMethods(cfg, []rpcMethod{
+		{methodName: "simulateTransaction"},
+		{methodName: "getHealth"},
+	})
+	require.EqualError(t, err, "unknown disabled methods: getLedger, simulateTransactoin")
+}
+
+func TestDisabledMethodNotFound(t *testing.T) {
+	var cfg config.Config
+	require.NoError(t, cfg.SetValues(func(string) (string, bool) { return "", false }))
+	cfg.DisabledMethods = []string{"getFeeStats"}
+	handler, err := NewJSONRPCHandler(&cfg, HandlerParams{
+		Daemon: interfaces.MakeNoOpDeamon(),
+		Logger: log.DefaultLogger,
+	})
+	require.NoError(t, err)
+	defer handler.Close()
+
+	call := func(method string) string {
+		body := `{"jsonrpc": "2.0", "id": 1, "method": "` + method + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		return recorder.Body.String()
+	}
+
+	assert.Contains(t, call("getFeeStats"), `"code":-32601`)
+	assert.NotContains(t, call("getMethods"), `"error"`)
+}
+
+func TestMethodAliases(t *testing.T) {
+	var cfg config.Config
+	require.NoError(t, cfg.SetValues(func(string) (string, bool) { return "", false }))
+	cfg.DisabledMethods = []string{"getFeeStats"}
+	cfg.MethodAliases = []string{"get_methods=getMethods", "get_fee_stats=getFeeStats"}
+	handler, err := NewJSONRPCHandler(&cfg, HandlerParams{
+		Daemon: interfaces.MakeNoOpDeamon(),
+		Logger: log.DefaultLogger,
+	})
+	require.NoError(t, err)
+	defer handler.Close()
+
+	call := func(method string) string {
+		body := `{"jsonrpc": "2.0", "id": 1, "method": "` + method + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		return recorder.Body.String()
+	}
+
+	assert.Equal(t, call("getMethods"), call("get_methods"))
+	assert.NotContains(t, call("get_methods"), "get_methods")
+	// aliases of disabled methods are disabled too
+	assert.Contains(t, call("get_fee_stats"), `"code":-32601`)
+
+	for _, aliases := range [][]string{
+		{"get_methods=getUnknown"},
+		{"getHealth=getMethods"},
+	} {
+		cfg.MethodAliases = aliases
+		_, err = NewJSONRPCHandler(&cfg, HandlerParams{
+			Daemon: interfaces.MakeNoOpDeamon(),
+			Logger: log.DefaultLogger,
+		})
+		assert.Error(t, err, aliases)
+	}
+}
+
+func TestMethodCacheTTLs(t *testing.T) {
+	var cfg config.Config
+	require.NoError(t, cfg.SetValues(func(string) (string, bool) { return "", false }))
+	cfg.MethodCacheTTLs = []string{"getVersionInfo=1m", "getFeeStats=5s"}
+	handler, err := NewJSONRPCHandler(&cfg, HandlerParams{
+		Daemon: interfaces.MakeNoOpDeamon(),
+		Logger: log.DefaultLogger,
+	})
+	require.NoError(t, err)
+	handler.Close()
+
+	for _, ttls := range [][]string{
+		{"getUnknown=1m"},
+		{"getTransaction=1m"},
+		{"getVersionInfo"},
+	} {
+		cfg.MethodCacheTTLs = ttls
+		_, err = NewJSONRPCHandler(&cfg, HandlerParams{
+			Daemon: interfaces.MakeNoOpDeamon(),
+			Logger: log.DefaultLogger,
+		})
+		assert.Error(t, err, ttls)
+	}
+}
+
+func TestWrapHTTPEndpoint(t *testing.T) {
+	var cfg config.Config
+	require.NoError(t, cfg.SetValues(func(string) (string, bool) { return "", false }))
+	cfg.DisabledMethods = []string{"getLedger"}
+	cfg.RequestBacklogGetTransactionQueueLimit = 1
+	handler, err := NewJSONRPCHandler(&cfg, HandlerParams{
+		Daemon: interfaces.MakeNoOpDeamon(),
+		Logger: log.DefaultLogger,
+	})
+	require.NoError(t, err)
+	defer handler.Close()
+
+	// endpoints are disabled along with the method serving the same data
+	_, ok := handler.WrapHTTPEndpoint("getLedger", "ledger_meta_http", http.NotFoundHandler(), false)
+	assert.False(t, ok)
+
+	// and limited like it
+	started, release := make(chan struct{}), make(chan struct{})
+	endpoint, ok := handler.WrapHTTPEndpoint("getTransaction", "transaction_envelope_http",
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		}), false)
+	require.True(t, ok)
+	served := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		endpoint.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		served <- recorder.Code
+	}()
+	<-started
+	recorder := httptest.NewRecorder()
+	endpoint.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	close(release)
+	assert.Equal(t, http.StatusOK, <-served)
+}
@@ -0,0 +1,112 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+)
+
+// DatabaseBackuper produces a consistent copy of the database at the given path,
+// returning the size of the copy.
+type DatabaseBackuper interface {
+	Backup(ctx context.Context, path string) (int64, error)
+}
+
+type BackupDatabaseRequest struct {
+	// Path of the backup file. Relative paths are resolved against the allowed backup directory.
+	Path string `json:"path"`
+}
+
+type BackupDatabaseResponse struct {
+	// Path is the absolute path of the produced backup.
+	Path string `json:"path"`
+	// Size is the size of the backup, in bytes.
+	Size int64 `json:"size"`
+	// DurationMs is how long the backup took, in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+}
+
+// resolveAdminPath returns the absolute path of the given path, resolved against allowedDir,
+// making sure it lies within allowedDir.
+func resolveAdminPath(allowedDir string, path string) (string, error) {
+	if path == "" {
+		return "", errors.New("path must be provided")
+	}
+	dir, err := filepath.Abs(allowedDir)
+	if err != nil {
+		return "", err
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	// resolve symlinks in the parent directory, so that they cannot be used to escape the allowed directory
+	parent, err := filepath.EvalSymlinks(filepath.Dir(filepath.Clean(path)))
+	if err != nil {
+		return "", err
+	}
+	resolved := filepath.Join(parent, filepath.Base(path))
+	rel, err := filepath.Rel(dir, resolved)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.New("path must be within the backup directory")
+	}
+	return resolved, nil
+}
+
+// resolveBackupPath returns the absolute path of the backup target, making sure it
+// lies within allowedDir and that no file exists there yet.
+func resolveBackupPath(allowedDir string, path string) (string, error) {
+	if allowedDir == "" {
+		return "", errors.New("database backups are disabled")
+	}
+	resolved, err := resolveAdminPath(allowedDir, path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(resolved); !errors.Is(err, os.ErrNotExist) {
+		return "", errors.New("path already exists")
+	}
+	return resolved, nil
+}
+
+// NewBackupDatabaseHandler returns an admin JSON RPC handler producing online backups of the
+// database into allowedDir. Backups are disabled if allowedDir is empty.
+func NewBackupDatabaseHandler(logger *log.Entry, backuper DatabaseBackuper, allowedDir string) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request BackupDatabaseRequest) (BackupDatabaseResponse, error) {
+		path, err := resolveBackupPath(allowedDir, request.Path)
+		if err != nil {
+			return BackupDatabaseResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+
+		startTime := time.Now()
+		size, err := backuper.Backup(ctx, path)
+		if err != nil {
+			logger.WithError(err).WithField("path", path).Error("could not back up database")
+			return BackupDatabaseResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not back up database",
+			}
+		}
+		duration := time.Since(startTime)
+		logger.WithField("path", path).WithField("size", size).WithField("duration", duration).
+			Info("database backup completed")
+
+		return BackupDatabaseResponse{
+			Path:       path,
+			Size:       size,
+			DurationMs: duration.Milliseconds(),
+		}, nil
+	})
+}
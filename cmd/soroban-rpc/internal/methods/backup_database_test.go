@@ -0,0 +1,41 @@
+package methods
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBackupPath(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "existing.sqlite"), nil, 0o600))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "escape")))
+
+	path, err := resolveBackupPath(dir, "backup.sqlite")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "backup.sqlite"), path)
+
+	path, err = resolveBackupPath(dir, filepath.Join(dir, "backup.sqlite"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "backup.sqlite"), path)
+
+	for _, invalid := range []string{
+		"",
+		".",
+		"../backup.sqlite",
+		filepath.Join(outside, "backup.sqlite"),
+		"escape/backup.sqlite",
+		"existing.sqlite",
+	} {
+		_, err = resolveBackupPath(dir, invalid)
+		require.Error(t, err, invalid)
+	}
+
+	_, err = resolveBackupPath("", "backup.sqlite")
+	require.ErrorContains(t, err, "disabled")
+}
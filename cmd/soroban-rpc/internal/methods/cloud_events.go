@@ -0,0 +1,159 @@
+package methods
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
+)
+
+// EventsFormatCloudEvents formats the diagnostic events as CloudEvents (v1.0) JSON envelopes.
+const EventsFormatCloudEvents = "cloudevents"
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	// cloudEventsTypePrefix prefixes the types of the events, followed by the type of the
+	// contract event (contract, system or diagnostic) and its first topic.
+	cloudEventsTypePrefix = "org.stellar."
+	// cloudEventsSystemSource is the source of the events not emitted by a contract.
+	cloudEventsSystemSource = "stellar:system"
+)
+
+// IsValidEventsFormat checks that format is EventsFormatCloudEvents (or empty, which returns
+// the events in the requested xdrFormat).
+func IsValidEventsFormat(format string) error {
+	switch format {
+	case "", EventsFormatCloudEvents:
+		return nil
+	default:
+		return fmt.Errorf("invalid events format %q (expected %q)", format, EventsFormatCloudEvents)
+	}
+}
+
+// CloudEvent is a diagnostic event in the JSON format of the CloudEvents specification
+// (https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/formats/json-format.md).
+type CloudEvent struct {
+	SpecVersion string `json:"specversion"`
+	// ID is unique per event: the hash of its transaction followed by its index in the
+	// diagnostic events of the transaction.
+	ID string `json:"id"`
+	// Source is the strkey-encoded ID of the contract which emitted the event, or
+	// "stellar:system" for the events not emitted by a contract.
+	Source string `json:"source"`
+	// Type is "org.stellar.", followed by the type of the event (contract, system or
+	// diagnostic) and, if it's a symbol or a string, its first topic (e.g.
+	// "org.stellar.contract.transfer").
+	Type string `json:"type"`
+	// Subject is the remaining topics of the event separated by "/", with the addresses
+	// strkey-encoded and the values other than symbols and strings as base64 ScVal XDR values.
+	Subject         string         `json:"subject,omitempty"`
+	Time            string         `json:"time"`
+	DataContentType string         `json:"datacontenttype"`
+	Data            CloudEventData `json:"data"`
+}
+
+// CloudEventData is the data of a CloudEvent: the topics and value of the event, in the
+// requested xdrFormat.
+type CloudEventData struct {
+	Ledger                   uint32            `json:"ledger"`
+	InSuccessfulContractCall bool              `json:"inSuccessfulContractCall"`
+	TopicsXDR                []string          `json:"topicsXdr,omitempty"`
+	TopicsJSON               []json.RawMessage `json:"topicsJson,omitempty"`
+	ValueXDR                 string            `json:"valueXdr,omitempty"`
+	ValueJSON                json.RawMessage   `json:"valueJson,omitempty"`
+}
+
+// cloudEventTopic renders a topic within the type or the subject of a CloudEvent.
+func cloudEventTopic(topic xdr.ScVal) (string, error) {
+	switch topic.Type {
+	case xdr.ScValTypeScvSymbol:
+		return string(*topic.Sym), nil
+	case xdr.ScValTypeScvString:
+		return string(*topic.Str), nil
+	case xdr.ScValTypeScvAddress:
+		return topic.Address.String()
+	default:
+		return xdr.MarshalBase64(topic)
+	}
+}
+
+// toCloudEvents converts the diagnostic events of the transaction to CloudEvents, with
+// their topics and values in the given format.
+func toCloudEvents(tx db.Transaction, format string) ([]CloudEvent, error) {
+	closeTime := time.Unix(tx.Ledger.CloseTime, 0).UTC().Format(time.RFC3339)
+	events := make([]CloudEvent, 0, len(tx.Events))
+	for i, eventXDR := range tx.Events {
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshal(eventXDR, &event); err != nil {
+			return nil, err
+		}
+		cloudEvent := CloudEvent{
+			SpecVersion:     cloudEventsSpecVersion,
+			ID:              fmt.Sprintf("%s-%d", tx.TransactionHash, i),
+			Source:          cloudEventsSystemSource,
+			Type:            cloudEventsTypePrefix + strings.ToLower(strings.TrimPrefix(event.Event.Type.String(), "ContractEventType")),
+			Time:            closeTime,
+			DataContentType: "application/json",
+			Data: CloudEventData{
+				Ledger:                   tx.Ledger.Sequence,
+				InSuccessfulContractCall: event.InSuccessfulContractCall,
+			},
+		}
+		if event.Event.ContractId != nil {
+			source, err := strkey.Encode(strkey.VersionByteContract, event.Event.ContractId[:])
+			if err != nil {
+				return nil, err
+			}
+			cloudEvent.Source = source
+		}
+
+		body := event.Event.Body.MustV0()
+		var subject []string
+		for j, topic := range body.Topics {
+			rendered, err := cloudEventTopic(topic)
+			if err != nil {
+				return nil, err
+			}
+			if j == 0 && (topic.Type == xdr.ScValTypeScvSymbol || topic.Type == xdr.ScValTypeScvString) {
+				cloudEvent.Type += "." + rendered
+				continue
+			}
+			subject = append(subject, rendered)
+		}
+		cloudEvent.Subject = strings.Join(subject, "/")
+
+		var err error
+		switch format {
+		case FormatJSON:
+			for _, topic := range body.Topics {
+				topicJSON, err := xdr2json.ConvertInterface(topic)
+				if err != nil {
+					return nil, err
+				}
+				cloudEvent.Data.TopicsJSON = append(cloudEvent.Data.TopicsJSON, topicJSON)
+			}
+			if cloudEvent.Data.ValueJSON, err = xdr2json.ConvertInterface(body.Data); err != nil {
+				return nil, err
+			}
+		default:
+			for _, topic := range body.Topics {
+				topicXDR, err := xdr.MarshalBase64(topic)
+				if err != nil {
+					return nil, err
+				}
+				cloudEvent.Data.TopicsXDR = append(cloudEvent.Data.TopicsXDR, topicXDR)
+			}
+			if cloudEvent.Data.ValueXDR, err = xdr.MarshalBase64(body.Data); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, cloudEvent)
+	}
+	return events, nil
+}
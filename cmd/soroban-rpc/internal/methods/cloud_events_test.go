@@ -0,0 +1,102 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestGetTransactionCloudEvents(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	meta := txMetaWithEvents(1, true)
+	require.NoError(t, store.InsertTransactions(meta))
+	hash := txHash(1)
+	request := GetTransactionRequest{Hash: hex.EncodeToString(hash[:]), EventsFormat: EventsFormatCloudEvents}
+
+	response, err := GetTransaction(context.TODO(), log.DefaultLogger, store, db.NewMockLedgerReader(store), 0, request)
+	require.NoError(t, err)
+	assert.Empty(t, response.DiagnosticEventsXDR)
+	require.Len(t, response.DiagnosticEventsCloudEvents, 1)
+	event := response.DiagnosticEventsCloudEvents[0]
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, hex.EncodeToString(hash[:])+"-0", event.ID)
+	contractID, err := hex.DecodeString("df06d62447fd25da07c0135eed7557e5a5497ee7d15b7fe345bd47e191d8f577")
+	require.NoError(t, err)
+	assert.Equal(t, strkey.MustEncode(strkey.VersionByteContract, contractID), event.Source)
+	assert.Equal(t, "org.stellar.contract.COUNTER", event.Type)
+	assert.Empty(t, event.Subject)
+	assert.Equal(t, time.Unix(meta.LedgerCloseTime(), 0).UTC().Format(time.RFC3339), event.Time)
+	assert.Equal(t, "application/json", event.DataContentType)
+	assert.Equal(t, uint32(101), event.Data.Ledger)
+	assert.Equal(t, []string{"AAAADwAAAAdDT1VOVEVSAA=="}, event.Data.TopicsXDR)
+	assert.Equal(t, "AAAADwAAAAdDT1VOVEVSAA==", event.Data.ValueXDR)
+
+	// the envelopes follow the JSON format of the CloudEvents specification
+	encoded, err := json.Marshal(event)
+	require.NoError(t, err)
+	var attributes map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &attributes))
+	for _, attribute := range []string{"specversion", "id", "source", "type", "time", "datacontenttype", "data"} {
+		assert.Contains(t, attributes, attribute)
+	}
+
+	request.EventsFormat = "avro"
+	_, err = GetTransaction(context.TODO(), log.DefaultLogger, store, db.NewMockLedgerReader(store), 0, request)
+	require.ErrorContains(t, err, "invalid events format")
+
+	request.EventsFormat = EventsFormatCloudEvents
+	request.CompressEvents = EventsCompressionGzip
+	_, err = GetTransaction(context.TODO(), log.DefaultLogger, store, db.NewMockLedgerReader(store), 0, request)
+	require.ErrorContains(t, err, "mutually exclusive")
+}
+
+func TestToCloudEventsTopics(t *testing.T) {
+	account := keypair.MustRandom().Address()
+	accountID := xdr.MustAddress(account)
+	transfer := xdr.ScSymbol("transfer")
+	amount := xdr.Uint32(42)
+	event := xdr.DiagnosticEvent{
+		Event: xdr.ContractEvent{
+			Type: xdr.ContractEventTypeSystem,
+			Body: xdr.ContractEventBody{
+				V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{
+						{Type: xdr.ScValTypeScvSymbol, Sym: &transfer},
+						{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{
+							Type:      xdr.ScAddressTypeScAddressTypeAccount,
+							AccountId: &accountID,
+						}},
+						{Type: xdr.ScValTypeScvU32, U32: &amount},
+					},
+					Data: xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &amount},
+				},
+			},
+		},
+	}
+	eventXDR, err := event.MarshalBinary()
+	require.NoError(t, err)
+	amountXDR, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &amount})
+	require.NoError(t, err)
+
+	events, err := toCloudEvents(db.Transaction{TransactionHash: "abcd", Events: [][]byte{eventXDR}}, FormatBase64)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "abcd-0", events[0].ID)
+	assert.Equal(t, "stellar:system", events[0].Source)
+	assert.Equal(t, "org.stellar.system.transfer", events[0].Type)
+	assert.Equal(t, account+"/"+amountXDR, events[0].Subject)
+	assert.Len(t, events[0].Data.TopicsXDR, 3)
+	assert.Equal(t, amountXDR, events[0].Data.ValueXDR)
+}
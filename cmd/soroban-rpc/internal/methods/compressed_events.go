@@ -0,0 +1,71 @@
+package methods
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression algorithms of the diagnostic events (see compressEvents).
+const (
+	EventsCompressionGzip = "gzip"
+	EventsCompressionZstd = "zstd"
+)
+
+// IsValidEventsCompression checks that compression is one of the EventsCompression* algorithms
+// (or empty, which doesn't compress the events).
+func IsValidEventsCompression(compression string) error {
+	switch compression {
+	case "", EventsCompressionGzip, EventsCompressionZstd:
+		return nil
+	default:
+		return fmt.Errorf("invalid events compression %q (expected %q or %q)",
+			compression, EventsCompressionGzip, EventsCompressionZstd)
+	}
+}
+
+// zstdEncoder is shared, since EncodeAll is safe for concurrent use.
+var zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+	return zstd.NewWriter(nil)
+})
+
+// compressEvents encodes the (XDR-encoded) diagnostic events as a single base64 blob. To decode it:
+//
+//  1. base64-decode the blob,
+//  2. decompress it with the requested algorithm (gzip or zstd),
+//  3. XDR-decode the result as a variable-length array of DiagnosticEvent (a 4-byte big-endian
+//     count followed by the events), e.g. into a []xdr.DiagnosticEvent with the Go SDK.
+func compressEvents(events [][]byte, compression string) (string, error) {
+	encoded := binary.BigEndian.AppendUint32(nil, uint32(len(events)))
+	for _, event := range events {
+		encoded = append(encoded, event...)
+	}
+
+	var compressed []byte
+	switch compression {
+	case EventsCompressionGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(encoded); err != nil {
+			return "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", err
+		}
+		compressed = buf.Bytes()
+	case EventsCompressionZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return "", err
+		}
+		compressed = encoder.EncodeAll(encoded, nil)
+	default:
+		return "", IsValidEventsCompression(compression)
+	}
+	return base64.StdEncoding.EncodeToString(compressed), nil
+}
@@ -0,0 +1,96 @@
+package methods
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// decompressEvents follows the decoding steps documented by compressEvents.
+func decompressEvents(t *testing.T, blob string, compression string) []xdr.DiagnosticEvent {
+	compressed, err := base64.StdEncoding.DecodeString(blob)
+	require.NoError(t, err)
+	var decompressed []byte
+	switch compression {
+	case EventsCompressionGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		require.NoError(t, err)
+		decompressed, err = io.ReadAll(reader)
+		require.NoError(t, err)
+	case EventsCompressionZstd:
+		decoder, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer decoder.Close()
+		decompressed, err = decoder.DecodeAll(compressed, nil)
+		require.NoError(t, err)
+	}
+	var events []xdr.DiagnosticEvent
+	require.NoError(t, xdr.SafeUnmarshal(decompressed, &events))
+	return events
+}
+
+func TestCompressEventsRoundTrip(t *testing.T) {
+	meta := txMetaWithEvents(1, true)
+	event, err := meta.V1.TxProcessing[0].TxApplyProcessing.GetDiagnosticEvents()
+	require.NoError(t, err)
+	original := []xdr.DiagnosticEvent{event[0], event[0], {InSuccessfulContractCall: true, Event: event[0].Event}}
+	var encoded [][]byte
+	for _, event := range original {
+		eventXDR, err := event.MarshalBinary()
+		require.NoError(t, err)
+		encoded = append(encoded, eventXDR)
+	}
+
+	for _, compression := range []string{EventsCompressionGzip, EventsCompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			blob, err := compressEvents(encoded, compression)
+			require.NoError(t, err)
+			decoded := decompressEvents(t, blob, compression)
+			require.Len(t, decoded, len(original))
+			for i := range original {
+				requireSameXDR(t, &original[i], &decoded[i])
+			}
+
+			blob, err = compressEvents(nil, compression)
+			require.NoError(t, err)
+			assert.Empty(t, decompressEvents(t, blob, compression))
+		})
+	}
+
+	_, err = compressEvents(encoded, "brotli")
+	require.Error(t, err)
+}
+
+func TestGetTransactionCompressedEvents(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	meta := txMetaWithEvents(1, true)
+	require.NoError(t, store.InsertTransactions(meta))
+	hash := txHash(1)
+	request := GetTransactionRequest{Hash: hex.EncodeToString(hash[:]), CompressEvents: EventsCompressionZstd}
+
+	response, err := GetTransaction(context.TODO(), log.DefaultLogger, store, db.NewMockLedgerReader(store), 0, request)
+	require.NoError(t, err)
+	assert.Empty(t, response.DiagnosticEventsXDR)
+	events := decompressEvents(t, response.DiagnosticEventsCompressed, EventsCompressionZstd)
+	expected, err := meta.V1.TxProcessing[0].TxApplyProcessing.GetDiagnosticEvents()
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	requireSameXDR(t, &expected[0], &events[0])
+
+	request.CompressEvents = "lz4"
+	_, err = GetTransaction(context.TODO(), log.DefaultLogger, store, db.NewMockLedgerReader(store), 0, request)
+	require.ErrorContains(t, err, "invalid events compression")
+}
@@ -0,0 +1,61 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+type ComputeTransactionHashRequest struct {
+	// Transaction is the base64 encoded transaction envelope.
+	Transaction string `json:"transaction"`
+}
+
+type ComputeTransactionHashResponse struct {
+	// Hash is the hex-encoded hash the transaction has on the network of this rpc instance, which
+	// getTransaction looks transactions up by. For fee-bump transactions, it's the hash of the
+	// fee-bump wrapper.
+	Hash string `json:"hash"`
+	// InnerHash is the hex-encoded hash of the inner transaction, only present for fee-bump
+	// transactions.
+	InnerHash string `json:"innerHash,omitempty"`
+}
+
+// NewComputeTransactionHashHandler returns a JSON RPC handler computing the hash of transaction
+// envelopes on the network with the given passphrase, without submitting them.
+func NewComputeTransactionHashHandler(passphrase string) jrpc2.Handler {
+	return NewHandler(func(_ context.Context, request ComputeTransactionHashRequest,
+	) (ComputeTransactionHashResponse, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(request.Transaction, &envelope); err != nil {
+			return ComputeTransactionHashResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "invalid_xdr",
+			}
+		}
+
+		hash, err := network.HashTransactionInEnvelope(envelope, passphrase)
+		if err != nil {
+			return ComputeTransactionHashResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "invalid_hash",
+			}
+		}
+		response := ComputeTransactionHashResponse{Hash: hex.EncodeToString(hash[:])}
+		if envelope.IsFeeBump() {
+			innerHash, err := network.HashTransaction(envelope.FeeBump.Tx.InnerTx.V1.Tx, passphrase)
+			if err != nil {
+				return ComputeTransactionHashResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: "invalid_hash",
+				}
+			}
+			response.InnerHash = hex.EncodeToString(innerHash[:])
+		}
+		return response, nil
+	})
+}
@@ -0,0 +1,59 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+)
+
+// a bump sequence transaction of the root account of the test network, and its fee-bump
+const (
+	transactionVector = "AAAAAgAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9wAAAGQAAAAAAAAAAgAAAAEAAAAAAAAAAAAAAAAAAAPo" +
+		"AAAAAAAAAAEAAAAAAAAACwAAAAAAAAAKAAAAAAAAAAA="
+	feeBumpVector = "AAAABQAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9wAAAAAAAAGQAAAAAgAAAABi/B0L0JGythwN1lY0aypo" +
+		"19NHxvLCyO5tBEcCVvwF9wAAAGQAAAAAAAAAAgAAAAEAAAAAAAAAAAAAAAAAAAPoAAAAAAAAAAEAAAAAAAAACwAAAAAAAAAKAAAA" +
+		"AAAAAAAAAAAAAAAAAA=="
+)
+
+func TestComputeTransactionHash(t *testing.T) {
+	computeHash := func(passphrase, transaction string) (ComputeTransactionHashResponse, error) {
+		params, err := json.Marshal(ComputeTransactionHashRequest{Transaction: transaction})
+		require.NoError(t, err)
+		request := jrpc2.ParsedRequest{ID: "1", Method: "computeTransactionHash", Params: params}
+		response, err := NewComputeTransactionHashHandler(passphrase)(context.Background(), request.ToRequest())
+		if err != nil {
+			return ComputeTransactionHashResponse{}, err
+		}
+		return response.(ComputeTransactionHashResponse), nil
+	}
+
+	response, err := computeHash(network.TestNetworkPassphrase, transactionVector)
+	require.NoError(t, err)
+	assert.Equal(t, ComputeTransactionHashResponse{
+		Hash: "0c23bdec932972117c712505debc764db7f9bbe971f1f7abf51ed0db3b8cfcd6",
+	}, response)
+
+	// the hash depends on the network
+	response, err = computeHash(network.PublicNetworkPassphrase, transactionVector)
+	require.NoError(t, err)
+	assert.Equal(t, "4dc4fa1f752780c07a76eb74c17a6e30de72fdf057f0804bcaa5f4b6afeecf11", response.Hash)
+
+	response, err = computeHash(network.TestNetworkPassphrase, feeBumpVector)
+	require.NoError(t, err)
+	assert.Equal(t, ComputeTransactionHashResponse{
+		Hash:      "08c232e48f67b96392ad90fc96e4fb0e36b53e407e080e64acc4b918ef6695d0",
+		InnerHash: "0c23bdec932972117c712505debc764db7f9bbe971f1f7abf51ed0db3b8cfcd6",
+	}, response)
+
+	_, err = computeHash(network.TestNetworkPassphrase, "not an envelope")
+	var jrpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	assert.Equal(t, "invalid_xdr", jrpcErr.Message)
+}
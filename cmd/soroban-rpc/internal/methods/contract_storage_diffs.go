@@ -0,0 +1,135 @@
+package methods
+
+import (
+	"encoding/json"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
+)
+
+// ContractStorageDiff are the changes of the storage of a contract made by a transaction.
+type ContractStorageDiff struct {
+	// ContractID is the strkey-encoded ID of the contract.
+	ContractID string `json:"contractId"`
+	// Changes are the changed storage keys of the contract, in the order they were first changed.
+	Changes []ContractStorageChange `json:"changes"`
+}
+
+// ContractStorageChange is the change of a contract storage key by a transaction. The keys
+// and values are ScVal XDR values, or their JSON representation in the JSON format.
+type ContractStorageChange struct {
+	KeyXDR  string          `json:"keyXdr,omitempty"`
+	KeyJSON json.RawMessage `json:"keyJson,omitempty"`
+	// Durability is the durability of the key (persistent or temporary).
+	Durability string `json:"durability"`
+	// BeforeXDR is the value before the transaction, absent when the transaction created the key.
+	BeforeXDR  string          `json:"beforeXdr,omitempty"`
+	BeforeJSON json.RawMessage `json:"beforeJson,omitempty"`
+	// AfterXDR is the value after the transaction, absent when the transaction removed the key.
+	AfterXDR  string          `json:"afterXdr,omitempty"`
+	AfterJSON json.RawMessage `json:"afterJson,omitempty"`
+}
+
+// contractStorageDiffs returns the contract data changes of the transaction with the given
+// meta, grouped by contract in the order the contracts were first changed. Like in
+// resultingState, the intermediate values set by the operations of the transaction are left out:
+// each key is reported with its value before its first change and after its last change.
+func contractStorageDiffs(meta xdr.TransactionMeta, format string) ([]ContractStorageDiff, error) {
+	changes, err := (&ingest.LedgerTransaction{UnsafeMeta: meta}).GetChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	type keyChange struct {
+		pre, post *xdr.LedgerEntry
+	}
+	var contracts []xdr.Hash
+	keys := map[xdr.Hash][]string{}
+	keyChanges := map[string]*keyChange{}
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeContractData {
+			continue
+		}
+		entry := change.Post
+		if entry == nil {
+			entry = change.Pre
+		}
+		contractID, ok := entry.Data.ContractData.Contract.GetContractId()
+		if !ok {
+			continue
+		}
+		key, err := entry.LedgerKey()
+		if err != nil {
+			return nil, err
+		}
+		keyXDR, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := keyChanges[keyXDR]; ok {
+			existing.post = change.Post
+			continue
+		}
+		if _, ok := keys[contractID]; !ok {
+			contracts = append(contracts, contractID)
+		}
+		keys[contractID] = append(keys[contractID], keyXDR)
+		keyChanges[keyXDR] = &keyChange{pre: change.Pre, post: change.Post}
+	}
+
+	diffs := make([]ContractStorageDiff, 0, len(contracts))
+	for _, contractID := range contracts {
+		id, err := strkey.Encode(strkey.VersionByteContract, contractID[:])
+		if err != nil {
+			return nil, err
+		}
+		diff := ContractStorageDiff{ContractID: id}
+		for _, keyXDR := range keys[contractID] {
+			change, err := newContractStorageChange(keyChanges[keyXDR].pre, keyChanges[keyXDR].post, format)
+			if err != nil {
+				return nil, err
+			}
+			diff.Changes = append(diff.Changes, change)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+func newContractStorageChange(pre, post *xdr.LedgerEntry, format string) (ContractStorageChange, error) {
+	entry := post
+	if entry == nil {
+		entry = pre
+	}
+	data := entry.Data.ContractData
+	change := ContractStorageChange{Durability: "persistent"}
+	if data.Durability == xdr.ContractDataDurabilityTemporary {
+		change.Durability = "temporary"
+	}
+	encode := func(value xdr.ScVal, encodedXDR *string, encodedJSON *json.RawMessage) error {
+		var err error
+		if format == FormatJSON {
+			*encodedJSON, err = xdr2json.ConvertInterface(value)
+		} else {
+			*encodedXDR, err = xdr.MarshalBase64(value)
+		}
+		return err
+	}
+	if err := encode(data.Key, &change.KeyXDR, &change.KeyJSON); err != nil {
+		return ContractStorageChange{}, err
+	}
+	if pre != nil {
+		if err := encode(pre.Data.ContractData.Val, &change.BeforeXDR, &change.BeforeJSON); err != nil {
+			return ContractStorageChange{}, err
+		}
+	}
+	if post != nil {
+		if err := encode(post.Data.ContractData.Val, &change.AfterXDR, &change.AfterJSON); err != nil {
+			return ContractStorageChange{}, err
+		}
+	}
+	return change, nil
+}
@@ -0,0 +1,102 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+func TestContractStorageDiffs(t *testing.T) {
+	balance := xdr.ScSymbol("balance")
+	balanceKey := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &balance}
+	admin := xdr.ScSymbol("admin")
+	adminKey := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &admin}
+	trueVal := xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &[]bool{true}[0]}
+	falseVal := xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &[]bool{false}[0]}
+
+	updated := contractDataEntry(xdr.Hash{1}, balanceKey)
+	intermediate := contractDataEntry(xdr.Hash{1}, balanceKey)
+	intermediate.Data.ContractData.Val = trueVal
+	final := contractDataEntry(xdr.Hash{1}, balanceKey)
+	final.Data.ContractData.Val = falseVal
+	created := contractDataEntry(xdr.Hash{2}, balanceKey)
+	created.Data.ContractData.Durability = xdr.ContractDataDurabilityTemporary
+	created.Data.ContractData.Val = trueVal
+	removed := contractDataEntry(xdr.Hash{1}, adminKey)
+	removedKey, err := removed.LedgerKey()
+	require.NoError(t, err)
+
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{
+				{
+					Changes: xdr.LedgerEntryChanges{
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &updated},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &intermediate},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &created},
+					},
+				},
+				{
+					Changes: xdr.LedgerEntryChanges{
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &intermediate},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &final},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &removed},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &removedKey},
+					},
+				},
+			},
+		},
+	}
+	encode := func(value xdr.ScVal) string {
+		encoded, err := xdr.MarshalBase64(value)
+		require.NoError(t, err)
+		return encoded
+	}
+	contractID := func(id xdr.Hash) string {
+		encoded, err := strkey.Encode(strkey.VersionByteContract, id[:])
+		require.NoError(t, err)
+		return encoded
+	}
+
+	// the changes are grouped per contract, skipping the intermediate values
+	diffs, err := contractStorageDiffs(meta, FormatBase64)
+	require.NoError(t, err)
+	assert.Equal(t, []ContractStorageDiff{
+		{
+			ContractID: contractID(xdr.Hash{1}),
+			Changes: []ContractStorageChange{
+				{
+					KeyXDR:     encode(balanceKey),
+					Durability: "persistent",
+					BeforeXDR:  encode(updated.Data.ContractData.Val),
+					AfterXDR:   encode(falseVal),
+				},
+				{
+					KeyXDR:     encode(adminKey),
+					Durability: "persistent",
+					BeforeXDR:  encode(removed.Data.ContractData.Val),
+				},
+			},
+		},
+		{
+			ContractID: contractID(xdr.Hash{2}),
+			Changes: []ContractStorageChange{
+				{
+					KeyXDR:     encode(balanceKey),
+					Durability: "temporary",
+					AfterXDR:   encode(trueVal),
+				},
+			},
+		},
+	}, diffs)
+
+	// transactions which didn't change contract storage
+	diffs, err = contractStorageDiffs(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}}, FormatBase64)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
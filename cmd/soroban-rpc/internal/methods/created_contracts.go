@@ -0,0 +1,27 @@
+package methods
+
+import (
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// createdContractIDs returns the (strkey-encoded) IDs of the contracts created by the transaction
+// with the given meta, in order of appearance in the meta (see db.CreatedContractIDs).
+func createdContractIDs(meta xdr.TransactionMeta) ([]string, error) {
+	contractIDs, err := db.CreatedContractIDs(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, contractID := range contractIDs {
+		id, err := strkey.Encode(strkey.VersionByteContract, contractID[:])
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
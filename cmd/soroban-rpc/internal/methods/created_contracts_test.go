@@ -0,0 +1,65 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+func contractDataEntry(contractID xdr.Hash, key xdr.ScVal) xdr.LedgerEntry {
+	return xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract: xdr.ScAddress{
+					Type:       xdr.ScAddressTypeScAddressTypeContract,
+					ContractId: &contractID,
+				},
+				Key:        key,
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+	}
+}
+
+func TestCreatedContractIDs(t *testing.T) {
+	instanceKey := xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}
+	symbol := xdr.ScSymbol("balance")
+	dataKey := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &symbol}
+	created := contractDataEntry(xdr.Hash{1}, instanceKey)
+	factoryCreated := contractDataEntry(xdr.Hash{2}, instanceKey)
+	updated := contractDataEntry(xdr.Hash{3}, instanceKey)
+	data := contractDataEntry(xdr.Hash{4}, dataKey)
+
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{{
+				Changes: xdr.LedgerEntryChanges{
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &created},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &data},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &updated},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &updated},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &factoryCreated},
+				},
+			}},
+		},
+	}
+	ids, err := createdContractIDs(meta)
+	require.NoError(t, err)
+	expected := make([]string, 0, 2)
+	for _, id := range []xdr.Hash{{1}, {2}} {
+		expected = append(expected, strkey.MustEncode(strkey.VersionByteContract, id[:]))
+	}
+	assert.Equal(t, expected, ids)
+
+	// transactions which didn't create contracts
+	ids, err = createdContractIDs(txMeta(1, true).V1.TxProcessing[0].TxApplyProcessing)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
@@ -0,0 +1,73 @@
+package methods
+
+import (
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// decodedTransaction decodes the XDR values of a stored transaction on demand, each at most
+// once, so that the fields of a getTransaction response derived from the same value share its
+// decoding.
+type decodedTransaction struct {
+	tx db.Transaction
+
+	envelope *xdr.TransactionEnvelope
+	result   *xdr.TransactionResult
+	meta     *xdr.TransactionMeta
+	events   *[]xdr.DiagnosticEvent
+}
+
+func newDecodedTransaction(tx db.Transaction) *decodedTransaction {
+	return &decodedTransaction{tx: tx}
+}
+
+func (d *decodedTransaction) Envelope() (xdr.TransactionEnvelope, error) {
+	if d.envelope == nil {
+		var envelope xdr.TransactionEnvelope
+		if err := envelope.UnmarshalBinary(d.tx.Envelope); err != nil {
+			return xdr.TransactionEnvelope{}, err
+		}
+		d.envelope = &envelope
+	}
+	return *d.envelope, nil
+}
+
+func (d *decodedTransaction) Result() (xdr.TransactionResult, error) {
+	if d.result == nil {
+		var result xdr.TransactionResult
+		if err := result.UnmarshalBinary(d.tx.Result); err != nil {
+			return xdr.TransactionResult{}, err
+		}
+		d.result = &result
+	}
+	return *d.result, nil
+}
+
+func (d *decodedTransaction) Meta() (xdr.TransactionMeta, error) {
+	if d.meta == nil {
+		var meta xdr.TransactionMeta
+		if err := meta.UnmarshalBinary(d.tx.Meta); err != nil {
+			return xdr.TransactionMeta{}, err
+		}
+		d.meta = &meta
+	}
+	return *d.meta, nil
+}
+
+// Events returns all the diagnostic events of the transaction, regardless of the events
+// omitted from the response.
+func (d *decodedTransaction) Events() ([]xdr.DiagnosticEvent, error) {
+	if d.events == nil {
+		events := make([]xdr.DiagnosticEvent, len(d.tx.Events))
+		for i, encoded := range d.tx.Events {
+			if err := xdr.SafeUnmarshal(encoded, &events[i]); err != nil {
+				return nil, fmt.Errorf("could not decode diagnostic event %d: %w", i, err)
+			}
+		}
+		d.events = &events
+	}
+	return *d.events, nil
+}
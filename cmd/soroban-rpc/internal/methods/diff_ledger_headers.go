@@ -0,0 +1,109 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type DiffLedgerHeadersRequest struct {
+	SeqA uint32 `json:"seqA"`
+	SeqB uint32 `json:"seqB"`
+}
+
+// LedgerHeaderDifference is a ledger header field whose value differs between two ledgers.
+// The values are decimal strings, since some of them (e.g. totalCoins) don't fit in JSON numbers.
+type LedgerHeaderDifference struct {
+	Field  string `json:"field"`
+	ValueA string `json:"valueA"`
+	ValueB string `json:"valueB"`
+}
+
+type DiffLedgerHeadersResponse struct {
+	SeqA uint32 `json:"seqA"`
+	SeqB uint32 `json:"seqB"`
+	// Differences lists the compared fields which differ, in ledgerHeaderFields order.
+	// The fields which change with every ledger (e.g. hashes and close times) aren't compared.
+	Differences []LedgerHeaderDifference `json:"differences"`
+}
+
+// ledgerHeaderFields are the compared ledger header fields, which are the ones changed by
+// network upgrades (and by the fees and inflation for totalCoins, feePool and inflationSeq).
+var ledgerHeaderFields = []struct {
+	name  string
+	value func(header xdr.LedgerHeader) string
+}{
+	{"protocolVersion", func(h xdr.LedgerHeader) string { return strconv.FormatUint(uint64(h.LedgerVersion), 10) }},
+	{"baseFee", func(h xdr.LedgerHeader) string { return strconv.FormatUint(uint64(h.BaseFee), 10) }},
+	{"baseReserve", func(h xdr.LedgerHeader) string { return strconv.FormatUint(uint64(h.BaseReserve), 10) }},
+	{"maxTxSetSize", func(h xdr.LedgerHeader) string { return strconv.FormatUint(uint64(h.MaxTxSetSize), 10) }},
+	{"totalCoins", func(h xdr.LedgerHeader) string { return strconv.FormatInt(int64(h.TotalCoins), 10) }},
+	{"feePool", func(h xdr.LedgerHeader) string { return strconv.FormatInt(int64(h.FeePool), 10) }},
+	{"inflationSeq", func(h xdr.LedgerHeader) string { return strconv.FormatUint(uint64(h.InflationSeq), 10) }},
+	{"flags", func(h xdr.LedgerHeader) string {
+		var flags uint32
+		if h.Ext.V1 != nil {
+			flags = uint32(h.Ext.V1.Flags)
+		}
+		return strconv.FormatUint(uint64(flags), 10)
+	}},
+}
+
+// diffLedgerHeaders returns the differences between two ledger headers.
+func diffLedgerHeaders(a, b xdr.LedgerHeader) []LedgerHeaderDifference {
+	differences := []LedgerHeaderDifference{}
+	for _, field := range ledgerHeaderFields {
+		valueA, valueB := field.value(a), field.value(b)
+		if valueA != valueB {
+			differences = append(differences, LedgerHeaderDifference{Field: field.name, ValueA: valueA, ValueB: valueB})
+		}
+	}
+	return differences
+}
+
+// NewDiffLedgerHeadersHandler returns a JSON RPC handler comparing the headers of two stored ledgers.
+func NewDiffLedgerHeadersHandler(ledgerReader db.LedgerReader) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request DiffLedgerHeadersRequest) (DiffLedgerHeadersResponse, error) {
+		getHeader := func(sequence uint32) (xdr.LedgerHeader, error) {
+			ledger, found, err := ledgerReader.GetLedger(ctx, sequence)
+			if err != nil {
+				return xdr.LedgerHeader{}, &jrpc2.Error{
+					Code:    jrpc2.InternalError,
+					Message: fmt.Sprintf("could not get ledger %d: %v", sequence, err),
+				}
+			}
+			if !found {
+				message := fmt.Sprintf("ledger %d is not stored", sequence)
+				if ledgerRange, err := ledgerReader.GetLedgerRange(ctx); err == nil {
+					message = fmt.Sprintf("ledger %d is not stored (the stored ledgers are %d to %d)",
+						sequence, ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence)
+				}
+				return xdr.LedgerHeader{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: message,
+				}
+			}
+			return ledger.LedgerHeaderHistoryEntry().Header, nil
+		}
+
+		headerA, err := getHeader(request.SeqA)
+		if err != nil {
+			return DiffLedgerHeadersResponse{}, err
+		}
+		headerB, err := getHeader(request.SeqB)
+		if err != nil {
+			return DiffLedgerHeadersResponse{}, err
+		}
+		return DiffLedgerHeadersResponse{
+			SeqA:        request.SeqA,
+			SeqB:        request.SeqB,
+			Differences: diffLedgerHeaders(headerA, headerB),
+		}, nil
+	})
+}
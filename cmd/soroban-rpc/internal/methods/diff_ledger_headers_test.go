@@ -0,0 +1,65 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestDiffLedgerHeaders(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := uint32(1); i <= 3; i++ {
+		ledger := createTestLedger(i)
+		header := &ledger.V1.LedgerHeader.Header
+		header.LedgerVersion = 21
+		header.BaseFee = 100
+		header.BaseReserve = 5000000
+		header.TotalCoins = 1000000000000000000
+		if i == 3 {
+			// a protocol and base reserve upgrade, and collected fees
+			header.LedgerVersion = 22
+			header.BaseReserve = 10000000
+			header.FeePool = 300
+			header.Ext = xdr.LedgerHeaderExt{V: 1, V1: &xdr.LedgerHeaderExtensionV1{Flags: 1}}
+		}
+		require.NoError(t, store.InsertTransactions(ledger))
+	}
+	handler := NewDiffLedgerHeadersHandler(db.NewMockLedgerReader(store))
+	diff := func(seqA, seqB uint32) (DiffLedgerHeadersResponse, error) {
+		params, err := json.Marshal(DiffLedgerHeadersRequest{SeqA: seqA, SeqB: seqB})
+		require.NoError(t, err)
+		request := jrpc2.ParsedRequest{ID: "1", Method: "diffLedgerHeaders", Params: params}
+		response, err := handler(context.Background(), request.ToRequest())
+		if err != nil {
+			return DiffLedgerHeadersResponse{}, err
+		}
+		return response.(DiffLedgerHeadersResponse), nil
+	}
+
+	response, err := diff(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, DiffLedgerHeadersResponse{SeqA: 1, SeqB: 2, Differences: []LedgerHeaderDifference{}}, response)
+
+	response, err = diff(1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerHeaderDifference{
+		{Field: "protocolVersion", ValueA: "21", ValueB: "22"},
+		{Field: "baseReserve", ValueA: "5000000", ValueB: "10000000"},
+		{Field: "feePool", ValueA: "0", ValueB: "300"},
+		{Field: "flags", ValueA: "0", ValueB: "1"},
+	}, response.Differences)
+
+	_, err = diff(1, 10)
+	var jrpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	assert.Contains(t, jrpcErr.Message, "ledger 10 is not stored")
+}
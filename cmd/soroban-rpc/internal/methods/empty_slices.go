@@ -0,0 +1,107 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+)
+
+// How the empty lists of the responses are rendered: the diagnostic event lists (e.g.
+// diagnosticEventsXdr), the topics of the events, and the pages of getEvents (events),
+// getTransactions (transactions or ledgers) and getLedgerEntries (entries).
+const (
+	// EmptySlicesOmit omits the fields, which is the default. The fields which are always present
+	// (e.g. events) are rendered as before: null for no events, [] for no ledger entries.
+	EmptySlicesOmit = "omit"
+	// EmptySlicesEmpty renders the fields as empty arrays ([]).
+	EmptySlicesEmpty = "empty"
+	// EmptySlicesNull renders the fields as null.
+	EmptySlicesNull = "null"
+)
+
+// IsValidEmptySlicesMode checks that mode is one of the EmptySlices* modes (or empty, which omits).
+func IsValidEmptySlicesMode(mode string) error {
+	switch mode {
+	case "", EmptySlicesOmit, EmptySlicesEmpty, EmptySlicesNull:
+		return nil
+	default:
+		return fmt.Errorf("invalid empty slices mode %q (expected %q, %q or %q)",
+			mode, EmptySlicesOmit, EmptySlicesEmpty, EmptySlicesNull)
+	}
+}
+
+// emptySlicesResult is implemented by the responses with lists, returning a copy of the
+// response which renders them according to mode when they are empty.
+type emptySlicesResult interface {
+	withEmptySlices(mode string) interface{}
+}
+
+// WithEmptySlices returns a handler whose results render their empty lists according to mode (see EmptySlicesEmpty and EmptySlicesNull) instead of omitting them. It
+// returns the handler as is if the mode omits them.
+func WithEmptySlices(handler jrpc2.Handler, mode string) jrpc2.Handler {
+	if mode == "" || mode == EmptySlicesOmit {
+		return handler
+	}
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		result, err := handler(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		if r, ok := result.(emptySlicesResult); ok {
+			return r.withEmptySlices(mode), nil
+		}
+		return result, nil
+	}
+}
+
+// optionalList is an omitempty list field of a response.
+type optionalList struct {
+	key string
+	// empty tells whether the list is set but empty. Lists which don't apply to the response
+	// (e.g. the events of a transaction which wasn't found) are left nil.
+	empty bool
+}
+
+func listField[T any](key string, list []T) optionalList {
+	return optionalList{key: key, empty: list != nil && len(list) == 0}
+}
+
+// requiredList returns a list field which is always present (without omitempty) as rendered
+// according to mode when it's empty: [] whether it's nil or not, or null.
+func requiredList[T any](list []T, mode string) []T {
+	switch {
+	case mode == EmptySlicesEmpty && list == nil:
+		return []T{}
+	case mode == EmptySlicesNull && len(list) == 0:
+		return nil
+	default:
+		return list
+	}
+}
+
+// appendEmptyLists appends the lists which are set but empty, which encoding/json omits, to the
+// JSON object encoded, rendered as [] or null depending on mode.
+func appendEmptyLists(encoded []byte, mode string, lists ...optionalList) []byte {
+	var rendered string
+	switch mode {
+	case EmptySlicesEmpty:
+		rendered = "[]"
+	case EmptySlicesNull:
+		rendered = "null"
+	default:
+		return encoded
+	}
+	// drop the closing brace of the object, without appending to the backing array of encoded
+	result := encoded[: len(encoded)-1 : len(encoded)-1]
+	for _, list := range lists {
+		if !list.empty {
+			continue
+		}
+		if len(result) > 1 {
+			result = append(result, ',')
+		}
+		result = append(result, fmt.Sprintf("%q:%s", list.key, rendered)...)
+	}
+	return append(result, '}')
+}
@@ -0,0 +1,120 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendEmptyLists(t *testing.T) {
+	lists := []optionalList{
+		listField("unset", []string(nil)),
+		listField("set", []string{"a"}),
+		listField("empty", []string{}),
+	}
+	assert.Equal(t, `{"empty":[]}`, string(appendEmptyLists([]byte(`{}`), EmptySlicesEmpty, lists...)))
+	assert.Equal(t, `{"set":["a"],"empty":null}`,
+		string(appendEmptyLists([]byte(`{"set":["a"]}`), EmptySlicesNull, lists...)))
+	assert.Equal(t, `{}`, string(appendEmptyLists([]byte(`{}`), EmptySlicesOmit, lists...)))
+	assert.Equal(t, `{}`, string(appendEmptyLists([]byte(`{}`), "", lists...)))
+}
+
+func TestWithEmptySlices(t *testing.T) {
+	found := GetTransactionResponse{Status: TransactionStatusSuccess}
+	found.DiagnosticEventsXDR = []string{}
+	response := found
+	handler := NewHandler(func(context.Context) (GetTransactionResponse, error) {
+		return response, nil
+	})
+	marshal := func(mode string) string {
+		result, err := WithEmptySlices(handler, mode)(context.Background(), &jrpc2.Request{})
+		require.NoError(t, err)
+		encoded, err := json.Marshal(result)
+		require.NoError(t, err)
+		return string(encoded)
+	}
+
+	assert.NotContains(t, marshal(EmptySlicesOmit), `diagnosticEvents`)
+	assert.Contains(t, marshal(EmptySlicesEmpty), `"diagnosticEventsXdr":[]`)
+	assert.Contains(t, marshal(EmptySlicesNull), `"diagnosticEventsXdr":null`)
+	// the events of the other format don't apply
+	assert.NotContains(t, marshal(EmptySlicesEmpty), `diagnosticEventsJson`)
+
+	// nor do the events of transactions which weren't found
+	response = GetTransactionResponse{Status: TransactionStatusNotFound}
+	assert.NotContains(t, marshal(EmptySlicesEmpty), `diagnosticEvents`)
+
+	require.NoError(t, IsValidEmptySlicesMode(EmptySlicesNull))
+	require.Error(t, IsValidEmptySlicesMode("nil"))
+}
+
+func TestWithEmptySlicesTransactions(t *testing.T) {
+	txs := []TransactionInfo{{DiagnosticEventsXDR: []string{}}, {DiagnosticEventsXDR: []string{"event"}}}
+	handler := NewHandler(func(context.Context) (GetTransactionsResponse, error) {
+		return GetTransactionsResponse{
+			Transactions: txs,
+			Ledgers:      []LedgerTransactions{{Transactions: txs[:1]}},
+		}, nil
+	})
+	result, err := WithEmptySlices(handler, EmptySlicesEmpty)(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Transactions []map[string]json.RawMessage `json:"transactions"`
+		Ledgers      []struct {
+			Transactions []map[string]json.RawMessage `json:"transactions"`
+		} `json:"ledgers"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Len(t, decoded.Transactions, 2)
+	assert.JSONEq(t, `[]`, string(decoded.Transactions[0]["diagnosticEventsXdr"]))
+	assert.JSONEq(t, `["event"]`, string(decoded.Transactions[1]["diagnosticEventsXdr"]))
+	require.Len(t, decoded.Ledgers, 1)
+	assert.JSONEq(t, `[]`, string(decoded.Ledgers[0].Transactions[0]["diagnosticEventsXdr"]))
+}
+
+func TestWithEmptySlicesPages(t *testing.T) {
+	marshal := func(result interface{}, mode string) string {
+		handler := func(context.Context, *jrpc2.Request) (interface{}, error) {
+			return result, nil
+		}
+		rendered, err := WithEmptySlices(handler, mode)(context.Background(), &jrpc2.Request{})
+		require.NoError(t, err)
+		encoded, err := json.Marshal(rendered)
+		require.NoError(t, err)
+		return string(encoded)
+	}
+
+	events := GetEventsResponse{}
+	assert.Contains(t, marshal(events, EmptySlicesOmit), `"events":null`)
+	assert.Contains(t, marshal(events, EmptySlicesEmpty), `"events":[]`)
+	assert.Contains(t, marshal(GetEventsResponse{Events: []EventInfo{}}, EmptySlicesNull), `"events":null`)
+	// the topics of the requested format, or the decoded ones if requested
+	events = GetEventsResponse{Events: []EventInfo{{TopicXDR: []string{}, TopicDecoded: []string{}}}}
+	assert.NotContains(t, marshal(events, EmptySlicesOmit), `"topic`)
+	assert.Contains(t, marshal(events, EmptySlicesEmpty), `"topic":[],"topicDecoded":[]`)
+	assert.Contains(t, marshal(events, EmptySlicesNull), `"topic":null,"topicDecoded":null`)
+	assert.NotContains(t, marshal(events, EmptySlicesEmpty), `topicJson`)
+
+	transactions := GetTransactionsResponse{}
+	assert.Contains(t, marshal(transactions, EmptySlicesOmit), `"transactions":null`)
+	assert.Contains(t, marshal(transactions, EmptySlicesEmpty), `"transactions":[]`)
+	assert.NotContains(t, marshal(transactions, EmptySlicesEmpty), `ledgers`)
+	// the transactions of the pages grouped by ledger are in their ledgers
+	grouped := GetTransactionsResponse{Ledgers: groupByLedger(nil, nil)}
+	assert.NotContains(t, marshal(grouped, EmptySlicesOmit), `ledgers`)
+	assert.Contains(t, marshal(grouped, EmptySlicesEmpty), `"transactions":null`)
+	assert.Contains(t, marshal(grouped, EmptySlicesEmpty), `"ledgers":[]`)
+	assert.Contains(t, marshal(grouped, EmptySlicesNull), `"ledgers":null`)
+
+	entries := GetLedgerEntriesResponse{Entries: []LedgerEntryResult{}}
+	assert.Contains(t, marshal(entries, EmptySlicesOmit), `"entries":[]`)
+	assert.Contains(t, marshal(entries, EmptySlicesEmpty), `"entries":[]`)
+	assert.Contains(t, marshal(entries, EmptySlicesNull), `"entries":null`)
+}
@@ -0,0 +1,29 @@
+package methods
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// EventCounts summarizes the diagnostic events of a transaction by the type of their
+// contract event, so that clients can decide whether to fetch the full events.
+type EventCounts struct {
+	Contract   uint `json:"contract"`
+	System     uint `json:"system"`
+	Diagnostic uint `json:"diagnostic"`
+}
+
+// countEvents counts the given diagnostic events by type.
+func countEvents(events []xdr.DiagnosticEvent) EventCounts {
+	var counts EventCounts
+	for _, event := range events {
+		switch event.Event.Type {
+		case xdr.ContractEventTypeContract:
+			counts.Contract++
+		case xdr.ContractEventTypeSystem:
+			counts.System++
+		case xdr.ContractEventTypeDiagnostic:
+			counts.Diagnostic++
+		}
+	}
+	return counts
+}
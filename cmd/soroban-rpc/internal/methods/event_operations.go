@@ -0,0 +1,150 @@
+package methods
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// unattributedEvent is the operation index of the diagnostic events whose operation can't be derived.
+const unattributedEvent = -1
+
+// isSorobanOperation tells whether operations of the given type can emit events.
+func isSorobanOperation(opType xdr.OperationType) bool {
+	switch opType {
+	case xdr.OperationTypeInvokeHostFunction, xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
+		return true
+	default:
+		return false
+	}
+}
+
+// onlySorobanOperation returns the index of the only Soroban operation of the given operations,
+// or unattributedEvent if there are none or several.
+func onlySorobanOperation(operations []xdr.Operation) int {
+	index := unattributedEvent
+	for i, op := range operations {
+		if !isSorobanOperation(op.Body.Type) {
+			continue
+		}
+		if index != unattributedEvent {
+			return unattributedEvent
+		}
+		index = i
+	}
+	return index
+}
+
+// changedContracts returns the contracts whose data (including their instance) was changed
+// by each operation, according to the operation-level meta.
+func changedContracts(meta xdr.TransactionMeta) ([]map[xdr.Hash]struct{}, error) {
+	operations := meta.OperationsMeta()
+	contracts := make([]map[xdr.Hash]struct{}, len(operations))
+	for i, operation := range operations {
+		contracts[i] = map[xdr.Hash]struct{}{}
+		for _, change := range operation.Changes {
+			key, err := change.LedgerKey()
+			if err != nil {
+				return nil, err
+			}
+			if key.ContractData != nil && key.ContractData.Contract.ContractId != nil {
+				contracts[i][*key.ContractData.Contract.ContractId] = struct{}{}
+			}
+		}
+	}
+	return contracts, nil
+}
+
+// diagnosticEventsOperationIndexes returns the index of the operation which emitted each of the
+// (encoded) diagnostic events of a transaction, or unattributedEvent if it can't be derived. The
+// transaction-level meta holds the events of all the operations, but only Soroban operations emit
+// events: when the transaction has a single one (always the case for the transactions accepted
+// by the network), all the events are attributed to it. Otherwise, the events of a contract are
+// attributed to the only operation whose meta changed the data of the contract, if any.
+func diagnosticEventsOperationIndexes(decoded *decodedTransaction, events [][]byte) ([]int, error) {
+	envelope, err := decoded.Envelope()
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, len(events))
+	var eventsOperation int
+	if operations := envelope.Operations(); len(operations) == 1 {
+		eventsOperation = 0
+	} else {
+		eventsOperation = onlySorobanOperation(operations)
+	}
+	if eventsOperation != unattributedEvent {
+		for i := range indexes {
+			indexes[i] = eventsOperation
+		}
+		return indexes, nil
+	}
+
+	meta, err := decoded.Meta()
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := changedContracts(meta)
+	if err != nil {
+		return nil, err
+	}
+	for i, encoded := range events {
+		indexes[i] = unattributedEvent
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshal(encoded, &event); err != nil {
+			return nil, err
+		}
+		if event.Event.ContractId == nil {
+			continue
+		}
+		for operation, changed := range contracts {
+			if _, ok := changed[*event.Event.ContractId]; !ok {
+				continue
+			}
+			if indexes[i] != unattributedEvent {
+				indexes[i] = unattributedEvent
+				break
+			}
+			indexes[i] = operation
+		}
+	}
+	return indexes, nil
+}
+
+// withOperationIndexes adds an operationIndex field to the JSON diagnostic events, with the
+// index of the operation which emitted each of them (see diagnosticEventsOperationIndexes).
+func withOperationIndexes(events []json.RawMessage, operationIndexes []int) []json.RawMessage {
+	for i, event := range events {
+		body := bytes.TrimSpace(event)
+		if operationIndexes[i] == unattributedEvent || len(body) < 2 || body[0] != '{' {
+			continue
+		}
+		field := []byte(`"operationIndex":` + strconv.Itoa(operationIndexes[i]))
+		withIndex := make([]byte, 0, len(body)+len(field)+1)
+		withIndex = append(withIndex, '{')
+		withIndex = append(withIndex, field...)
+		if rest := bytes.TrimSpace(body[1:]); rest[0] != '}' {
+			withIndex = append(withIndex, ',')
+		}
+		events[i] = append(withIndex, body[1:]...)
+	}
+	return events
+}
+
+// jsonifyDiagnosticEvents converts the diagnostic events of a transaction to JSON, attributing
+// them to their operation when possible.
+func jsonifyDiagnosticEvents(tx db.Transaction, decoded *decodedTransaction) ([]json.RawMessage, error) {
+	events, err := jsonifySlice(xdr.DiagnosticEvent{}, tx.Events)
+	if err != nil || len(events) == 0 {
+		return events, err
+	}
+	operationIndexes, err := diagnosticEventsOperationIndexes(decoded, tx.Events)
+	if err != nil {
+		return nil, err
+	}
+	return withOperationIndexes(events, operationIndexes), nil
+}
@@ -0,0 +1,152 @@
+package methods
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// encodedContractEvent returns an encoded diagnostic event emitted by the given contract.
+func encodedContractEvent(t *testing.T, contractID xdr.Hash) []byte {
+	encoded, err := xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event:                    contractEvent(contractID, nil, xdr.ScVal{Type: xdr.ScValTypeScvVoid}),
+	}.MarshalBinary()
+	require.NoError(t, err)
+	return encoded
+}
+
+// contractDataChange returns a ledger entry change creating the instance of the given contract.
+func contractDataChange(contractID xdr.Hash) xdr.LedgerEntryChange {
+	return xdr.LedgerEntryChange{
+		Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+		Created: &xdr.LedgerEntry{
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeContractData,
+				ContractData: &xdr.ContractDataEntry{
+					Contract: xdr.ScAddress{
+						Type:       xdr.ScAddressTypeScAddressTypeContract,
+						ContractId: &contractID,
+					},
+					Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+					Durability: xdr.ContractDataDurabilityPersistent,
+					Val:        xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+				},
+			},
+		},
+	}
+}
+
+func TestDiagnosticEventsOperationIndexes(t *testing.T) {
+	bumpOp := xdr.Operation{
+		Body: xdr.OperationBody{
+			Type:           xdr.OperationTypeBumpSequence,
+			BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 10},
+		},
+	}
+	contractA, contractB, contractC := xdr.Hash{0xa}, xdr.Hash{0xb}, xdr.Hash{0xc}
+	invokeOp := xdr.Operation{
+		Body: xdr.OperationBody{
+			Type: xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+				HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{
+						ContractAddress: xdr.ScAddress{
+							Type:       xdr.ScAddressTypeScAddressTypeContract,
+							ContractId: &contractA,
+						},
+						FunctionName: "f",
+					},
+				},
+			},
+		},
+	}
+	events := [][]byte{
+		encodedContractEvent(t, contractA), encodedContractEvent(t, contractB), encodedContractEvent(t, contractC),
+	}
+	for _, testCase := range []struct {
+		name       string
+		operations []xdr.Operation
+		// changes are the ledger entry changes of each operation
+		changes  [][]xdr.LedgerEntryChange
+		expected []int
+	}{
+		{
+			name:       "single operation",
+			operations: []xdr.Operation{bumpOp},
+			expected:   []int{0, 0, 0},
+		},
+		{
+			name:       "single Soroban operation",
+			operations: []xdr.Operation{bumpOp, invokeOp},
+			expected:   []int{1, 1, 1},
+		},
+		{
+			name:       "several Soroban operations",
+			operations: []xdr.Operation{invokeOp, invokeOp},
+			changes: [][]xdr.LedgerEntryChange{
+				{contractDataChange(contractA)},
+				{contractDataChange(contractB)},
+			},
+			expected: []int{0, 1, unattributedEvent},
+		},
+		{
+			name:       "contract changed by several operations",
+			operations: []xdr.Operation{invokeOp, invokeOp},
+			changes: [][]xdr.LedgerEntryChange{
+				{contractDataChange(contractA), contractDataChange(contractB)},
+				{contractDataChange(contractB)},
+			},
+			expected: []int{0, unattributedEvent, unattributedEvent},
+		},
+		{
+			name:       "no Soroban operations",
+			operations: []xdr.Operation{bumpOp, bumpOp, bumpOp},
+			expected:   []int{unattributedEvent, unattributedEvent, unattributedEvent},
+		},
+		{
+			name:     "no operations",
+			expected: []int{unattributedEvent, unattributedEvent, unattributedEvent},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			envelope := txEnvelope(1)
+			envelope.V1.Tx.Operations = testCase.operations
+			encodedEnvelope, err := envelope.MarshalBinary()
+			require.NoError(t, err)
+			operations := make([]xdr.OperationMeta, len(testCase.operations))
+			for i, changes := range testCase.changes {
+				operations[i].Changes = changes
+			}
+			encodedMeta, err := xdr.TransactionMeta{
+				V:  3,
+				V3: &xdr.TransactionMetaV3{Operations: operations},
+			}.MarshalBinary()
+			require.NoError(t, err)
+
+			decoded := newDecodedTransaction(db.Transaction{Envelope: encodedEnvelope, Meta: encodedMeta})
+			indexes, err := diagnosticEventsOperationIndexes(decoded, events)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, indexes)
+		})
+	}
+}
+
+func TestWithOperationIndexes(t *testing.T) {
+	events := []json.RawMessage{
+		json.RawMessage(`{"in_successful_contract_call":true,"event":{}}`),
+		json.RawMessage(` {} `),
+		json.RawMessage(`{"in_successful_contract_call":false}`),
+	}
+	events = withOperationIndexes(events, []int{2, 1, unattributedEvent})
+	assert.JSONEq(t, `{"operationIndex":2,"in_successful_contract_call":true,"event":{}}`, string(events[0]))
+	assert.JSONEq(t, `{"operationIndex":1}`, string(events[1]))
+	assert.JSONEq(t, `{"in_successful_contract_call":false}`, string(events[2]))
+}
@@ -0,0 +1,57 @@
+package methods
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/stellar/go/xdr"
+)
+
+// decodeTopicSegment returns a human-readable representation of common topic values:
+// symbols and strings as is, addresses as strkeys, booleans and integers in decimal.
+// Other values are returned as base64-encoded XDR.
+func decodeTopicSegment(segment xdr.ScVal) (string, error) {
+	switch segment.Type {
+	case xdr.ScValTypeScvSymbol:
+		return string(*segment.Sym), nil
+	case xdr.ScValTypeScvString:
+		return string(*segment.Str), nil
+	case xdr.ScValTypeScvAddress:
+		return segment.Address.String()
+	case xdr.ScValTypeScvBool:
+		return strconv.FormatBool(*segment.B), nil
+	case xdr.ScValTypeScvU32:
+		return strconv.FormatUint(uint64(*segment.U32), 10), nil
+	case xdr.ScValTypeScvI32:
+		return strconv.FormatInt(int64(*segment.I32), 10), nil
+	case xdr.ScValTypeScvU64:
+		return strconv.FormatUint(uint64(*segment.U64), 10), nil
+	case xdr.ScValTypeScvI64:
+		return strconv.FormatInt(int64(*segment.I64), 10), nil
+	case xdr.ScValTypeScvU128:
+		hi := new(big.Int).SetUint64(uint64(segment.U128.Hi))
+		return int128String(hi, uint64(segment.U128.Lo)), nil
+	case xdr.ScValTypeScvI128:
+		hi := big.NewInt(int64(segment.I128.Hi))
+		return int128String(hi, uint64(segment.I128.Lo)), nil
+	default:
+		return xdr.MarshalBase64(segment)
+	}
+}
+
+func int128String(hi *big.Int, lo uint64) string {
+	value := hi.Lsh(hi, 64)
+	return value.Add(value, new(big.Int).SetUint64(lo)).String()
+}
+
+func decodeTopic(topic []xdr.ScVal) ([]string, error) {
+	decoded := make([]string, 0, len(topic))
+	for _, segment := range topic {
+		segmentStr, err := decodeTopicSegment(segment)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, segmentStr)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,80 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestDecodeTopicSegment(t *testing.T) {
+	account := keypair.MustRandom().Address()
+	accountID := xdr.MustAddress(account)
+	sym := xdr.ScSymbol("transfer")
+	str := xdr.ScString("hello")
+	b := true
+	u32 := xdr.Uint32(42)
+	i32 := xdr.Int32(-42)
+	u64 := xdr.Uint64(1 << 40)
+	i64 := xdr.Int64(-1 << 40)
+	bytes := xdr.ScBytes{1, 2, 3}
+	for _, tc := range []struct {
+		value    xdr.ScVal
+		expected string
+	}{
+		{xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, "transfer"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, "hello"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{
+			Type:      xdr.ScAddressTypeScAddressTypeAccount,
+			AccountId: &accountID,
+		}}, account},
+		{xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, "true"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u32}, "42"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i32}, "-42"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u64}, "1099511627776"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i64}, "-1099511627776"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: 1, Lo: 2}}, "18446744073709551618"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: 0xffffffffffffff9c}}, "-100"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &bytes}, "AAAADQAAAAMBAgMA"},
+	} {
+		decoded, err := decodeTopicSegment(tc.value)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, decoded)
+	}
+}
+
+func TestEventInfoForEvent_DecodeTopics(t *testing.T) {
+	sym := xdr.ScSymbol("transfer")
+	counter := xdr.Uint32(1)
+	event := xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			Type: xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{
+				V: 0,
+				V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{
+						{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+						{Type: xdr.ScValTypeScvU32, U32: &counter},
+					},
+					Data: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+				},
+			},
+		},
+	}
+
+	info, err := eventInfoForEvent(event, db.Cursor{Ledger: 1}, "", "", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"transfer", "1"}, info.TopicDecoded)
+	// the raw topic is still present
+	assert.Len(t, info.TopicXDR, 2)
+
+	info, err = eventInfoForEvent(event, db.Cursor{Ledger: 1}, "", "", "", false)
+	require.NoError(t, err)
+	assert.Nil(t, info.TopicDecoded)
+}
@@ -0,0 +1,156 @@
+package methods
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
+)
+
+// Types of the decoded footprint keys.
+const (
+	FootprintKeyTypeAccount          = "account"
+	FootprintKeyTypeTrustline        = "trustline"
+	FootprintKeyTypeOffer            = "offer"
+	FootprintKeyTypeData             = "data"
+	FootprintKeyTypeClaimableBalance = "claimableBalance"
+	FootprintKeyTypeLiquidityPool    = "liquidityPool"
+	FootprintKeyTypeContractData     = "contractData"
+	FootprintKeyTypeContractCode     = "contractCode"
+	FootprintKeyTypeConfigSetting    = "configSetting"
+	FootprintKeyTypeTTL              = "ttl"
+)
+
+// Footprint is the footprint of a Soroban transaction, with its keys decoded.
+type Footprint struct {
+	ReadOnly  []FootprintKey `json:"readOnly"`
+	ReadWrite []FootprintKey `json:"readWrite"`
+}
+
+// FootprintKey is a decoded LedgerKey. Only the fields of its type are present.
+type FootprintKey struct {
+	// Type is one of the FootprintKeyType* constants.
+	Type string `json:"type"`
+	// Account is the strkey-encoded account of account, trustline, offer and data keys.
+	Account string `json:"account,omitempty"`
+	// Asset is the canonical form of the asset of trustline keys (e.g. "native" or "USDC:G..."),
+	// or the hex-encoded ID of their liquidity pool for pool share trustlines.
+	Asset string `json:"asset,omitempty"`
+	// OfferID is the ID of offer keys.
+	OfferID int64 `json:"offerId,string,omitempty"`
+	// DataName is the name of data keys.
+	DataName string `json:"dataName,omitempty"`
+	// BalanceID is the hex-encoded ClaimableBalanceId XDR value of claimable balance keys.
+	BalanceID string `json:"balanceId,omitempty"`
+	// LiquidityPoolID is the hex-encoded ID of liquidity pool keys.
+	LiquidityPoolID string `json:"liquidityPoolId,omitempty"`
+	// Contract is the strkey-encoded address of contract data keys (usually a contract).
+	Contract string `json:"contract,omitempty"`
+	// KeyXDR is the ScVal XDR value of the key of contract data keys.
+	KeyXDR  string          `json:"keyXdr,omitempty"`
+	KeyJSON json.RawMessage `json:"keyJson,omitempty"`
+	// Durability is the durability (persistent or temporary) of contract data keys.
+	Durability string `json:"durability,omitempty"`
+	// WasmHash is the hex-encoded hash of the Wasm of contract code keys.
+	WasmHash string `json:"wasmHash,omitempty"`
+	// ConfigSettingID is the name of the setting of config setting keys (e.g. ConfigSettingContractMaxSizeBytes).
+	ConfigSettingID string `json:"configSettingId,omitempty"`
+	// KeyHash is the hex-encoded hash of the key whose TTL is held by TTL keys.
+	KeyHash string `json:"keyHash,omitempty"`
+}
+
+// decodeFootprintKey decodes a ledger key, encoding the contract data keys in the given format.
+func decodeFootprintKey(key xdr.LedgerKey, format string) (FootprintKey, error) {
+	var decoded FootprintKey
+	var err error
+	switch key.Type {
+	case xdr.LedgerEntryTypeAccount:
+		decoded.Type = FootprintKeyTypeAccount
+		decoded.Account = key.Account.AccountId.Address()
+	case xdr.LedgerEntryTypeTrustline:
+		decoded.Type = FootprintKeyTypeTrustline
+		decoded.Account = key.TrustLine.AccountId.Address()
+		if poolID, ok := key.TrustLine.Asset.GetLiquidityPoolId(); ok {
+			decoded.Asset = hex.EncodeToString(poolID[:])
+		} else {
+			decoded.Asset = key.TrustLine.Asset.ToAsset().StringCanonical()
+		}
+	case xdr.LedgerEntryTypeOffer:
+		decoded.Type = FootprintKeyTypeOffer
+		decoded.Account = key.Offer.SellerId.Address()
+		decoded.OfferID = int64(key.Offer.OfferId)
+	case xdr.LedgerEntryTypeData:
+		decoded.Type = FootprintKeyTypeData
+		decoded.Account = key.Data.AccountId.Address()
+		decoded.DataName = string(key.Data.DataName)
+	case xdr.LedgerEntryTypeClaimableBalance:
+		decoded.Type = FootprintKeyTypeClaimableBalance
+		if decoded.BalanceID, err = xdr.MarshalHex(key.ClaimableBalance.BalanceId); err != nil {
+			return FootprintKey{}, err
+		}
+	case xdr.LedgerEntryTypeLiquidityPool:
+		decoded.Type = FootprintKeyTypeLiquidityPool
+		poolID := key.LiquidityPool.LiquidityPoolId
+		decoded.LiquidityPoolID = hex.EncodeToString(poolID[:])
+	case xdr.LedgerEntryTypeContractData:
+		decoded.Type = FootprintKeyTypeContractData
+		if decoded.Contract, err = key.ContractData.Contract.String(); err != nil {
+			return FootprintKey{}, err
+		}
+		switch format {
+		case FormatJSON:
+			if decoded.KeyJSON, err = xdr2json.ConvertInterface(key.ContractData.Key); err != nil {
+				return FootprintKey{}, err
+			}
+		default:
+			if decoded.KeyXDR, err = xdr.MarshalBase64(key.ContractData.Key); err != nil {
+				return FootprintKey{}, err
+			}
+		}
+		switch key.ContractData.Durability {
+		case xdr.ContractDataDurabilityPersistent:
+			decoded.Durability = "persistent"
+		case xdr.ContractDataDurabilityTemporary:
+			decoded.Durability = "temporary"
+		}
+	case xdr.LedgerEntryTypeContractCode:
+		decoded.Type = FootprintKeyTypeContractCode
+		decoded.WasmHash = key.ContractCode.Hash.HexString()
+	case xdr.LedgerEntryTypeConfigSetting:
+		decoded.Type = FootprintKeyTypeConfigSetting
+		decoded.ConfigSettingID = resultCodeName(key.ConfigSetting.ConfigSettingId)
+	case xdr.LedgerEntryTypeTtl:
+		decoded.Type = FootprintKeyTypeTTL
+		decoded.KeyHash = key.Ttl.KeyHash.HexString()
+	default:
+		return FootprintKey{}, fmt.Errorf("unknown ledger key type: %d", key.Type)
+	}
+	return decoded, nil
+}
+
+// decodeFootprint decodes the keys of a footprint.
+func decodeFootprint(footprint xdr.LedgerFootprint, format string) (Footprint, error) {
+	decodeKeys := func(keys []xdr.LedgerKey) ([]FootprintKey, error) {
+		decoded := make([]FootprintKey, 0, len(keys))
+		for _, key := range keys {
+			decodedKey, err := decodeFootprintKey(key, format)
+			if err != nil {
+				return nil, err
+			}
+			decoded = append(decoded, decodedKey)
+		}
+		return decoded, nil
+	}
+	readOnly, err := decodeKeys(footprint.ReadOnly)
+	if err != nil {
+		return Footprint{}, err
+	}
+	readWrite, err := decodeKeys(footprint.ReadWrite)
+	if err != nil {
+		return Footprint{}, err
+	}
+	return Footprint{ReadOnly: readOnly, ReadWrite: readWrite}, nil
+}
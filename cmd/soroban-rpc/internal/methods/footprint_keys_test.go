@@ -0,0 +1,184 @@
+package methods
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+func TestDecodeFootprintKey(t *testing.T) {
+	account := keypair.MustRandom().Address()
+	accountID := xdr.MustAddress(account)
+	issuer := keypair.MustRandom().Address()
+	contractID := xdr.Hash{1, 2, 3}
+	poolID := xdr.PoolId{4, 5, 6}
+	balanceID := xdr.ClaimableBalanceId{
+		Type: xdr.ClaimableBalanceIdTypeClaimableBalanceIdTypeV0,
+		V0:   &xdr.Hash{7, 8, 9},
+	}
+	balanceIDHex, err := xdr.MarshalHex(balanceID)
+	require.NoError(t, err)
+	symbol := xdr.ScSymbol("balance")
+	dataKey := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &symbol}
+	dataKeyXDR, err := xdr.MarshalBase64(dataKey)
+	require.NoError(t, err)
+	keyHash := xdr.Hash(sha256.Sum256([]byte("key")))
+
+	for _, testCase := range []struct {
+		name     string
+		key      xdr.LedgerKey
+		expected FootprintKey
+	}{
+		{
+			name: "account",
+			key: xdr.LedgerKey{
+				Type:    xdr.LedgerEntryTypeAccount,
+				Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+			},
+			expected: FootprintKey{Type: FootprintKeyTypeAccount, Account: account},
+		},
+		{
+			name: "trustline",
+			key: xdr.LedgerKey{
+				Type: xdr.LedgerEntryTypeTrustline,
+				TrustLine: &xdr.LedgerKeyTrustLine{
+					AccountId: accountID,
+					Asset:     xdr.MustNewCreditAsset("USDC", issuer).ToTrustLineAsset(),
+				},
+			},
+			expected: FootprintKey{Type: FootprintKeyTypeTrustline, Account: account, Asset: "USDC:" + issuer},
+		},
+		{
+			name: "pool share trustline",
+			key: xdr.LedgerKey{
+				Type: xdr.LedgerEntryTypeTrustline,
+				TrustLine: &xdr.LedgerKeyTrustLine{
+					AccountId: accountID,
+					Asset: xdr.TrustLineAsset{
+						Type:            xdr.AssetTypeAssetTypePoolShare,
+						LiquidityPoolId: &poolID,
+					},
+				},
+			},
+			expected: FootprintKey{
+				Type:    FootprintKeyTypeTrustline,
+				Account: account,
+				Asset:   "0405060000000000000000000000000000000000000000000000000000000000",
+			},
+		},
+		{
+			name: "offer",
+			key: xdr.LedgerKey{
+				Type:  xdr.LedgerEntryTypeOffer,
+				Offer: &xdr.LedgerKeyOffer{SellerId: accountID, OfferId: 42},
+			},
+			expected: FootprintKey{Type: FootprintKeyTypeOffer, Account: account, OfferID: 42},
+		},
+		{
+			name: "data",
+			key: xdr.LedgerKey{
+				Type: xdr.LedgerEntryTypeData,
+				Data: &xdr.LedgerKeyData{AccountId: accountID, DataName: "config"},
+			},
+			expected: FootprintKey{Type: FootprintKeyTypeData, Account: account, DataName: "config"},
+		},
+		{
+			name: "claimable balance",
+			key: xdr.LedgerKey{
+				Type:             xdr.LedgerEntryTypeClaimableBalance,
+				ClaimableBalance: &xdr.LedgerKeyClaimableBalance{BalanceId: balanceID},
+			},
+			expected: FootprintKey{Type: FootprintKeyTypeClaimableBalance, BalanceID: balanceIDHex},
+		},
+		{
+			name: "liquidity pool",
+			key: xdr.LedgerKey{
+				Type:          xdr.LedgerEntryTypeLiquidityPool,
+				LiquidityPool: &xdr.LedgerKeyLiquidityPool{LiquidityPoolId: poolID},
+			},
+			expected: FootprintKey{
+				Type:            FootprintKeyTypeLiquidityPool,
+				LiquidityPoolID: "0405060000000000000000000000000000000000000000000000000000000000",
+			},
+		},
+		{
+			name: "contract data",
+			key: xdr.LedgerKey{
+				Type: xdr.LedgerEntryTypeContractData,
+				ContractData: &xdr.LedgerKeyContractData{
+					Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+					Key:        dataKey,
+					Durability: xdr.ContractDataDurabilityTemporary,
+				},
+			},
+			expected: FootprintKey{
+				Type:       FootprintKeyTypeContractData,
+				Contract:   strkey.MustEncode(strkey.VersionByteContract, contractID[:]),
+				KeyXDR:     dataKeyXDR,
+				Durability: "temporary",
+			},
+		},
+		{
+			name: "contract code",
+			key: xdr.LedgerKey{
+				Type:         xdr.LedgerEntryTypeContractCode,
+				ContractCode: &xdr.LedgerKeyContractCode{Hash: contractID},
+			},
+			expected: FootprintKey{Type: FootprintKeyTypeContractCode, WasmHash: contractID.HexString()},
+		},
+		{
+			name: "config setting",
+			key: xdr.LedgerKey{
+				Type: xdr.LedgerEntryTypeConfigSetting,
+				ConfigSetting: &xdr.LedgerKeyConfigSetting{
+					ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+				},
+			},
+			expected: FootprintKey{
+				Type:            FootprintKeyTypeConfigSetting,
+				ConfigSettingID: "ConfigSettingContractMaxSizeBytes",
+			},
+		},
+		{
+			name:     "ttl",
+			key:      xdr.LedgerKey{Type: xdr.LedgerEntryTypeTtl, Ttl: &xdr.LedgerKeyTtl{KeyHash: keyHash}},
+			expected: FootprintKey{Type: FootprintKeyTypeTTL, KeyHash: keyHash.HexString()},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			decoded, err := decodeFootprintKey(testCase.key, FormatBase64)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, decoded)
+		})
+	}
+}
+
+func TestDecodeFootprint(t *testing.T) {
+	accountKey := xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(keypair.MustRandom().Address())},
+	}
+	codeKey := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{1}},
+	}
+	footprint, err := decodeFootprint(xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{codeKey}}, FormatBase64)
+	require.NoError(t, err)
+	assert.Equal(t, []FootprintKey{{Type: FootprintKeyTypeContractCode, WasmHash: xdr.Hash{1}.HexString()}},
+		footprint.ReadOnly)
+	assert.Empty(t, footprint.ReadWrite)
+
+	footprint, err = decodeFootprint(xdr.LedgerFootprint{
+		ReadOnly:  []xdr.LedgerKey{codeKey},
+		ReadWrite: []xdr.LedgerKey{accountKey},
+	}, FormatBase64)
+	require.NoError(t, err)
+	require.Len(t, footprint.ReadWrite, 1)
+	assert.Equal(t, FootprintKeyTypeAccount, footprint.ReadWrite[0].Type)
+}
@@ -0,0 +1,129 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/strkey"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+const (
+	// maxContractActivityLedgerRange is the maximum number of ledgers aggregated by a
+	// getContractActivity request.
+	maxContractActivityLedgerRange = LedgerScanLimit
+	defaultContractActivityLimit   = 10
+	maxContractActivityLimit       = 100
+)
+
+type GetContractActivityRequest struct {
+	StartLedger uint32 `json:"startLedger"`
+	// EndLedger (inclusive) defaults to the latest ledger, within maxContractActivityLedgerRange
+	// ledgers of StartLedger.
+	EndLedger uint32 `json:"endLedger,omitempty"`
+	// Limit is the number of most active contracts returned.
+	Limit uint `json:"limit,omitempty"`
+}
+
+// ContractActivity is the activity of a contract, as recorded by the events it emitted.
+type ContractActivity struct {
+	// ContractID is the strkey-encoded contract ID (C...).
+	ContractID string `json:"contractId"`
+	// TransactionCount is the number of successful transactions in which the contract emitted
+	// events, i.e. its invocations which emitted events.
+	TransactionCount uint32 `json:"transactionCount"`
+	// EventCount is the number of events (including the diagnostic events) emitted by the contract.
+	EventCount uint32 `json:"eventCount"`
+}
+
+type GetContractActivityResponse struct {
+	// Contracts are the most active contracts over the range, by decreasing transaction count
+	// and then event count.
+	Contracts   []ContractActivity `json:"contracts"`
+	StartLedger uint32             `json:"startLedger"`
+	EndLedger   uint32             `json:"endLedger"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+}
+
+// NewGetContractActivityHandler returns a JSON RPC handler aggregating the activity of the
+// contracts over a range of ledgers, through the index of the events by contract ID.
+func NewGetContractActivityHandler(ledgerReader db.LedgerReader, activityReader db.ContractActivityReader,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request GetContractActivityRequest,
+	) (GetContractActivityResponse, error) {
+		limit := request.Limit
+		if limit == 0 {
+			limit = defaultContractActivityLimit
+		} else if limit > maxContractActivityLimit {
+			return GetContractActivityResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("limit must not exceed %d", maxContractActivityLimit),
+			}
+		}
+
+		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil {
+			return GetContractActivityResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("unable to get ledger range: %v", err),
+			}
+		}
+		if request.StartLedger < ledgerRange.FirstLedger.Sequence ||
+			request.StartLedger > ledgerRange.LastLedger.Sequence {
+			return GetContractActivityResponse{}, &jrpc2.Error{
+				Code: jrpc2.InvalidParams,
+				Message: fmt.Sprintf(
+					"startLedger must be within the ledger range: %d - %d",
+					ledgerRange.FirstLedger.Sequence,
+					ledgerRange.LastLedger.Sequence,
+				),
+			}
+		}
+		endLedger := ledgerRange.LastLedger.Sequence
+		if request.EndLedger != 0 {
+			if request.EndLedger < request.StartLedger {
+				return GetContractActivityResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: "endLedger must not be lower than startLedger",
+				}
+			}
+			endLedger = min(request.EndLedger, endLedger)
+		}
+		if endLedger-request.StartLedger >= maxContractActivityLedgerRange {
+			if request.EndLedger != 0 {
+				return GetContractActivityResponse{}, &jrpc2.Error{
+					Code: jrpc2.InvalidParams,
+					Message: fmt.Sprintf("the ledger range cannot span more than %d ledgers",
+						maxContractActivityLedgerRange),
+				}
+			}
+			endLedger = request.StartLedger + maxContractActivityLedgerRange - 1
+		}
+
+		activity, err := activityReader.GetContractActivity(ctx, request.StartLedger, endLedger, limit)
+		if err != nil {
+			return GetContractActivityResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response := GetContractActivityResponse{
+			Contracts:    make([]ContractActivity, 0, len(activity)),
+			StartLedger:  request.StartLedger,
+			EndLedger:    endLedger,
+			LatestLedger: ledgerRange.LastLedger.Sequence,
+		}
+		for _, contract := range activity {
+			response.Contracts = append(response.Contracts, ContractActivity{
+				ContractID:       strkey.MustEncode(strkey.VersionByteContract, contract.ContractID[:]),
+				TransactionCount: contract.TransactionCount,
+				EventCount:       contract.EventCount,
+			})
+		}
+		return response, nil
+	})
+}
@@ -0,0 +1,99 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// contractActivityRecorder is a db.ContractActivityReader recording the aggregated ranges.
+type contractActivityRecorder struct {
+	activity   []db.ContractActivity
+	start, end uint32
+	limit      uint
+}
+
+func (r *contractActivityRecorder) GetContractActivity(_ context.Context, startLedger, endLedger uint32,
+	limit uint,
+) ([]db.ContractActivity, error) {
+	r.start, r.end, r.limit = startLedger, endLedger, limit
+	return r.activity[:min(int(limit), len(r.activity))], nil
+}
+
+func TestGetContractActivity(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(uint32(i))))
+	}
+	contractA, contractB := xdr.Hash{1}, xdr.Hash{2}
+	reader := &contractActivityRecorder{
+		activity: []db.ContractActivity{
+			{ContractID: contractA, TransactionCount: 5, EventCount: 7},
+			{ContractID: contractB, TransactionCount: 3, EventCount: 12},
+		},
+	}
+	handler := NewGetContractActivityHandler(db.NewMockLedgerReader(store), reader)
+	call := func(params string) (GetContractActivityResponse, error) {
+		requests, err := jrpc2.ParseRequests([]byte(
+			`{"jsonrpc":"2.0","id":1,"method":"getContractActivity","params":` + params + `}`))
+		require.NoError(t, err)
+		response, err := handler(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return GetContractActivityResponse{}, err
+		}
+		return response.(GetContractActivityResponse), nil
+	}
+
+	response, err := call(`{"startLedger":2}`)
+	require.NoError(t, err)
+	assert.Equal(t, GetContractActivityResponse{
+		Contracts: []ContractActivity{
+			{
+				ContractID:       strkey.MustEncode(strkey.VersionByteContract, contractA[:]),
+				TransactionCount: 5,
+				EventCount:       7,
+			},
+			{
+				ContractID:       strkey.MustEncode(strkey.VersionByteContract, contractB[:]),
+				TransactionCount: 3,
+				EventCount:       12,
+			},
+		},
+		StartLedger:  2,
+		EndLedger:    10,
+		LatestLedger: 10,
+	}, response)
+	assert.Equal(t, uint(defaultContractActivityLimit), reader.limit)
+
+	// the end ledger and the number of contracts are bounded by the request
+	response, err = call(`{"startLedger":3,"endLedger":5,"limit":1}`)
+	require.NoError(t, err)
+	assert.Len(t, response.Contracts, 1)
+	assert.Equal(t, uint32(5), response.EndLedger)
+	assert.Equal(t, [2]uint32{3, 5}, [2]uint32{reader.start, reader.end})
+
+	// the end ledger is capped at the latest ledger
+	response, err = call(`{"startLedger":1,"endLedger":20000}`)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), response.EndLedger)
+
+	for params, message := range map[string]string{
+		`{"startLedger":11}`:              "startLedger must be within the ledger range: 1 - 10",
+		`{"startLedger":5,"endLedger":4}`: "endLedger must not be lower than startLedger",
+		`{"startLedger":1,"limit":101}`:   "limit must not exceed 100",
+	} {
+		_, err := call(params)
+		var jrpcErr *jrpc2.Error
+		require.ErrorAs(t, err, &jrpcErr, params)
+		assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+		assert.Equal(t, message, jrpcErr.Message)
+	}
+}
@@ -0,0 +1,94 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+const (
+	// ContractCreationStatusFound indicates the creation of the contract is stored.
+	ContractCreationStatusFound = "FOUND"
+	// ContractCreationStatusNotFound indicates the contract is unknown, or was created
+	// before the oldest stored ledger.
+	ContractCreationStatusNotFound = "NOT_FOUND"
+)
+
+type GetContractCreationRequest struct {
+	// ContractID is the strkey-encoded contract ID (C...).
+	ContractID string `json:"contractId"`
+}
+
+type GetContractCreationResponse struct {
+	// Status is one of: ContractCreationStatusFound, ContractCreationStatusNotFound.
+	Status string `json:"status"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+	// OldestLedger is the oldest ledger stored in Soroban-RPC. The contracts created
+	// before it are not found.
+	OldestLedger uint32 `json:"oldestLedger"`
+
+	// TransactionHash is the hex-encoded hash of the transaction which created the contract.
+	TransactionHash string `json:"txHash,omitempty"`
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger,omitempty"`
+	// ApplicationOrder is the index of the transaction among the transactions of the ledger.
+	ApplicationOrder int32 `json:"applicationOrder,omitempty"`
+	// LedgerCloseTime is the unix timestamp of when the contract was created.
+	LedgerCloseTime int64 `json:"createdAt,string,omitempty"`
+}
+
+// NewGetContractCreationHandler returns a JSON RPC handler looking up the transaction which
+// created a contract, through the contract creation index.
+func NewGetContractCreationHandler(ledgerReader db.LedgerReader,
+	creationReader db.ContractCreationReader,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request GetContractCreationRequest,
+	) (GetContractCreationResponse, error) {
+		decoded, err := strkey.Decode(strkey.VersionByteContract, request.ContractID)
+		if err != nil {
+			return GetContractCreationResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("invalid contract ID %q: %v", request.ContractID, err),
+			}
+		}
+		var contractID xdr.Hash
+		copy(contractID[:], decoded)
+
+		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil {
+			return GetContractCreationResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("unable to get ledger range: %v", err),
+			}
+		}
+		response := GetContractCreationResponse{
+			Status:       ContractCreationStatusNotFound,
+			LatestLedger: ledgerRange.LastLedger.Sequence,
+			OldestLedger: ledgerRange.FirstLedger.Sequence,
+		}
+
+		creation, found, err := creationReader.GetContractCreation(ctx, contractID)
+		if err != nil {
+			return GetContractCreationResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		if !found {
+			return response, nil
+		}
+		response.Status = ContractCreationStatusFound
+		response.TransactionHash = creation.TransactionHash.HexString()
+		response.Ledger = creation.Ledger
+		response.ApplicationOrder = creation.ApplicationOrder
+		response.LedgerCloseTime = creation.LedgerCloseTime
+		return response, nil
+	})
+}
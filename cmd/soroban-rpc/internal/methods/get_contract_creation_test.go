@@ -0,0 +1,77 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type mockContractCreationReader map[xdr.Hash]db.ContractCreation
+
+func (m mockContractCreationReader) GetContractCreation(_ context.Context, contractID xdr.Hash,
+) (db.ContractCreation, bool, error) {
+	creation, found := m[contractID]
+	return creation, found, nil
+}
+
+func TestGetContractCreation(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := uint32(1); i <= 3; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(i)))
+	}
+	creations := mockContractCreationReader{
+		xdr.Hash{1}: {
+			TransactionLocation: db.TransactionLocation{Ledger: 2, ApplicationOrder: 3},
+			TransactionHash:     xdr.Hash{0xab},
+			LedgerCloseTime:     1700000000,
+		},
+	}
+	handler := NewGetContractCreationHandler(db.NewMockLedgerReader(store), creations)
+	getCreation := func(contractID string) (GetContractCreationResponse, error) {
+		params, err := json.Marshal(GetContractCreationRequest{ContractID: contractID})
+		require.NoError(t, err)
+		request := jrpc2.ParsedRequest{ID: "1", Method: "getContractCreation", Params: params}
+		response, err := handler(context.Background(), request.ToRequest())
+		if err != nil {
+			return GetContractCreationResponse{}, err
+		}
+		return response.(GetContractCreationResponse), nil
+	}
+
+	id := xdr.Hash{1}
+	response, err := getCreation(strkey.MustEncode(strkey.VersionByteContract, id[:]))
+	require.NoError(t, err)
+	assert.Equal(t, GetContractCreationResponse{
+		Status:           ContractCreationStatusFound,
+		LatestLedger:     3,
+		OldestLedger:     1,
+		TransactionHash:  xdr.Hash{0xab}.HexString(),
+		Ledger:           2,
+		ApplicationOrder: 3,
+		LedgerCloseTime:  1700000000,
+	}, response)
+
+	unknown := xdr.Hash{2}
+	response, err = getCreation(strkey.MustEncode(strkey.VersionByteContract, unknown[:]))
+	require.NoError(t, err)
+	assert.Equal(t, GetContractCreationResponse{
+		Status:       ContractCreationStatusNotFound,
+		LatestLedger: 3,
+		OldestLedger: 1,
+	}, response)
+
+	// account IDs aren't contract IDs
+	_, err = getCreation(strkey.MustEncode(strkey.VersionByteAccountID, id[:]))
+	var jrpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+}
@@ -26,6 +26,8 @@ const (
 	maxTopicsLimit      = 5
 	maxFiltersLimit     = 5
 	maxEventTypes       = 3
+	// maxTopicPrefixLen is the maximum length of a symbol
+	maxTopicPrefixLen = 32
 )
 
 type eventTypeSet map[string]interface{}
@@ -100,10 +102,28 @@ type EventInfo struct {
 	// TopicXDR is a base64-encoded list of ScVals
 	TopicXDR  []string          `json:"topic,omitempty"`
 	TopicJSON []json.RawMessage `json:"topicJson,omitempty"`
+	// TopicDecoded is a human-readable rendering of the topic, present if requested
+	// through DecodeTopics. Values without a readable form are base64-encoded ScVals.
+	TopicDecoded []string `json:"topicDecoded,omitempty"`
 
 	// ValueXDR is a base64-encoded ScVal
 	ValueXDR  string          `json:"value,omitempty"`
 	ValueJSON json.RawMessage `json:"valueJson,omitempty"`
+
+	// emptySlices is how the empty topics are rendered (see WithEmptySlices).
+	emptySlices string
+}
+
+func (e EventInfo) MarshalJSON() ([]byte, error) {
+	type eventInfo EventInfo
+	encoded, err := json.Marshal(eventInfo(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendEmptyLists(encoded, e.emptySlices,
+		listField("topic", e.TopicXDR),
+		listField("topicJson", e.TopicJSON),
+		listField("topicDecoded", e.TopicDecoded)), nil
 }
 
 type GetEventsRequest struct {
@@ -112,6 +132,11 @@ type GetEventsRequest struct {
 	Filters     []EventFilter      `json:"filters"`
 	Pagination  *PaginationOptions `json:"pagination,omitempty"`
 	Format      string             `json:"xdrFormat,omitempty"`
+	// DecodeTopics adds a human-readable rendering of the topics (TopicDecoded) to the events.
+	DecodeTopics bool `json:"decodeTopics,omitempty"`
+	// IncludeTotal adds the number of events matching the request (Total) to the response.
+	// Counting requires scanning the whole search window, so it can be expensive.
+	IncludeTotal bool `json:"includeTotal,omitempty"`
 }
 
 func (g *GetEventsRequest) Valid(maxLimit uint) error {
@@ -183,6 +208,10 @@ type EventFilter struct {
 	EventType   eventTypeSet  `json:"type,omitempty"`
 	ContractIDs []string      `json:"contractIds,omitempty"`
 	Topics      []TopicFilter `json:"topics,omitempty"`
+	// TopicPrefix, if set, only matches the events whose first topic is a symbol (or a
+	// string) starting with it, regardless of their other topics, e.g. "transfer" matches
+	// the "transfer" and "transfer_from" events.
+	TopicPrefix string `json:"topicPrefix,omitempty"`
 }
 
 func (e *EventFilter) Valid() error {
@@ -206,11 +235,15 @@ func (e *EventFilter) Valid() error {
 			return fmt.Errorf("topic %d invalid: %w", i+1, err)
 		}
 	}
+	if len(e.TopicPrefix) > maxTopicPrefixLen {
+		return fmt.Errorf("topic prefix cannot be longer than %d characters", maxTopicPrefixLen)
+	}
 	return nil
 }
 
 func (e *EventFilter) Matches(event xdr.DiagnosticEvent) bool {
-	return e.EventType.matches(event.Event) && e.matchesContractIDs(event.Event) && e.matchesTopics(event.Event)
+	return e.EventType.matches(event.Event) && e.matchesContractIDs(event.Event) && e.matchesTopics(event.Event) &&
+		e.matchesTopicPrefix(event.Event)
 }
 
 func (e *EventFilter) matchesContractIDs(event xdr.ContractEvent) bool {
@@ -245,6 +278,26 @@ func (e *EventFilter) matchesTopics(event xdr.ContractEvent) bool {
 	return false
 }
 
+func (e *EventFilter) matchesTopicPrefix(event xdr.ContractEvent) bool {
+	if e.TopicPrefix == "" {
+		return true
+	}
+	v0, ok := event.Body.GetV0()
+	if !ok || len(v0.Topics) == 0 {
+		return false
+	}
+	var name string
+	switch topic := v0.Topics[0]; topic.Type {
+	case xdr.ScValTypeScvSymbol:
+		name = string(*topic.Sym)
+	case xdr.ScValTypeScvString:
+		name = string(*topic.Str)
+	default:
+		return false
+	}
+	return strings.HasPrefix(name, e.TopicPrefix)
+}
+
 type TopicFilter []SegmentFilter
 
 func (t *TopicFilter) Valid() error {
@@ -341,6 +394,17 @@ type GetEventsResponse struct {
 	LatestLedger uint32      `json:"latestLedger"`
 	// Cursor represents last populated event ID if total events reach the limit or end of the search window
 	Cursor string `json:"cursor"`
+	// Total is the number of events matching the request from the start of the page (startLedger or cursor)
+	// to the end of the search window. It is only set when requested through includeTotal.
+	Total *uint64 `json:"total,omitempty"`
+}
+
+func (r GetEventsResponse) withEmptySlices(mode string) interface{} {
+	r.Events = requiredList(r.Events, mode)
+	for i := range r.Events {
+		r.Events[i].emptySlices = mode
+	}
+	return r
 }
 
 type eventsRPCHandler struct {
@@ -485,14 +549,19 @@ func (h eventsRPCHandler) getEvents(ctx context.Context, request GetEventsReques
 
 	eventTypes := combineEventTypes(request.Filters)
 
-	// Scan function to apply filters
+	// Scan function to apply filters. When counting, the scan goes on after the limit is reached.
+	var total uint64
 	eventScanFunction := func(
 		event xdr.DiagnosticEvent, cursor db.Cursor, ledgerCloseTimestamp int64, txHash *xdr.Hash,
 	) bool {
-		if request.Matches(event) {
+		if !request.Matches(event) {
+			return true
+		}
+		total++
+		if uint(len(found)) < limit {
 			found = append(found, entry{cursor, ledgerCloseTimestamp, event, txHash})
 		}
-		return uint(len(found)) < limit
+		return request.IncludeTotal || uint(len(found)) < limit
 	}
 
 	err = h.dbReader.GetEvents(ctx, cursorRange, contractIDs, topics, eventTypes, eventScanFunction)
@@ -510,6 +579,7 @@ func (h eventsRPCHandler) getEvents(ctx context.Context, request GetEventsReques
 			time.Unix(entry.ledgerCloseTimestamp, 0).UTC().Format(time.RFC3339),
 			entry.txHash.HexString(),
 			request.Format,
+			request.DecodeTopics,
 		)
 		if err != nil {
 			return GetEventsResponse{}, errors.Wrap(err, "could not parse event")
@@ -528,17 +598,22 @@ func (h eventsRPCHandler) getEvents(ctx context.Context, request GetEventsReques
 		cursor = db.Cursor{Ledger: endLedger - 1, Tx: math.MaxUint32, Event: math.MaxUint32 - 1}.String()
 	}
 
-	return GetEventsResponse{
+	response := GetEventsResponse{
 		LatestLedger: ledgerRange.LastLedger.Sequence,
 		Events:       results,
 		Cursor:       cursor,
-	}, nil
+	}
+	if request.IncludeTotal {
+		response.Total = &total
+	}
+	return response, nil
 }
 
 func eventInfoForEvent(
 	event xdr.DiagnosticEvent,
 	cursor db.Cursor,
 	ledgerClosedAt, txHash, format string,
+	decodeTopics bool,
 ) (EventInfo, error) {
 	v0, ok := event.Event.Body.GetV0()
 	if !ok {
@@ -599,6 +674,13 @@ func eventInfoForEvent(
 		info.ValueXDR = data
 	}
 
+	if decodeTopics {
+		var err error
+		if info.TopicDecoded, err = decodeTopic(v0.Topics); err != nil {
+			return EventInfo{}, err
+		}
+	}
+
 	if event.Event.ContractId != nil {
 		info.ContractID = strkey.MustEncode(
 			strkey.VersionByteContract,
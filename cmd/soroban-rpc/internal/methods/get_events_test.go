@@ -367,6 +367,53 @@ func TestTopicFilterMatches(t *testing.T) {
 	}
 }
 
+func TestEventFilterTopicPrefix(t *testing.T) {
+	event := func(topics ...xdr.ScVal) xdr.DiagnosticEvent {
+		return xdr.DiagnosticEvent{
+			Event: xdr.ContractEvent{
+				Type: xdr.ContractEventTypeContract,
+				Body: xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{Topics: topics}},
+			},
+		}
+	}
+	symbol := func(name string) xdr.ScVal {
+		sym := xdr.ScSymbol(name)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+	}
+	str := xdr.ScString("transfer")
+	sixtyfour := xdr.Uint64(64)
+
+	filter := EventFilter{TopicPrefix: "transfer"}
+	require.NoError(t, filter.Valid())
+	for _, included := range []xdr.DiagnosticEvent{
+		event(symbol("transfer")),
+		event(symbol("transfer_from"), symbol("alice"), symbol("bob")),
+		event(xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}),
+	} {
+		assert.True(t, filter.Matches(included))
+	}
+	for _, excluded := range []xdr.DiagnosticEvent{
+		event(),
+		event(symbol("mint")),
+		event(symbol("trans")),
+		event(symbol("approve"), symbol("transfer")),
+		event(xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &sixtyfour}),
+	} {
+		assert.False(t, filter.Matches(excluded))
+	}
+
+	// the prefix applies on top of the topic filters
+	star := "*"
+	alice := symbol("alice")
+	filter.Topics = []TopicFilter{{{wildcard: &star}, {scval: &alice}}}
+	assert.True(t, filter.Matches(event(symbol("transfer_from"), alice)))
+	assert.False(t, filter.Matches(event(symbol("transfer_from"), symbol("bob"))))
+	assert.False(t, filter.Matches(event(symbol("mint"), alice)))
+
+	filter = EventFilter{TopicPrefix: strings.Repeat("a", 33)}
+	require.ErrorContains(t, filter.Valid(), "topic prefix cannot be longer than 32 characters")
+}
+
 func TestTopicFilterJSON(t *testing.T) {
 	var got TopicFilter
 
@@ -657,7 +704,7 @@ func TestGetEvents(t *testing.T) {
 			})
 		}
 		cursor := db.Cursor{Ledger: 1, Tx: math.MaxUint32, Event: math.MaxUint32 - 1}.String()
-		assert.Equal(t, GetEventsResponse{expected, 1, cursor}, results)
+		assert.Equal(t, GetEventsResponse{expected, 1, cursor, nil}, results)
 	})
 
 	t.Run("filtering by contract id", func(t *testing.T) {
@@ -805,7 +852,7 @@ func TestGetEvents(t *testing.T) {
 		}
 		cursor := db.Cursor{Ledger: 1, Tx: math.MaxUint32, Event: math.MaxUint32 - 1}.String()
 
-		assert.Equal(t, GetEventsResponse{expected, 1, cursor}, results)
+		assert.Equal(t, GetEventsResponse{expected, 1, cursor, nil}, results)
 
 		results, err = handler.getEvents(ctx, GetEventsRequest{
 			StartLedger: 1,
@@ -839,7 +886,7 @@ func TestGetEvents(t *testing.T) {
 
 		expected[0].ValueJSON = valueJs
 		expected[0].TopicJSON = topicsJs
-		require.Equal(t, GetEventsResponse{expected, 1, cursor}, results)
+		require.Equal(t, GetEventsResponse{expected, 1, cursor, nil}, results)
 	})
 
 	t.Run("filtering by both contract id and topic", func(t *testing.T) {
@@ -952,7 +999,7 @@ func TestGetEvents(t *testing.T) {
 		}
 		cursor := db.Cursor{Ledger: 1, Tx: math.MaxUint32, Event: math.MaxUint32 - 1}.String()
 
-		assert.Equal(t, GetEventsResponse{expected, 1, cursor}, results)
+		assert.Equal(t, GetEventsResponse{expected, 1, cursor, nil}, results)
 	})
 
 	t.Run("filtering by event type", func(t *testing.T) {
@@ -1029,7 +1076,7 @@ func TestGetEvents(t *testing.T) {
 		}
 		cursor := db.Cursor{Ledger: 1, Tx: math.MaxUint32, Event: math.MaxUint32 - 1}.String()
 
-		assert.Equal(t, GetEventsResponse{expected, 1, cursor}, results)
+		assert.Equal(t, GetEventsResponse{expected, 1, cursor, nil}, results)
 	})
 
 	t.Run("with limit", func(t *testing.T) {
@@ -1102,7 +1149,27 @@ func TestGetEvents(t *testing.T) {
 		}
 		cursor := expected[len(expected)-1].ID
 
-		assert.Equal(t, GetEventsResponse{expected, 1, cursor}, results)
+		assert.Equal(t, GetEventsResponse{expected, 1, cursor, nil}, results)
+
+		// the total counts all the matching events, beyond the limit
+		results, err = handler.getEvents(context.TODO(), GetEventsRequest{
+			StartLedger:  1,
+			Filters:      []EventFilter{},
+			Pagination:   &PaginationOptions{Limit: 10},
+			IncludeTotal: true,
+		})
+		require.NoError(t, err)
+		total := uint64(180)
+		assert.Equal(t, GetEventsResponse{expected, 1, cursor, &total}, results)
+
+		results, err = handler.getEvents(context.TODO(), GetEventsRequest{
+			Filters:      []EventFilter{},
+			Pagination:   &PaginationOptions{Cursor: &db.Cursor{Ledger: 1, Tx: 175}, Limit: 10},
+			IncludeTotal: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, results.Events, 5)
+		assert.Equal(t, uint64(5), *results.Total)
 	})
 
 	t.Run("with cursor", func(t *testing.T) {
@@ -1203,7 +1270,7 @@ func TestGetEvents(t *testing.T) {
 			})
 		}
 		cursor := expected[len(expected)-1].ID
-		assert.Equal(t, GetEventsResponse{expected, 5, cursor}, results)
+		assert.Equal(t, GetEventsResponse{expected, 5, cursor, nil}, results)
 
 		results, err = handler.getEvents(context.TODO(), GetEventsRequest{
 			Pagination: &PaginationOptions{
@@ -1219,7 +1286,7 @@ func TestGetEvents(t *testing.T) {
 		// Note: endLedger is always exclusive when fetching events
 		// so search window is always max Cursor value with endLedger - 1
 		cursor = db.Cursor{Ledger: uint32(endLedger - 1), Tx: math.MaxUint32, Event: math.MaxUint32 - 1}.String()
-		assert.Equal(t, GetEventsResponse{[]EventInfo{}, 5, cursor}, results)
+		assert.Equal(t, GetEventsResponse{[]EventInfo{}, 5, cursor, nil}, results)
 	})
 }
 
@@ -0,0 +1,50 @@
+package methods
+
+import (
+	"context"
+	"errors"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type GetIngestionCheckpointResponse struct {
+	// Checkpoint is the sequence of the last ledger whose ingestion was committed.
+	// It is omitted if no ledger was committed since the checkpoint was introduced.
+	Checkpoint uint32 `json:"checkpoint,omitempty"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger,omitempty"`
+	// Consistent indicates whether the checkpoint matches the latest stored ledger.
+	Consistent bool `json:"consistent"`
+}
+
+// NewGetIngestionCheckpointHandler returns an admin JSON RPC handler reporting the ingestion
+// checkpoint together with the latest stored ledger.
+func NewGetIngestionCheckpointHandler(
+	checkpointReader db.IngestionCheckpointReader,
+	ledgerReader db.LedgerReader,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context) (GetIngestionCheckpointResponse, error) {
+		var response GetIngestionCheckpointResponse
+		checkpoint, err := checkpointReader.GetIngestionCheckpoint(ctx)
+		if err != nil && !errors.Is(err, db.ErrEmptyDB) {
+			return GetIngestionCheckpointResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get ingestion checkpoint",
+			}
+		}
+		response.Checkpoint = checkpoint
+
+		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil && !errors.Is(err, db.ErrEmptyDB) {
+			return GetIngestionCheckpointResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get ledger range",
+			}
+		}
+		response.LatestLedger = ledgerRange.LastLedger.Sequence
+		response.Consistent = response.Checkpoint == response.LatestLedger
+		return response, nil
+	})
+}
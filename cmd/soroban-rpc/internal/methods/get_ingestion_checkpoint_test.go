@@ -0,0 +1,44 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type constantCheckpointReader struct {
+	checkpoint uint32
+	err        error
+}
+
+func (r constantCheckpointReader) GetIngestionCheckpoint(_ context.Context) (uint32, error) {
+	return r.checkpoint, r.err
+}
+
+func TestGetIngestionCheckpoint(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(uint32(i))))
+	}
+
+	handler := NewGetIngestionCheckpointHandler(constantCheckpointReader{checkpoint: 3}, ledgerReader)
+	resp, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, GetIngestionCheckpointResponse{Checkpoint: 3, LatestLedger: 3, Consistent: true}, resp)
+
+	handler = NewGetIngestionCheckpointHandler(constantCheckpointReader{checkpoint: 2}, ledgerReader)
+	resp, err = handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, GetIngestionCheckpointResponse{Checkpoint: 2, LatestLedger: 3, Consistent: false}, resp)
+
+	handler = NewGetIngestionCheckpointHandler(constantCheckpointReader{err: db.ErrEmptyDB}, ledgerReader)
+	resp, err = handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, GetIngestionCheckpointResponse{LatestLedger: 3, Consistent: false}, resp)
+}
@@ -0,0 +1,253 @@
+package methods
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+// Metrics by which getLargestTransactions ranks the transactions.
+const (
+	// LargestTransactionsMetricResourceFee is the resource fee charged to Soroban transactions
+	// (their declared resource fee if the meta doesn't record the charged fee).
+	LargestTransactionsMetricResourceFee = "resourceFee"
+	// LargestTransactionsMetricInstructions is the number of CPU instructions declared by Soroban transactions.
+	LargestTransactionsMetricInstructions = "instructions"
+	// LargestTransactionsMetricMetaSize is the size in bytes of the TransactionMeta XDR of the transactions.
+	LargestTransactionsMetricMetaSize = "metaSize"
+
+	defaultLargestTransactionsLimit = 10
+	maxLargestTransactionsLimit     = 100
+)
+
+type GetLargestTransactionsRequest struct {
+	StartLedger uint32 `json:"startLedger"`
+	// EndLedger defaults to the latest ledger.
+	EndLedger uint32 `json:"endLedger,omitempty"`
+	// Metric is one of: LargestTransactionsMetricResourceFee (the default),
+	// LargestTransactionsMetricInstructions, LargestTransactionsMetricMetaSize.
+	Metric string `json:"metric,omitempty"`
+	// Limit is the number of returned transactions (10 by default, at most 100).
+	Limit uint `json:"limit,omitempty"`
+}
+
+// LargestTransaction is a transaction along with its resource usage.
+type LargestTransaction struct {
+	TransactionHash  string `json:"txHash"`
+	Ledger           uint32 `json:"ledger"`
+	ApplicationOrder int32  `json:"applicationOrder"`
+	ResourceFee      int64  `json:"resourceFee,string"`
+	Instructions     uint32 `json:"instructions"`
+	MetaSize         uint32 `json:"metaSize"`
+}
+
+type GetLargestTransactionsResponse struct {
+	// Transactions are sorted by decreasing metric. Transactions with equal metrics are
+	// sorted by application order.
+	Transactions []LargestTransaction `json:"transactions"`
+	LatestLedger uint32               `json:"latestLedger"`
+	OldestLedger uint32               `json:"oldestLedger"`
+}
+
+// metric returns the value of the transaction for the given metric.
+func (tx LargestTransaction) metric(metric string) int64 {
+	switch metric {
+	case LargestTransactionsMetricInstructions:
+		return int64(tx.Instructions)
+	case LargestTransactionsMetricMetaSize:
+		return int64(tx.MetaSize)
+	default:
+		return tx.ResourceFee
+	}
+}
+
+// ranksBefore tells whether tx ranks before other: it has a larger metric, or an equal metric
+// and an earlier application.
+func (tx LargestTransaction) ranksBefore(other LargestTransaction, metric string) bool {
+	if a, b := tx.metric(metric), other.metric(metric); a != b {
+		return a > b
+	}
+	if tx.Ledger != other.Ledger {
+		return tx.Ledger < other.Ledger
+	}
+	return tx.ApplicationOrder < other.ApplicationOrder
+}
+
+// largestTransactionsHeap keeps the top transactions, with the lowest ranked one at the root.
+type largestTransactionsHeap struct {
+	transactions []LargestTransaction
+	metric       string
+}
+
+func (h *largestTransactionsHeap) Len() int { return len(h.transactions) }
+
+func (h *largestTransactionsHeap) Less(i, j int) bool {
+	return h.transactions[j].ranksBefore(h.transactions[i], h.metric)
+}
+
+func (h *largestTransactionsHeap) Swap(i, j int) {
+	h.transactions[i], h.transactions[j] = h.transactions[j], h.transactions[i]
+}
+
+func (h *largestTransactionsHeap) Push(x any) {
+	h.transactions = append(h.transactions, x.(LargestTransaction))
+}
+
+func (h *largestTransactionsHeap) Pop() any {
+	last := h.transactions[len(h.transactions)-1]
+	h.transactions = h.transactions[:len(h.transactions)-1]
+	return last
+}
+
+// add adds tx to the heap if it ranks among the top limit transactions.
+func (h *largestTransactionsHeap) add(tx LargestTransaction, limit int) {
+	if h.Len() < limit {
+		heap.Push(h, tx)
+	} else if tx.ranksBefore(h.transactions[0], h.metric) {
+		h.transactions[0] = tx
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted returns the transactions of the heap by rank.
+func (h *largestTransactionsHeap) sorted() []LargestTransaction {
+	sorted := append([]LargestTransaction{}, h.transactions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ranksBefore(sorted[j], h.metric) })
+	return sorted
+}
+
+// largestTransaction returns the resource usage of a transaction.
+func largestTransaction(ledgerSeq uint32, tx ingest.LedgerTransaction) (LargestTransaction, error) {
+	encodedMeta, err := tx.UnsafeMeta.MarshalBinary()
+	if err != nil {
+		return LargestTransaction{}, err
+	}
+	result := LargestTransaction{
+		TransactionHash:  tx.Result.TransactionHash.HexString(),
+		Ledger:           ledgerSeq,
+		ApplicationOrder: int32(tx.Index),
+		MetaSize:         uint32(len(encodedMeta)),
+	}
+	sorobanData, ok := getSorobanData(tx.Envelope)
+	if !ok {
+		return result, nil
+	}
+	breakdown, err := resourceFeeBreakdown(sorobanData, tx.UnsafeMeta)
+	if err != nil {
+		return LargestTransaction{}, err
+	}
+	result.Instructions = breakdown.Instructions
+	result.ResourceFee = breakdown.ResourceFee
+	if charged := breakdown.NonRefundableResourceFeeCharged + breakdown.RefundableResourceFeeCharged; charged > 0 {
+		result.ResourceFee = charged
+	}
+	return result, nil
+}
+
+// isValid checks the request and returns the metric, limit and end ledger to use.
+func (request GetLargestTransactionsRequest) isValid(ledgerRange ledgerbucketwindow.LedgerRange, maxLedgers uint32,
+) (string, int, uint32, error) {
+	metric := request.Metric
+	switch metric {
+	case "":
+		metric = LargestTransactionsMetricResourceFee
+	case LargestTransactionsMetricResourceFee, LargestTransactionsMetricInstructions, LargestTransactionsMetricMetaSize:
+	default:
+		return "", 0, 0, fmt.Errorf("metric must be one of %q, %q or %q", LargestTransactionsMetricResourceFee,
+			LargestTransactionsMetricInstructions, LargestTransactionsMetricMetaSize)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultLargestTransactionsLimit
+	} else if limit > maxLargestTransactionsLimit {
+		return "", 0, 0, fmt.Errorf("limit must not exceed %d", maxLargestTransactionsLimit)
+	}
+
+	if request.StartLedger < ledgerRange.FirstLedger.Sequence || request.StartLedger > ledgerRange.LastLedger.Sequence {
+		return "", 0, 0, fmt.Errorf(
+			"start ledger must be between the oldest ledger: %d and the latest ledger: %d for this rpc instance",
+			ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence)
+	}
+	endLedger := ledgerRange.LastLedger.Sequence
+	if request.EndLedger != 0 {
+		if request.EndLedger < request.StartLedger {
+			return "", 0, 0, errors.New("endLedger must not be lower than startLedger")
+		}
+		endLedger = min(request.EndLedger, endLedger)
+	}
+	if maxLedgers != 0 && endLedger-request.StartLedger+1 > maxLedgers {
+		return "", 0, 0, fmt.Errorf("the ledger range must not exceed %d ledgers", maxLedgers)
+	}
+	return metric, int(limit), endLedger, nil
+}
+
+// NewGetLargestTransactionsHandler returns a JSON RPC handler returning the transactions of
+// a range of ledgers with the largest resource usage. The range is capped to maxLedgers
+// ledgers (0 meaning unlimited), since all its transactions are decoded.
+func NewGetLargestTransactionsHandler(ledgerReader db.LedgerReader, networkPassphrase string,
+	maxLedgers uint32,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request GetLargestTransactionsRequest,
+	) (GetLargestTransactionsResponse, error) {
+		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil {
+			return GetLargestTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("unable to get ledger range: %v", err),
+			}
+		}
+		metric, limit, endLedger, err := request.isValid(ledgerRange, maxLedgers)
+		if err != nil {
+			return GetLargestTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+
+		largest := &largestTransactionsHeap{metric: metric}
+		err = ledgerReader.StreamLedgerRange(ctx, request.StartLedger, endLedger,
+			func(ledger xdr.LedgerCloseMeta) error {
+				reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(networkPassphrase, ledger)
+				if err != nil {
+					return err
+				}
+				for {
+					tx, err := reader.Read()
+					if errors.Is(err, io.EOF) {
+						return nil
+					} else if err != nil {
+						return err
+					}
+					result, err := largestTransaction(ledger.LedgerSequence(), tx)
+					if err != nil {
+						return err
+					}
+					largest.add(result, limit)
+				}
+			})
+		if err != nil {
+			return GetLargestTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+
+		return GetLargestTransactionsResponse{
+			Transactions: largest.sorted(),
+			LatestLedger: ledgerRange.LastLedger.Sequence,
+			OldestLedger: ledgerRange.FirstLedger.Sequence,
+		}, nil
+	})
+}
@@ -0,0 +1,143 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// sorobanTxMeta is a ledger with a Soroban transaction using the given resources. The events
+// inflate the meta of the transaction.
+func sorobanTxMeta(acctSeq uint32, instructions uint32, resourceFee, chargedFee int64, events int,
+) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, true)
+	components := *meta.V1.TxSet.V1TxSet.Phases[0].V0Components
+	envelope := &components[0].TxsMaybeDiscountedFee.Txs[0]
+	envelope.V1.Tx.Ext = xdr.TransactionExt{
+		V: 1,
+		SorobanData: &xdr.SorobanTransactionData{
+			Resources:   xdr.SorobanResources{Instructions: xdr.Uint32(instructions)},
+			ResourceFee: xdr.Int64(resourceFee),
+		},
+	}
+	hash, err := network.HashTransactionInEnvelope(*envelope, "passphrase")
+	if err != nil {
+		panic(err)
+	}
+	txProcessing := &meta.V1.TxProcessing[0]
+	txProcessing.Result.TransactionHash = hash
+
+	sorobanMeta := &xdr.SorobanTransactionMeta{ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid}}
+	if chargedFee > 0 {
+		sorobanMeta.Ext = xdr.SorobanTransactionMetaExt{
+			V:  1,
+			V1: &xdr.SorobanTransactionMetaExtV1{TotalNonRefundableResourceFeeCharged: xdr.Int64(chargedFee)},
+		}
+	}
+	event := txMetaWithEvents(acctSeq, true).V1.TxProcessing[0].TxApplyProcessing.V3.SorobanMeta.Events[0]
+	for range events {
+		sorobanMeta.Events = append(sorobanMeta.Events, event)
+	}
+	txProcessing.TxApplyProcessing.V3.SorobanMeta = sorobanMeta
+	return meta
+}
+
+func TestGetLargestTransactions(t *testing.T) {
+	dbx := NewTestDB(t)
+	ctx := context.Background()
+	ledgers := []xdr.LedgerCloseMeta{
+		txMeta(1, true),
+		sorobanTxMeta(2, 5000, 900, 400, 0),
+		// without a charged fee, the declared resource fee is used
+		sorobanTxMeta(3, 1000, 700, 0, 5),
+		sorobanTxMeta(4, 3000, 100, 100, 1),
+	}
+	writer := db.NewReadWriter(log.DefaultLogger, dbx, interfaces.MakeNoOpDeamon(), 10, 100, "passphrase")
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for _, ledger := range ledgers {
+		require.NoError(t, write.LedgerWriter().InsertLedger(ledger))
+	}
+	require.NoError(t, write.Commit(ledgers[len(ledgers)-1]))
+
+	handler := NewGetLargestTransactionsHandler(db.NewLedgerReader(dbx), "passphrase", 10)
+	getLargest := func(request GetLargestTransactionsRequest) (GetLargestTransactionsResponse, error) {
+		params, err := json.Marshal(request)
+		require.NoError(t, err)
+		rpcRequest := jrpc2.ParsedRequest{ID: "1", Method: "getLargestTransactions", Params: params}
+		response, err := handler(ctx, rpcRequest.ToRequest())
+		if err != nil {
+			return GetLargestTransactionsResponse{}, err
+		}
+		return response.(GetLargestTransactionsResponse), nil
+	}
+	ledgersOf := func(response GetLargestTransactionsResponse) []uint32 {
+		var sequences []uint32
+		for _, tx := range response.Transactions {
+			sequences = append(sequences, tx.Ledger)
+		}
+		return sequences
+	}
+
+	response, err := getLargest(GetLargestTransactionsRequest{StartLedger: 101, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{103, 102}, ledgersOf(response))
+	assert.Equal(t, uint32(104), response.LatestLedger)
+	assert.Equal(t, uint32(101), response.OldestLedger)
+	second := response.Transactions[1]
+	assert.Equal(t, ledgers[1].TransactionHash(0).HexString(), second.TransactionHash)
+	assert.Equal(t, int32(1), second.ApplicationOrder)
+	assert.Equal(t, int64(400), second.ResourceFee)
+	assert.Equal(t, uint32(5000), second.Instructions)
+	assert.Equal(t, int64(700), response.Transactions[0].ResourceFee)
+
+	response, err = getLargest(GetLargestTransactionsRequest{
+		StartLedger: 101,
+		Metric:      LargestTransactionsMetricInstructions,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{102, 104, 103, 101}, ledgersOf(response))
+
+	response, err = getLargest(GetLargestTransactionsRequest{
+		StartLedger: 101,
+		Metric:      LargestTransactionsMetricMetaSize,
+		Limit:       3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{103, 104, 102}, ledgersOf(response))
+	assert.Greater(t, response.Transactions[0].MetaSize, response.Transactions[1].MetaSize)
+
+	response, err = getLargest(GetLargestTransactionsRequest{StartLedger: 102, EndLedger: 102})
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{102}, ledgersOf(response))
+
+	for _, request := range []GetLargestTransactionsRequest{
+		{StartLedger: 100},
+		{StartLedger: 102, EndLedger: 101},
+		{StartLedger: 101, Metric: "fee"},
+		{StartLedger: 101, Limit: maxLargestTransactionsLimit + 1},
+	} {
+		_, err = getLargest(request)
+		var jrpcErr *jrpc2.Error
+		require.ErrorAs(t, err, &jrpcErr)
+		assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	}
+
+	// the range is capped
+	handler = NewGetLargestTransactionsHandler(db.NewLedgerReader(dbx), "passphrase", 3)
+	_, err = getLargest(GetLargestTransactionsRequest{StartLedger: 101})
+	require.ErrorContains(t, err, "the ledger range must not exceed 3 ledgers")
+	_, err = getLargest(GetLargestTransactionsRequest{StartLedger: 101, EndLedger: 103})
+	require.NoError(t, err)
+}
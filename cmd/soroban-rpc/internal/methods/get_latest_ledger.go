@@ -2,12 +2,22 @@ package methods
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/creachadair/jrpc2"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
 )
 
+type GetLatestLedgerRequest struct {
+	// MinLedger, if set, makes the request wait (up to the configured maximum wait) until a
+	// ledger later than MinLedger is ingested, if the latest ledger isn't already. The
+	// latest ledger is returned when the wait times out.
+	MinLedger uint32 `json:"minLedger,omitempty"`
+}
+
 type GetLatestLedgerResponse struct {
 	// Hash of the latest ledger as a hex-encoded string
 	Hash string `json:"id"`
@@ -17,30 +27,80 @@ type GetLatestLedgerResponse struct {
 	Sequence uint32 `json:"sequence"`
 }
 
-// NewGetLatestLedgerHandler returns a JSON RPC handler to retrieve the latest ledger entry from Stellar core.
-func NewGetLatestLedgerHandler(ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader) jrpc2.Handler {
-	return NewHandler(func(ctx context.Context) (GetLatestLedgerResponse, error) {
-		latestSequence, err := ledgerEntryReader.GetLatestLedgerSequence(ctx)
-		if err != nil {
-			return GetLatestLedgerResponse{}, &jrpc2.Error{
-				Code:    jrpc2.InternalError,
-				Message: "could not get latest ledger sequence",
-			}
-		}
+type latestLedgerHandler struct {
+	ledgerEntryReader db.LedgerEntryReader
+	ledgerReader      db.LedgerReader
+	ingestionWindow   *ingestionwindow.IngestionWindow
+	maxWait           time.Duration
+}
 
-		latestLedger, found, err := ledgerReader.GetLedger(ctx, latestSequence)
-		if (err != nil) || (!found) {
-			return GetLatestLedgerResponse{}, &jrpc2.Error{
-				Code:    jrpc2.InternalError,
-				Message: "could not get latest ledger",
-			}
+func (h latestLedgerHandler) latestLedger(ctx context.Context) (GetLatestLedgerResponse, error) {
+	latestSequence, err := h.ledgerEntryReader.GetLatestLedgerSequence(ctx)
+	if err != nil {
+		return GetLatestLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not get latest ledger sequence",
 		}
+	}
 
-		response := GetLatestLedgerResponse{
-			Hash:            latestLedger.LedgerHash().HexString(),
-			ProtocolVersion: latestLedger.ProtocolVersion(),
-			Sequence:        latestSequence,
+	latestLedger, found, err := h.ledgerReader.GetLedger(ctx, latestSequence)
+	if (err != nil) || (!found) {
+		return GetLatestLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not get latest ledger",
 		}
+	}
+
+	response := GetLatestLedgerResponse{
+		Hash:            latestLedger.LedgerHash().HexString(),
+		ProtocolVersion: latestLedger.ProtocolVersion(),
+		Sequence:        latestSequence,
+	}
+	return response, nil
+}
+
+// getLatestLedger returns the latest ledger, after waiting for the ingestion of a ledger later than
+// the minLedger of the request (if any) through the ingestion window, for up to maxWait.
+func (h latestLedgerHandler) getLatestLedger(ctx context.Context, request GetLatestLedgerRequest,
+) (GetLatestLedgerResponse, error) {
+	response, err := h.latestLedger(ctx)
+	if err != nil || request.MinLedger == 0 || response.Sequence > request.MinLedger ||
+		h.ingestionWindow == nil || h.maxWait <= 0 {
+		return response, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, h.maxWait)
+	defer cancel()
+	if !h.ingestionWindow.WaitForLedger(waitCtx, request.MinLedger) {
 		return response, nil
-	})
+	}
+	return h.latestLedger(ctx)
+}
+
+// GetLatestLedgerCacheKey derives the result cache key of getLatestLedger requests (see
+// ResultCacheConfig.Key). The requests with a minLedger aren't cached, since their result
+// depends on whether their wait timed out.
+func GetLatestLedgerCacheKey(request *jrpc2.Request) (string, error) {
+	var params GetLatestLedgerRequest
+	if request.HasParams() {
+		if err := request.UnmarshalParams(&params); err != nil {
+			return "", err
+		}
+	}
+	if params.MinLedger != 0 {
+		return "", errors.New("requests waiting for a ledger aren't cached")
+	}
+	return ParamsCacheKey(request)
+}
+
+// NewGetLatestLedgerHandler returns a JSON RPC handler to retrieve the latest ledger entry from Stellar core.
+func NewGetLatestLedgerHandler(ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader,
+	ingestionWindow *ingestionwindow.IngestionWindow, maxWait time.Duration,
+) jrpc2.Handler {
+	return NewHandler(latestLedgerHandler{
+		ledgerEntryReader: ledgerEntryReader,
+		ledgerReader:      ledgerReader,
+		ingestionWindow:   ingestionWindow,
+		maxWait:           maxWait,
+	}.getLatestLedger)
 }
@@ -2,7 +2,10 @@ package methods
 
 import (
 	"context"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/creachadair/jrpc2"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +14,7 @@ import (
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
 )
 
@@ -85,7 +89,7 @@ func createLedger(ledgerSequence uint32, protocolVersion uint32, hash byte) xdr.
 }
 
 func TestGetLatestLedger(t *testing.T) {
-	getLatestLedgerHandler := NewGetLatestLedgerHandler(&ConstantLedgerEntryReader{}, &ConstantLedgerReader{})
+	getLatestLedgerHandler := NewGetLatestLedgerHandler(&ConstantLedgerEntryReader{}, &ConstantLedgerReader{}, nil, 0)
 	latestLedgerRespI, err := getLatestLedgerHandler(context.Background(), &jrpc2.Request{})
 	latestLedgerResp := latestLedgerRespI.(GetLatestLedgerResponse)
 	require.NoError(t, err)
@@ -96,3 +100,87 @@ func TestGetLatestLedger(t *testing.T) {
 	assert.Equal(t, expectedLatestLedgerProtocolVersion, latestLedgerResp.ProtocolVersion)
 	assert.Equal(t, expectedLatestLedgerSequence, latestLedgerResp.Sequence)
 }
+
+// ingestingLedgerEntryReader is a ConstantLedgerEntryReader whose latest ledger moves forward.
+type ingestingLedgerEntryReader struct {
+	ConstantLedgerEntryReader
+	latestSequence atomic.Uint32
+}
+
+func (entryReader *ingestingLedgerEntryReader) GetLatestLedgerSequence(_ context.Context) (uint32, error) {
+	return entryReader.latestSequence.Load(), nil
+}
+
+func TestGetLatestLedgerMinLedger(t *testing.T) {
+	entryReader := &ingestingLedgerEntryReader{}
+	entryReader.latestSequence.Store(expectedLatestLedgerSequence)
+	window := ingestionwindow.NewIngestionWindow(10)
+	handler := latestLedgerHandler{
+		ledgerEntryReader: entryReader,
+		ledgerReader:      &ConstantLedgerReader{},
+		ingestionWindow:   window,
+		maxWait:           time.Minute,
+	}
+	ctx := context.Background()
+
+	// the latest ledger is already later than minLedger
+	response, err := handler.getLatestLedger(ctx, GetLatestLedgerRequest{MinLedger: expectedLatestLedgerSequence - 1})
+	require.NoError(t, err)
+	assert.Equal(t, expectedLatestLedgerSequence, response.Sequence)
+
+	// the request waits for the ingestion of the next ledger
+	responses := make(chan GetLatestLedgerResponse)
+	go func() {
+		response, err := handler.getLatestLedger(ctx, GetLatestLedgerRequest{MinLedger: expectedLatestLedgerSequence})
+		assert.NoError(t, err)
+		responses <- response
+	}()
+	select {
+	case <-responses:
+		t.Fatal("the request didn't wait for a new ledger")
+	case <-time.After(50 * time.Millisecond):
+	}
+	entryReader.latestSequence.Store(expectedLatestLedgerSequence + 1)
+	window.AppendLedger(expectedLatestLedgerSequence+1, 0, time.Now())
+	select {
+	case response := <-responses:
+		assert.Equal(t, expectedLatestLedgerSequence+1, response.Sequence)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the request wasn't unblocked by the new ledger")
+	}
+
+	// the current latest ledger is returned when the wait times out
+	handler.maxWait = 10 * time.Millisecond
+	response, err = handler.getLatestLedger(ctx, GetLatestLedgerRequest{MinLedger: expectedLatestLedgerSequence + 5})
+	require.NoError(t, err)
+	assert.Equal(t, expectedLatestLedgerSequence+1, response.Sequence)
+}
+
+func TestGetLatestLedgerResultCache(t *testing.T) {
+	entryReader := &ingestingLedgerEntryReader{}
+	entryReader.latestSequence.Store(expectedLatestLedgerSequence)
+	handler := WithResultCache(
+		NewGetLatestLedgerHandler(entryReader, &ConstantLedgerReader{}, ingestionwindow.NewIngestionWindow(10),
+			10*time.Millisecond),
+		ResultCacheConfig{TTL: time.Minute, Key: GetLatestLedgerCacheKey},
+	)
+	call := func(params string) uint32 {
+		requests, err := jrpc2.ParseRequests([]byte(
+			`{"jsonrpc":"2.0","id":1,"method":"getLatestLedger","params":` + params + `}`))
+		require.NoError(t, err)
+		response, err := handler(context.Background(), requests[0].ToRequest())
+		require.NoError(t, err)
+		return response.(GetLatestLedgerResponse).Sequence
+	}
+
+	// the result of a timed out wait isn't cached
+	waiting := `{"minLedger":` + strconv.Itoa(int(expectedLatestLedgerSequence+5)) + `}`
+	assert.Equal(t, expectedLatestLedgerSequence, call(waiting))
+	entryReader.latestSequence.Store(expectedLatestLedgerSequence + 1)
+	assert.Equal(t, expectedLatestLedgerSequence+1, call(waiting))
+
+	// the other requests are
+	assert.Equal(t, expectedLatestLedgerSequence+1, call(`{}`))
+	entryReader.latestSequence.Store(expectedLatestLedgerSequence + 2)
+	assert.Equal(t, expectedLatestLedgerSequence+1, call(`{}`))
+}
@@ -0,0 +1,295 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
+)
+
+const (
+	// LedgerStatusFound indicates the ledger is stored by Soroban-RPC.
+	LedgerStatusFound = "FOUND"
+	// LedgerStatusNotFound indicates the ledger is out of the ledger range of Soroban-RPC
+	// (or missing from it).
+	LedgerStatusNotFound = "NOT_FOUND"
+)
+
+// GetLedgerRequest is the request for the Soroban-RPC getLedger() endpoint
+type GetLedgerRequest struct {
+	// Ledger is the sequence of the ledger.
+	Ledger uint32 `json:"ledger"`
+	Format string `json:"xdrFormat,omitempty"`
+	// IncludeEconomics adds the TotalCoins, FeePool and InflationSeq of the ledger header to the
+	// response.
+	IncludeEconomics bool `json:"includeEconomics,omitempty"`
+	// IncludeScpInfo adds the SCP messages recorded in the meta of the ledger to the response.
+	IncludeScpInfo bool `json:"includeScpInfo,omitempty"`
+}
+
+// GetLedgerResponse is the response for the Soroban-RPC getLedger() endpoint
+type GetLedgerResponse struct {
+	// Status is one of: LedgerStatusFound or LedgerStatusNotFound.
+	Status string `json:"status"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedgerCloseTime is the unix timestamp of when the latest ledger was closed.
+	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+	// OldestLedger is the oldest ledger stored in Soroban-RPC.
+	OldestLedger uint32 `json:"oldestLedger"`
+	// OldestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
+	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
+
+	// The fields below are only present if Status is LedgerStatusFound.
+
+	// Sequence is the sequence of the ledger.
+	Sequence uint32 `json:"sequence,omitempty"`
+	// Hash is the hex-encoded hash of the ledger.
+	Hash string `json:"hash,omitempty"`
+	// LedgerCloseTime is the unix timestamp of when the ledger was closed.
+	LedgerCloseTime int64 `json:"closeTime,string,omitempty"`
+	// CloseTimeDriftSeconds is the interval between the close times of the previous ledger and
+	// this one, minus the target interval (see the ledger-close-time-target option): it is
+	// positive for ledgers which closed late. It is omitted for the oldest stored ledger, whose
+	// previous ledger isn't stored anymore.
+	CloseTimeDriftSeconds *int64 `json:"closeTimeDriftSeconds,omitempty"`
+	// HeaderXDR is the LedgerHeader XDR value of the ledger.
+	HeaderXDR  string          `json:"headerXdr,omitempty"`
+	HeaderJSON json.RawMessage `json:"headerJson,omitempty"`
+	// TotalCoins is the total number of stroops in existence. It is only present when requested
+	// through IncludeEconomics, like FeePool and InflationSeq.
+	TotalCoins *int64 `json:"totalCoins,omitempty,string"`
+	// FeePool is the number of stroops of the fees collected by the network.
+	FeePool *int64 `json:"feePool,omitempty,string"`
+	// InflationSeq is the number of inflation runs.
+	InflationSeq *uint32 `json:"inflationSeq,omitempty"`
+	// Upgrades are the upgrades applied at the ledger (e.g. protocol version bumps and base fee
+	// changes), in application order. The list is empty if there are none.
+	Upgrades *[]LedgerUpgrade `json:"upgrades,omitempty"`
+	// ScpInfoXDR are the ScpHistoryEntry XDR values recorded in the meta of the ledger (the
+	// quorum sets and the SCP messages which externalized it), or ScpInfoJSON with the JSON
+	// format. They are only present when requested through IncludeScpInfo, and if the meta has
+	// any: Stellar Core only records them in the metas of some configurations.
+	ScpInfoXDR  []string          `json:"scpInfoXdr,omitempty"`
+	ScpInfoJSON []json.RawMessage `json:"scpInfoJson,omitempty"`
+}
+
+// LedgerUpgrade is an upgrade applied at a ledger, along with its result.
+type LedgerUpgrade struct {
+	// Type is the snake_case type of the upgrade, e.g. version or base_fee.
+	Type string `json:"type"`
+	// UpgradeXDR is the LedgerUpgrade XDR value.
+	UpgradeXDR  string          `json:"upgradeXdr,omitempty"`
+	UpgradeJSON json.RawMessage `json:"upgradeJson,omitempty"`
+	// ChangesXDR is the LedgerEntryChanges XDR value of the ledger entries changed by the
+	// upgrade, e.g. the config settings updated by config upgrades.
+	ChangesXDR  string          `json:"changesXdr,omitempty"`
+	ChangesJSON json.RawMessage `json:"changesJson,omitempty"`
+}
+
+// setScpInfo fills in the response with the SCP messages of the meta of the ledger, if any.
+func setScpInfo(response *GetLedgerResponse, ledger xdr.LedgerCloseMeta, format string) error {
+	var scpInfo []xdr.ScpHistoryEntry
+	switch ledger.V {
+	case 0:
+		scpInfo = ledger.MustV0().ScpInfo
+	case 1:
+		scpInfo = ledger.MustV1().ScpInfo
+	}
+	if len(scpInfo) == 0 {
+		return nil
+	}
+	encoded := make([][]byte, len(scpInfo))
+	for i, entry := range scpInfo {
+		var err error
+		if encoded[i], err = entry.MarshalBinary(); err != nil {
+			return err
+		}
+	}
+	switch format {
+	case FormatJSON:
+		var err error
+		response.ScpInfoJSON, err = jsonifySlice(xdr.ScpHistoryEntry{}, encoded)
+		return err
+	default:
+		response.ScpInfoXDR = base64EncodeSlice(encoded)
+		return nil
+	}
+}
+
+// ledgerUpgrades returns the upgrades applied at a ledger, in the requested format.
+func ledgerUpgrades(ledger xdr.LedgerCloseMeta, format string) ([]LedgerUpgrade, error) {
+	var processing []xdr.UpgradeEntryMeta
+	switch ledger.V {
+	case 0:
+		processing = ledger.MustV0().UpgradesProcessing
+	case 1:
+		processing = ledger.MustV1().UpgradesProcessing
+	}
+	upgrades := make([]LedgerUpgrade, 0, len(processing))
+	for _, meta := range processing {
+		upgrade := LedgerUpgrade{
+			Type: toSnakeCase(strings.TrimPrefix(meta.Upgrade.Type.String(), "LedgerUpgradeTypeLedgerUpgrade")),
+		}
+		var err error
+		switch format {
+		case FormatJSON:
+			if upgrade.UpgradeJSON, err = xdr2json.ConvertInterface(meta.Upgrade); err != nil {
+				return nil, err
+			}
+			upgrade.ChangesJSON, err = xdr2json.ConvertInterface(meta.Changes)
+		default:
+			if upgrade.UpgradeXDR, err = xdr.MarshalBase64(meta.Upgrade); err != nil {
+				return nil, err
+			}
+			upgrade.ChangesXDR, err = xdr.MarshalBase64(meta.Changes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+	return upgrades, nil
+}
+
+// closeTimeDrift returns the drift of the close time of a ledger from the target interval after
+// the close time of the previous ledger, or nil if the previous ledger isn't stored.
+func closeTimeDrift(
+	ctx context.Context,
+	closeTimeReader db.LedgerCloseTimeReader,
+	closeTimeTarget time.Duration,
+	oldestLedger uint32,
+	ledger xdr.LedgerCloseMeta,
+) (*int64, error) {
+	sequence := ledger.LedgerSequence()
+	if closeTimeReader == nil || sequence <= oldestLedger {
+		return nil, nil
+	}
+	previous, err := closeTimeReader.GetLedgerCloseTimes(ctx, sequence-1, sequence-1, 1)
+	if err != nil || len(previous) == 0 {
+		return nil, err
+	}
+	drift := ledger.LedgerCloseTime() - previous[0].CloseTime - int64(closeTimeTarget/time.Second)
+	return &drift, nil
+}
+
+// GetLedger returns the header of a stored ledger, or a LedgerStatusNotFound response if
+// the ledger isn't stored. The close time drift of the ledger is computed from the close time of
+// the previous ledger read through closeTimeReader, and the closeTimeTarget interval.
+func GetLedger(
+	ctx context.Context,
+	ledgerReader db.LedgerReader,
+	closeTimeReader db.LedgerCloseTimeReader,
+	closeTimeTarget time.Duration,
+	request GetLedgerRequest,
+) (GetLedgerResponse, error) {
+	if request.Ledger == 0 {
+		return GetLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: "ledger must be set",
+		}
+	}
+	if err := IsValidFormat(request.Format); err != nil {
+		return GetLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	storeRange, err := ledgerReader.GetLedgerRange(ctx)
+	if err != nil {
+		return GetLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: fmt.Sprintf("unable to get ledger range: %v", err),
+		}
+	}
+	response := GetLedgerResponse{
+		Status:                LedgerStatusNotFound,
+		LatestLedger:          storeRange.LastLedger.Sequence,
+		LatestLedgerCloseTime: storeRange.LastLedger.CloseTime,
+		OldestLedger:          storeRange.FirstLedger.Sequence,
+		OldestLedgerCloseTime: storeRange.FirstLedger.CloseTime,
+	}
+	if request.Ledger < storeRange.FirstLedger.Sequence || request.Ledger > storeRange.LastLedger.Sequence {
+		return response, nil
+	}
+
+	ledger, found, err := ledgerReader.GetLedger(ctx, request.Ledger)
+	if err != nil {
+		return GetLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: fmt.Sprintf("unable to get ledger %d: %v", request.Ledger, err),
+		}
+	}
+	if !found {
+		return response, nil
+	}
+
+	response.Status = LedgerStatusFound
+	response.Sequence = ledger.LedgerSequence()
+	response.Hash = ledger.LedgerHash().HexString()
+	response.LedgerCloseTime = ledger.LedgerCloseTime()
+	response.CloseTimeDriftSeconds, err = closeTimeDrift(
+		ctx, closeTimeReader, closeTimeTarget, storeRange.FirstLedger.Sequence, ledger)
+	if err != nil {
+		return GetLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: fmt.Sprintf("unable to get the close time of ledger %d: %v", request.Ledger-1, err),
+		}
+	}
+	header := ledger.LedgerHeaderHistoryEntry().Header
+	switch request.Format {
+	case FormatJSON:
+		response.HeaderJSON, err = xdr2json.ConvertInterface(header)
+	default:
+		response.HeaderXDR, err = xdr.MarshalBase64(header)
+	}
+	if err != nil {
+		return GetLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	upgrades, err := ledgerUpgrades(ledger, request.Format)
+	if err != nil {
+		return GetLedgerResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	response.Upgrades = &upgrades
+	if request.IncludeScpInfo {
+		if err := setScpInfo(&response, ledger, request.Format); err != nil {
+			return GetLedgerResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+	}
+	if request.IncludeEconomics {
+		totalCoins, feePool, inflationSeq := int64(header.TotalCoins), int64(header.FeePool), uint32(header.InflationSeq)
+		response.TotalCoins = &totalCoins
+		response.FeePool = &feePool
+		response.InflationSeq = &inflationSeq
+	}
+	return response, nil
+}
+
+// NewGetLedgerHandler returns a get ledger json rpc handler
+func NewGetLedgerHandler(
+	ledgerReader db.LedgerReader,
+	closeTimeReader db.LedgerCloseTimeReader,
+	closeTimeTarget time.Duration,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request GetLedgerRequest) (GetLedgerResponse, error) {
+		return GetLedger(ctx, ledgerReader, closeTimeReader, closeTimeTarget, request)
+	})
+}
@@ -0,0 +1,130 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+const (
+	// maxLedgerCloseTimesLedgerRange is the maximum number of ledgers spanned by a
+	// getLedgerCloseTimes request (about a week of ledgers).
+	maxLedgerCloseTimesLedgerRange = 7 * 17280
+	// maxLedgerCloseTimesPoints is the maximum number of close times returned by a
+	// getLedgerCloseTimes request.
+	maxLedgerCloseTimesPoints = 10000
+)
+
+type GetLedgerCloseTimesRequest struct {
+	StartLedger uint32 `json:"startLedger"`
+	// EndLedger (inclusive) defaults to the latest ledger, within the range and points limits.
+	EndLedger uint32 `json:"endLedger,omitempty"`
+	// Stride samples every Stride-th ledger from StartLedger (every ledger by default).
+	Stride uint32 `json:"stride,omitempty"`
+}
+
+// LedgerCloseTime is the close time of a ledger.
+type LedgerCloseTime struct {
+	Sequence uint32 `json:"sequence"`
+	// CloseTime is the unix timestamp of when the ledger was closed.
+	CloseTime int64 `json:"closeTime,string"`
+}
+
+type GetLedgerCloseTimesResponse struct {
+	// CloseTimes are the close times of the sampled ledgers, in ledger order.
+	CloseTimes  []LedgerCloseTime `json:"closeTimes"`
+	StartLedger uint32            `json:"startLedger"`
+	EndLedger   uint32            `json:"endLedger"`
+	Stride      uint32            `json:"stride"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+}
+
+// isValid checks the request and returns the end ledger and stride to use. Without an explicit
+// endLedger, the range is cut to the range and points limits instead of being rejected.
+func (request GetLedgerCloseTimesRequest) isValid(ledgerRange ledgerbucketwindow.LedgerRange) (uint32, uint32, error) {
+	stride := request.Stride
+	if stride == 0 {
+		stride = 1
+	} else if stride > maxLedgerCloseTimesLedgerRange {
+		return 0, 0, fmt.Errorf("stride must not exceed %d", maxLedgerCloseTimesLedgerRange)
+	}
+
+	if request.StartLedger < ledgerRange.FirstLedger.Sequence || request.StartLedger > ledgerRange.LastLedger.Sequence {
+		return 0, 0, fmt.Errorf("startLedger must be within the ledger range: %d - %d",
+			ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence)
+	}
+	endLedger := ledgerRange.LastLedger.Sequence
+	if request.EndLedger != 0 {
+		if request.EndLedger < request.StartLedger {
+			return 0, 0, errors.New("endLedger must not be lower than startLedger")
+		}
+		endLedger = min(request.EndLedger, endLedger)
+	}
+	if endLedger-request.StartLedger >= maxLedgerCloseTimesLedgerRange {
+		if request.EndLedger != 0 {
+			return 0, 0, fmt.Errorf("the ledger range cannot span more than %d ledgers", maxLedgerCloseTimesLedgerRange)
+		}
+		endLedger = request.StartLedger + maxLedgerCloseTimesLedgerRange - 1
+	}
+	if points := (endLedger-request.StartLedger)/stride + 1; points > maxLedgerCloseTimesPoints {
+		if request.EndLedger != 0 {
+			return 0, 0, fmt.Errorf("the range would return %d close times, more than %d: increase the stride",
+				points, maxLedgerCloseTimesPoints)
+		}
+		endLedger = request.StartLedger + (maxLedgerCloseTimesPoints-1)*stride
+	}
+	return endLedger, stride, nil
+}
+
+// NewGetLedgerCloseTimesHandler returns a JSON RPC handler returning the close times of the
+// ledgers of a range, sampled every stride ledgers, for charting the intervals between
+// ledgers. The close times are read from the close_time column of the stored ledgers, without
+// decoding them.
+func NewGetLedgerCloseTimesHandler(ledgerReader db.LedgerReader, closeTimeReader db.LedgerCloseTimeReader,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request GetLedgerCloseTimesRequest,
+	) (GetLedgerCloseTimesResponse, error) {
+		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil {
+			return GetLedgerCloseTimesResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("unable to get ledger range: %v", err),
+			}
+		}
+		endLedger, stride, err := request.isValid(ledgerRange)
+		if err != nil {
+			return GetLedgerCloseTimesResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+
+		closeTimes, err := closeTimeReader.GetLedgerCloseTimes(ctx, request.StartLedger, endLedger, stride)
+		if err != nil {
+			return GetLedgerCloseTimesResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response := GetLedgerCloseTimesResponse{
+			CloseTimes:   make([]LedgerCloseTime, 0, len(closeTimes)),
+			StartLedger:  request.StartLedger,
+			EndLedger:    endLedger,
+			Stride:       stride,
+			LatestLedger: ledgerRange.LastLedger.Sequence,
+		}
+		for _, closeTime := range closeTimes {
+			response.CloseTimes = append(response.CloseTimes, LedgerCloseTime{
+				Sequence:  closeTime.Sequence,
+				CloseTime: closeTime.CloseTime,
+			})
+		}
+		return response, nil
+	})
+}
@@ -0,0 +1,91 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+// sampledCloseTimes is a db.LedgerCloseTimeReader whose ledgers close every 5 seconds.
+type sampledCloseTimes struct{}
+
+func (sampledCloseTimes) GetLedgerCloseTimes(_ context.Context, startLedger, endLedger, stride uint32,
+) ([]db.LedgerCloseTime, error) {
+	var closeTimes []db.LedgerCloseTime
+	for sequence := startLedger; sequence <= endLedger; sequence += stride {
+		closeTimes = append(closeTimes, db.LedgerCloseTime{Sequence: sequence, CloseTime: 5 * int64(sequence)})
+	}
+	return closeTimes, nil
+}
+
+func TestGetLedgerCloseTimes(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(uint32(i))))
+	}
+	handler := NewGetLedgerCloseTimesHandler(db.NewMockLedgerReader(store), sampledCloseTimes{})
+	call := func(params string) (GetLedgerCloseTimesResponse, error) {
+		requests, err := jrpc2.ParseRequests([]byte(
+			`{"jsonrpc":"2.0","id":1,"method":"getLedgerCloseTimes","params":` + params + `}`))
+		require.NoError(t, err)
+		response, err := handler(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return GetLedgerCloseTimesResponse{}, err
+		}
+		return response.(GetLedgerCloseTimesResponse), nil
+	}
+
+	response, err := call(`{"startLedger":2,"stride":3}`)
+	require.NoError(t, err)
+	assert.Equal(t, GetLedgerCloseTimesResponse{
+		CloseTimes:   []LedgerCloseTime{{Sequence: 2, CloseTime: 10}, {Sequence: 5, CloseTime: 25}, {Sequence: 8, CloseTime: 40}},
+		StartLedger:  2,
+		EndLedger:    10,
+		Stride:       3,
+		LatestLedger: 10,
+	}, response)
+
+	// every ledger by default
+	response, err = call(`{"startLedger":8,"endLedger":9}`)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerCloseTime{{Sequence: 8, CloseTime: 40}, {Sequence: 9, CloseTime: 45}}, response.CloseTimes)
+	assert.Equal(t, uint32(1), response.Stride)
+
+	_, err = call(`{"startLedger":11}`)
+	require.ErrorContains(t, err, "startLedger must be within the ledger range: 1 - 10")
+	_, err = call(`{"startLedger":5,"endLedger":4}`)
+	require.ErrorContains(t, err, "endLedger must not be lower than startLedger")
+	_, err = call(`{"startLedger":5,"stride":200000}`)
+	require.ErrorContains(t, err, "stride must not exceed 120960")
+}
+
+func TestGetLedgerCloseTimesLimits(t *testing.T) {
+	ledgerRange := ledgerbucketwindow.LedgerRange{
+		FirstLedger: ledgerbucketwindow.LedgerInfo{Sequence: 1},
+		LastLedger:  ledgerbucketwindow.LedgerInfo{Sequence: 1_000_000},
+	}
+
+	// without an end ledger, the range is cut to the points limit, or to the range limit
+	endLedger, stride, err := GetLedgerCloseTimesRequest{StartLedger: 1}.isValid(ledgerRange)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(maxLedgerCloseTimesPoints), endLedger)
+	assert.Equal(t, uint32(1), stride)
+	endLedger, _, err = GetLedgerCloseTimesRequest{StartLedger: 1, Stride: 100}.isValid(ledgerRange)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(maxLedgerCloseTimesLedgerRange), endLedger)
+
+	// explicit end ledgers beyond the limits are rejected
+	_, _, err = GetLedgerCloseTimesRequest{StartLedger: 1, EndLedger: 20000}.isValid(ledgerRange)
+	require.ErrorContains(t, err, "the range would return 20000 close times, more than 10000: increase the stride")
+	endLedger, _, err = GetLedgerCloseTimesRequest{StartLedger: 1, EndLedger: 20000, Stride: 2}.isValid(ledgerRange)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(20000), endLedger)
+	_, _, err = GetLedgerCloseTimesRequest{StartLedger: 1, EndLedger: 200000, Stride: 100}.isValid(ledgerRange)
+	require.ErrorContains(t, err, "the ledger range cannot span more than 120960 ledgers")
+}
@@ -42,6 +42,11 @@ type GetLedgerEntriesResponse struct {
 	LatestLedger uint32 `json:"latestLedger"`
 }
 
+func (r GetLedgerEntriesResponse) withEmptySlices(mode string) interface{} {
+	r.Entries = requiredList(r.Entries, mode)
+	return r
+}
+
 const getLedgerEntriesMaxKeys = 200
 
 // NewGetLedgerEntriesHandler returns a JSON RPC handler to retrieve the specified ledger entries from Stellar Core.
@@ -0,0 +1,223 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestGetLedger(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	var ledgers []xdr.LedgerCloseMeta
+	for i := 2; i <= 4; i++ {
+		meta := createTestLedger(uint32(i))
+		require.NoError(t, store.InsertTransactions(meta))
+		ledgers = append(ledgers, meta)
+	}
+	storeRange, err := ledgerReader.GetLedgerRange(ctx)
+	require.NoError(t, err)
+
+	for _, ledger := range ledgers {
+		response, err := GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: ledger.LedgerSequence()})
+		require.NoError(t, err)
+		require.Equal(t, LedgerStatusFound, response.Status)
+		require.Equal(t, ledger.LedgerSequence(), response.Sequence)
+		require.Equal(t, ledger.LedgerHash().HexString(), response.Hash)
+		require.Equal(t, ledger.LedgerCloseTime(), response.LedgerCloseTime)
+		require.Equal(t, storeRange.FirstLedger.Sequence, response.OldestLedger)
+		require.Equal(t, storeRange.LastLedger.Sequence, response.LatestLedger)
+
+		var header xdr.LedgerHeader
+		require.NoError(t, xdr.SafeUnmarshalBase64(response.HeaderXDR, &header))
+		require.Equal(t, ledger.LedgerHeaderHistoryEntry().Header, header)
+	}
+
+	// the ledgers out of the stored range aren't found
+	for _, sequence := range []uint32{storeRange.FirstLedger.Sequence - 1, storeRange.LastLedger.Sequence + 1} {
+		response, err := GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: sequence})
+		require.NoError(t, err)
+		require.Equal(t, GetLedgerResponse{
+			Status:                LedgerStatusNotFound,
+			LatestLedger:          storeRange.LastLedger.Sequence,
+			LatestLedgerCloseTime: storeRange.LastLedger.CloseTime,
+			OldestLedger:          storeRange.FirstLedger.Sequence,
+			OldestLedgerCloseTime: storeRange.FirstLedger.CloseTime,
+		}, response)
+	}
+
+	_, err = GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{})
+	require.ErrorContains(t, err, "ledger must be set")
+	_, err = GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: ledgers[0].LedgerSequence(), Format: "yaml"})
+	require.Error(t, err)
+}
+
+func TestGetLedgerEconomics(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	meta := createTestLedger(2)
+	meta.V1.LedgerHeader.Header.TotalCoins = 1_000_000_000
+	meta.V1.LedgerHeader.Header.FeePool = 12345
+	meta.V1.LedgerHeader.Header.InflationSeq = 0
+	require.NoError(t, store.InsertTransactions(meta))
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	response, err := GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: 2})
+	require.NoError(t, err)
+	require.Nil(t, response.TotalCoins)
+	require.Nil(t, response.FeePool)
+	require.Nil(t, response.InflationSeq)
+
+	response, err = GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: 2, IncludeEconomics: true})
+	require.NoError(t, err)
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	require.JSONEq(t, `"1000000000"`, string(fields["totalCoins"]))
+	require.JSONEq(t, `"12345"`, string(fields["feePool"]))
+	// a zero inflation sequence is still present
+	require.JSONEq(t, `0`, string(fields["inflationSeq"]))
+}
+
+func TestGetLedgerUpgrades(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	version, baseFee := xdr.Uint32(22), xdr.Uint32(200)
+	upgraded := createTestLedger(2)
+	upgraded.V1.UpgradesProcessing = []xdr.UpgradeEntryMeta{
+		{Upgrade: xdr.LedgerUpgrade{Type: xdr.LedgerUpgradeTypeLedgerUpgradeVersion, NewLedgerVersion: &version}},
+		{Upgrade: xdr.LedgerUpgrade{Type: xdr.LedgerUpgradeTypeLedgerUpgradeBaseFee, NewBaseFee: &baseFee}},
+	}
+	require.NoError(t, store.InsertTransactions(upgraded))
+	require.NoError(t, store.InsertTransactions(createTestLedger(3)))
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	response, err := GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: 2})
+	require.NoError(t, err)
+	require.NotNil(t, response.Upgrades)
+	upgrades := *response.Upgrades
+	require.Len(t, upgrades, 2)
+	require.Equal(t, "version", upgrades[0].Type)
+	require.Equal(t, "base_fee", upgrades[1].Type)
+	for i, upgrade := range upgrades {
+		var decoded xdr.LedgerUpgrade
+		require.NoError(t, xdr.SafeUnmarshalBase64(upgrade.UpgradeXDR, &decoded))
+		require.Equal(t, upgraded.V1.UpgradesProcessing[i].Upgrade, decoded)
+		var changes xdr.LedgerEntryChanges
+		require.NoError(t, xdr.SafeUnmarshalBase64(upgrade.ChangesXDR, &changes))
+		require.Empty(t, changes)
+	}
+
+	// ledgers without upgrades have an empty list
+	response, err = GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: 3})
+	require.NoError(t, err)
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	require.JSONEq(t, `[]`, string(fields["upgrades"]))
+}
+
+func TestGetLedgerScpInfo(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	withScpInfo := createTestLedger(2)
+	withScpInfo.V1.ScpInfo = []xdr.ScpHistoryEntry{{
+		V: 0,
+		V0: &xdr.ScpHistoryEntryV0{
+			QuorumSets: []xdr.ScpQuorumSet{{Threshold: 1}},
+			LedgerMessages: xdr.LedgerScpMessages{
+				LedgerSeq: 2,
+				Messages: []xdr.ScpEnvelope{{
+					Statement: xdr.ScpStatement{
+						NodeId:    xdr.NodeId(xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")),
+						SlotIndex: 2,
+						Pledges: xdr.ScpStatementPledges{
+							Type:        xdr.ScpStatementTypeScpStExternalize,
+							Externalize: &xdr.ScpStatementExternalize{Commit: xdr.ScpBallot{Counter: 1}},
+						},
+					},
+				}},
+			},
+		},
+	}}
+	require.NoError(t, store.InsertTransactions(withScpInfo))
+	require.NoError(t, store.InsertTransactions(createTestLedger(3)))
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	response, err := GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: 2})
+	require.NoError(t, err)
+	require.Empty(t, response.ScpInfoXDR)
+
+	response, err = GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: 2, IncludeScpInfo: true})
+	require.NoError(t, err)
+	require.Len(t, response.ScpInfoXDR, 1)
+	expected, err := xdr.MarshalBase64(withScpInfo.V1.ScpInfo[0])
+	require.NoError(t, err)
+	require.Equal(t, expected, response.ScpInfoXDR[0])
+
+	// the field is omitted for the metas without SCP messages
+	response, err = GetLedger(ctx, ledgerReader, nil, 0, GetLedgerRequest{Ledger: 3, IncludeScpInfo: true})
+	require.NoError(t, err)
+	require.Nil(t, response.ScpInfoXDR)
+	require.Nil(t, response.ScpInfoJSON)
+}
+
+// ledgerCloseTimes is a db.LedgerCloseTimeReader of known close times.
+type ledgerCloseTimes map[uint32]int64
+
+func (closeTimes ledgerCloseTimes) GetLedgerCloseTimes(_ context.Context, startLedger, endLedger, stride uint32,
+) ([]db.LedgerCloseTime, error) {
+	var result []db.LedgerCloseTime
+	for sequence := startLedger; sequence <= endLedger; sequence += stride {
+		if closeTime, ok := closeTimes[sequence]; ok {
+			result = append(result, db.LedgerCloseTime{Sequence: sequence, CloseTime: closeTime})
+		}
+	}
+	return result, nil
+}
+
+func TestGetLedgerCloseTimeDrift(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	closeTimes := ledgerCloseTimes{2: 100, 3: 105, 4: 112, 5: 114}
+	for sequence := uint32(2); sequence <= 5; sequence++ {
+		meta := createTestLedger(sequence)
+		meta.V1.LedgerHeader.Header.ScpValue.CloseTime = xdr.TimePoint(closeTimes[sequence])
+		require.NoError(t, store.InsertTransactions(meta))
+	}
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	drift := func(sequence uint32, closeTimes db.LedgerCloseTimeReader) *int64 {
+		response, err := GetLedger(ctx, ledgerReader, closeTimes, 5*time.Second, GetLedgerRequest{Ledger: sequence})
+		require.NoError(t, err)
+		require.Equal(t, LedgerStatusFound, response.Status)
+		return response.CloseTimeDriftSeconds
+	}
+	seconds := func(drift int64) *int64 { return &drift }
+
+	// the previous ledger of the oldest ledger isn't stored
+	require.Nil(t, drift(2, closeTimes))
+	require.Equal(t, seconds(0), drift(3, closeTimes))
+	require.Equal(t, seconds(2), drift(4, closeTimes))
+	require.Equal(t, seconds(-3), drift(5, closeTimes))
+
+	// the drift is omitted when the previous ledger was trimmed
+	delete(closeTimes, 4)
+	require.Nil(t, drift(5, closeTimes))
+
+	response, err := GetLedger(ctx, ledgerReader, closeTimes, 5*time.Second, GetLedgerRequest{Ledger: 3})
+	require.NoError(t, err)
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"closeTimeDriftSeconds":0`)
+}
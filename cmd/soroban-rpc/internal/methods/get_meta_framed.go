@@ -0,0 +1,97 @@
+package methods
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+const (
+	// LedgerMetaPath is the HTTP path serving the framed LedgerCloseMeta of a ledger.
+	LedgerMetaPath = "/ledgers/{sequence}/meta"
+	// TransactionMetaPath is the HTTP path serving the framed TransactionMeta of a transaction.
+	TransactionMetaPath = "/transactions/{hash}/meta"
+)
+
+// writeFramedXDR writes the value using the framing of the Stellar Core meta stream
+// (e.g. the file passed to --metadata-output-stream): a 4-byte big-endian header,
+// whose most significant bit is set (marking the last and only fragment of the record)
+// and whose remaining 31 bits are the length of the XDR, followed by the binary XDR.
+// This is the XDR record marking standard (RFC 5531, section 11), so the responses can be
+// fed to existing meta stream consumers unchanged.
+func writeFramedXDR(logger *log.Entry, w http.ResponseWriter, value interface{}, filename string) {
+	var framed bytes.Buffer
+	if err := xdr.MarshalFramed(&framed, value); err != nil {
+		logger.WithError(err).Error("failed to frame XDR value")
+		http.Error(w, "failed to encode meta", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(framed.Len()))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if _, err := framed.WriteTo(w); err != nil {
+		logger.WithError(err).Debug("could not write framed meta")
+	}
+}
+
+// NewGetLedgerMetaHTTPHandler returns a plain HTTP handler serving the LedgerCloseMeta of a
+// stored ledger, framed as in the Stellar Core meta stream (see writeFramedXDR).
+func NewGetLedgerMetaHTTPHandler(logger *log.Entry, reader db.LedgerReader) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sequenceParam := chi.URLParam(r, "sequence")
+		sequence, err := strconv.ParseUint(sequenceParam, 10, 32)
+		if err != nil {
+			http.Error(w, "incorrect ledger sequence: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ledger, found, err := reader.GetLedger(r.Context(), uint32(sequence))
+		if err != nil {
+			logger.WithError(err).WithField("sequence", sequence).Error("failed to fetch ledger")
+			http.Error(w, "failed to fetch ledger", http.StatusInternalServerError)
+			return
+		} else if !found {
+			http.Error(w, "ledger not found", http.StatusNotFound)
+			return
+		}
+		writeFramedXDR(logger, w, ledger, "ledger-"+sequenceParam+".xdr")
+	})
+}
+
+// NewGetTransactionMetaHTTPHandler returns a plain HTTP handler serving the TransactionMeta of
+// a stored transaction, framed as in the Stellar Core meta stream (see writeFramedXDR).
+func NewGetTransactionMetaHTTPHandler(logger *log.Entry, reader db.TransactionReader) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		txHash, err := parseTransactionHash(chi.URLParam(r, "hash"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tx, err := reader.GetTransaction(r.Context(), txHash)
+		if errors.Is(err, db.ErrNoTransaction) {
+			http.Error(w, "transaction not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			logger.WithError(err).WithField("hash", txHash).Error("failed to fetch transaction")
+			http.Error(w, "failed to fetch transaction", http.StatusInternalServerError)
+			return
+		}
+
+		var meta xdr.TransactionMeta
+		if err := xdr.SafeUnmarshal(tx.Meta, &meta); err != nil {
+			logger.WithError(err).WithField("hash", txHash).Error("failed to decode transaction meta")
+			http.Error(w, "failed to decode transaction meta", http.StatusInternalServerError)
+			return
+		}
+		writeFramedXDR(logger, w, meta, txHash.HexString()+"-meta.xdr")
+	})
+}
@@ -0,0 +1,92 @@
+package methods
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// readFrame reads a single record of the Stellar Core meta stream.
+func readFrame(t *testing.T, body []byte, dest interface{}) {
+	require.GreaterOrEqual(t, len(body), 4)
+	header := binary.BigEndian.Uint32(body[:4])
+	require.NotZero(t, header&0x80000000, "the last fragment bit must be set")
+	require.Equal(t, int(header&0x7fffffff), len(body)-4)
+	require.NoError(t, xdr.SafeUnmarshal(body[4:], dest))
+}
+
+func requireSameXDR(t *testing.T, expected, actual xdr.EncoderTo) {
+	expectedXDR, err := xdr.MarshalBase64(expected)
+	require.NoError(t, err)
+	actualXDR, err := xdr.MarshalBase64(actual)
+	require.NoError(t, err)
+	require.Equal(t, expectedXDR, actualXDR)
+}
+
+func TestWriteFramedXDR(t *testing.T) {
+	// an empty V0 TransactionMeta is the (4-byte) union discriminant followed by the
+	// (4-byte) length of the operations array, so its frame header is 0x80000008
+	recorder := httptest.NewRecorder()
+	writeFramedXDR(log.DefaultLogger, recorder, xdr.TransactionMeta{V: 0, Operations: &[]xdr.OperationMeta{}}, "meta.xdr")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "800000080000000000000000", hex.EncodeToString(recorder.Body.Bytes()))
+	require.Equal(t, "12", recorder.Header().Get("Content-Length"))
+}
+
+func TestGetMetaFramedHTTPHandlers(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledger := txMeta(1, true)
+	require.NoError(t, store.InsertTransactions(ledger))
+
+	router := chi.NewRouter()
+	router.Handle(LedgerMetaPath, NewGetLedgerMetaHTTPHandler(log.DefaultLogger, db.NewMockLedgerReader(store)))
+	router.Handle(TransactionMetaPath, NewGetTransactionMetaHTTPHandler(log.DefaultLogger, store))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	get := func(path string) (*http.Response, []byte) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	sequence := strconv.Itoa(int(ledger.LedgerSequence()))
+	resp, body := get("/ledgers/" + sequence + "/meta")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+	var decodedLedger xdr.LedgerCloseMeta
+	readFrame(t, body, &decodedLedger)
+	requireSameXDR(t, ledger, decodedLedger)
+
+	hash := txHash(1)
+	resp, body = get("/transactions/" + hex.EncodeToString(hash[:]) + "/meta")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var decodedMeta xdr.TransactionMeta
+	readFrame(t, body, &decodedMeta)
+	requireSameXDR(t, ledger.V1.TxProcessing[0].TxApplyProcessing, decodedMeta)
+
+	resp, _ = get("/ledgers/1/meta")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = get("/ledgers/abc/meta")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = get("/transactions/" + strings.Repeat("ab", 32) + "/meta")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = get("/transactions/ab/meta")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
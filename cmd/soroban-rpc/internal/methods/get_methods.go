@@ -0,0 +1,25 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+)
+
+// MethodInfo describes a JSON-RPC method known to Soroban-RPC.
+type MethodInfo struct {
+	Name string `json:"name"`
+	// Enabled is false if the method was disabled through the configuration.
+	Enabled bool `json:"enabled"`
+}
+
+type GetMethodsResponse struct {
+	Methods []MethodInfo `json:"methods"`
+}
+
+// NewGetMethodsHandler returns a JSON RPC handler listing the given methods.
+func NewGetMethodsHandler(methods []MethodInfo) jrpc2.Handler {
+	return NewHandler(func(_ context.Context) (GetMethodsResponse, error) {
+		return GetMethodsResponse{Methods: methods}, nil
+	})
+}
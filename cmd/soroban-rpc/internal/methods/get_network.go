@@ -2,17 +2,23 @@ package methods
 
 import (
 	"context"
+	"encoding/hex"
 
 	"github.com/creachadair/jrpc2"
 
+	"github.com/stellar/go/network"
+
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 )
 
 type GetNetworkRequest struct{}
 
 type GetNetworkResponse struct {
-	FriendbotURL    string `json:"friendbotUrl,omitempty"`
-	Passphrase      string `json:"passphrase"`
+	FriendbotURL string `json:"friendbotUrl,omitempty"`
+	Passphrase   string `json:"passphrase"`
+	// NetworkID is the hex-encoded network ID (the SHA-256 hash of the passphrase), which is part
+	// of the signed payload of transactions.
+	NetworkID       string `json:"networkId"`
 	ProtocolVersion int    `json:"protocolVersion"`
 }
 
@@ -23,6 +29,7 @@ func NewGetNetworkHandler(
 	ledgerEntryReader db.LedgerEntryReader,
 	ledgerReader db.LedgerReader,
 ) jrpc2.Handler {
+	networkID := network.ID(networkPassphrase)
 	return NewHandler(func(ctx context.Context, request GetNetworkRequest) (GetNetworkResponse, error) {
 		protocolVersion, err := getProtocolVersion(ctx, ledgerEntryReader, ledgerReader)
 		if err != nil {
@@ -35,6 +42,7 @@ func NewGetNetworkHandler(
 		return GetNetworkResponse{
 			FriendbotURL:    friendbotURL,
 			Passphrase:      networkPassphrase,
+			NetworkID:       hex.EncodeToString(networkID[:]),
 			ProtocolVersion: int(protocolVersion),
 		}, nil
 	})
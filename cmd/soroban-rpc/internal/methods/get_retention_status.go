@@ -0,0 +1,65 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// RetentionWindows holds the configured retention windows, expressed in number of ledgers.
+type RetentionWindows struct {
+	History         uint32
+	ClassicFeeStats uint32
+	SorobanFeeStats uint32
+}
+
+type GetRetentionStatusResponse struct {
+	// HistoryRetentionWindow is the number of ledgers for which transactions and events are kept.
+	HistoryRetentionWindow uint32 `json:"historyRetentionWindow"`
+	// ClassicFeeStatsRetentionWindow is the number of ledgers used to compute classic fee stats.
+	ClassicFeeStatsRetentionWindow uint32 `json:"classicFeeStatsRetentionWindow"`
+	// SorobanFeeStatsRetentionWindow is the number of ledgers used to compute Soroban fee stats.
+	SorobanFeeStatsRetentionWindow uint32 `json:"sorobanFeeStatsRetentionWindow"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedgerCloseTime is the unix timestamp of when the latest ledger was closed.
+	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+	// OldestLedger is the oldest ledger stored in Soroban-RPC.
+	OldestLedger uint32 `json:"oldestLedger"`
+	// OldestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
+	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
+	// StoredLedgers is the number of ledgers currently stored.
+	StoredLedgers uint32 `json:"storedLedgers"`
+	// SteadyState indicates whether the store holds the full history retention window,
+	// i.e. whether ingesting a new ledger evicts the oldest one.
+	SteadyState bool `json:"steadyState"`
+}
+
+// NewGetRetentionStatusHandler returns a JSON RPC handler reporting the configured retention
+// windows together with the ledgers actually stored.
+func NewGetRetentionStatusHandler(ledgerReader db.LedgerReader, windows RetentionWindows) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context) (GetRetentionStatusResponse, error) {
+		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil {
+			return GetRetentionStatusResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get ledger range",
+			}
+		}
+
+		storedLedgers := ledgerRange.LastLedger.Sequence - ledgerRange.FirstLedger.Sequence + 1
+		return GetRetentionStatusResponse{
+			HistoryRetentionWindow:         windows.History,
+			ClassicFeeStatsRetentionWindow: windows.ClassicFeeStats,
+			SorobanFeeStatsRetentionWindow: windows.SorobanFeeStats,
+			LatestLedger:                   ledgerRange.LastLedger.Sequence,
+			LatestLedgerCloseTime:          ledgerRange.LastLedger.CloseTime,
+			OldestLedger:                   ledgerRange.FirstLedger.Sequence,
+			OldestLedgerCloseTime:          ledgerRange.FirstLedger.CloseTime,
+			StoredLedgers:                  storedLedgers,
+			SteadyState:                    storedLedgers >= windows.History,
+		}, nil
+	})
+}
@@ -0,0 +1,41 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestGetRetentionStatus(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(uint32(i))))
+	}
+
+	windows := RetentionWindows{History: 10, ClassicFeeStats: 2, SorobanFeeStats: 3}
+	respI, err := NewGetRetentionStatusHandler(ledgerReader, windows)(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	resp := respI.(GetRetentionStatusResponse)
+	assert.Equal(t, GetRetentionStatusResponse{
+		HistoryRetentionWindow:         10,
+		ClassicFeeStatsRetentionWindow: 2,
+		SorobanFeeStatsRetentionWindow: 3,
+		LatestLedger:                   3,
+		LatestLedgerCloseTime:          175,
+		OldestLedger:                   1,
+		OldestLedgerCloseTime:          125,
+		StoredLedgers:                  3,
+		SteadyState:                    false,
+	}, resp)
+
+	windows.History = 3
+	respI, err = NewGetRetentionStatusHandler(ledgerReader, windows)(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.True(t, respI.(GetRetentionStatusResponse).SteadyState)
+}
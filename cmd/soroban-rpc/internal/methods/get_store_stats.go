@@ -0,0 +1,57 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// StoreStatsGetter reports the disk usage of the database.
+type StoreStatsGetter interface {
+	GetStoreStats(ctx context.Context) (db.StoreStats, error)
+}
+
+type GetStoreStatsResponse struct {
+	// TotalSize is the size in bytes of the database.
+	TotalSize int64 `json:"totalSize,string"`
+	// StoredLedgers is the number of ledgers currently stored.
+	StoredLedgers uint32 `json:"storedLedgers"`
+	// AverageBytesPerLedger is TotalSize divided by StoredLedgers, which can be used
+	// to project the disk usage of a given history retention window.
+	AverageBytesPerLedger int64 `json:"averageBytesPerLedger,string,omitempty"`
+	// LedgerCloseMetaSize is the size in bytes of the ledger_close_meta table. It and
+	// AverageLedgerCloseMetaBytes are only present if SQLite was built with dbstat support.
+	LedgerCloseMetaSize         *int64 `json:"ledgerCloseMetaSize,string,omitempty"`
+	AverageLedgerCloseMetaBytes *int64 `json:"averageLedgerCloseMetaBytes,string,omitempty"`
+	// Indexes are the indexes present in the database.
+	Indexes []db.IndexInfo `json:"indexes"`
+}
+
+// NewGetStoreStatsHandler returns an admin JSON RPC handler reporting the disk usage of the database.
+func NewGetStoreStatsHandler(statsGetter StoreStatsGetter) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context) (GetStoreStatsResponse, error) {
+		stats, err := statsGetter.GetStoreStats(ctx)
+		if err != nil {
+			return GetStoreStatsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get store stats",
+			}
+		}
+		response := GetStoreStatsResponse{
+			TotalSize:           stats.TotalSize,
+			StoredLedgers:       stats.StoredLedgers,
+			LedgerCloseMetaSize: stats.LedgerCloseMetaSize,
+			Indexes:             stats.Indexes,
+		}
+		if stats.StoredLedgers > 0 {
+			response.AverageBytesPerLedger = stats.TotalSize / int64(stats.StoredLedgers)
+			if stats.LedgerCloseMetaSize != nil {
+				average := *stats.LedgerCloseMetaSize / int64(stats.StoredLedgers)
+				response.AverageLedgerCloseMetaBytes = &average
+			}
+		}
+		return response, nil
+	})
+}
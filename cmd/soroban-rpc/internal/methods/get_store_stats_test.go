@@ -0,0 +1,46 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type constantStoreStatsGetter db.StoreStats
+
+func (g constantStoreStatsGetter) GetStoreStats(_ context.Context) (db.StoreStats, error) {
+	return db.StoreStats(g), nil
+}
+
+func TestGetStoreStats(t *testing.T) {
+	tableSize := int64(600)
+	indexes := []db.IndexInfo{{Name: "idx_contract_id", Table: "events"}}
+	handler := NewGetStoreStatsHandler(constantStoreStatsGetter{
+		TotalSize:           1000,
+		LedgerCloseMetaSize: &tableSize,
+		StoredLedgers:       10,
+		Indexes:             indexes,
+	})
+	resp, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	averageTableBytes := int64(60)
+	assert.Equal(t, GetStoreStatsResponse{
+		TotalSize:                   1000,
+		StoredLedgers:               10,
+		AverageBytesPerLedger:       100,
+		LedgerCloseMetaSize:         &tableSize,
+		AverageLedgerCloseMetaBytes: &averageTableBytes,
+		Indexes:                     indexes,
+	}, resp)
+
+	// without ledgers or dbstat support there are no averages
+	handler = NewGetStoreStatsHandler(constantStoreStatsGetter{TotalSize: 1000})
+	resp, err = handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, GetStoreStatsResponse{TotalSize: 1000}, resp)
+}
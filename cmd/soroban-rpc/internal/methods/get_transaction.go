@@ -14,6 +14,8 @@ import (
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
 )
 
 const (
@@ -41,13 +43,81 @@ type GetTransactionResponse struct {
 	// LatestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
 	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
 
-	// The fields below are only present if Status is not TransactionNotFound.
+	// The TransactionDetails fields are only present if Status is not TransactionNotFound.
+	TransactionDetails
+}
 
+// TransactionDetails are the fields of a getTransaction response describing the transaction,
+// as opposed to the ledger range of Soroban-RPC.
+type TransactionDetails struct {
+	TransactionClassification
 	// ApplicationOrder is the index of the transaction among all the transactions
 	// for that ledger.
 	ApplicationOrder int32 `json:"applicationOrder,omitempty"`
 	// FeeBump indicates whether the transaction is a feebump transaction
 	FeeBump bool `json:"feeBump,omitempty"`
+	// SourceAccountSequence is the sequence number of the transaction's source account
+	// consumed by the transaction. For fee-bump transactions it is the sequence number
+	// of the inner transaction.
+	SourceAccountSequence int64 `json:"sourceAccountSequence,string,omitempty"`
+	// FeeAccount is the account which paid the transaction fee. For fee-bump transactions
+	// it is the fee source of the fee-bump wrapper, otherwise it is the transaction's
+	// source account.
+	FeeAccount string `json:"feeAccount,omitempty"`
+	// ResourceFeeBreakdown is only present for Soroban transactions, when requested
+	// through IncludeSorobanResources.
+	ResourceFeeBreakdown *ResourceFeeBreakdown `json:"resourceFeeBreakdown,omitempty"`
+	// ReadEntries are the entries of the read-only footprint of Soroban transactions, only
+	// present when requested through IncludeSorobanResources.
+	ReadEntries []ReadEntry `json:"readEntries,omitempty"`
+	// Footprint is the footprint of Soroban transactions, with its keys decoded, only present
+	// when requested through IncludeSorobanResources.
+	Footprint *Footprint `json:"footprint,omitempty"`
+	// FeeComparison is only present when requested through IncludeFeeComparison.
+	FeeComparison *FeeComparison `json:"feeComparison,omitempty"`
+	// Preconditions is only present if the transaction has preconditions, when requested
+	// through IncludePreconditions.
+	Preconditions *TransactionPreconditions `json:"preconditions,omitempty"`
+	// ValidUntilLedger is the last ledger the transaction can be included in, according to
+	// its ledger bounds. It is omitted if the transaction has no upper ledger bound.
+	ValidUntilLedger uint32 `json:"validUntilLedger,omitempty"`
+	// ValidUntilTime is the unix timestamp after which ledgers can no longer include the
+	// transaction, according to its time bounds. It is omitted if the transaction has no
+	// upper time bound.
+	ValidUntilTime int64 `json:"validUntilTime,string,omitempty"`
+	// ValidityExpired indicates that the latest ledger is past ValidUntilLedger or ValidUntilTime,
+	// so that a new submission of the transaction would be rejected.
+	ValidityExpired bool `json:"validityExpired,omitempty"`
+	// InnerTransaction is only present for fee-bump transactions.
+	InnerTransaction *InnerTransaction `json:"innerTransaction,omitempty"`
+	// TransactionSetProof is only present when requested through IncludeTransactionSetProof.
+	TransactionSetProof *TransactionSetProof `json:"transactionSetProof,omitempty"`
+	// ReserveImpact is only present when requested through IncludeReserveImpact.
+	ReserveImpact *ReserveImpact `json:"reserveImpact,omitempty"`
+	// LedgerHeaderXDR is the LedgerHeader XDR value of the ledger which included the
+	// transaction. It is only present when requested through IncludeLedgerHeader.
+	LedgerHeaderXDR  string          `json:"ledgerHeaderXdr,omitempty"`
+	LedgerHeaderJSON json.RawMessage `json:"ledgerHeaderJson,omitempty"`
+	// BucketListHash is the hex-encoded hash of the bucket list (the ledger state) in the
+	// header of the ledger which included the transaction. It is only present when requested
+	// through IncludeBucketListHash.
+	BucketListHash string `json:"bucketListHash,omitempty"`
+	// RestoredEntries are the archived ledger entries restored by the transaction. It is only
+	// present if the transaction restored entries.
+	RestoredEntries []RestoredEntry `json:"restoredEntries,omitempty"`
+	// ExtendedTtlEntries are the live ledger entries whose TTL was extended by the transaction.
+	// It is only present if the transaction extended TTLs.
+	ExtendedTtlEntries []ExtendedTtlEntry `json:"extendedTtlEntries,omitempty"`
+	// CreatedContractIDs are the strkey-encoded IDs of the contracts created by the transaction.
+	// It is empty for the transactions which didn't create contracts.
+	CreatedContractIDs []string `json:"createdContractIds,omitempty"`
+	// ContractStorageDiffs are the changes of the contract data entries made by the transaction,
+	// grouped by contract. They are only present when requested through IncludeContractStorageDiffs.
+	ContractStorageDiffs []ContractStorageDiff `json:"contractStorageDiffs,omitempty"`
+	// ResultingState is the state of the ledger entries written by the transaction after it,
+	// keyed by their base64 LedgerKey XDR value. It is only present when requested through
+	// IncludeResultingState.
+	ResultingState map[string]ResultingEntry `json:"resultingState,omitempty"`
 	// EnvelopeXDR is the TransactionEnvelope XDR value.
 	EnvelopeXDR  string          `json:"envelopeXdr,omitempty"`
 	EnvelopeJSON json.RawMessage `json:"envelopeJson,omitempty"`
@@ -57,6 +127,10 @@ type GetTransactionResponse struct {
 	// ResultMetaXDR is the TransactionMeta XDR value.
 	ResultMetaXDR  string          `json:"resultMetaXdr,omitempty"`
 	ResultMetaJSON json.RawMessage `json:"resultMetaJson,omitempty"`
+	// OperationResultXDR is the OperationResult XDR value of the operation
+	// requested through OperationIndex. It replaces ResultXDR when present.
+	OperationResultXDR  string          `json:"operationResultXdr,omitempty"`
+	OperationResultJSON json.RawMessage `json:"operationResultJson,omitempty"`
 
 	// Ledger is the sequence of the ledger which included the transaction.
 	Ledger uint32 `json:"ledger,omitempty"`
@@ -65,13 +139,220 @@ type GetTransactionResponse struct {
 
 	// DiagnosticEventsXDR is present only if Status is equal to TransactionFailed.
 	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent
-	DiagnosticEventsXDR  []string          `json:"diagnosticEventsXdr,omitempty"`
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// DiagnosticEventsJSON events have an operationIndex field when the operation which emitted
+	// them can be derived (see diagnosticEventsOperationIndexes).
 	DiagnosticEventsJSON []json.RawMessage `json:"diagnosticEventsJson,omitempty"`
+	// EventsTruncated indicates that the diagnostic events were capped to the maximum
+	// number of events per transaction configured in the server.
+	EventsTruncated bool `json:"eventsTruncated,omitempty"`
+	// TotalEvents is the number of diagnostic events of the transaction (of distinct events
+	// with CollapseDuplicateEvents). It is only present if EventsTruncated is true.
+	TotalEvents uint `json:"totalEvents,omitempty"`
+	// DiagnosticEventsCompressed replaces DiagnosticEventsXDR and DiagnosticEventsJSON when
+	// requested through CompressEvents. See compressEvents for how to decode it.
+	DiagnosticEventsCompressed string `json:"diagnosticEventsCompressed,omitempty"`
+	// DiagnosticEventsCloudEvents replaces DiagnosticEventsXDR and DiagnosticEventsJSON when
+	// requested through EventsFormat.
+	DiagnosticEventsCloudEvents []CloudEvent `json:"diagnosticEventsCloudEvents,omitempty"`
+	// DiagnosticEventsOccurrences is only present when requested through CollapseDuplicateEvents.
+	// It holds the number of occurrences of each of the (deduplicated) diagnostic events, in the
+	// same order.
+	DiagnosticEventsOccurrences []uint `json:"diagnosticEventsOccurrences,omitempty"`
+	// EventCounts are the numbers of diagnostic events of the transaction by type. They are
+	// present even when the events are truncated or omitted through IncludeEvents.
+	EventCounts *EventCounts `json:"eventCounts,omitempty"`
+	// Operations are the operations of the transaction with their effective source accounts. They
+	// are only present when requested through IncludeOperations.
+	Operations []TransactionOperation `json:"operations,omitempty"`
+	// Outcome summarizes whether the transaction succeeded and, if not, why.
+	Outcome *TransactionOutcome `json:"outcome,omitempty"`
+	// Horizon is the transaction in the shape of Horizon's transaction resource. It is only
+	// present when requested through Compat.
+	Horizon *HorizonTransaction `json:"horizon,omitempty"`
+
+	// emptySlices is how the empty diagnostic event lists are rendered (see WithEmptySlices).
+	emptySlices string
+}
+
+// appendEmptyEventLists renders the empty diagnostic event lists of the details into encoded.
+func (d TransactionDetails) appendEmptyEventLists(encoded []byte) []byte {
+	return appendEmptyLists(encoded, d.emptySlices,
+		listField("diagnosticEventsXdr", d.DiagnosticEventsXDR),
+		listField("diagnosticEventsJson", d.DiagnosticEventsJSON),
+		listField("diagnosticEventsCloudEvents", d.DiagnosticEventsCloudEvents))
+}
+
+func (r GetTransactionResponse) withEmptySlices(mode string) interface{} {
+	r.emptySlices = mode
+	return r
+}
+
+func (r GetTransactionResponse) MarshalJSON() ([]byte, error) {
+	type getTransactionResponse GetTransactionResponse
+	encoded, err := json.Marshal(getTransactionResponse(r))
+	if err != nil {
+		return nil, err
+	}
+	return r.appendEmptyEventLists(encoded), nil
 }
 
 type GetTransactionRequest struct {
-	Hash   string `json:"hash"`
-	Format string `json:"xdrFormat,omitempty"`
+	// Hash is the hex-encoded or base64-encoded hash of the transaction.
+	Hash string `json:"hash"`
+	// ResultHash, which replaces Hash, looks the transaction up by the hex-encoded or
+	// base64-encoded SHA-256 hash of its TransactionResult XDR (see db.HashTransactionResult)
+	// instead. If several transactions have the same result, the earliest one is returned.
+	ResultHash string `json:"resultHash,omitempty"`
+	Format     string `json:"xdrFormat,omitempty"`
+	// APIVersion pins the response to the field set of a version (see LatestGetTransactionAPIVersion),
+	// defaulting to the latest version.
+	APIVersion uint `json:"apiVersion,omitempty"`
+	// OperationIndex, when set, narrows the returned result to the result
+	// of the operation at that (zero-based) index.
+	OperationIndex *int `json:"operationIndex,omitempty"`
+	// IncludeSorobanResources adds the resource fee breakdown, the read-only footprint
+	// entries and the decoded footprint of Soroban transactions to the response.
+	IncludeSorobanResources bool `json:"includeSorobanResources,omitempty"`
+	// IncludeFeeComparison adds the comparison of the maximum fee of the transaction
+	// with the fee it was charged to the response.
+	IncludeFeeComparison bool `json:"includeFeeComparison,omitempty"`
+	// IncludePreconditions adds the transaction preconditions to the response.
+	IncludePreconditions bool `json:"includePreconditions,omitempty"`
+	// IncludeTransactionSetProof adds the proof of inclusion of the transaction in
+	// its ledger to the response. The transaction denylist doesn't apply to the proof,
+	// which lists the hashes of all the transactions of the ledger (see TransactionSetProof).
+	IncludeTransactionSetProof bool `json:"includeTransactionSetProof,omitempty"`
+	// IncludeReserveImpact adds an estimate of the impact of the transaction on the
+	// minimum balance of the accounts it modified to the response.
+	IncludeReserveImpact bool `json:"includeReserveImpact,omitempty"`
+	// IncludeLedgerHeader adds the header of the ledger which included the transaction
+	// to the response.
+	IncludeLedgerHeader bool `json:"includeLedgerHeader,omitempty"`
+	// IncludeBucketListHash adds the bucket list hash of the ledger which included the
+	// transaction to the response.
+	IncludeBucketListHash bool `json:"includeBucketListHash,omitempty"`
+	// IncludeResultingState adds the final state of the ledger entries written by the
+	// transaction to the response.
+	IncludeResultingState bool `json:"includeResultingState,omitempty"`
+	// IncludeOperations adds the operations of the transaction, with their effective source
+	// accounts, to the response.
+	IncludeOperations bool `json:"includeOperations,omitempty"`
+	// IncludeContractStorageDiffs adds the changes of the contract storage made by the transaction,
+	// grouped by contract, to the response.
+	IncludeContractStorageDiffs bool `json:"includeContractStorageDiffs,omitempty"`
+	// CompressEvents, if set (to EventsCompressionGzip or EventsCompressionZstd), returns the
+	// diagnostic events as a single compressed blob (DiagnosticEventsCompressed).
+	CompressEvents string `json:"compressEvents,omitempty"`
+	// IncludeEvents, if set to false, omits the diagnostic events of the transaction from the
+	// response (EventCounts is still present). The events are included by default.
+	IncludeEvents *bool `json:"includeEvents,omitempty"`
+	// EventsFormat, if set to EventsFormatCloudEvents, returns the diagnostic events as
+	// CloudEvents JSON envelopes (DiagnosticEventsCloudEvents).
+	EventsFormat string `json:"eventsFormat,omitempty"`
+	// CollapseDuplicateEvents returns the byte-identical diagnostic events once, in the order of
+	// their first occurrence, along with their numbers of occurrences (DiagnosticEventsOccurrences).
+	CollapseDuplicateEvents bool `json:"collapseDuplicateEvents,omitempty"`
+	// Compat, if set to CompatHorizon, adds the transaction in the shape of Horizon's
+	// transaction resource (Horizon) to the native response.
+	Compat string `json:"compat,omitempty"`
+}
+
+// InnerTransaction is the transaction wrapped by a fee-bump transaction.
+type InnerTransaction struct {
+	// Hash is the hex-encoded hash of the inner transaction.
+	Hash string `json:"hash,omitempty"`
+	// EnvelopeXDR is the inner transaction, as a TransactionEnvelope XDR value.
+	EnvelopeXDR  string          `json:"envelopeXdr,omitempty"`
+	EnvelopeJSON json.RawMessage `json:"envelopeJson,omitempty"`
+	// ResultXDR is the InnerTransactionResult XDR value. It and Hash are omitted
+	// if the fee-bump transaction failed without processing its inner transaction.
+	ResultXDR  string          `json:"resultXdr,omitempty"`
+	ResultJSON json.RawMessage `json:"resultJson,omitempty"`
+}
+
+// TransactionPreconditions are the preconditions of a transaction. For fee-bump
+// transactions they are the preconditions of the inner transaction.
+type TransactionPreconditions struct {
+	TimeBounds   *TimeBounds   `json:"timeBounds,omitempty"`
+	LedgerBounds *LedgerBounds `json:"ledgerBounds,omitempty"`
+	// MinSeqNum is the minimum source account sequence number.
+	MinSeqNum *int64 `json:"minSeqNum,string,omitempty"`
+	// MinSeqAge is the minimum number of seconds since the source account sequence number changed.
+	MinSeqAge uint64 `json:"minSeqAge,string,omitempty"`
+	// MinSeqLedgerGap is the minimum number of ledgers since the source account sequence number changed.
+	MinSeqLedgerGap uint32 `json:"minSeqLedgerGap,omitempty"`
+	// ExtraSigners are the addresses of the additional required signers.
+	ExtraSigners []string `json:"extraSigners,omitempty"`
+}
+
+// TimeBounds are expressed as unix timestamps. A MaxTime of 0 means no upper bound.
+type TimeBounds struct {
+	MinTime uint64 `json:"minTime,string"`
+	MaxTime uint64 `json:"maxTime,string"`
+}
+
+// LedgerBounds are expressed as ledger sequences. A MaxLedger of 0 means no upper bound.
+type LedgerBounds struct {
+	MinLedger uint32 `json:"minLedger"`
+	MaxLedger uint32 `json:"maxLedger"`
+}
+
+// FeeComparison compares the maximum fee declared by a transaction with the fee it was charged.
+// For fee-bump transactions, both are the fees of the fee-bump wrapper, paid by its fee source
+// (FeeAccount), rather than the ones of the inner transaction.
+type FeeComparison struct {
+	// MaxFee is the maximum fee declared by the transaction envelope.
+	MaxFee int64 `json:"maxFee,string"`
+	// FeeCharged is the fee charged to the fee account, according to the transaction result.
+	FeeCharged int64 `json:"feeCharged,string"`
+	// Refund is the part of the maximum fee which wasn't charged.
+	Refund int64 `json:"refund,string"`
+	// FeeRatio is the ratio of FeeCharged to MaxFee.
+	FeeRatio float64 `json:"feeRatio"`
+}
+
+// feeComparison compares the maximum fee of a transaction with the fee of its result.
+func feeComparison(envelope xdr.TransactionEnvelope, result xdr.TransactionResult) FeeComparison {
+	maxFee := int64(envelope.Fee())
+	if envelope.IsFeeBump() {
+		maxFee = envelope.FeeBumpFee()
+	}
+	comparison := FeeComparison{
+		MaxFee:     maxFee,
+		FeeCharged: int64(result.FeeCharged),
+		Refund:     maxFee - int64(result.FeeCharged),
+	}
+	if maxFee > 0 {
+		comparison.FeeRatio = float64(comparison.FeeCharged) / float64(maxFee)
+	}
+	return comparison
+}
+
+// ResourceFeeBreakdown details the resources declared by a Soroban transaction
+// and the resource fees it was charged.
+type ResourceFeeBreakdown struct {
+	// Instructions is the number of CPU instructions declared by the transaction.
+	Instructions uint32 `json:"instructions"`
+	// ReadBytes is the number of bytes declared to be read from the ledger.
+	ReadBytes uint32 `json:"readBytes"`
+	// WriteBytes is the number of bytes declared to be written to the ledger.
+	WriteBytes uint32 `json:"writeBytes"`
+	// ReadLedgerEntries is the number of ledger entries in the footprint (all of them are read).
+	ReadLedgerEntries uint32 `json:"readLedgerEntries"`
+	// WriteLedgerEntries is the number of read-write ledger entries in the footprint.
+	WriteLedgerEntries uint32 `json:"writeLedgerEntries"`
+	// EventsAndReturnValueSize is the size in bytes of the emitted contract events and return value.
+	EventsAndReturnValueSize uint32 `json:"eventsAndReturnValueSize"`
+	// ResourceFee is the maximum resource fee declared by the transaction.
+	ResourceFee int64 `json:"resourceFee,string"`
+	// NonRefundableResourceFeeCharged is the fee charged for CPU instructions, ledger reads/writes
+	// and transaction size. It and the fields below are omitted if the transaction meta doesn't report them.
+	NonRefundableResourceFeeCharged int64 `json:"nonRefundableResourceFeeCharged,string,omitempty"`
+	// RefundableResourceFeeCharged is the fee charged for events, return value and rent.
+	RefundableResourceFeeCharged int64 `json:"refundableResourceFeeCharged,string,omitempty"`
+	// RentFeeCharged is the part of RefundableResourceFeeCharged paid to extend entry TTLs.
+	RentFeeCharged int64 `json:"rentFeeCharged,string,omitempty"`
 }
 
 func GetTransaction(
@@ -79,30 +360,13 @@ func GetTransaction(
 	log *log.Entry,
 	reader db.TransactionReader,
 	ledgerReader db.LedgerReader,
+	maxEventsPerTransaction uint,
 	request GetTransactionRequest,
 ) (GetTransactionResponse, error) {
-	if err := IsValidFormat(request.Format); err != nil {
-		return GetTransactionResponse{}, &jrpc2.Error{
-			Code:    jrpc2.InvalidParams,
-			Message: err.Error(),
-		}
-	}
-
-	// parse hash
-	if hex.DecodedLen(len(request.Hash)) != len(xdr.Hash{}) {
-		return GetTransactionResponse{}, &jrpc2.Error{
-			Code:    jrpc2.InvalidParams,
-			Message: fmt.Sprintf("unexpected hash length (%d)", len(request.Hash)),
-		}
-	}
-
-	var txHash xdr.Hash
-	_, err := hex.Decode(txHash[:], []byte(request.Hash))
+	// lookupHash is the transaction hash, or the result hash if ResultHash is set
+	lookupHash, err := validateGetTransactionRequest(request)
 	if err != nil {
-		return GetTransactionResponse{}, &jrpc2.Error{
-			Code:    jrpc2.InvalidParams,
-			Message: fmt.Sprintf("incorrect hash: %v", err),
-		}
+		return GetTransactionResponse{}, err
 	}
 
 	storeRange, err := ledgerReader.GetLedgerRange(ctx)
@@ -112,8 +376,31 @@ func GetTransaction(
 			Message: fmt.Sprintf("unable to get ledger range: %v", err),
 		}
 	}
+	return getTransaction(ctx, log, reader, ledgerReader, maxEventsPerTransaction, request, lookupHash, storeRange)
+}
 
-	tx, err := reader.GetTransaction(ctx, txHash)
+// getTransaction looks up the transaction of a validated request (by lookupHash, see
+// validateGetTransactionRequest), within the given ledger range of the store.
+func getTransaction(
+	ctx context.Context,
+	log *log.Entry,
+	reader db.TransactionReader,
+	ledgerReader db.LedgerReader,
+	maxEventsPerTransaction uint,
+	request GetTransactionRequest,
+	lookupHash xdr.Hash,
+	storeRange ledgerbucketwindow.LedgerRange,
+) (GetTransactionResponse, error) {
+	var err error
+	txHash := lookupHash
+	var tx db.Transaction
+	if request.ResultHash != "" {
+		if tx, err = reader.GetTransactionByResultHash(ctx, lookupHash); err == nil {
+			txHash, err = parseTransactionHash(tx.TransactionHash)
+		}
+	} else {
+		tx, err = reader.GetTransaction(ctx, txHash)
+	}
 
 	response := GetTransactionResponse{
 		LatestLedger:          storeRange.LastLedger.Sequence,
@@ -126,7 +413,7 @@ func GetTransaction(
 		return response, nil
 	} else if err != nil {
 		log.WithError(err).
-			WithField("hash", txHash).
+			WithField("hash", lookupHash).
 			Errorf("failed to fetch transaction")
 		return response, &jrpc2.Error{
 			Code:    jrpc2.InternalError,
@@ -138,19 +425,298 @@ func GetTransaction(
 	response.FeeBump = tx.FeeBump
 	response.Ledger = tx.Ledger.Sequence
 	response.LedgerCloseTime = tx.Ledger.CloseTime
+	// the XDR values of the transaction are decoded once, for all the response fields
+	decoded := newDecodedTransaction(tx)
+	if err := setEnvelopeDetails(&response, decoded, request); err != nil {
+		return response, err
+	}
+	if err := setLedgerDetails(ctx, &response, ledgerReader, txHash, tx, request); err != nil {
+		return response, err
+	}
+	result, err := decoded.Result()
+	if err != nil {
+		return response, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	events, err := decoded.Events()
+	if err != nil {
+		return response, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	eventCounts := countEvents(events)
+	response.EventCounts = &eventCounts
+	outcome := transactionOutcome(result, events)
+	response.Outcome = &outcome
+	if request.IncludeEvents != nil && !*request.IncludeEvents {
+		tx.Events = nil
+	}
+	if request.CollapseDuplicateEvents && tx.Events != nil {
+		tx.Events, response.DiagnosticEventsOccurrences = collapseDuplicateEvents(tx.Events)
+	}
+	if events, total, truncated := truncateEvents(tx.Events, maxEventsPerTransaction); truncated {
+		tx.Events = events
+		if response.DiagnosticEventsOccurrences != nil {
+			response.DiagnosticEventsOccurrences = response.DiagnosticEventsOccurrences[:len(events)]
+		}
+		response.EventsTruncated = true
+		response.TotalEvents = total
+	}
+	if request.CompressEvents != "" && tx.Events != nil {
+		compressed, err := compressEvents(tx.Events, request.CompressEvents)
+		if err != nil {
+			return response, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.DiagnosticEventsCompressed = compressed
+		// the compressed events replace the events array
+		tx.Events = nil
+	}
+	if request.EventsFormat == EventsFormatCloudEvents && tx.Events != nil {
+		if response.DiagnosticEventsCloudEvents, err = toCloudEvents(tx, request.Format); err != nil {
+			return response, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		// the CloudEvents replace the events array
+		tx.Events = nil
+	}
+
+	if err := setTransactionData(&response, tx, decoded, request.Format); err != nil {
+		return response, err
+	}
+	meta, err := decoded.Meta()
+	if err != nil {
+		return response, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if response.CreatedContractIDs, err = createdContractIDs(meta); err != nil {
+		return response, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if request.IncludeResultingState {
+		if response.ResultingState, err = resultingState(meta, request.Format); err != nil {
+			return response, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	if request.IncludeContractStorageDiffs {
+		if response.ContractStorageDiffs, err = contractStorageDiffs(meta, request.Format); err != nil {
+			return response, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+	}
+	if request.Compat == CompatHorizon {
+		htx, err := horizonTransaction(tx, txHash)
+		if err != nil {
+			return response, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.Horizon = &htx
+	}
+
+	if request.OperationIndex != nil {
+		if err := setOperationResult(&response, result, *request.OperationIndex, request.Format); err != nil {
+			return response, err
+		}
+	}
+
+	response.Status = TransactionStatusFailed
+	if tx.Successful {
+		response.Status = TransactionStatusSuccess
+	}
+	return response, nil
+}
+
+// validateGetTransactionRequest validates the request parameters and returns the requested hash.
+func validateGetTransactionRequest(request GetTransactionRequest) (xdr.Hash, error) {
+	if err := IsValidFormat(request.Format); err != nil {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+	if err := IsValidEventsCompression(request.CompressEvents); err != nil {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+	if err := IsValidEventsFormat(request.EventsFormat); err != nil {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+	if err := IsValidCompat(request.Compat); err != nil {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+	if err := IsValidGetTransactionAPIVersion(request.APIVersion); err != nil {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+	if request.EventsFormat != "" && request.CompressEvents != "" {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: "eventsFormat and compressEvents are mutually exclusive",
+		}
+	}
+
+	if request.OperationIndex != nil && *request.OperationIndex < 0 {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: "operationIndex must not be negative",
+		}
+	}
+
+	if request.ResultHash != "" {
+		if request.Hash != "" {
+			return xdr.Hash{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "hash and resultHash are mutually exclusive",
+			}
+		}
+		resultHash, err := parseTransactionHash(request.ResultHash)
+		if err != nil {
+			return xdr.Hash{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "invalid resultHash: " + err.Error(),
+			}
+		}
+		return resultHash, nil
+	}
+
+	txHash, err := parseTransactionHash(request.Hash)
+	if err != nil {
+		return xdr.Hash{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+	return txHash, nil
+}
 
-	switch request.Format {
+// parseTransactionHash decodes a hex-encoded (64 characters) or base64-encoded
+// (44 characters) transaction hash. The encoding is determined by the length alone.
+func parseTransactionHash(hash string) (xdr.Hash, error) {
+	var txHash xdr.Hash
+	switch {
+	case hex.DecodedLen(len(hash)) == len(txHash):
+		if _, err := hex.Decode(txHash[:], []byte(hash)); err != nil {
+			return xdr.Hash{}, fmt.Errorf("incorrect hash: %w", err)
+		}
+	case len(hash) == base64.StdEncoding.EncodedLen(len(txHash)):
+		decoded, err := base64.StdEncoding.DecodeString(hash)
+		if err != nil {
+			return xdr.Hash{}, fmt.Errorf("incorrect base64 hash: %w", err)
+		}
+		if len(decoded) != len(txHash) {
+			return xdr.Hash{}, fmt.Errorf("incorrect base64 hash: unexpected decoded length (%d)", len(decoded))
+		}
+		copy(txHash[:], decoded)
+	default:
+		return xdr.Hash{}, fmt.Errorf(
+			"unexpected hash length (%d): expected a hex-encoded (%d characters) or base64-encoded (%d characters) hash",
+			len(hash), hex.EncodedLen(len(txHash)), base64.StdEncoding.EncodedLen(len(txHash)))
+	}
+	return txHash, nil
+}
+
+// setLedgerDetails fills in the response fields requiring the ledger which included the transaction.
+func setLedgerDetails(ctx context.Context, response *GetTransactionResponse, ledgerReader db.LedgerReader,
+	txHash xdr.Hash, tx db.Transaction, request GetTransactionRequest,
+) error {
+	if !request.IncludeTransactionSetProof && !request.IncludeReserveImpact && !request.IncludeLedgerHeader &&
+		!request.IncludeBucketListHash {
+		return nil
+	}
+	ledger, found, err := ledgerReader.GetLedger(ctx, response.Ledger)
+	if err != nil || !found {
+		return &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: fmt.Sprintf("could not get ledger %d", response.Ledger),
+		}
+	}
+	if request.IncludeTransactionSetProof {
+		proof, err := newTransactionSetProof(ledger, txHash)
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.TransactionSetProof = &proof
+	}
+	if request.IncludeReserveImpact {
+		baseReserve := int64(ledger.LedgerHeaderHistoryEntry().Header.BaseReserve)
+		impact, err := reserveImpact(tx.Meta, baseReserve)
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.ReserveImpact = &impact
+	}
+	if request.IncludeLedgerHeader {
+		header := ledger.LedgerHeaderHistoryEntry().Header
+		switch request.Format {
+		case FormatJSON:
+			response.LedgerHeaderJSON, err = xdr2json.ConvertInterface(header)
+		default:
+			response.LedgerHeaderXDR, err = xdr.MarshalBase64(header)
+		}
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+	}
+	if request.IncludeBucketListHash {
+		response.BucketListHash = ledger.LedgerHeaderHistoryEntry().Header.BucketListHash.HexString()
+	}
+	return nil
+}
+
+// setTransactionData fills in the response with the transaction data in the requested format.
+func setTransactionData(response *GetTransactionResponse, tx db.Transaction, decoded *decodedTransaction,
+	format string,
+) error {
+	switch format {
 	case FormatJSON:
 		result, envelope, meta, convErr := transactionToJSON(tx)
 		if convErr != nil {
-			return response, &jrpc2.Error{
+			return &jrpc2.Error{
 				Code:    jrpc2.InternalError,
 				Message: convErr.Error(),
 			}
 		}
-		diagEvents, convErr := jsonifySlice(xdr.DiagnosticEvent{}, tx.Events)
+		diagEvents, convErr := jsonifyDiagnosticEvents(tx, decoded)
 		if convErr != nil {
-			return response, &jrpc2.Error{
+			return &jrpc2.Error{
 				Code:    jrpc2.InternalError,
 				Message: convErr.Error(),
 			}
@@ -159,28 +725,367 @@ func GetTransaction(
 		response.ResultJSON = result
 		response.EnvelopeJSON = envelope
 		response.ResultMetaJSON = meta
-		response.DiagnosticEventsJSON = diagEvents
+		if tx.Events != nil {
+			response.DiagnosticEventsJSON = diagEvents
+		}
 
 	default:
 		response.ResultXDR = base64.StdEncoding.EncodeToString(tx.Result)
 		response.EnvelopeXDR = base64.StdEncoding.EncodeToString(tx.Envelope)
 		response.ResultMetaXDR = base64.StdEncoding.EncodeToString(tx.Meta)
-		response.DiagnosticEventsXDR = base64EncodeSlice(tx.Events)
+		if tx.Events != nil {
+			response.DiagnosticEventsXDR = base64EncodeSlice(tx.Events)
+		}
 	}
 
-	response.Status = TransactionStatusFailed
-	if tx.Successful {
-		response.Status = TransactionStatusSuccess
+	if tx.FeeBump {
+		inner, err := innerTransaction(decoded, format)
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.InnerTransaction = &inner
 	}
-	return response, nil
+	return nil
+}
+
+// innerTransaction returns the inner transaction of a fee-bump transaction.
+func innerTransaction(decoded *decodedTransaction, format string) (InnerTransaction, error) {
+	envelope, err := decoded.Envelope()
+	if err != nil {
+		return InnerTransaction{}, err
+	}
+	if !envelope.IsFeeBump() {
+		return InnerTransaction{}, errors.New("not a fee-bump transaction")
+	}
+	innerEnvelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   envelope.FeeBump.Tx.InnerTx.V1,
+	}
+	envelopeBytes, err := innerEnvelope.MarshalBinary()
+	if err != nil {
+		return InnerTransaction{}, err
+	}
+
+	result, err := decoded.Result()
+	if err != nil {
+		return InnerTransaction{}, err
+	}
+	var inner InnerTransaction
+	var resultBytes []byte
+	if pair, ok := result.Result.GetInnerResultPair(); ok {
+		inner.Hash = hex.EncodeToString(pair.TransactionHash[:])
+		if resultBytes, err = pair.Result.MarshalBinary(); err != nil {
+			return InnerTransaction{}, err
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		if inner.EnvelopeJSON, err = xdr2json.ConvertBytes(xdr.TransactionEnvelope{}, envelopeBytes); err != nil {
+			return InnerTransaction{}, err
+		}
+		if resultBytes != nil {
+			if inner.ResultJSON, err = xdr2json.ConvertBytes(xdr.InnerTransactionResult{}, resultBytes); err != nil {
+				return InnerTransaction{}, err
+			}
+		}
+	default:
+		inner.EnvelopeXDR = base64.StdEncoding.EncodeToString(envelopeBytes)
+		if resultBytes != nil {
+			inner.ResultXDR = base64.StdEncoding.EncodeToString(resultBytes)
+		}
+	}
+	return inner, nil
 }
 
-// NewGetTransactionHandler returns a get transaction json rpc handler
+// setEnvelopeDetails fills in the response fields decoded from the transaction envelope.
+func setEnvelopeDetails(response *GetTransactionResponse, decoded *decodedTransaction,
+	request GetTransactionRequest,
+) error {
+	envelope, err := decoded.Envelope()
+	if err != nil {
+		return &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	response.TransactionClassification = classifyTransaction(envelope)
+	response.SourceAccountSequence = envelope.SeqNum()
+	if envelope.IsFeeBump() {
+		feeAccount := envelope.FeeBumpAccount()
+		response.FeeAccount = feeAccount.Address()
+	} else {
+		sourceAccount := envelope.SourceAccount()
+		response.FeeAccount = sourceAccount.Address()
+	}
+
+	if request.IncludePreconditions {
+		response.Preconditions = transactionPreconditions(envelope)
+	}
+	if request.IncludeOperations {
+		response.Operations = transactionOperations(envelope)
+	}
+	setValidity(response, envelope)
+
+	if request.IncludeFeeComparison {
+		result, err := decoded.Result()
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		comparison := feeComparison(envelope, result)
+		response.FeeComparison = &comparison
+	}
 
+	sorobanData, ok := getSorobanData(envelope)
+	if !ok {
+		return nil
+	}
+	meta, err := decoded.Meta()
+	if err != nil {
+		return &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if request.IncludeSorobanResources {
+		breakdown, err := resourceFeeBreakdown(sorobanData, meta)
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.ResourceFeeBreakdown = &breakdown
+		entries, err := readEntries(sorobanData.Resources.Footprint, meta, request.Format)
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.ReadEntries = entries
+		footprint, err := decodeFootprint(sorobanData.Resources.Footprint, request.Format)
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.Footprint = &footprint
+	}
+	restored, extended, err := restoredAndExtendedEntries(sorobanData.Resources.Footprint, meta,
+		decoded.tx.Ledger.Sequence, request.Format)
+	if err != nil {
+		return &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	response.RestoredEntries = restored
+	response.ExtendedTtlEntries = extended
+	return nil
+}
+
+// setValidity fills in the end of the validity period of the transaction, as set by
+// the upper ledger and time bounds of its preconditions, relative to the latest ledger.
+func setValidity(response *GetTransactionResponse, envelope xdr.TransactionEnvelope) {
+	if ledgerBounds := envelope.LedgerBounds(); ledgerBounds != nil && ledgerBounds.MaxLedger != 0 {
+		// the upper ledger bound is exclusive
+		response.ValidUntilLedger = uint32(ledgerBounds.MaxLedger) - 1
+		if response.LatestLedger > response.ValidUntilLedger {
+			response.ValidityExpired = true
+		}
+	}
+	if timeBounds := envelope.TimeBounds(); timeBounds != nil && timeBounds.MaxTime != 0 {
+		response.ValidUntilTime = int64(timeBounds.MaxTime)
+		if response.LatestLedgerCloseTime > response.ValidUntilTime {
+			response.ValidityExpired = true
+		}
+	}
+}
+
+// transactionPreconditions returns the preconditions of the transaction or nil if it has none.
+func transactionPreconditions(envelope xdr.TransactionEnvelope) *TransactionPreconditions {
+	var preconditions TransactionPreconditions
+	empty := true
+	if timeBounds := envelope.TimeBounds(); timeBounds != nil {
+		preconditions.TimeBounds = &TimeBounds{
+			MinTime: uint64(timeBounds.MinTime),
+			MaxTime: uint64(timeBounds.MaxTime),
+		}
+		empty = false
+	}
+	if ledgerBounds := envelope.LedgerBounds(); ledgerBounds != nil {
+		preconditions.LedgerBounds = &LedgerBounds{
+			MinLedger: uint32(ledgerBounds.MinLedger),
+			MaxLedger: uint32(ledgerBounds.MaxLedger),
+		}
+		empty = false
+	}
+	if minSeqNum := envelope.MinSeqNum(); minSeqNum != nil {
+		preconditions.MinSeqNum = minSeqNum
+		empty = false
+	}
+	if minSeqAge := envelope.MinSeqAge(); minSeqAge != nil && *minSeqAge != 0 {
+		preconditions.MinSeqAge = uint64(*minSeqAge)
+		empty = false
+	}
+	if minSeqLedgerGap := envelope.MinSeqLedgerGap(); minSeqLedgerGap != nil && *minSeqLedgerGap != 0 {
+		preconditions.MinSeqLedgerGap = uint32(*minSeqLedgerGap)
+		empty = false
+	}
+	for _, signer := range envelope.ExtraSigners() {
+		preconditions.ExtraSigners = append(preconditions.ExtraSigners, signer.Address())
+		empty = false
+	}
+	if empty {
+		return nil
+	}
+	return &preconditions
+}
+
+func getSorobanData(envelope xdr.TransactionEnvelope) (xdr.SorobanTransactionData, bool) {
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		return envelope.V1.Tx.Ext.GetSorobanData()
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		return envelope.FeeBump.Tx.InnerTx.V1.Tx.Ext.GetSorobanData()
+	default:
+		return xdr.SorobanTransactionData{}, false
+	}
+}
+
+// resourceFeeBreakdown returns the resource fee breakdown of a Soroban transaction.
+func resourceFeeBreakdown(sorobanData xdr.SorobanTransactionData, meta xdr.TransactionMeta,
+) (ResourceFeeBreakdown, error) {
+	resources := sorobanData.Resources
+	breakdown := ResourceFeeBreakdown{
+		Instructions:       uint32(resources.Instructions),
+		ReadBytes:          uint32(resources.ReadBytes),
+		WriteBytes:         uint32(resources.WriteBytes),
+		ReadLedgerEntries:  uint32(len(resources.Footprint.ReadOnly) + len(resources.Footprint.ReadWrite)),
+		WriteLedgerEntries: uint32(len(resources.Footprint.ReadWrite)),
+		ResourceFee:        int64(sorobanData.ResourceFee),
+	}
+
+	if meta.V != 3 || meta.V3.SorobanMeta == nil {
+		return breakdown, nil
+	}
+	sorobanMeta := meta.V3.SorobanMeta
+	size, err := eventsAndReturnValueSize(sorobanMeta)
+	if err != nil {
+		return ResourceFeeBreakdown{}, err
+	}
+	breakdown.EventsAndReturnValueSize = size
+	if extV1, ok := sorobanMeta.Ext.GetV1(); ok {
+		breakdown.NonRefundableResourceFeeCharged = int64(extV1.TotalNonRefundableResourceFeeCharged)
+		breakdown.RefundableResourceFeeCharged = int64(extV1.TotalRefundableResourceFeeCharged)
+		breakdown.RentFeeCharged = int64(extV1.RentFeeCharged)
+	}
+	return breakdown, nil
+}
+
+func eventsAndReturnValueSize(sorobanMeta *xdr.SorobanTransactionMeta) (uint32, error) {
+	size := 0
+	for _, event := range sorobanMeta.Events {
+		encoded, err := event.MarshalBinary()
+		if err != nil {
+			return 0, err
+		}
+		size += len(encoded)
+	}
+	encoded, err := sorobanMeta.ReturnValue.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return uint32(size + len(encoded)), nil
+}
+
+// setOperationResult replaces the transaction result in the response with the
+// result of the operation at the given index.
+func setOperationResult(response *GetTransactionResponse, txResult xdr.TransactionResult, index int,
+	format string,
+) error {
+	opResults, _ := txResult.OperationResults()
+	if index >= len(opResults) {
+		return &jrpc2.Error{
+			Code: jrpc2.InvalidParams,
+			Message: fmt.Sprintf("operationIndex %d is out of range (the transaction has %d operation results)",
+				index, len(opResults)),
+		}
+	}
+
+	response.ResultXDR = ""
+	response.ResultJSON = nil
+	switch format {
+	case FormatJSON:
+		opResult, err := xdr2json.ConvertInterface(opResults[index])
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.OperationResultJSON = opResult
+	default:
+		opResult, err := xdr.MarshalBase64(opResults[index])
+		if err != nil {
+			return &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.OperationResultXDR = opResult
+	}
+	return nil
+}
+
+// NewGetTransactionHandler returns a get transaction json rpc handler. The ledgerReader must
+// return the ledgers as stored, without stripping the denied transactions: the transaction set
+// proofs are only verifiable with the results of all the transactions of their ledger.
 func NewGetTransactionHandler(logger *log.Entry, getter db.TransactionReader,
-	ledgerReader db.LedgerReader,
+	ledgerReader db.LedgerReader, maxEventsPerTransaction uint,
 ) jrpc2.Handler {
-	return NewHandler(func(ctx context.Context, request GetTransactionRequest) (GetTransactionResponse, error) {
-		return GetTransaction(ctx, logger, getter, ledgerReader, request)
+	return NewHandler(func(ctx context.Context, request GetTransactionRequest) (interface{}, error) {
+		response, err := GetTransaction(ctx, logger, getter, ledgerReader, maxEventsPerTransaction, request)
+		if err != nil {
+			return nil, err
+		}
+		return versionedTransactionResponse(response, request.APIVersion), nil
 	})
 }
+
+// truncateEvents caps the given events to maxEvents (0 meaning unlimited), returning
+// the capped events, the total number of events and whether they were truncated.
+func truncateEvents(events [][]byte, maxEvents uint) ([][]byte, uint, bool) {
+	total := uint(len(events))
+	if maxEvents == 0 || total <= maxEvents {
+		return events, total, false
+	}
+	return events[:maxEvents], total, true
+}
+
+// collapseDuplicateEvents deduplicates the byte-identical events, keeping the order of their first
+// occurrence, and returns the deduplicated events along with their numbers of occurrences.
+func collapseDuplicateEvents(events [][]byte) ([][]byte, []uint) {
+	collapsed := make([][]byte, 0, len(events))
+	occurrences := make([]uint, 0, len(events))
+	indexes := make(map[string]int, len(events))
+	for _, event := range events {
+		if i, ok := indexes[string(event)]; ok {
+			occurrences[i]++
+			continue
+		}
+		indexes[string(event)] = len(collapsed)
+		collapsed = append(collapsed, event)
+		occurrences = append(occurrences, 1)
+	}
+	return collapsed, occurrences
+}
@@ -0,0 +1,53 @@
+package methods
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// TransactionEnvelopePath is the HTTP path serving raw transaction envelopes.
+const TransactionEnvelopePath = "/transactions/{hash}/envelope"
+
+// NewGetTransactionEnvelopeHTTPHandler returns a plain HTTP handler serving the envelope of
+// a stored transaction in its exact wire format (binary XDR), e.g. to rebroadcast it.
+// The JSON-RPC equivalent is the base64-encoded envelopeXdr returned by getTransaction.
+func NewGetTransactionEnvelopeHTTPHandler(logger *log.Entry, reader db.TransactionReader) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hashParam := chi.URLParam(r, "hash")
+		var txHash xdr.Hash
+		if hex.DecodedLen(len(hashParam)) != len(txHash) {
+			http.Error(w, "unexpected hash length", http.StatusBadRequest)
+			return
+		}
+		if _, err := hex.Decode(txHash[:], []byte(hashParam)); err != nil {
+			http.Error(w, "incorrect hash: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tx, err := reader.GetTransaction(r.Context(), txHash)
+		if errors.Is(err, db.ErrNoTransaction) {
+			http.Error(w, "transaction not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			logger.WithError(err).WithField("hash", txHash).Error("failed to fetch transaction")
+			http.Error(w, "failed to fetch transaction", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(tx.Envelope)))
+		w.Header().Set("Content-Disposition", `attachment; filename="`+hashParam+`.xdr"`)
+		if _, err := w.Write(tx.Envelope); err != nil {
+			logger.WithError(err).Debug("could not write transaction envelope")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package methods
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestGetTransactionEnvelopeHTTPHandler(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	require.NoError(t, store.InsertTransactions(txMeta(1, true)))
+
+	router := chi.NewRouter()
+	router.Handle(TransactionEnvelopePath, NewGetTransactionEnvelopeHTTPHandler(log.DefaultLogger, store))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	get := func(hash string) *http.Response {
+		resp, err := http.Get(server.URL + "/transactions/" + hash + "/envelope")
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	xdrHash := txHash(1)
+	resp := get(hex.EncodeToString(xdrHash[:]))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	expected, err := txEnvelope(1).MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, expected, body)
+
+	require.Equal(t, http.StatusNotFound, get(strings.Repeat("ab", 32)).StatusCode)
+	require.Equal(t, http.StatusBadRequest, get("ab").StatusCode)
+}
@@ -2,13 +2,16 @@ package methods
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
@@ -26,14 +29,15 @@ func TestGetTransaction(t *testing.T) {
 	)
 	log.SetLevel(logrus.DebugLevel)
 
-	_, err := GetTransaction(ctx, log, store, ledgerReader, GetTransactionRequest{"ab", ""})
-	require.EqualError(t, err, "[-32602] unexpected hash length (2)")
-	_, err = GetTransaction(ctx, log, store, ledgerReader,
-		GetTransactionRequest{"foo                                                              ", ""})
+	_, err := GetTransaction(ctx, log, store, ledgerReader, 0, GetTransactionRequest{Hash: "ab"})
+	require.EqualError(t, err, "[-32602] unexpected hash length (2): "+
+		"expected a hex-encoded (64 characters) or base64-encoded (44 characters) hash")
+	_, err = GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: "foo                                                              "})
 	require.EqualError(t, err, "[-32602] incorrect hash: encoding/hex: invalid byte: U+006F 'o'")
 
 	hash := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
-	tx, err := GetTransaction(ctx, log, store, ledgerReader, GetTransactionRequest{hash, ""})
+	tx, err := GetTransaction(ctx, log, store, ledgerReader, 0, GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{Status: TransactionStatusNotFound}, tx)
 
@@ -42,7 +46,7 @@ func TestGetTransaction(t *testing.T) {
 
 	xdrHash := txHash(1)
 	hash = hex.EncodeToString(xdrHash[:])
-	tx, err = GetTransaction(ctx, log, store, ledgerReader, GetTransactionRequest{hash, ""})
+	tx, err = GetTransaction(ctx, log, store, ledgerReader, 0, GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 
 	expectedTxResult, err := xdr.MarshalBase64(meta.V1.TxProcessing[0].Result.Result)
@@ -57,22 +61,34 @@ func TestGetTransaction(t *testing.T) {
 		LatestLedgerCloseTime: 2625,
 		OldestLedger:          101,
 		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXDR:           expectedEnvelope,
-		ResultXDR:             expectedTxResult,
-		ResultMetaXDR:         expectedTxMeta,
-		Ledger:                101,
-		LedgerCloseTime:       2625,
-		DiagnosticEventsXDR:   []string{},
+		TransactionDetails: TransactionDetails{
+			ApplicationOrder:      1,
+			FeeBump:               false,
+			SourceAccountSequence: 1,
+			FeeAccount:            txSourceAccount,
+			EnvelopeXDR:           expectedEnvelope,
+			ResultXDR:             expectedTxResult,
+			ResultMetaXDR:         expectedTxMeta,
+			Ledger:                101,
+			LedgerCloseTime:       2625,
+			DiagnosticEventsXDR:   []string{},
+			EventCounts:           &EventCounts{},
+			Outcome:               &TransactionOutcome{Success: true},
+		},
 	}, tx)
 
+	// the hash can also be base64-encoded
+	base64Tx, err := GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: base64.StdEncoding.EncodeToString(xdrHash[:])})
+	require.NoError(t, err)
+	require.Equal(t, tx, base64Tx)
+
 	// ingest another (failed) transaction
 	meta = txMeta(2, false)
 	require.NoError(t, store.InsertTransactions(meta))
 
 	// the first transaction should still be there
-	tx, err = GetTransaction(ctx, log, store, ledgerReader, GetTransactionRequest{hash, ""})
+	tx, err = GetTransaction(ctx, log, store, ledgerReader, 0, GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{
 		Status:                TransactionStatusSuccess,
@@ -80,14 +96,20 @@ func TestGetTransaction(t *testing.T) {
 		LatestLedgerCloseTime: 2650,
 		OldestLedger:          101,
 		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXDR:           expectedEnvelope,
-		ResultXDR:             expectedTxResult,
-		ResultMetaXDR:         expectedTxMeta,
-		Ledger:                101,
-		LedgerCloseTime:       2625,
-		DiagnosticEventsXDR:   []string{},
+		TransactionDetails: TransactionDetails{
+			ApplicationOrder:      1,
+			FeeBump:               false,
+			SourceAccountSequence: 1,
+			FeeAccount:            txSourceAccount,
+			EnvelopeXDR:           expectedEnvelope,
+			ResultXDR:             expectedTxResult,
+			ResultMetaXDR:         expectedTxMeta,
+			Ledger:                101,
+			LedgerCloseTime:       2625,
+			DiagnosticEventsXDR:   []string{},
+			EventCounts:           &EventCounts{},
+			Outcome:               &TransactionOutcome{Success: true},
+		},
 	}, tx)
 
 	// the new transaction should also be there
@@ -101,7 +123,7 @@ func TestGetTransaction(t *testing.T) {
 	expectedTxMeta, err = xdr.MarshalBase64(meta.V1.TxProcessing[0].TxApplyProcessing)
 	require.NoError(t, err)
 
-	tx, err = GetTransaction(ctx, log, store, ledgerReader, GetTransactionRequest{hash, ""})
+	tx, err = GetTransaction(ctx, log, store, ledgerReader, 0, GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{
 		Status:                TransactionStatusFailed,
@@ -109,14 +131,20 @@ func TestGetTransaction(t *testing.T) {
 		LatestLedgerCloseTime: 2650,
 		OldestLedger:          101,
 		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXDR:           expectedEnvelope,
-		ResultXDR:             expectedTxResult,
-		ResultMetaXDR:         expectedTxMeta,
-		Ledger:                102,
-		LedgerCloseTime:       2650,
-		DiagnosticEventsXDR:   []string{},
+		TransactionDetails: TransactionDetails{
+			ApplicationOrder:      1,
+			FeeBump:               false,
+			SourceAccountSequence: 2,
+			FeeAccount:            txSourceAccount,
+			EnvelopeXDR:           expectedEnvelope,
+			ResultXDR:             expectedTxResult,
+			ResultMetaXDR:         expectedTxMeta,
+			Ledger:                102,
+			LedgerCloseTime:       2650,
+			DiagnosticEventsXDR:   []string{},
+			EventCounts:           &EventCounts{},
+			Outcome:               &TransactionOutcome{ErrorCode: "TxBadSeq"},
+		},
 	}, tx)
 
 	// Test Txn with events
@@ -138,7 +166,7 @@ func TestGetTransaction(t *testing.T) {
 	expectedEventsMeta, err := xdr.MarshalBase64(diagnosticEvents[0])
 	require.NoError(t, err)
 
-	tx, err = GetTransaction(ctx, log, store, ledgerReader, GetTransactionRequest{hash, ""})
+	tx, err = GetTransaction(ctx, log, store, ledgerReader, 0, GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{
 		Status:                TransactionStatusSuccess,
@@ -146,14 +174,20 @@ func TestGetTransaction(t *testing.T) {
 		LatestLedgerCloseTime: 2675,
 		OldestLedger:          101,
 		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXDR:           expectedEnvelope,
-		ResultXDR:             expectedTxResult,
-		ResultMetaXDR:         expectedTxMeta,
-		Ledger:                103,
-		LedgerCloseTime:       2675,
-		DiagnosticEventsXDR:   []string{expectedEventsMeta},
+		TransactionDetails: TransactionDetails{
+			ApplicationOrder:      1,
+			FeeBump:               false,
+			SourceAccountSequence: 3,
+			FeeAccount:            txSourceAccount,
+			EnvelopeXDR:           expectedEnvelope,
+			ResultXDR:             expectedTxResult,
+			ResultMetaXDR:         expectedTxMeta,
+			Ledger:                103,
+			LedgerCloseTime:       2675,
+			DiagnosticEventsXDR:   []string{expectedEventsMeta},
+			EventCounts:           &EventCounts{Contract: 1},
+			Outcome:               &TransactionOutcome{Success: true},
+		},
 	}, tx)
 }
 
@@ -171,12 +205,14 @@ func txHash(acctSeq uint32) xdr.Hash {
 	return hash
 }
 
+const txSourceAccount = "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"
+
 func txEnvelope(acctSeq uint32) xdr.TransactionEnvelope {
 	envelope, err := xdr.NewTransactionEnvelope(xdr.EnvelopeTypeEnvelopeTypeTx, xdr.TransactionV1Envelope{
 		Tx: xdr.Transaction{
 			Fee:           1,
 			SeqNum:        xdr.SequenceNumber(acctSeq),
-			SourceAccount: xdr.MustMuxedAddress("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"),
+			SourceAccount: xdr.MustMuxedAddress(txSourceAccount),
 		},
 	})
 	if err != nil {
@@ -316,7 +352,7 @@ func TestGetTransaction_JSONFormat(t *testing.T) {
 		Hash:   lookupHash,
 	}
 
-	txResp, err := GetTransaction(context.TODO(), nil, mockDBReader, mockLedgerReader, request)
+	txResp, err := GetTransaction(context.TODO(), nil, mockDBReader, mockLedgerReader, 0, request)
 	require.NoError(t, err)
 
 	// Do a marshaling round-trip on a transaction so we can check that the
@@ -344,6 +380,511 @@ func TestGetTransaction_JSONFormat(t *testing.T) {
 	require.Equal(t, envelope, tx["envelopeJson"])
 }
 
+func TestGetTransaction_OperationIndex(t *testing.T) {
+	store := db.NewMockTransactionStore("passphrase")
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	meta := txMeta(1, true)
+	opResults := []xdr.OperationResult{
+		{Code: xdr.OperationResultCodeOpBadAuth},
+		{Code: xdr.OperationResultCodeOpNoAccount},
+	}
+	meta.V1.TxProcessing[0].Result.Result.Result.Results = &opResults
+	require.NoError(t, store.InsertTransactions(meta))
+
+	xdrHash := txHash(1)
+	hash := hex.EncodeToString(xdrHash[:])
+	index := 1
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash, OperationIndex: &index})
+	require.NoError(t, err)
+
+	expectedOpResult, err := xdr.MarshalBase64(opResults[1])
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusSuccess, tx.Status)
+	require.Equal(t, expectedOpResult, tx.OperationResultXDR)
+	require.Empty(t, tx.ResultXDR)
+	require.NotEmpty(t, tx.EnvelopeXDR)
+
+	index = 2
+	_, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash, OperationIndex: &index})
+	require.EqualError(t, err,
+		"[-32602] operationIndex 2 is out of range (the transaction has 2 operation results)")
+
+	index = -1
+	_, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash, OperationIndex: &index})
+	require.EqualError(t, err, "[-32602] operationIndex must not be negative")
+}
+
+func TestGetTransaction_FeeBumpEnvelopeDetails(t *testing.T) {
+	inner := txEnvelope(7)
+	feeSource := keypair.MustRandom().Address()
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: xdr.MustMuxedAddress(feeSource),
+				Fee:       200,
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   inner.V1,
+				},
+			},
+		},
+	}
+	envelopeBytes, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+
+	var response GetTransactionResponse
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(db.Transaction{Envelope: envelopeBytes}), GetTransactionRequest{}))
+	require.Equal(t, int64(7), response.SourceAccountSequence)
+	require.Equal(t, feeSource, response.FeeAccount)
+}
+
+func TestGetTransaction_FeeComparison(t *testing.T) {
+	result := transactionResult(true)
+	result.FeeCharged = 60
+	resultBytes, err := result.MarshalBinary()
+	require.NoError(t, err)
+
+	envelope := txEnvelope(7)
+	envelope.V1.Tx.Fee = 100
+	envelopeBytes, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+	tx := db.Transaction{Envelope: envelopeBytes, Result: resultBytes}
+
+	var response GetTransactionResponse
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(tx), GetTransactionRequest{}))
+	require.Nil(t, response.FeeComparison)
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(tx), GetTransactionRequest{IncludeFeeComparison: true}))
+	require.Equal(t, &FeeComparison{MaxFee: 100, FeeCharged: 60, Refund: 40, FeeRatio: 0.6}, response.FeeComparison)
+
+	// the fees of fee-bump transactions are the ones of the wrapper
+	feeBump := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+				Fee:       300,
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   envelope.V1,
+				},
+			},
+		},
+	}
+	tx.Envelope, err = feeBump.MarshalBinary()
+	require.NoError(t, err)
+	response = GetTransactionResponse{}
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(tx), GetTransactionRequest{IncludeFeeComparison: true}))
+	require.Equal(t, &FeeComparison{MaxFee: 300, FeeCharged: 60, Refund: 240, FeeRatio: 0.2}, response.FeeComparison)
+}
+
+func TestGetTransaction_LedgerHeader(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	meta := txMeta(1, true)
+	require.NoError(t, store.InsertTransactions(meta))
+	hash := txHash(1)
+
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString()})
+	require.NoError(t, err)
+	require.Empty(t, tx.LedgerHeaderXDR)
+
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString(), IncludeLedgerHeader: true})
+	require.NoError(t, err)
+	expectedHeader, err := xdr.MarshalBase64(meta.LedgerHeaderHistoryEntry().Header)
+	require.NoError(t, err)
+	require.Equal(t, expectedHeader, tx.LedgerHeaderXDR)
+
+	// not found transactions have no ledger
+	missing := txHash(2)
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: missing.HexString(), IncludeLedgerHeader: true})
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusNotFound, tx.Status)
+	require.Empty(t, tx.LedgerHeaderXDR)
+}
+
+func TestGetTransaction_BucketListHash(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	meta := txMeta(1, true)
+	meta.V1.LedgerHeader.Header.BucketListHash = xdr.Hash{0xab, 0xcd, 0xef}
+	require.NoError(t, store.InsertTransactions(meta))
+	hash := txHash(1)
+
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString()})
+	require.NoError(t, err)
+	require.Empty(t, tx.BucketListHash)
+
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString(), IncludeBucketListHash: true})
+	require.NoError(t, err)
+	require.Equal(t, "abcdef"+strings.Repeat("00", 29), tx.BucketListHash)
+	require.Equal(t, meta.LedgerHeaderHistoryEntry().Header.BucketListHash.HexString(), tx.BucketListHash)
+	require.Empty(t, tx.LedgerHeaderXDR)
+}
+
+func TestGetTransaction_InnerTransaction(t *testing.T) {
+	inner := txEnvelope(7)
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+				Fee:       200,
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   inner.V1,
+				},
+			},
+		},
+	}
+	envelopeBytes, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+	innerResult := xdr.InnerTransactionResult{
+		FeeCharged: 100,
+		Result: xdr.InnerTransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &[]xdr.OperationResult{},
+		},
+	}
+	innerHash := xdr.Hash{1, 2, 3}
+	resultBytes, err := xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFeeBumpInnerSuccess,
+			InnerResultPair: &xdr.InnerTransactionResultPair{
+				TransactionHash: innerHash,
+				Result:          innerResult,
+			},
+		},
+	}.MarshalBinary()
+	require.NoError(t, err)
+
+	var response GetTransactionResponse
+	tx := db.Transaction{FeeBump: true, Envelope: envelopeBytes, Result: resultBytes}
+	require.NoError(t, setTransactionData(&response, tx, newDecodedTransaction(tx), ""))
+	require.NotNil(t, response.InnerTransaction)
+	require.Equal(t, innerHash.HexString(), response.InnerTransaction.Hash)
+
+	var innerEnvelope xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(response.InnerTransaction.EnvelopeXDR, &innerEnvelope))
+	require.Equal(t, inner, innerEnvelope)
+	expectedResult, err := xdr.MarshalBase64(innerResult)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, response.InnerTransaction.ResultXDR)
+
+	// non fee-bump transactions don't have an inner transaction
+	response = GetTransactionResponse{}
+	innerBytes, err := inner.MarshalBinary()
+	require.NoError(t, err)
+	innerTx := db.Transaction{Envelope: innerBytes, Result: resultBytes}
+	require.NoError(t, setTransactionData(&response, innerTx, newDecodedTransaction(innerTx), ""))
+	require.Nil(t, response.InnerTransaction)
+}
+
+func TestGetTransaction_ResourceFeeBreakdown(t *testing.T) {
+	envelope := txEnvelope(1)
+	var response GetTransactionResponse
+	envelopeBytes, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+	metaBytes, err := xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}}.MarshalBinary()
+	require.NoError(t, err)
+
+	// classic transactions don't have a breakdown
+	tx := db.Transaction{Envelope: envelopeBytes, Meta: metaBytes}
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(tx), GetTransactionRequest{IncludeSorobanResources: true}))
+	require.Nil(t, response.ResourceFeeBreakdown)
+	require.Nil(t, response.ReadEntries)
+	require.Nil(t, response.Footprint)
+
+	key := xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(keypair.MustRandom().Address())},
+	}
+	envelope.V1.Tx.Ext = xdr.TransactionExt{
+		V: 1,
+		SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{
+				Footprint: xdr.LedgerFootprint{
+					ReadOnly:  []xdr.LedgerKey{key, key},
+					ReadWrite: []xdr.LedgerKey{key},
+				},
+				Instructions: 1000,
+				ReadBytes:    200,
+				WriteBytes:   30,
+			},
+			ResourceFee: 500,
+		},
+	}
+	envelopeBytes, err = envelope.MarshalBinary()
+	require.NoError(t, err)
+	returnValue := xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+	metaBytes, err = xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+		SorobanMeta: &xdr.SorobanTransactionMeta{
+			Ext: xdr.SorobanTransactionMetaExt{
+				V: 1,
+				V1: &xdr.SorobanTransactionMetaExtV1{
+					TotalNonRefundableResourceFeeCharged: 300,
+					TotalRefundableResourceFeeCharged:    100,
+					RentFeeCharged:                       40,
+				},
+			},
+			ReturnValue: returnValue,
+		},
+	}}.MarshalBinary()
+	require.NoError(t, err)
+	returnValueBytes, err := returnValue.MarshalBinary()
+	require.NoError(t, err)
+
+	tx = db.Transaction{Envelope: envelopeBytes, Meta: metaBytes}
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(tx), GetTransactionRequest{}))
+	require.Nil(t, response.ResourceFeeBreakdown)
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(tx), GetTransactionRequest{IncludeSorobanResources: true}))
+	require.Equal(t, &ResourceFeeBreakdown{
+		Instructions:                    1000,
+		ReadBytes:                       200,
+		WriteBytes:                      30,
+		ReadLedgerEntries:               3,
+		WriteLedgerEntries:              1,
+		EventsAndReturnValueSize:        uint32(len(returnValueBytes)),
+		ResourceFee:                     500,
+		NonRefundableResourceFeeCharged: 300,
+		RefundableResourceFeeCharged:    100,
+		RentFeeCharged:                  40,
+	}, response.ResourceFeeBreakdown)
+	accountKey := FootprintKey{Type: FootprintKeyTypeAccount, Account: key.Account.AccountId.Address()}
+	require.Equal(t, &Footprint{
+		ReadOnly:  []FootprintKey{accountKey, accountKey},
+		ReadWrite: []FootprintKey{accountKey},
+	}, response.Footprint)
+}
+
+func TestGetTransaction_Preconditions(t *testing.T) {
+	envelope := txEnvelope(1)
+	envelopeBytes, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+	var response GetTransactionResponse
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(db.Transaction{Envelope: envelopeBytes}),
+		GetTransactionRequest{IncludePreconditions: true}))
+	require.Nil(t, response.Preconditions)
+
+	// v1 preconditions (time bounds only)
+	envelope.V1.Tx.Cond = xdr.NewPreconditionsWithTimeBounds(&xdr.TimeBounds{MinTime: 10, MaxTime: 20})
+	envelopeBytes, err = envelope.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(db.Transaction{Envelope: envelopeBytes}),
+		GetTransactionRequest{IncludePreconditions: true}))
+	require.Equal(t, &TransactionPreconditions{
+		TimeBounds: &TimeBounds{MinTime: 10, MaxTime: 20},
+	}, response.Preconditions)
+
+	// v2 preconditions
+	minSeqNum := xdr.SequenceNumber(5)
+	minSeqAge := xdr.Duration(60)
+	signer := keypair.MustRandom().Address()
+	var signerKey xdr.SignerKey
+	require.NoError(t, signerKey.SetAddress(signer))
+	envelope.V1.Tx.Cond = xdr.Preconditions{
+		Type: xdr.PreconditionTypePrecondV2,
+		V2: &xdr.PreconditionsV2{
+			LedgerBounds:    &xdr.LedgerBounds{MinLedger: 100, MaxLedger: 200},
+			MinSeqNum:       &minSeqNum,
+			MinSeqAge:       minSeqAge,
+			MinSeqLedgerGap: 3,
+			ExtraSigners:    []xdr.SignerKey{signerKey},
+		},
+	}
+	envelopeBytes, err = envelope.MarshalBinary()
+	require.NoError(t, err)
+	response = GetTransactionResponse{}
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(db.Transaction{Envelope: envelopeBytes}), GetTransactionRequest{}))
+	require.Nil(t, response.Preconditions)
+	require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(db.Transaction{Envelope: envelopeBytes}),
+		GetTransactionRequest{IncludePreconditions: true}))
+	expectedMinSeqNum := int64(5)
+	require.Equal(t, &TransactionPreconditions{
+		LedgerBounds:    &LedgerBounds{MinLedger: 100, MaxLedger: 200},
+		MinSeqNum:       &expectedMinSeqNum,
+		MinSeqAge:       60,
+		MinSeqLedgerGap: 3,
+		ExtraSigners:    []string{signer},
+	}, response.Preconditions)
+}
+
+func TestGetTransaction_Validity(t *testing.T) {
+	setDetails := func(cond xdr.Preconditions, latestLedger uint32, latestCloseTime int64) GetTransactionResponse {
+		envelope := txEnvelope(1)
+		envelope.V1.Tx.Cond = cond
+		envelopeBytes, err := envelope.MarshalBinary()
+		require.NoError(t, err)
+		response := GetTransactionResponse{LatestLedger: latestLedger, LatestLedgerCloseTime: latestCloseTime}
+		require.NoError(t, setEnvelopeDetails(&response, newDecodedTransaction(db.Transaction{Envelope: envelopeBytes}), GetTransactionRequest{}))
+		return response
+	}
+
+	// no bounds, or no upper bounds
+	response := setDetails(xdr.Preconditions{}, 150, 1000)
+	require.Zero(t, response.ValidUntilLedger)
+	require.Zero(t, response.ValidUntilTime)
+	require.False(t, response.ValidityExpired)
+	response = setDetails(xdr.NewPreconditionsWithTimeBounds(&xdr.TimeBounds{MinTime: 10}), 150, 1000)
+	require.Zero(t, response.ValidUntilTime)
+	require.False(t, response.ValidityExpired)
+
+	bounds := xdr.Preconditions{
+		Type: xdr.PreconditionTypePrecondV2,
+		V2: &xdr.PreconditionsV2{
+			TimeBounds:   &xdr.TimeBounds{MinTime: 10, MaxTime: 2000},
+			LedgerBounds: &xdr.LedgerBounds{MinLedger: 100, MaxLedger: 200},
+		},
+	}
+	response = setDetails(bounds, 199, 2000)
+	require.Equal(t, uint32(199), response.ValidUntilLedger)
+	require.Equal(t, int64(2000), response.ValidUntilTime)
+	require.False(t, response.ValidityExpired)
+
+	// the maximum ledger bound is exclusive and the maximum time bound is inclusive
+	require.True(t, setDetails(bounds, 200, 1000).ValidityExpired)
+	require.True(t, setDetails(bounds, 150, 2001).ValidityExpired)
+}
+
+func TestGetTransaction_MaxEventsPerTransaction(t *testing.T) {
+	store := db.NewMockTransactionStore("passphrase")
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	meta := txMetaWithEvents(1, true)
+	sorobanMeta := meta.V1.TxProcessing[0].TxApplyProcessing.V3.SorobanMeta
+	event := sorobanMeta.Events[0]
+	sorobanMeta.Events = []xdr.ContractEvent{event, event, event}
+	require.NoError(t, store.InsertTransactions(meta))
+
+	xdrHash := txHash(1)
+	request := GetTransactionRequest{Hash: hex.EncodeToString(xdrHash[:])}
+
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0, request)
+	require.NoError(t, err)
+	require.Len(t, tx.DiagnosticEventsXDR, 3)
+	require.False(t, tx.EventsTruncated)
+	require.Zero(t, tx.TotalEvents)
+
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 3, request)
+	require.NoError(t, err)
+	require.Len(t, tx.DiagnosticEventsXDR, 3)
+	require.False(t, tx.EventsTruncated)
+
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 2, request)
+	require.NoError(t, err)
+	require.Len(t, tx.DiagnosticEventsXDR, 2)
+	require.True(t, tx.EventsTruncated)
+	require.Equal(t, uint(3), tx.TotalEvents)
+}
+
+func TestGetTransaction_CollapseDuplicateEvents(t *testing.T) {
+	store := db.NewMockTransactionStore("passphrase")
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	meta := txMetaWithEvents(1, true)
+	sorobanMeta := meta.V1.TxProcessing[0].TxApplyProcessing.V3.SorobanMeta
+	event := sorobanMeta.Events[0]
+	otherEvent := event
+	otherEvent.Body.V0 = &xdr.ContractEventV0{
+		Topics: event.Body.V0.Topics,
+		Data:   xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+	}
+	sorobanMeta.Events = []xdr.ContractEvent{event, otherEvent, event, event, otherEvent}
+	require.NoError(t, store.InsertTransactions(meta))
+
+	xdrHash := txHash(1)
+	request := GetTransactionRequest{Hash: hex.EncodeToString(xdrHash[:])}
+
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0, request)
+	require.NoError(t, err)
+	require.Len(t, tx.DiagnosticEventsXDR, 5)
+	require.Nil(t, tx.DiagnosticEventsOccurrences)
+	all := tx.DiagnosticEventsXDR
+
+	request.CollapseDuplicateEvents = true
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0, request)
+	require.NoError(t, err)
+	require.Equal(t, []string{all[0], all[1]}, tx.DiagnosticEventsXDR)
+	require.Equal(t, []uint{3, 2}, tx.DiagnosticEventsOccurrences)
+	require.Equal(t, uint(5), tx.EventCounts.Contract+tx.EventCounts.System+tx.EventCounts.Diagnostic)
+
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 1, request)
+	require.NoError(t, err)
+	require.Equal(t, []string{all[0]}, tx.DiagnosticEventsXDR)
+	require.Equal(t, []uint{3}, tx.DiagnosticEventsOccurrences)
+	require.True(t, tx.EventsTruncated)
+	require.Equal(t, uint(2), tx.TotalEvents)
+}
+
+func TestGetTransaction_EventCounts(t *testing.T) {
+	store := db.NewMockTransactionStore("passphrase")
+	ledgerReader := db.NewMockLedgerReader(store)
+
+	meta := txMetaWithEvents(1, true)
+	sorobanMeta := meta.V1.TxProcessing[0].TxApplyProcessing.V3.SorobanMeta
+	event := sorobanMeta.Events[0]
+	for _, eventType := range []xdr.ContractEventType{
+		xdr.ContractEventTypeContract,
+		xdr.ContractEventTypeDiagnostic,
+		xdr.ContractEventTypeSystem,
+		xdr.ContractEventTypeContract,
+		xdr.ContractEventTypeDiagnostic,
+		xdr.ContractEventTypeDiagnostic,
+	} {
+		event.Type = eventType
+		sorobanMeta.DiagnosticEvents = append(sorobanMeta.DiagnosticEvents,
+			xdr.DiagnosticEvent{InSuccessfulContractCall: true, Event: event})
+	}
+	require.NoError(t, store.InsertTransactions(meta))
+
+	xdrHash := txHash(1)
+	request := GetTransactionRequest{Hash: hex.EncodeToString(xdrHash[:])}
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0, request)
+	require.NoError(t, err)
+	require.Len(t, tx.DiagnosticEventsXDR, 6)
+
+	// the counts match the full events
+	var expected EventCounts
+	for _, encoded := range tx.DiagnosticEventsXDR {
+		var diagnosticEvent xdr.DiagnosticEvent
+		require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &diagnosticEvent))
+		switch diagnosticEvent.Event.Type {
+		case xdr.ContractEventTypeContract:
+			expected.Contract++
+		case xdr.ContractEventTypeSystem:
+			expected.System++
+		case xdr.ContractEventTypeDiagnostic:
+			expected.Diagnostic++
+		}
+	}
+	require.Equal(t, EventCounts{Contract: 2, System: 1, Diagnostic: 3}, expected)
+	require.Equal(t, &expected, tx.EventCounts)
+
+	// the counts are still present when the events are truncated or omitted
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 2, request)
+	require.NoError(t, err)
+	require.Len(t, tx.DiagnosticEventsXDR, 2)
+	require.Equal(t, &expected, tx.EventCounts)
+
+	includeEvents := false
+	request.IncludeEvents = &includeEvents
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0, request)
+	require.NoError(t, err)
+	require.Empty(t, tx.DiagnosticEventsXDR)
+	require.False(t, tx.EventsTruncated)
+	require.Equal(t, &expected, tx.EventCounts)
+}
+
 func BenchmarkJSONTransactions(b *testing.B) {
 	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
 	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
@@ -377,6 +918,7 @@ func BenchmarkJSONTransactions(b *testing.B) {
 				nil,
 				mockDBReader,
 				mockLedgerReader,
+				0,
 				request)
 			require.NoError(bb, err)
 		}
@@ -393,8 +935,122 @@ func BenchmarkJSONTransactions(b *testing.B) {
 				nil,
 				mockDBReader,
 				mockLedgerReader,
+				0,
 				request)
 			require.NoError(bb, err)
 		}
 	})
 }
+
+func TestParseTransactionHash(t *testing.T) {
+	expected := xdr.Hash{0xfb, 0xff, 0x01, 0x02}
+	for _, encoded := range []string{
+		hex.EncodeToString(expected[:]),
+		strings.ToUpper(hex.EncodeToString(expected[:])),
+		base64.StdEncoding.EncodeToString(expected[:]),
+	} {
+		hash, err := parseTransactionHash(encoded)
+		require.NoError(t, err, encoded)
+		require.Equal(t, expected, hash, encoded)
+	}
+
+	// URL-safe base64 and malformed padding are rejected
+	_, err := parseTransactionHash(base64.URLEncoding.EncodeToString(expected[:]))
+	require.ErrorContains(t, err, "incorrect base64 hash")
+	_, err = parseTransactionHash(strings.Repeat("A", 44))
+	require.ErrorContains(t, err, "incorrect base64 hash")
+	_, err = parseTransactionHash(base64.RawStdEncoding.EncodeToString(expected[:]))
+	require.ErrorContains(t, err, "unexpected hash length (43)")
+}
+
+func TestGetTransaction_APIVersion(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		log          = log.DefaultLogger
+		store        = db.NewMockTransactionStore("passphrase")
+		ledgerReader = db.NewMockLedgerReader(store)
+	)
+	require.NoError(t, store.InsertTransactions(txMeta(1, true)))
+	xdrHash := txHash(1)
+	request := GetTransactionRequest{Hash: hex.EncodeToString(xdrHash[:])}
+
+	_, err := GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: request.Hash, APIVersion: LatestGetTransactionAPIVersion + 1})
+	require.ErrorContains(t, err, "invalid apiVersion")
+
+	fields := func(version uint) map[string]json.RawMessage {
+		response, err := GetTransaction(ctx, log, store, ledgerReader, 0, request)
+		require.NoError(t, err)
+		encoded, err := json.Marshal(versionedTransactionResponse(response, version))
+		require.NoError(t, err)
+		var decoded map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		return decoded
+	}
+
+	// version 1 only has the original fields
+	v1 := fields(GetTransactionAPIVersion1)
+	for _, field := range []string{"status", "latestLedger", "envelopeXdr", "resultXdr", "resultMetaXdr", "ledger", "createdAt"} {
+		require.Contains(t, v1, field)
+	}
+	for _, field := range []string{"sourceAccountSequence", "feeAccount", "eventCounts", "outcome"} {
+		require.NotContains(t, v1, field)
+	}
+
+	// version 2 adds the other fields but the classification flags
+	v2 := fields(GetTransactionAPIVersion2)
+	for _, field := range []string{"status", "envelopeXdr", "sourceAccountSequence", "feeAccount", "outcome"} {
+		require.Contains(t, v2, field)
+	}
+
+	// version 3 and the default (latest) version have all the fields
+	require.Equal(t, fields(GetTransactionAPIVersion3), fields(0))
+
+	// the classification flags are only returned from version 3
+	classified := GetTransactionResponse{TransactionDetails: TransactionDetails{
+		TransactionClassification: TransactionClassification{IsInvoke: true},
+	}}
+	require.False(t, versionedTransactionResponse(classified, GetTransactionAPIVersion2).(GetTransactionResponse).IsInvoke)
+	require.True(t, versionedTransactionResponse(classified, GetTransactionAPIVersion3).(GetTransactionResponse).IsInvoke)
+}
+
+func TestGetTransaction_ResultHash(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		log          = log.DefaultLogger
+		store        = db.NewMockTransactionStore("passphrase")
+		ledgerReader = db.NewMockLedgerReader(store)
+	)
+	require.NoError(t, store.InsertTransactions(txMeta(1, true)))
+	require.NoError(t, store.InsertTransactions(txMeta(2, false)))
+	resultHash, err := db.HashTransactionResult(transactionResult(false))
+	require.NoError(t, err)
+
+	xdrHash := txHash(2)
+	byHash, err := GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hex.EncodeToString(xdrHash[:])})
+	require.NoError(t, err)
+	byResultHash, err := GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{ResultHash: hex.EncodeToString(resultHash[:])})
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusFailed, byResultHash.Status)
+	require.Equal(t, byHash, byResultHash)
+
+	// base64-encoded result hashes are accepted too
+	byResultHash, err = GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{ResultHash: base64.StdEncoding.EncodeToString(resultHash[:])})
+	require.NoError(t, err)
+	require.Equal(t, byHash, byResultHash)
+
+	unknown := xdr.Hash{1}
+	response, err := GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{ResultHash: hex.EncodeToString(unknown[:])})
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusNotFound, response.Status)
+
+	_, err = GetTransaction(ctx, log, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hex.EncodeToString(xdrHash[:]), ResultHash: hex.EncodeToString(resultHash[:])})
+	require.ErrorContains(t, err, "hash and resultHash are mutually exclusive")
+	_, err = GetTransaction(ctx, log, store, ledgerReader, 0, GetTransactionRequest{ResultHash: "ab"})
+	require.ErrorContains(t, err, "invalid resultHash: unexpected hash length (2)")
+}
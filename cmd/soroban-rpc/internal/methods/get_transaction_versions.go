@@ -0,0 +1,117 @@
+package methods
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The versions of the getTransaction response, which clients can pin through the apiVersion
+// request field so that they aren't handed the fields added after the version they were
+// written against:
+//   - Version 1 is the original field set: status, latestLedger, latestLedgerCloseTime,
+//     oldestLedger, oldestLedgerCloseTime, applicationOrder, feeBump, envelopeXdr/envelopeJson,
+//     resultXdr/resultJson, resultMetaXdr/resultMetaJson, ledger, createdAt and
+//     diagnosticEventsXdr/diagnosticEventsJson (see GetTransactionResponseV1).
+//   - Version 2 adds all the other fields of GetTransactionResponse: sourceAccountSequence,
+//     feeAccount, the validity period, innerTransaction, eventCounts, outcome, operationResult*
+//     and the fields of the opt-in request flags (includeSorobanResources, compat etc.).
+//   - Version 3 adds the TransactionClassification flags: isRestore, isExtendTtl,
+//     isCreateContract and isInvoke.
+//
+// Responses are of the latest version unless a version is requested. A new version must be
+// added whenever fields are added to GetTransactionResponse.
+const (
+	GetTransactionAPIVersion1      = 1
+	GetTransactionAPIVersion2      = 2
+	GetTransactionAPIVersion3      = 3
+	LatestGetTransactionAPIVersion = GetTransactionAPIVersion3
+)
+
+// IsValidGetTransactionAPIVersion checks that version is a getTransaction response version
+// (or 0, for the latest version).
+func IsValidGetTransactionAPIVersion(version uint) error {
+	if version > LatestGetTransactionAPIVersion {
+		return fmt.Errorf("invalid apiVersion %d (expected at most %d)", version, LatestGetTransactionAPIVersion)
+	}
+	return nil
+}
+
+// GetTransactionResponseV1 is the getTransaction response of version 1.
+type GetTransactionResponseV1 struct {
+	Status                string `json:"status"`
+	LatestLedger          uint32 `json:"latestLedger"`
+	LatestLedgerCloseTime int64  `json:"latestLedgerCloseTime,string"`
+	OldestLedger          uint32 `json:"oldestLedger"`
+	OldestLedgerCloseTime int64  `json:"oldestLedgerCloseTime,string"`
+
+	ApplicationOrder int32 `json:"applicationOrder,omitempty"`
+	FeeBump          bool  `json:"feeBump,omitempty"`
+
+	EnvelopeXDR    string          `json:"envelopeXdr,omitempty"`
+	EnvelopeJSON   json.RawMessage `json:"envelopeJson,omitempty"`
+	ResultXDR      string          `json:"resultXdr,omitempty"`
+	ResultJSON     json.RawMessage `json:"resultJson,omitempty"`
+	ResultMetaXDR  string          `json:"resultMetaXdr,omitempty"`
+	ResultMetaJSON json.RawMessage `json:"resultMetaJson,omitempty"`
+
+	Ledger          uint32 `json:"ledger,omitempty"`
+	LedgerCloseTime int64  `json:"createdAt,string,omitempty"`
+
+	DiagnosticEventsXDR  []string          `json:"diagnosticEventsXdr,omitempty"`
+	DiagnosticEventsJSON []json.RawMessage `json:"diagnosticEventsJson,omitempty"`
+
+	// emptySlices is how the empty diagnostic event lists are rendered (see WithEmptySlices).
+	emptySlices string
+}
+
+func (r GetTransactionResponseV1) withEmptySlices(mode string) interface{} {
+	r.emptySlices = mode
+	return r
+}
+
+func (r GetTransactionResponseV1) MarshalJSON() ([]byte, error) {
+	type getTransactionResponseV1 GetTransactionResponseV1
+	encoded, err := json.Marshal(getTransactionResponseV1(r))
+	if err != nil {
+		return nil, err
+	}
+	return appendEmptyLists(encoded, r.emptySlices,
+		listField("diagnosticEventsXdr", r.DiagnosticEventsXDR),
+		listField("diagnosticEventsJson", r.DiagnosticEventsJSON)), nil
+}
+
+// versionedTransactionResponse returns the response restricted to the fields of the given
+// version (0 meaning the latest version).
+func versionedTransactionResponse(response GetTransactionResponse, version uint) interface{} {
+	switch version {
+	case GetTransactionAPIVersion1:
+		return transactionResponseV1(response)
+	case GetTransactionAPIVersion2:
+		response.TransactionClassification = TransactionClassification{}
+		return response
+	default:
+		return response
+	}
+}
+
+func transactionResponseV1(response GetTransactionResponse) GetTransactionResponseV1 {
+	return GetTransactionResponseV1{
+		Status:                response.Status,
+		LatestLedger:          response.LatestLedger,
+		LatestLedgerCloseTime: response.LatestLedgerCloseTime,
+		OldestLedger:          response.OldestLedger,
+		OldestLedgerCloseTime: response.OldestLedgerCloseTime,
+		ApplicationOrder:      response.ApplicationOrder,
+		FeeBump:               response.FeeBump,
+		EnvelopeXDR:           response.EnvelopeXDR,
+		EnvelopeJSON:          response.EnvelopeJSON,
+		ResultXDR:             response.ResultXDR,
+		ResultJSON:            response.ResultJSON,
+		ResultMetaXDR:         response.ResultMetaXDR,
+		ResultMetaJSON:        response.ResultMetaJSON,
+		Ledger:                response.Ledger,
+		LedgerCloseTime:       response.LedgerCloseTime,
+		DiagnosticEventsXDR:   response.DiagnosticEventsXDR,
+		DiagnosticEventsJSON:  response.DiagnosticEventsJSON,
+	}
+}
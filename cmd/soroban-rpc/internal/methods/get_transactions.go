@@ -19,6 +19,7 @@ import (
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/txdenylist"
 )
 
 // TransactionsPaginationOptions defines the available options for paginating through transactions.
@@ -32,6 +33,52 @@ type GetTransactionsRequest struct {
 	StartLedger uint32                         `json:"startLedger"`
 	Pagination  *TransactionsPaginationOptions `json:"pagination,omitempty"`
 	Format      string                         `json:"xdrFormat,omitempty"`
+	// IncludeTotal adds the number of transactions matching the request (Total) to the response.
+	IncludeTotal bool `json:"includeTotal,omitempty"`
+	// CompressEvents, if set (to EventsCompressionGzip or EventsCompressionZstd), returns the
+	// diagnostic events of each transaction as a single compressed blob (DiagnosticEventsCompressed).
+	CompressEvents string `json:"compressEvents,omitempty"`
+	// GroupByLedger returns the transactions grouped by the ledger which included them (Ledgers),
+	// instead of the flat Transactions list.
+	GroupByLedger bool `json:"groupByLedger,omitempty"`
+	// Filters, if set, only returns the matching transactions, through the transaction indexes.
+	Filters *TransactionFilters `json:"filters,omitempty"`
+}
+
+// TransactionFilters restrict the transactions returned by getTransactions. Exactly one of them
+// must be set.
+type TransactionFilters struct {
+	// SourceAccount is the (G...) address of the source account of the transactions, that of the
+	// inner transaction for fee-bump transactions.
+	SourceAccount string `json:"sourceAccount,omitempty"`
+	// OperationType is the snake_case type of an operation of the transactions (see
+	// TransactionOperation.Type), e.g. invoke_host_function.
+	OperationType string `json:"operationType,omitempty"`
+}
+
+// parse returns the source account or the operation type to filter the transactions by.
+func (f TransactionFilters) parse() (*xdr.AccountId, *xdr.OperationType, error) {
+	if f.SourceAccount != "" {
+		account, err := xdr.AddressToAccountId(f.SourceAccount)
+		if err != nil {
+			return nil, nil, fmt.Errorf("incorrect source account: %w", err)
+		}
+		return &account, nil, nil
+	}
+	for value := range xdr.OperationTypeToStringMap {
+		if operationType := xdr.OperationType(value); operationTypeName(operationType) == f.OperationType {
+			return nil, &operationType, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("unknown operation type %q", f.OperationType)
+}
+
+func startLedgerRangeError(ledgerRange ledgerbucketwindow.LedgerRange) error {
+	return fmt.Errorf(
+		"start ledger must be between the oldest ledger: %d and the latest ledger: %d for this rpc instance",
+		ledgerRange.FirstLedger.Sequence,
+		ledgerRange.LastLedger.Sequence,
+	)
 }
 
 // isValid checks the validity of the request parameters.
@@ -41,17 +88,24 @@ func (req GetTransactionsRequest) isValid(maxLimit uint, ledgerRange ledgerbucke
 			return errors.New("startLedger and cursor cannot both be set")
 		}
 	} else if req.StartLedger < ledgerRange.FirstLedger.Sequence || req.StartLedger > ledgerRange.LastLedger.Sequence {
-		return fmt.Errorf(
-			"start ledger must be between the oldest ledger: %d and the latest ledger: %d for this rpc instance",
-			ledgerRange.FirstLedger.Sequence,
-			ledgerRange.LastLedger.Sequence,
-		)
+		return startLedgerRangeError(ledgerRange)
 	}
 
 	if req.Pagination != nil && req.Pagination.Limit > maxLimit {
 		return fmt.Errorf("limit must not exceed %d", maxLimit)
 	}
 
+	if err := IsValidEventsCompression(req.CompressEvents); err != nil {
+		return err
+	}
+	if req.Filters != nil {
+		if (req.Filters.SourceAccount == "") == (req.Filters.OperationType == "") {
+			return errors.New("exactly one of the sourceAccount and operationType filters must be set")
+		}
+		if req.IncludeTotal || req.GroupByLedger {
+			return errors.New("includeTotal and groupByLedger cannot be combined with filters")
+		}
+	}
 	return IsValidFormat(req.Format)
 }
 
@@ -66,6 +120,7 @@ type TransactionInfo struct {
 	ApplicationOrder int32 `json:"applicationOrder"`
 	// FeeBump indicates whether the transaction is a feebump transaction
 	FeeBump bool `json:"feeBump"`
+	TransactionClassification
 	// EnvelopeXDR is the TransactionEnvelope XDR value.
 	EnvelopeXDR  string          `json:"envelopeXdr,omitempty"`
 	EnvelopeJSON json.RawMessage `json:"envelopeJson,omitempty"`
@@ -77,12 +132,52 @@ type TransactionInfo struct {
 	ResultMetaJSON json.RawMessage `json:"resultMetaJson,omitempty"`
 	// DiagnosticEventsXDR is present only if transaction was not successful.
 	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent
-	DiagnosticEventsXDR  []string          `json:"diagnosticEventsXdr,omitempty"`
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// DiagnosticEventsJSON events have an operationIndex field when the operation which emitted
+	// them can be derived (see diagnosticEventsOperationIndexes).
 	DiagnosticEventsJSON []json.RawMessage `json:"diagnosticEventsJson,omitempty"`
+	// EventsTruncated indicates that the diagnostic events were capped to the maximum
+	// number of events per transaction configured in the server.
+	EventsTruncated bool `json:"eventsTruncated,omitempty"`
+	// TotalEvents is the number of diagnostic events of the transaction. It is only
+	// present if EventsTruncated is true.
+	TotalEvents uint `json:"totalEvents,omitempty"`
+	// ConversionError is set, instead of the JSON fields, if the JSON conversion of the
+	// transaction timed out.
+	ConversionError string `json:"conversionError,omitempty"`
+	// DiagnosticEventsCompressed replaces DiagnosticEventsXDR and DiagnosticEventsJSON when
+	// requested through CompressEvents. See compressEvents for how to decode it.
+	DiagnosticEventsCompressed string `json:"diagnosticEventsCompressed,omitempty"`
 	// Ledger is the sequence of the ledger which included the transaction.
 	Ledger uint32 `json:"ledger"`
 	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
 	LedgerCloseTime int64 `json:"createdAt"`
+
+	// emptySlices is how the empty diagnostic event lists are rendered (see WithEmptySlices).
+	emptySlices string
+}
+
+func (t TransactionInfo) MarshalJSON() ([]byte, error) {
+	type transactionInfo TransactionInfo
+	encoded, err := json.Marshal(transactionInfo(t))
+	if err != nil {
+		return nil, err
+	}
+	return appendEmptyLists(encoded, t.emptySlices,
+		listField("diagnosticEventsXdr", t.DiagnosticEventsXDR),
+		listField("diagnosticEventsJson", t.DiagnosticEventsJSON)), nil
+}
+
+// LedgerTransactions are the transactions of a page included in a given ledger.
+type LedgerTransactions struct {
+	// Sequence is the sequence of the ledger.
+	Sequence uint32 `json:"sequence"`
+	// CloseTime is the unix timestamp of when the ledger was closed.
+	CloseTime int64 `json:"closeTime"`
+	// Hash is the hex-encoded hash of the ledger.
+	Hash string `json:"hash"`
+	// Transactions are the transactions of the ledger, in application order.
+	Transactions []TransactionInfo `json:"transactions"`
 }
 
 // GetTransactionsResponse encapsulates the response structure for getTransactions queries.
@@ -93,14 +188,63 @@ type GetTransactionsResponse struct {
 	OldestLedger          uint32            `json:"oldestLedger"`
 	OldestLedgerCloseTime int64             `json:"oldestLedgerCloseTimestamp"`
 	Cursor                string            `json:"cursor"`
+	// SizeLimited indicates that the page was cut short of the requested limit because its
+	// transactions exceeded the maximum response size. The cursor then points to the last returned
+	// transaction, so that the next page (requested with the cursor, as usual) resumes right after it.
+	SizeLimited bool `json:"sizeLimited,omitempty"`
+	// Total is the number of transactions from the start of the page (startLedger or cursor) to
+	// the latest ledger, denied transactions included. It is only set when requested through includeTotal.
+	Total *uint64 `json:"total,omitempty"`
+	// Ledgers replace Transactions when requested through GroupByLedger. They only include the
+	// ledgers of the page holding transactions.
+	Ledgers []LedgerTransactions `json:"ledgers,omitempty"`
+
+	// emptySlices is how the empty lists are rendered (see WithEmptySlices).
+	emptySlices string
+}
+
+func (r GetTransactionsResponse) MarshalJSON() ([]byte, error) {
+	type getTransactionsResponse GetTransactionsResponse
+	encoded, err := json.Marshal(getTransactionsResponse(r))
+	if err != nil {
+		return nil, err
+	}
+	return appendEmptyLists(encoded, r.emptySlices, listField("ledgers", r.Ledgers)), nil
+}
+
+func (r GetTransactionsResponse) withEmptySlices(mode string) interface{} {
+	r.emptySlices = mode
+	// the transactions are in the ledgers of the pages grouped by ledger
+	if r.Ledgers == nil {
+		r.Transactions = requiredList(r.Transactions, mode)
+	}
+	for i := range r.Transactions {
+		r.Transactions[i].emptySlices = mode
+	}
+	for _, ledger := range r.Ledgers {
+		for i := range ledger.Transactions {
+			ledger.Transactions[i].emptySlices = mode
+		}
+	}
+	return r
 }
 
 type transactionsRPCHandler struct {
 	ledgerReader      db.LedgerReader
+	transactionReader db.TransactionReader
 	maxLimit          uint
 	defaultLimit      uint
 	logger            *log.Entry
 	networkPassphrase string
+	denylist          *txdenylist.Denylist
+	// maxEventsPerTransaction caps the diagnostic events returned per transaction (0 means unlimited)
+	maxEventsPerTransaction uint
+	// jsonConverter, if set, runs the JSON conversions of the transactions
+	jsonConverter *JSONConverter
+	// maxResponseSize caps the size (in bytes) of the transactions of a page (0 means unlimited)
+	maxResponseSize uint
+	// filterReader serves the filtered requests
+	filterReader db.TransactionFilterReader
 }
 
 // initializePagination sets the pagination limit and cursor
@@ -145,12 +289,11 @@ func (h transactionsRPCHandler) fetchLedgerData(ctx context.Context, ledgerSeq u
 	return ledger, nil
 }
 
-// processTransactionsInLedger cycles through all the transactions in a ledger, extracts the transaction info
+// processTransactionsInLedger cycles through all the transactions in a ledger, parses them
 // and builds the list of transactions.
 func (h transactionsRPCHandler) processTransactionsInLedger(
 	ledger xdr.LedgerCloseMeta, start toid.ID,
-	txns *[]TransactionInfo, limit uint,
-	format string,
+	txns *[]db.Transaction, limit uint,
 ) (*toid.ID, bool, error) {
 	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(h.networkPassphrase, ledger)
 	if err != nil {
@@ -195,57 +338,165 @@ func (h transactionsRPCHandler) processTransactionsInLedger(
 				Message: err.Error(),
 			}
 		}
+		if h.denylist.IsDenied(tx.TransactionHash) {
+			continue
+		}
 
-		txInfo := TransactionInfo{
-			TransactionHash:  tx.TransactionHash,
-			ApplicationOrder: tx.ApplicationOrder,
-			FeeBump:          tx.FeeBump,
-			Ledger:           tx.Ledger.Sequence,
-			LedgerCloseTime:  tx.Ledger.CloseTime,
+		*txns = append(*txns, tx)
+		if len(*txns) >= int(limit) {
+			return cursor, true, nil
 		}
+	}
 
-		switch format {
-		case FormatJSON:
-			result, envelope, meta, convErr := transactionToJSON(tx)
-			if convErr != nil {
-				return nil, false, &jrpc2.Error{
-					Code:    jrpc2.InternalError,
-					Message: convErr.Error(),
-				}
+	return cursor, false, nil
+}
+
+// newTransactionInfos builds the transaction infos of transactions in the requested format.
+// The JSON conversions run through the JSON converter: the transactions whose conversion timed
+// out are returned without their XDR fields, with a ConversionError.
+func (h transactionsRPCHandler) newTransactionInfos(ctx context.Context, txs []db.Transaction, format string,
+	eventsCompression string,
+) ([]TransactionInfo, error) {
+	var converter *JSONConverter
+	if format == FormatJSON {
+		converter = h.jsonConverter
+	}
+	txInfos, errs := convertAll(ctx, converter, len(txs), func(i int) (TransactionInfo, error) {
+		return h.newTransactionInfo(txs[i], format, eventsCompression)
+	})
+	for i, err := range errs {
+		if errors.Is(err, ErrJSONConversionTimeout) {
+			txInfos[i] = baseTransactionInfo(txs[i])
+			txInfos[i].ConversionError = err.Error()
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return txInfos, nil
+}
+
+// limitResponseSize returns the longest prefix of the transactions whose JSON encoding fits in
+// the maximum response size, and whether transactions were cut. The first transaction is always
+// returned, so that paginating through oversized transactions still makes progress.
+func (h transactionsRPCHandler) limitResponseSize(txInfos []TransactionInfo) ([]TransactionInfo, bool, error) {
+	if h.maxResponseSize == 0 {
+		return txInfos, false, nil
+	}
+	var size uint
+	for i, txInfo := range txInfos {
+		encoded, err := json.Marshal(txInfo)
+		if err != nil {
+			return nil, false, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
 			}
+		}
+		size += uint(len(encoded))
+		if size > h.maxResponseSize && i > 0 {
+			return txInfos[:i], true, nil
+		}
+	}
+	return txInfos, false, nil
+}
 
-			diagEvents, convErr := jsonifySlice(xdr.DiagnosticEvent{}, tx.Events)
-			if convErr != nil {
-				return nil, false, &jrpc2.Error{
-					Code:    jrpc2.InternalError,
-					Message: convErr.Error(),
-				}
+// baseTransactionInfo builds the transaction info of a transaction, without its XDR fields.
+func baseTransactionInfo(tx db.Transaction) TransactionInfo {
+	txInfo := TransactionInfo{
+		Status:           TransactionStatusFailed,
+		TransactionHash:  tx.TransactionHash,
+		ApplicationOrder: tx.ApplicationOrder,
+		FeeBump:          tx.FeeBump,
+		Ledger:           tx.Ledger.Sequence,
+		LedgerCloseTime:  tx.Ledger.CloseTime,
+	}
+	if tx.Successful {
+		txInfo.Status = TransactionStatusSuccess
+	}
+	return txInfo
+}
+
+// newTransactionInfo builds the transaction info of a transaction in the requested format,
+// compressing its events if eventsCompression is set.
+func (h transactionsRPCHandler) newTransactionInfo(tx db.Transaction, format string, eventsCompression string) (
+	TransactionInfo, error,
+) {
+	txInfo := baseTransactionInfo(tx)
+	decoded := newDecodedTransaction(tx)
+	envelope, err := decoded.Envelope()
+	if err != nil {
+		return TransactionInfo{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	txInfo.TransactionClassification = classifyTransaction(envelope)
+	if events, total, truncated := truncateEvents(tx.Events, h.maxEventsPerTransaction); truncated {
+		tx.Events = events
+		txInfo.EventsTruncated = true
+		txInfo.TotalEvents = total
+	}
+	if eventsCompression != "" {
+		compressed, err := compressEvents(tx.Events, eventsCompression)
+		if err != nil {
+			return TransactionInfo{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
 			}
+		}
+		txInfo.DiagnosticEventsCompressed = compressed
+		// the compressed events replace the events array
+		tx.Events = nil
+	}
 
-			txInfo.ResultJSON = result
-			txInfo.ResultMetaJSON = envelope
-			txInfo.EnvelopeJSON = meta
-			txInfo.DiagnosticEventsJSON = diagEvents
+	switch format {
+	case FormatJSON:
+		result, envelope, meta, convErr := transactionToJSON(tx)
+		if convErr != nil {
+			return TransactionInfo{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: convErr.Error(),
+			}
+		}
 
-		default:
-			txInfo.ResultXDR = base64.StdEncoding.EncodeToString(tx.Result)
-			txInfo.ResultMetaXDR = base64.StdEncoding.EncodeToString(tx.Meta)
-			txInfo.EnvelopeXDR = base64.StdEncoding.EncodeToString(tx.Envelope)
-			txInfo.DiagnosticEventsXDR = base64EncodeSlice(tx.Events)
+		diagEvents, convErr := jsonifyDiagnosticEvents(tx, decoded)
+		if convErr != nil {
+			return TransactionInfo{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: convErr.Error(),
+			}
 		}
 
-		txInfo.Status = TransactionStatusFailed
-		if tx.Successful {
-			txInfo.Status = TransactionStatusSuccess
+		txInfo.ResultJSON = result
+		txInfo.ResultMetaJSON = envelope
+		txInfo.EnvelopeJSON = meta
+		if tx.Events != nil {
+			txInfo.DiagnosticEventsJSON = diagEvents
 		}
 
-		*txns = append(*txns, txInfo)
-		if len(*txns) >= int(limit) {
-			return cursor, true, nil
+	default:
+		txInfo.ResultXDR = base64.StdEncoding.EncodeToString(tx.Result)
+		txInfo.ResultMetaXDR = base64.StdEncoding.EncodeToString(tx.Meta)
+		txInfo.EnvelopeXDR = base64.StdEncoding.EncodeToString(tx.Envelope)
+		if tx.Events != nil {
+			txInfo.DiagnosticEventsXDR = base64EncodeSlice(tx.Events)
 		}
 	}
+	return txInfo, nil
+}
 
-	return cursor, false, nil
+// countTransactions counts the transactions from start to the end ledger, through the transactions index.
+func (h transactionsRPCHandler) countTransactions(ctx context.Context, start toid.ID, endLedger uint32) (
+	uint64, error,
+) {
+	count, err := h.transactionReader.CountTransactions(ctx, uint32(start.LedgerSequence), endLedger)
+	if err != nil {
+		return 0, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	// don't count the transactions of the start ledger preceding the start of the page
+	return count - min(count, uint64(start.TransactionOrder-1)), nil
 }
 
 // getTransactionsByLedgerSequence fetches transactions between the start and end ledgers, inclusive of both.
@@ -273,46 +524,148 @@ func (h transactionsRPCHandler) getTransactionsByLedgerSequence(ctx context.Cont
 	if err != nil {
 		return GetTransactionsResponse{}, err
 	}
+	if uint32(start.LedgerSequence) < ledgerRange.FirstLedger.Sequence {
+		// the ledger of the cursor was trimmed since the previous page was returned
+		message := fmt.Sprintf("cursor points to ledger %d, which is no longer retained: %v",
+			start.LedgerSequence, startLedgerRangeError(ledgerRange))
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: message,
+		}
+	}
+	if request.Filters != nil {
+		return h.getFilteredTransactions(ctx, request, start, limit, ledgerRange)
+	}
 
 	// Iterate through each ledger and its transactions until limit or end range is reached.
 	// The latest ledger acts as the end ledger range for the request.
-	var txns []TransactionInfo
+	var txs []db.Transaction
 	var done bool
 	cursor := toid.New(0, 0, 0)
+	ledgerHashes := map[uint32]string{}
 	for ledgerSeq := start.LedgerSequence; ledgerSeq <= int32(ledgerRange.LastLedger.Sequence); ledgerSeq++ {
 		ledger, err := h.fetchLedgerData(ctx, uint32(ledgerSeq))
 		if err != nil {
 			return GetTransactionsResponse{}, err
 		}
 
-		cursor, done, err = h.processTransactionsInLedger(ledger, start, &txns, limit, request.Format)
+		cursor, done, err = h.processTransactionsInLedger(ledger, start, &txs, limit)
 		if err != nil {
 			return GetTransactionsResponse{}, err
 		}
+		if request.GroupByLedger {
+			ledgerHashes[uint32(ledgerSeq)] = ledger.LedgerHash().HexString()
+		}
 		if done {
 			break
 		}
 	}
 
-	return GetTransactionsResponse{
+	var txns []TransactionInfo
+	var sizeLimited bool
+	if len(txs) > 0 {
+		if txns, err = h.newTransactionInfos(ctx, txs, request.Format, request.CompressEvents); err != nil {
+			return GetTransactionsResponse{}, err
+		}
+		if txns, sizeLimited, err = h.limitResponseSize(txns); err != nil {
+			return GetTransactionsResponse{}, err
+		}
+		if sizeLimited {
+			last := txns[len(txns)-1]
+			cursor = toid.New(int32(last.Ledger), last.ApplicationOrder, 1)
+		}
+	}
+
+	response := GetTransactionsResponse{
 		Transactions:          txns,
 		LatestLedger:          ledgerRange.LastLedger.Sequence,
 		LatestLedgerCloseTime: ledgerRange.LastLedger.CloseTime,
 		OldestLedger:          ledgerRange.FirstLedger.Sequence,
 		OldestLedgerCloseTime: ledgerRange.FirstLedger.CloseTime,
 		Cursor:                cursor.String(),
-	}, nil
+		SizeLimited:           sizeLimited,
+	}
+	if request.GroupByLedger {
+		response.Ledgers = groupByLedger(txns, ledgerHashes)
+		response.Transactions = nil
+	}
+	if request.IncludeTotal {
+		total, err := h.countTransactions(ctx, start, ledgerRange.LastLedger.Sequence)
+		if err != nil {
+			return GetTransactionsResponse{}, err
+		}
+		response.Total = &total
+	}
+	return response, nil
+}
+
+// getFilteredTransactions fetches the page of transactions matching the filters of the request, from
+// start to the latest ledger, through the transaction indexes.
+func (h transactionsRPCHandler) getFilteredTransactions(ctx context.Context, request GetTransactionsRequest,
+	start toid.ID, limit uint, ledgerRange ledgerbucketwindow.LedgerRange,
+) (GetTransactionsResponse, error) {
+	account, operationType, err := request.Filters.parse()
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	endLedger := ledgerRange.LastLedger.Sequence
+	var locations []db.TransactionLocation
+	if account != nil {
+		locations, err = h.filterReader.GetTransactionsBySourceAccount(ctx, *account,
+			uint32(start.LedgerSequence), start.TransactionOrder, endLedger, limit)
+	} else {
+		locations, err = h.filterReader.GetTransactionsByOperationType(ctx, *operationType,
+			uint32(start.LedgerSequence), start.TransactionOrder, endLedger, limit)
+	}
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	return h.transactionsAtLocations(ctx, locations, limit, endLedger, ledgerRange, request.Format,
+		request.CompressEvents)
+}
+
+// groupByLedger groups the (ordered) transactions by the ledger which included them.
+func groupByLedger(txns []TransactionInfo, ledgerHashes map[uint32]string) []LedgerTransactions {
+	groups := []LedgerTransactions{}
+	for _, txn := range txns {
+		if len(groups) == 0 || groups[len(groups)-1].Sequence != txn.Ledger {
+			groups = append(groups, LedgerTransactions{
+				Sequence:  txn.Ledger,
+				CloseTime: txn.LedgerCloseTime,
+				Hash:      ledgerHashes[txn.Ledger],
+			})
+		}
+		group := &groups[len(groups)-1]
+		group.Transactions = append(group.Transactions, txn)
+	}
+	return groups
 }
 
-func NewGetTransactionsHandler(logger *log.Entry, ledgerReader db.LedgerReader, maxLimit,
-	defaultLimit uint, networkPassphrase string,
+func NewGetTransactionsHandler(logger *log.Entry, ledgerReader db.LedgerReader,
+	transactionReader db.TransactionReader, maxLimit, defaultLimit uint, networkPassphrase string,
+	denylist *txdenylist.Denylist, maxEventsPerTransaction uint, jsonConverter *JSONConverter, maxResponseSize uint,
+	filterReader db.TransactionFilterReader,
 ) jrpc2.Handler {
 	transactionsHandler := transactionsRPCHandler{
-		ledgerReader:      ledgerReader,
-		maxLimit:          maxLimit,
-		defaultLimit:      defaultLimit,
-		logger:            logger,
-		networkPassphrase: networkPassphrase,
+		filterReader:            filterReader,
+		jsonConverter:           jsonConverter,
+		maxResponseSize:         maxResponseSize,
+		ledgerReader:            ledgerReader,
+		transactionReader:       transactionReader,
+		denylist:                denylist,
+		maxEventsPerTransaction: maxEventsPerTransaction,
+		maxLimit:                maxLimit,
+		defaultLimit:            defaultLimit,
+		logger:                  logger,
+		networkPassphrase:       networkPassphrase,
 	}
 
 	return handler.New(transactionsHandler.getTransactionsByLedgerSequence)
@@ -0,0 +1,236 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// maxTransactionsByHashBatchSize is the maximum number of hashes of a getTransactionsByHash request.
+const maxTransactionsByHashBatchSize = 200
+
+// GetTransactionsByHashRequest looks up several transactions by hash at once.
+type GetTransactionsByHashRequest struct {
+	// Hashes are the hex-encoded or base64-encoded hashes of the transactions.
+	Hashes []string `json:"hashes"`
+	Format string   `json:"xdrFormat,omitempty"`
+	// StatusFilter, if set, restricts the response to the transactions with one of the statuses:
+	// TransactionStatusSuccess, TransactionStatusFailed or TransactionStatusNotFound. The unknown
+	// transactions are only returned if TransactionStatusNotFound is listed.
+	StatusFilter []string `json:"statusFilter,omitempty"`
+}
+
+// TransactionByHash is the getTransaction response of a hash of a getTransactionsByHash request,
+// without the ledger range of Soroban-RPC (which is returned once for the batch).
+type TransactionByHash struct {
+	// Hash is the hash as requested.
+	Hash string `json:"hash"`
+	// Status is one of: TransactionSuccess, TransactionNotFound, or TransactionFailed.
+	Status string `json:"status"`
+	// The TransactionDetails fields are only present if Status is not TransactionNotFound.
+	TransactionDetails
+}
+
+type GetTransactionsByHashResponse struct {
+	// Transactions are in the order of the requested hashes.
+	Transactions          []TransactionByHash `json:"transactions"`
+	LatestLedger          uint32              `json:"latestLedger"`
+	LatestLedgerCloseTime int64               `json:"latestLedgerCloseTime,string"`
+	OldestLedger          uint32              `json:"oldestLedger"`
+	OldestLedgerCloseTime int64               `json:"oldestLedgerCloseTime,string"`
+	// LookedUpCount is the number of transactions looked up, and FilteredCount the number of
+	// them left out by the StatusFilter of the request. They are only present with a StatusFilter.
+	LookedUpCount *int `json:"lookedUpCount,omitempty"`
+	FilteredCount *int `json:"filteredCount,omitempty"`
+}
+
+func (r GetTransactionsByHashResponse) withEmptySlices(mode string) interface{} {
+	for i := range r.Transactions {
+		r.Transactions[i].emptySlices = mode
+	}
+	return r
+}
+
+func (t TransactionByHash) MarshalJSON() ([]byte, error) {
+	type transactionByHash TransactionByHash
+	encoded, err := json.Marshal(transactionByHash(t))
+	if err != nil {
+		return nil, err
+	}
+	return t.appendEmptyEventLists(encoded), nil
+}
+
+// parseStatusFilter returns the set of the statuses of a StatusFilter, or nil if it's empty.
+func parseStatusFilter(filter []string) (map[string]bool, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	statuses := make(map[string]bool, len(filter))
+	for _, status := range filter {
+		switch status {
+		case TransactionStatusSuccess, TransactionStatusFailed, TransactionStatusNotFound:
+			statuses[status] = true
+		default:
+			return nil, fmt.Errorf("statusFilter: unknown status %q (expected %s, %s or %s)", status,
+				TransactionStatusSuccess, TransactionStatusFailed, TransactionStatusNotFound)
+		}
+	}
+	return statuses, nil
+}
+
+// filterByStatus restricts the transactions of the response to the given statuses, if any, and
+// reports how many of them were looked up and left out.
+func filterByStatus(response *GetTransactionsByHashResponse, statuses map[string]bool) {
+	if statuses == nil {
+		return
+	}
+	lookedUp := len(response.Transactions)
+	matching := make([]TransactionByHash, 0, lookedUp)
+	for _, tx := range response.Transactions {
+		if statuses[tx.Status] {
+			matching = append(matching, tx)
+		}
+	}
+	filtered := lookedUp - len(matching)
+	response.Transactions = matching
+	response.LookedUpCount = &lookedUp
+	response.FilteredCount = &filtered
+}
+
+// lookUpConcurrently calls lookUp for the indexes from 0 to n-1, from up to concurrency
+// goroutines, and returns the first error. The remaining lookups are skipped once one fails.
+func lookUpConcurrently(ctx context.Context, n int, concurrency uint, lookUp func(ctx context.Context, i int) error,
+) error {
+	if concurrency <= 1 || n <= 1 {
+		for i := range n {
+			if err := lookUp(ctx, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	indexes := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for range min(int(concurrency), n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := lookUp(ctx, i); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+feed:
+	for i := range n {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if firstErr == nil {
+		// the lookups were skipped if the request was cancelled
+		return ctx.Err()
+	}
+	return firstErr
+}
+
+// NewGetTransactionsByHashHandler returns a JSON RPC handler looking up a batch of transactions
+// by hash, like getTransaction does for each of them, but reading the ledger range only once.
+// Unknown transactions are reported as not found instead of failing the batch. Up to concurrency
+// transactions are looked up at once, and returned in the order of the requested hashes (only
+// those matching the StatusFilter of the request, if any).
+func NewGetTransactionsByHashHandler(logger *log.Entry, reader db.TransactionReader,
+	ledgerReader db.LedgerReader, maxEventsPerTransaction uint, concurrency uint,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request GetTransactionsByHashRequest,
+	) (GetTransactionsByHashResponse, error) {
+		if len(request.Hashes) == 0 || len(request.Hashes) > maxTransactionsByHashBatchSize {
+			return GetTransactionsByHashResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("hashes must contain between 1 and %d hashes", maxTransactionsByHashBatchSize),
+			}
+		}
+		statuses, err := parseStatusFilter(request.StatusFilter)
+		if err != nil {
+			return GetTransactionsByHashResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+		requests := make([]GetTransactionRequest, 0, len(request.Hashes))
+		txHashes := make([]xdr.Hash, 0, len(request.Hashes))
+		for i, hash := range request.Hashes {
+			txRequest := GetTransactionRequest{Hash: hash, Format: request.Format}
+			txHash, err := validateGetTransactionRequest(txRequest)
+			if err != nil {
+				message := err.Error()
+				var jrpcErr *jrpc2.Error
+				if errors.As(err, &jrpcErr) {
+					message = jrpcErr.Message
+				}
+				return GetTransactionsByHashResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: fmt.Sprintf("hashes[%d]: %s", i, message),
+				}
+			}
+			requests = append(requests, txRequest)
+			txHashes = append(txHashes, txHash)
+		}
+
+		storeRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil {
+			return GetTransactionsByHashResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("unable to get ledger range: %v", err),
+			}
+		}
+		response := GetTransactionsByHashResponse{
+			Transactions:          make([]TransactionByHash, len(requests)),
+			LatestLedger:          storeRange.LastLedger.Sequence,
+			LatestLedgerCloseTime: storeRange.LastLedger.CloseTime,
+			OldestLedger:          storeRange.FirstLedger.Sequence,
+			OldestLedgerCloseTime: storeRange.FirstLedger.CloseTime,
+		}
+		err = lookUpConcurrently(ctx, len(requests), concurrency, func(ctx context.Context, i int) error {
+			tx, err := getTransaction(ctx, logger, reader, ledgerReader, maxEventsPerTransaction,
+				requests[i], txHashes[i], storeRange)
+			if err != nil {
+				return err
+			}
+			response.Transactions[i] = TransactionByHash{
+				Hash:               requests[i].Hash,
+				Status:             tx.Status,
+				TransactionDetails: tx.TransactionDetails,
+			}
+			return nil
+		})
+		if err != nil {
+			return GetTransactionsByHashResponse{}, err
+		}
+		filterByStatus(&response, statuses)
+		return response, nil
+	})
+}
@@ -0,0 +1,240 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+// ledgerRangeCounter is a db.LedgerReader counting the ledger range lookups.
+type ledgerRangeCounter struct {
+	db.LedgerReader
+	calls int
+}
+
+func (c *ledgerRangeCounter) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
+	c.calls++
+	return c.LedgerReader.GetLedgerRange(ctx)
+}
+
+func TestGetTransactionsByHash(t *testing.T) {
+	store := db.NewMockTransactionStore("passphrase")
+	ledgerReader := &ledgerRangeCounter{LedgerReader: db.NewMockLedgerReader(store)}
+	require.NoError(t, store.InsertTransactions(txMeta(1, true)))
+	require.NoError(t, store.InsertTransactions(txMeta(2, false)))
+	handler := NewGetTransactionsByHashHandler(log.DefaultLogger, store, ledgerReader, 0, 1)
+
+	call := func(hashes []string) (GetTransactionsByHashResponse, error) {
+		params, err := json.Marshal(GetTransactionsByHashRequest{Hashes: hashes})
+		require.NoError(t, err)
+		requests, err := jrpc2.ParseRequests([]byte(
+			`{"jsonrpc":"2.0","id":1,"method":"getTransactionsByHash","params":` + string(params) + `}`))
+		require.NoError(t, err)
+		response, err := handler(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return GetTransactionsByHashResponse{}, err
+		}
+		return response.(GetTransactionsByHashResponse), nil
+	}
+
+	hash1, hash2, unknown := txHash(1), txHash(2), xdr.Hash{1}
+	hashes := []string{
+		hex.EncodeToString(hash2[:]),
+		hex.EncodeToString(unknown[:]),
+		hex.EncodeToString(hash1[:]),
+	}
+	response, err := call(hashes)
+	require.NoError(t, err)
+	require.Equal(t, 1, ledgerReader.calls)
+
+	// every transaction is reported as getTransaction reports it, in the requested order
+	require.Len(t, response.Transactions, 3)
+	for i, hash := range hashes {
+		expected, err := GetTransaction(context.Background(), log.DefaultLogger, store, ledgerReader, 0,
+			GetTransactionRequest{Hash: hash})
+		require.NoError(t, err)
+		require.Equal(t, hash, response.Transactions[i].Hash)
+		require.Equal(t, expected.Status, response.Transactions[i].Status)
+		require.Equal(t, expected.TransactionDetails, response.Transactions[i].TransactionDetails)
+		require.Equal(t, expected.LatestLedger, response.LatestLedger)
+		require.Equal(t, expected.OldestLedger, response.OldestLedger)
+	}
+	require.Equal(t, TransactionStatusFailed, response.Transactions[0].Status)
+	require.Equal(t, TransactionStatusNotFound, response.Transactions[1].Status)
+	require.Equal(t, TransactionDetails{}, response.Transactions[1].TransactionDetails)
+	require.Equal(t, TransactionStatusSuccess, response.Transactions[2].Status)
+
+	_, err = call([]string{hashes[0], "ab"})
+	require.ErrorContains(t, err, "hashes[1]: unexpected hash length (2)")
+	_, err = call(nil)
+	require.ErrorContains(t, err, "hashes must contain between 1 and 200 hashes")
+	tooMany := strings.Split(strings.Repeat(hashes[0]+",", maxTransactionsByHashBatchSize), ",")
+	require.Len(t, tooMany, maxTransactionsByHashBatchSize+1)
+	_, err = call(tooMany)
+	require.ErrorContains(t, err, "hashes must contain between 1 and 200 hashes")
+}
+
+// slowTransactionReader is a db.TransactionReader taking delays[hash] to look up a transaction.
+type slowTransactionReader struct {
+	db.TransactionReader
+	delays map[xdr.Hash]time.Duration
+	failed map[xdr.Hash]bool
+}
+
+func (r slowTransactionReader) GetTransaction(ctx context.Context, hash xdr.Hash) (db.Transaction, error) {
+	time.Sleep(r.delays[hash])
+	if r.failed[hash] {
+		return db.Transaction{}, errors.New("lookup failed")
+	}
+	return r.TransactionReader.GetTransaction(ctx, hash)
+}
+
+// hashesBatch stores count transactions, returning the request looking them up and a reader
+// taking delay to look up each of them.
+func hashesBatch(t testing.TB, count int, delay func(i int) time.Duration,
+) (GetTransactionsByHashRequest, slowTransactionReader, db.LedgerReader) {
+	store := db.NewMockTransactionStore("passphrase")
+	reader := slowTransactionReader{TransactionReader: store, delays: map[xdr.Hash]time.Duration{}}
+	var request GetTransactionsByHashRequest
+	for i := 1; i <= count; i++ {
+		require.NoError(t, store.InsertTransactions(txMeta(uint32(i), i%2 == 0)))
+		hash := txHash(uint32(i))
+		reader.delays[hash] = delay(i)
+		request.Hashes = append(request.Hashes, hex.EncodeToString(hash[:]))
+	}
+	return request, reader, db.NewMockLedgerReader(store)
+}
+
+func callTransactionsByHash(t testing.TB, handler jrpc2.Handler, request GetTransactionsByHashRequest,
+) (GetTransactionsByHashResponse, error) {
+	params, err := json.Marshal(request)
+	require.NoError(t, err)
+	requests, err := jrpc2.ParseRequests([]byte(
+		`{"jsonrpc":"2.0","id":1,"method":"getTransactionsByHash","params":` + string(params) + `}`))
+	require.NoError(t, err)
+	response, err := handler(context.Background(), requests[0].ToRequest())
+	if err != nil {
+		return GetTransactionsByHashResponse{}, err
+	}
+	return response.(GetTransactionsByHashResponse), nil
+}
+
+func TestGetTransactionsByHashConcurrentOrder(t *testing.T) {
+	// the first transactions are the slowest to look up, so they complete last
+	const count = 20
+	request, reader, ledgerReader := hashesBatch(t, count, func(i int) time.Duration {
+		return time.Duration(count-i) * time.Millisecond
+	})
+	sequential, err := callTransactionsByHash(t,
+		NewGetTransactionsByHashHandler(log.DefaultLogger, reader, ledgerReader, 0, 1), request)
+	require.NoError(t, err)
+	concurrent, err := callTransactionsByHash(t,
+		NewGetTransactionsByHashHandler(log.DefaultLogger, reader, ledgerReader, 0, 8), request)
+	require.NoError(t, err)
+
+	require.Len(t, concurrent.Transactions, count)
+	for i, hash := range request.Hashes {
+		require.Equal(t, hash, concurrent.Transactions[i].Hash)
+	}
+	require.Equal(t, sequential, concurrent)
+
+	// a failed lookup fails the batch
+	reader.failed = map[xdr.Hash]bool{txHash(count / 2): true}
+	_, err = callTransactionsByHash(t,
+		NewGetTransactionsByHashHandler(log.DefaultLogger, reader, ledgerReader, 0, 8), request)
+	require.ErrorContains(t, err, "lookup failed")
+}
+
+func BenchmarkGetTransactionsByHash(b *testing.B) {
+	// each lookup waits for the database like a query would
+	request, reader, ledgerReader := hashesBatch(b, 100, func(int) time.Duration {
+		return 100 * time.Microsecond
+	})
+	for _, concurrency := range []uint{1, 4, 16} {
+		handler := NewGetTransactionsByHashHandler(log.DefaultLogger, reader, ledgerReader, 0, concurrency)
+		name := "sequential"
+		if concurrency > 1 {
+			name = fmt.Sprintf("concurrency %d", concurrency)
+		}
+		b.Run(name, func(b *testing.B) {
+			for range b.N {
+				_, err := callTransactionsByHash(b, handler, request)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestGetTransactionsByHashStatusFilter(t *testing.T) {
+	store := db.NewMockTransactionStore("passphrase")
+	require.NoError(t, store.InsertTransactions(txMeta(1, true)))
+	require.NoError(t, store.InsertTransactions(txMeta(2, false)))
+	require.NoError(t, store.InsertTransactions(txMeta(3, false)))
+	handler := NewGetTransactionsByHashHandler(log.DefaultLogger, store, db.NewMockLedgerReader(store), 0, 2)
+
+	var hashes []string
+	for _, hash := range []xdr.Hash{txHash(1), txHash(2), {1}, txHash(3), {2}} {
+		hashes = append(hashes, hex.EncodeToString(hash[:]))
+	}
+	call := func(statuses ...string) (GetTransactionsByHashResponse, error) {
+		return callTransactionsByHash(t, handler, GetTransactionsByHashRequest{Hashes: hashes, StatusFilter: statuses})
+	}
+	transactionHashes := func(response GetTransactionsByHashResponse) []string {
+		var result []string
+		for _, tx := range response.Transactions {
+			result = append(result, tx.Hash)
+		}
+		return result
+	}
+	count := func(n int) *int { return &n }
+
+	response, err := call(TransactionStatusFailed)
+	require.NoError(t, err)
+	require.Equal(t, []string{hashes[1], hashes[3]}, transactionHashes(response))
+	require.Equal(t, count(5), response.LookedUpCount)
+	require.Equal(t, count(3), response.FilteredCount)
+
+	// the unknown transactions are only returned when NOT_FOUND is listed
+	response, err = call(TransactionStatusSuccess, TransactionStatusNotFound)
+	require.NoError(t, err)
+	require.Equal(t, []string{hashes[0], hashes[2], hashes[4]}, transactionHashes(response))
+	require.Equal(t, TransactionStatusNotFound, response.Transactions[1].Status)
+	require.Equal(t, count(5), response.LookedUpCount)
+	require.Equal(t, count(2), response.FilteredCount)
+
+	// an empty result still reports the counts
+	store = db.NewMockTransactionStore("passphrase")
+	handler = NewGetTransactionsByHashHandler(log.DefaultLogger, store, db.NewMockLedgerReader(store), 0, 2)
+	response, err = call(TransactionStatusSuccess, TransactionStatusFailed)
+	require.NoError(t, err)
+	require.Empty(t, response.Transactions)
+	require.Equal(t, count(5), response.LookedUpCount)
+	require.Equal(t, count(5), response.FilteredCount)
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"transactions":[]`)
+
+	// without a filter, every transaction is returned without the counts
+	response, err = call()
+	require.NoError(t, err)
+	require.Equal(t, hashes, transactionHashes(response))
+	require.Nil(t, response.LookedUpCount)
+	require.Nil(t, response.FilteredCount)
+
+	_, err = call("PENDING")
+	require.ErrorContains(t, err, `statusFilter: unknown status "PENDING"`)
+}
@@ -0,0 +1,124 @@
+package methods
+
+import (
+	"context"
+	"errors"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/txdenylist"
+)
+
+// GetTransactionsByLedgerKeyRequest represents the request parameters for fetching the
+// transactions which created, updated or removed a ledger entry within a range of ledgers.
+type GetTransactionsByLedgerKeyRequest struct {
+	// LedgerKey is the key of the entry, as a base64-encoded LedgerKey XDR value.
+	LedgerKey   string `json:"ledgerKey"`
+	StartLedger uint32 `json:"startLedger,omitempty"`
+	// EndLedger defaults to the latest ledger.
+	EndLedger  uint32                         `json:"endLedger,omitempty"`
+	Pagination *TransactionsPaginationOptions `json:"pagination,omitempty"`
+	Format     string                         `json:"xdrFormat,omitempty"`
+	// CompressEvents is the same as the one of GetTransactionsRequest.
+	CompressEvents string `json:"compressEvents,omitempty"`
+}
+
+// isValid checks the validity of the request parameters.
+func (req GetTransactionsByLedgerKeyRequest) isValid(maxLimit uint, ledgerRange ledgerbucketwindow.LedgerRange,
+) error {
+	if err := (GetTransactionsRequest{
+		StartLedger:    req.StartLedger,
+		Pagination:     req.Pagination,
+		Format:         req.Format,
+		CompressEvents: req.CompressEvents,
+	}).isValid(maxLimit, ledgerRange); err != nil {
+		return err
+	}
+	if req.EndLedger != 0 && req.StartLedger > req.EndLedger {
+		return errors.New("endLedger must not be lower than startLedger")
+	}
+	return nil
+}
+
+type transactionsByLedgerKeyRPCHandler struct {
+	transactionsRPCHandler
+	keyReader db.LedgerKeyTransactionReader
+}
+
+// getTransactionsByLedgerKey fetches the transactions which changed the requested ledger entry
+// between the start and end ledgers, inclusive of both, through the ledger key index.
+func (h transactionsByLedgerKeyRPCHandler) getTransactionsByLedgerKey(ctx context.Context,
+	request GetTransactionsByLedgerKeyRequest,
+) (GetTransactionsResponse, error) {
+	ledgerRange, err := h.ledgerReader.GetLedgerRange(ctx)
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	if err := request.isValid(h.maxLimit, ledgerRange); err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: err.Error(),
+		}
+	}
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(request.LedgerKey, &key); err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: "cannot unmarshal ledgerKey: " + err.Error(),
+		}
+	}
+
+	start, limit, err := h.initializePagination(GetTransactionsRequest{
+		StartLedger: request.StartLedger,
+		Pagination:  request.Pagination,
+	})
+	if err != nil {
+		return GetTransactionsResponse{}, err
+	}
+	endLedger := ledgerRange.LastLedger.Sequence
+	if request.EndLedger != 0 {
+		endLedger = min(request.EndLedger, endLedger)
+	}
+
+	locations, err := h.keyReader.GetTransactionsByLedgerKey(ctx, key,
+		uint32(start.LedgerSequence), start.TransactionOrder, endLedger, limit)
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	return h.transactionsAtLocations(ctx, locations, limit, endLedger, ledgerRange, request.Format,
+		request.CompressEvents)
+}
+
+func NewGetTransactionsByLedgerKeyHandler(logger *log.Entry, ledgerReader db.LedgerReader,
+	keyReader db.LedgerKeyTransactionReader, maxLimit, defaultLimit uint, networkPassphrase string,
+	denylist *txdenylist.Denylist, maxEventsPerTransaction uint, jsonConverter *JSONConverter,
+) jrpc2.Handler {
+	keyHandler := transactionsByLedgerKeyRPCHandler{
+		transactionsRPCHandler: transactionsRPCHandler{
+			ledgerReader:            ledgerReader,
+			jsonConverter:           jsonConverter,
+			denylist:                denylist,
+			maxEventsPerTransaction: maxEventsPerTransaction,
+			maxLimit:                maxLimit,
+			defaultLimit:            defaultLimit,
+			logger:                  logger,
+			networkPassphrase:       networkPassphrase,
+		},
+		keyReader: keyReader,
+	}
+
+	return handler.New(keyHandler.getTransactionsByLedgerKey)
+}
@@ -0,0 +1,107 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/toid"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// ledgerKeyIndex is an in-memory db.LedgerKeyTransactionReader indexing the transactions of a
+// single ledger key.
+type ledgerKeyIndex struct {
+	key       xdr.LedgerKey
+	locations []db.TransactionLocation
+}
+
+func (m ledgerKeyIndex) GetTransactionsByLedgerKey(_ context.Context, key xdr.LedgerKey,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]db.TransactionLocation, error) {
+	if !key.Equals(m.key) {
+		return nil, nil
+	}
+	return memoIndex{locations: m.locations}.GetTransactionsByMemo(context.TODO(), 0, nil,
+		startLedger, startOrder, endLedger, limit)
+}
+
+func TestGetTransactionsByLedgerKey(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(uint32(i))))
+	}
+	var key, otherKey xdr.LedgerKey
+	require.NoError(t, key.SetAccount(xdr.MustAddress(keypair.MustRandom().Address())))
+	require.NoError(t, otherKey.SetAccount(xdr.MustAddress(keypair.MustRandom().Address())))
+	index := ledgerKeyIndex{
+		key: key,
+		locations: []db.TransactionLocation{
+			{Ledger: 2, ApplicationOrder: 1},
+			{Ledger: 3, ApplicationOrder: 2},
+			{Ledger: 6, ApplicationOrder: 1},
+		},
+	}
+	handler := transactionsByLedgerKeyRPCHandler{
+		transactionsRPCHandler: transactionsRPCHandler{
+			ledgerReader:      db.NewMockLedgerReader(store),
+			maxLimit:          100,
+			defaultLimit:      10,
+			networkPassphrase: NetworkPassphrase,
+		},
+		keyReader: index,
+	}
+	keyXDR, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	getPage := func(request GetTransactionsByLedgerKeyRequest) ([]db.TransactionLocation, string) {
+		response, err := handler.getTransactionsByLedgerKey(context.TODO(), request)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(10), response.LatestLedger)
+		var locations []db.TransactionLocation
+		for _, tx := range response.Transactions {
+			locations = append(locations, db.TransactionLocation{Ledger: tx.Ledger, ApplicationOrder: tx.ApplicationOrder})
+		}
+		return locations, response.Cursor
+	}
+
+	request := GetTransactionsByLedgerKeyRequest{
+		LedgerKey:   keyXDR,
+		StartLedger: 1,
+		Pagination:  &TransactionsPaginationOptions{Limit: 2},
+	}
+	locations, cursor := getPage(request)
+	assert.Equal(t, index.locations[:2], locations)
+	assert.Equal(t, toid.New(3, 2, 1).String(), cursor)
+
+	request.StartLedger = 0
+	request.Pagination.Cursor = cursor
+	locations, cursor = getPage(request)
+	assert.Equal(t, index.locations[2:], locations)
+	assert.Equal(t, toid.AfterLedger(10).String(), cursor)
+
+	// the end ledger bounds the results
+	locations, cursor = getPage(GetTransactionsByLedgerKeyRequest{LedgerKey: keyXDR, StartLedger: 3, EndLedger: 5})
+	assert.Equal(t, index.locations[1:2], locations)
+	assert.Equal(t, toid.AfterLedger(5).String(), cursor)
+
+	otherKeyXDR, err := xdr.MarshalBase64(otherKey)
+	require.NoError(t, err)
+	locations, _ = getPage(GetTransactionsByLedgerKeyRequest{LedgerKey: otherKeyXDR, StartLedger: 1})
+	assert.Empty(t, locations)
+
+	_, err = handler.getTransactionsByLedgerKey(context.TODO(),
+		GetTransactionsByLedgerKeyRequest{LedgerKey: "not a key", StartLedger: 1})
+	var jrpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	_, err = handler.getTransactionsByLedgerKey(context.TODO(), GetTransactionsByLedgerKeyRequest{
+		LedgerKey: keyXDR, StartLedger: 5, EndLedger: 4,
+	})
+	require.ErrorContains(t, err, "endLedger must not be lower than startLedger")
+}
@@ -0,0 +1,245 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/toid"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/txdenylist"
+)
+
+// Memo types accepted by getTransactionsByMemo.
+const (
+	MemoTypeText   = "text"
+	MemoTypeID     = "id"
+	MemoTypeHash   = "hash"
+	MemoTypeReturn = "return"
+)
+
+// maxMemoTextLength is the maximum length of text memos, in bytes.
+const maxMemoTextLength = 28
+
+// GetTransactionsByMemoRequest represents the request parameters for fetching the transactions
+// with a given memo within a range of ledgers.
+type GetTransactionsByMemoRequest struct {
+	// MemoType is one of: MemoTypeText, MemoTypeID, MemoTypeHash, MemoTypeReturn.
+	MemoType string `json:"memoType"`
+	// MemoValue is the text of text memos, the decimal ID of ID memos and the hex (or base64)
+	// encoded hash of hash and return memos.
+	MemoValue   string `json:"memoValue"`
+	StartLedger uint32 `json:"startLedger,omitempty"`
+	// EndLedger defaults to the latest ledger.
+	EndLedger  uint32                         `json:"endLedger,omitempty"`
+	Pagination *TransactionsPaginationOptions `json:"pagination,omitempty"`
+	Format     string                         `json:"xdrFormat,omitempty"`
+	// CompressEvents is the same as the one of GetTransactionsRequest.
+	CompressEvents string `json:"compressEvents,omitempty"`
+}
+
+// parseMemo returns the memo of the request, as it is stored (see db.EncodeMemo).
+func (req GetTransactionsByMemoRequest) parseMemo() (xdr.MemoType, []byte, error) {
+	var memo xdr.Memo
+	switch req.MemoType {
+	case MemoTypeText:
+		if req.MemoValue == "" || len(req.MemoValue) > maxMemoTextLength {
+			return 0, nil, fmt.Errorf("text memos must be between 1 and %d bytes long", maxMemoTextLength)
+		}
+		memo = xdr.MemoText(req.MemoValue)
+	case MemoTypeID:
+		id, err := strconv.ParseUint(req.MemoValue, 10, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("incorrect id memo: %w", err)
+		}
+		memo = xdr.MemoID(id)
+	case MemoTypeHash, MemoTypeReturn:
+		hash, err := parseTransactionHash(req.MemoValue)
+		if err != nil {
+			return 0, nil, fmt.Errorf("incorrect %s memo: %w", req.MemoType, err)
+		}
+		if req.MemoType == MemoTypeHash {
+			memo = xdr.MemoHash(hash)
+		} else {
+			memo = xdr.MemoRetHash(hash)
+		}
+	default:
+		return 0, nil, fmt.Errorf("memo type must be one of %q, %q, %q or %q",
+			MemoTypeText, MemoTypeID, MemoTypeHash, MemoTypeReturn)
+	}
+	memoType, memoValue := db.EncodeMemo(memo)
+	return memoType, memoValue, nil
+}
+
+// isValid checks the validity of the request parameters.
+func (req GetTransactionsByMemoRequest) isValid(maxLimit uint, ledgerRange ledgerbucketwindow.LedgerRange) error {
+	if err := (GetTransactionsRequest{
+		StartLedger:    req.StartLedger,
+		Pagination:     req.Pagination,
+		Format:         req.Format,
+		CompressEvents: req.CompressEvents,
+	}).isValid(maxLimit, ledgerRange); err != nil {
+		return err
+	}
+	if req.EndLedger != 0 && req.StartLedger > req.EndLedger {
+		return errors.New("endLedger must not be lower than startLedger")
+	}
+	return nil
+}
+
+type transactionsByMemoRPCHandler struct {
+	transactionsRPCHandler
+	memoReader db.TransactionMemoReader
+}
+
+// readTransaction reads the transaction at the given location of a ledger.
+func (h transactionsRPCHandler) readTransaction(ledger xdr.LedgerCloseMeta,
+	location db.TransactionLocation,
+) (db.Transaction, error) {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(h.networkPassphrase, ledger)
+	if err != nil {
+		return db.Transaction{}, err
+	}
+	if err := reader.Seek(int(location.ApplicationOrder) - 1); err != nil {
+		return db.Transaction{}, err
+	}
+	ingestTx, err := reader.Read()
+	if err != nil {
+		return db.Transaction{}, err
+	}
+	return db.ParseTransaction(ledger, ingestTx)
+}
+
+// transactionsAtLocations returns the page of transactions at the given locations, found by an
+// index lookup of at most limit locations up to endLedger.
+func (h transactionsRPCHandler) transactionsAtLocations(ctx context.Context, locations []db.TransactionLocation,
+	limit uint, endLedger uint32, ledgerRange ledgerbucketwindow.LedgerRange, format string, compressEvents string,
+) (GetTransactionsResponse, error) {
+	var txs []db.Transaction
+	var ledger xdr.LedgerCloseMeta
+	var ledgerSeq uint32
+	var err error
+	for _, location := range locations {
+		if ledgerSeq != location.Ledger {
+			ledgerSeq = location.Ledger
+			if ledger, err = h.fetchLedgerData(ctx, location.Ledger); err != nil {
+				return GetTransactionsResponse{}, err
+			}
+		}
+		tx, err := h.readTransaction(ledger, location)
+		if err != nil {
+			return GetTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		if h.denylist.IsDenied(tx.TransactionHash) {
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	var txns []TransactionInfo
+	if len(txs) > 0 {
+		if txns, err = h.newTransactionInfos(ctx, txs, format, compressEvents); err != nil {
+			return GetTransactionsResponse{}, err
+		}
+	}
+
+	// a partial page means there are no more matches up to the end ledger
+	cursor := toid.AfterLedger(int32(endLedger))
+	if len(locations) > 0 && len(locations) >= int(limit) {
+		last := locations[len(locations)-1]
+		cursor = toid.New(int32(last.Ledger), last.ApplicationOrder, 1)
+	}
+
+	return GetTransactionsResponse{
+		Transactions:          txns,
+		LatestLedger:          ledgerRange.LastLedger.Sequence,
+		LatestLedgerCloseTime: ledgerRange.LastLedger.CloseTime,
+		OldestLedger:          ledgerRange.FirstLedger.Sequence,
+		OldestLedgerCloseTime: ledgerRange.FirstLedger.CloseTime,
+		Cursor:                cursor.String(),
+	}, nil
+}
+
+// getTransactionsByMemo fetches the transactions with the requested memo between the start and
+// end ledgers, inclusive of both, through the memo index.
+func (h transactionsByMemoRPCHandler) getTransactionsByMemo(ctx context.Context,
+	request GetTransactionsByMemoRequest,
+) (GetTransactionsResponse, error) {
+	ledgerRange, err := h.ledgerReader.GetLedgerRange(ctx)
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	if err := request.isValid(h.maxLimit, ledgerRange); err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: err.Error(),
+		}
+	}
+	memoType, memoValue, err := request.parseMemo()
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	start, limit, err := h.initializePagination(GetTransactionsRequest{
+		StartLedger: request.StartLedger,
+		Pagination:  request.Pagination,
+	})
+	if err != nil {
+		return GetTransactionsResponse{}, err
+	}
+	endLedger := ledgerRange.LastLedger.Sequence
+	if request.EndLedger != 0 {
+		endLedger = min(request.EndLedger, endLedger)
+	}
+
+	locations, err := h.memoReader.GetTransactionsByMemo(ctx, memoType, memoValue,
+		uint32(start.LedgerSequence), start.TransactionOrder, endLedger, limit)
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	return h.transactionsAtLocations(ctx, locations, limit, endLedger, ledgerRange, request.Format,
+		request.CompressEvents)
+}
+
+func NewGetTransactionsByMemoHandler(logger *log.Entry, ledgerReader db.LedgerReader,
+	memoReader db.TransactionMemoReader, maxLimit, defaultLimit uint, networkPassphrase string,
+	denylist *txdenylist.Denylist, maxEventsPerTransaction uint, jsonConverter *JSONConverter,
+) jrpc2.Handler {
+	memoHandler := transactionsByMemoRPCHandler{
+		transactionsRPCHandler: transactionsRPCHandler{
+			ledgerReader:            ledgerReader,
+			jsonConverter:           jsonConverter,
+			denylist:                denylist,
+			maxEventsPerTransaction: maxEventsPerTransaction,
+			maxLimit:                maxLimit,
+			defaultLimit:            defaultLimit,
+			logger:                  logger,
+			networkPassphrase:       networkPassphrase,
+		},
+		memoReader: memoReader,
+	}
+
+	return handler.New(memoHandler.getTransactionsByMemo)
+}
@@ -0,0 +1,124 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/toid"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// memoIndex is an in-memory db.TransactionMemoReader indexing the transactions of a single memo.
+type memoIndex struct {
+	memoType  xdr.MemoType
+	memoValue []byte
+	locations []db.TransactionLocation
+}
+
+func (m memoIndex) GetTransactionsByMemo(_ context.Context, memoType xdr.MemoType, memoValue []byte,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]db.TransactionLocation, error) {
+	if memoType != m.memoType || string(memoValue) != string(m.memoValue) {
+		return nil, nil
+	}
+	var result []db.TransactionLocation
+	for _, location := range m.locations {
+		if location.Ledger < startLedger || location.Ledger > endLedger ||
+			(location.Ledger == startLedger && location.ApplicationOrder < startOrder) {
+			continue
+		}
+		if len(result) == int(limit) {
+			break
+		}
+		result = append(result, location)
+	}
+	return result, nil
+}
+
+func TestGetTransactionsByMemo(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(uint32(i))))
+	}
+	memoType, memoValue := db.EncodeMemo(xdr.MemoID(42))
+	index := memoIndex{
+		memoType:  memoType,
+		memoValue: memoValue,
+		locations: []db.TransactionLocation{
+			{Ledger: 2, ApplicationOrder: 1},
+			{Ledger: 2, ApplicationOrder: 2},
+			{Ledger: 5, ApplicationOrder: 1},
+			{Ledger: 8, ApplicationOrder: 2},
+		},
+	}
+	handler := transactionsByMemoRPCHandler{
+		transactionsRPCHandler: transactionsRPCHandler{
+			ledgerReader:      db.NewMockLedgerReader(store),
+			maxLimit:          100,
+			defaultLimit:      10,
+			networkPassphrase: NetworkPassphrase,
+		},
+		memoReader: index,
+	}
+	getPage := func(request GetTransactionsByMemoRequest) ([]db.TransactionLocation, string) {
+		response, err := handler.getTransactionsByMemo(context.TODO(), request)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(10), response.LatestLedger)
+		var locations []db.TransactionLocation
+		for _, tx := range response.Transactions {
+			locations = append(locations, db.TransactionLocation{Ledger: tx.Ledger, ApplicationOrder: tx.ApplicationOrder})
+		}
+		return locations, response.Cursor
+	}
+
+	request := GetTransactionsByMemoRequest{
+		MemoType:    MemoTypeID,
+		MemoValue:   "42",
+		StartLedger: 1,
+		Pagination:  &TransactionsPaginationOptions{Limit: 3},
+	}
+	locations, cursor := getPage(request)
+	assert.Equal(t, index.locations[:3], locations)
+	assert.Equal(t, toid.New(5, 1, 1).String(), cursor)
+
+	request.StartLedger = 0
+	request.Pagination.Cursor = cursor
+	locations, cursor = getPage(request)
+	assert.Equal(t, index.locations[3:], locations)
+	assert.Equal(t, toid.AfterLedger(10).String(), cursor)
+
+	// the end ledger bounds the results
+	locations, cursor = getPage(GetTransactionsByMemoRequest{
+		MemoType:    MemoTypeID,
+		MemoValue:   "42",
+		StartLedger: 3,
+		EndLedger:   7,
+	})
+	assert.Equal(t, index.locations[2:3], locations)
+	assert.Equal(t, toid.AfterLedger(7).String(), cursor)
+
+	locations, _ = getPage(GetTransactionsByMemoRequest{MemoType: MemoTypeText, MemoValue: "42", StartLedger: 1})
+	assert.Empty(t, locations)
+
+	for _, request := range []GetTransactionsByMemoRequest{
+		{MemoType: "none", MemoValue: "42", StartLedger: 1},
+		{MemoType: MemoTypeID, MemoValue: "-1", StartLedger: 1},
+		{MemoType: MemoTypeText, MemoValue: "this memo is way too long for a text memo", StartLedger: 1},
+		{MemoType: MemoTypeHash, MemoValue: "abcd", StartLedger: 1},
+	} {
+		_, err := handler.getTransactionsByMemo(context.TODO(), request)
+		var jrpcErr *jrpc2.Error
+		require.ErrorAs(t, err, &jrpcErr)
+		assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	}
+	_, err := handler.getTransactionsByMemo(context.TODO(), GetTransactionsByMemoRequest{
+		MemoType: MemoTypeID, MemoValue: "42", StartLedger: 5, EndLedger: 4,
+	})
+	require.ErrorContains(t, err, "endLedger must not be lower than startLedger")
+}
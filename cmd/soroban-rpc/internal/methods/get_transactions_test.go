@@ -4,16 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/creachadair/jrpc2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/toid"
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/txdenylist"
 )
 
 const (
@@ -88,6 +92,82 @@ func TestGetTransactions_DefaultLimit(t *testing.T) {
 	assert.Equal(t, expectedTransactionInfo, response.Transactions[0])
 }
 
+func TestGetTransactions_IncludeTotal(t *testing.T) {
+	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
+	for i := 1; i <= 10; i++ {
+		meta := createTestLedger(uint32(i))
+		err := mockDBReader.InsertTransactions(meta)
+		require.NoError(t, err)
+	}
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		transactionReader: mockDBReader,
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+	}
+
+	response, err := handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{StartLedger: 1})
+	require.NoError(t, err)
+	assert.Nil(t, response.Total)
+
+	// every ledger has two transactions
+	response, err = handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{
+		StartLedger:  3,
+		IncludeTotal: true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, response.Total)
+	assert.Equal(t, uint64(16), *response.Total)
+	assert.Len(t, response.Transactions, 10)
+
+	// with a cursor, the total counts from the transaction following it
+	response, err = handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{
+		Pagination:   &TransactionsPaginationOptions{Cursor: toid.New(5, 1, 1).String()},
+		IncludeTotal: true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, response.Total)
+	assert.Equal(t, uint64(11), *response.Total)
+	assert.Len(t, response.Transactions, 10)
+}
+
+func TestGetTransactions_Denylist(t *testing.T) {
+	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
+	for i := 1; i <= 3; i++ {
+		meta := createTestLedger(uint32(i))
+		err := mockDBReader.InsertTransactions(meta)
+		require.NoError(t, err)
+	}
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+	}
+	response, err := handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{StartLedger: 1})
+	require.NoError(t, err)
+	require.Len(t, response.Transactions, 6)
+	deniedHash := response.Transactions[1].TransactionHash
+
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte(deniedHash+"\n"), 0o600))
+	handler.denylist, err = txdenylist.NewDenylist(path, log.DefaultLogger)
+	require.NoError(t, err)
+
+	response, err = handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{StartLedger: 1})
+	require.NoError(t, err)
+	// the test ledgers contain the denied transaction envelope twice (successful and failed)
+	require.Len(t, response.Transactions, 4)
+	for _, tx := range response.Transactions {
+		assert.NotEqual(t, deniedHash, tx.TransactionHash)
+	}
+}
+
 func TestGetTransactions_DefaultLimitExceedsLatestLedger(t *testing.T) {
 	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
 	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
@@ -207,6 +287,58 @@ func TestGetTransactions_CustomLimitAndCursor(t *testing.T) {
 	assert.Equal(t, uint32(3), response.Transactions[2].Ledger)
 }
 
+func TestGetTransactions_GroupByLedger(t *testing.T) {
+	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
+	for i := 1; i <= 10; i++ {
+		meta := createTestLedger(uint32(i))
+		meta.V1.LedgerHeader.Hash = xdr.Hash{byte(i)}
+		err := mockDBReader.InsertTransactions(meta)
+		require.NoError(t, err)
+	}
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+	}
+
+	request := GetTransactionsRequest{
+		Pagination: &TransactionsPaginationOptions{
+			Cursor: toid.New(1, 2, 1).String(),
+			Limit:  5,
+		},
+	}
+	flat, err := handler.getTransactionsByLedgerSequence(context.TODO(), request)
+	require.NoError(t, err)
+	require.Len(t, flat.Transactions, 5)
+	require.Empty(t, flat.Ledgers)
+
+	request.GroupByLedger = true
+	grouped, err := handler.getTransactionsByLedgerSequence(context.TODO(), request)
+	require.NoError(t, err)
+	require.Nil(t, grouped.Transactions)
+	require.Equal(t, flat.Cursor, grouped.Cursor)
+
+	// the page spans ledgers 2 to 4, the last one being cut short by the limit
+	require.Len(t, grouped.Ledgers, 3)
+	var regrouped []TransactionInfo
+	for i, group := range grouped.Ledgers {
+		sequence := uint32(i + 2)
+		assert.Equal(t, sequence, group.Sequence)
+		assert.Equal(t, ledgerCloseTime(sequence), group.CloseTime)
+		assert.Equal(t, xdr.Hash{byte(sequence)}.HexString(), group.Hash)
+		for j, txn := range group.Transactions {
+			assert.Equal(t, sequence, txn.Ledger)
+			assert.Equal(t, int32(j+1), txn.ApplicationOrder)
+		}
+		regrouped = append(regrouped, group.Transactions...)
+	}
+	assert.Len(t, grouped.Ledgers[2].Transactions, 1)
+	assert.Equal(t, flat.Transactions, regrouped)
+}
+
 func TestGetTransactions_InvalidStartLedger(t *testing.T) {
 	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
 	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
@@ -236,6 +368,38 @@ func TestGetTransactions_InvalidStartLedger(t *testing.T) {
 	assert.Nil(t, response.Transactions)
 }
 
+func TestGetTransactions_TrimmedCursor(t *testing.T) {
+	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
+	for i := 3; i <= 5; i++ {
+		meta := createTestLedger(uint32(i))
+		err := mockDBReader.InsertTransactions(meta)
+		require.NoError(t, err)
+	}
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+	}
+
+	request := GetTransactionsRequest{
+		Pagination: &TransactionsPaginationOptions{
+			Cursor: toid.New(1, 2, 1).String(),
+		},
+	}
+
+	response, err := handler.getTransactionsByLedgerSequence(context.TODO(), request)
+	expectedErr := fmt.Errorf(
+		"[%d] cursor points to ledger 1, which is no longer retained: "+
+			"start ledger must be between the oldest ledger: 3 and the latest ledger: 5 for this rpc instance",
+		jrpc2.InvalidParams,
+	)
+	assert.Equal(t, expectedErr.Error(), err.Error())
+	assert.Nil(t, response.Transactions)
+}
+
 func TestGetTransactions_LedgerNotFound(t *testing.T) {
 	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
 	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
@@ -361,3 +525,157 @@ func TestGetTransactions_JSONFormat(t *testing.T) {
 	require.Nilf(t, tx["resultMetaXdr"], "field: 'resultMetaXdr'")
 	require.NotNilf(t, tx["resultMetaJson"], "field: 'resultMetaJson'")
 }
+
+func TestGetTransactions_MaxResponseSize(t *testing.T) {
+	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
+	for i := 1; i <= 10; i++ {
+		meta := createTestLedger(uint32(i))
+		err := mockDBReader.InsertTransactions(meta)
+		require.NoError(t, err)
+	}
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		transactionReader: mockDBReader,
+		maxLimit:          100,
+		defaultLimit:      100,
+		networkPassphrase: NetworkPassphrase,
+	}
+	unlimited, err := handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{StartLedger: 1})
+	require.NoError(t, err)
+	require.Len(t, unlimited.Transactions, 20)
+	assert.False(t, unlimited.SizeLimited)
+	txSize, err := json.Marshal(unlimited.Transactions[0])
+	require.NoError(t, err)
+
+	// a size limit of 1 byte still returns a transaction per page
+	for _, maxSize := range []uint{uint(len(txSize)) * 5 / 2, 1} {
+		handler.maxResponseSize = maxSize
+		var paginated []TransactionInfo
+		request := GetTransactionsRequest{StartLedger: 1}
+		for {
+			response, err := handler.getTransactionsByLedgerSequence(context.TODO(), request)
+			require.NoError(t, err)
+			if len(response.Transactions) == 0 {
+				break
+			}
+			assert.LessOrEqual(t, len(response.Transactions), max(int(maxSize)/len(txSize), 1))
+			assert.Equal(t, len(paginated)+len(response.Transactions) < 20, response.SizeLimited)
+			paginated = append(paginated, response.Transactions...)
+			request = GetTransactionsRequest{Pagination: &TransactionsPaginationOptions{Cursor: response.Cursor}}
+		}
+		assert.Equal(t, unlimited.Transactions, paginated)
+	}
+}
+
+// filterIndex is an in-memory db.TransactionFilterReader indexing the transactions of a single
+// source account and operation type.
+type filterIndex struct {
+	account       xdr.AccountId
+	operationType xdr.OperationType
+	locations     []db.TransactionLocation
+}
+
+func (f filterIndex) GetTransactionsBySourceAccount(ctx context.Context, account xdr.AccountId,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]db.TransactionLocation, error) {
+	if !account.Equals(f.account) {
+		return nil, nil
+	}
+	return memoIndex{locations: f.locations}.GetTransactionsByMemo(ctx, 0, nil,
+		startLedger, startOrder, endLedger, limit)
+}
+
+func (f filterIndex) GetTransactionsByOperationType(ctx context.Context, operationType xdr.OperationType,
+	startLedger uint32, startOrder int32, endLedger uint32, limit uint,
+) ([]db.TransactionLocation, error) {
+	if operationType != f.operationType {
+		return nil, nil
+	}
+	return memoIndex{locations: f.locations}.GetTransactionsByMemo(ctx, 0, nil,
+		startLedger, startOrder, endLedger, limit)
+}
+
+func TestGetTransactions_Filters(t *testing.T) {
+	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, mockDBReader.InsertTransactions(createTestLedger(uint32(i))))
+	}
+	account := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	index := filterIndex{
+		account:       account,
+		operationType: xdr.OperationTypeInvokeHostFunction,
+		locations: []db.TransactionLocation{
+			{Ledger: 2, ApplicationOrder: 2},
+			{Ledger: 4, ApplicationOrder: 1},
+			{Ledger: 9, ApplicationOrder: 2},
+		},
+	}
+	handler := transactionsRPCHandler{
+		ledgerReader:      db.NewMockLedgerReader(mockDBReader),
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+		filterReader:      index,
+	}
+	getPage := func(request GetTransactionsRequest) ([]db.TransactionLocation, string) {
+		response, err := handler.getTransactionsByLedgerSequence(context.TODO(), request)
+		require.NoError(t, err)
+		var locations []db.TransactionLocation
+		for _, tx := range response.Transactions {
+			locations = append(locations, db.TransactionLocation{Ledger: tx.Ledger, ApplicationOrder: tx.ApplicationOrder})
+		}
+		return locations, response.Cursor
+	}
+
+	request := GetTransactionsRequest{
+		StartLedger: 1,
+		Pagination:  &TransactionsPaginationOptions{Limit: 2},
+		Filters:     &TransactionFilters{SourceAccount: account.Address()},
+	}
+	locations, cursor := getPage(request)
+	assert.Equal(t, index.locations[:2], locations)
+	assert.Equal(t, toid.New(4, 1, 1).String(), cursor)
+
+	request.StartLedger = 0
+	request.Pagination.Cursor = cursor
+	locations, cursor = getPage(request)
+	assert.Equal(t, index.locations[2:], locations)
+	assert.Equal(t, toid.AfterLedger(10).String(), cursor)
+
+	locations, _ = getPage(GetTransactionsRequest{
+		StartLedger: 3,
+		Filters:     &TransactionFilters{OperationType: "invoke_host_function"},
+	})
+	assert.Equal(t, index.locations[1:], locations)
+
+	locations, _ = getPage(GetTransactionsRequest{
+		StartLedger: 1,
+		Filters:     &TransactionFilters{OperationType: "payment"},
+	})
+	assert.Empty(t, locations)
+
+	for _, filters := range []TransactionFilters{
+		{SourceAccount: "GABC"},
+		{OperationType: "invoke"},
+	} {
+		_, err := handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{
+			StartLedger: 1,
+			Filters:     &filters,
+		})
+		var jrpcErr *jrpc2.Error
+		require.ErrorAs(t, err, &jrpcErr)
+		assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	}
+	for _, request := range []GetTransactionsRequest{
+		{StartLedger: 1, Filters: &TransactionFilters{}},
+		{StartLedger: 1, Filters: &TransactionFilters{SourceAccount: account.Address(), OperationType: "payment"}},
+		{StartLedger: 1, Filters: &TransactionFilters{OperationType: "payment"}, IncludeTotal: true},
+	} {
+		_, err := handler.getTransactionsByLedgerSequence(context.TODO(), request)
+		var jrpcErr *jrpc2.Error
+		require.ErrorAs(t, err, &jrpcErr)
+		assert.Equal(t, jrpc2.InvalidRequest, jrpcErr.Code)
+	}
+}
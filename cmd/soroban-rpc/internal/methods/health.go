@@ -2,29 +2,89 @@ package methods
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/creachadair/jrpc2"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
 )
 
+const (
+	defaultIngestionRateSince = 60
+	maxIngestionRateSince     = 3600
+)
+
+type HealthCheckRequest struct {
+	// Since is the period (in seconds) over which the ingestion rate is computed.
+	Since uint32 `json:"since,omitempty"`
+}
+
+type IngestionRateInfo struct {
+	// Since is the period (in seconds) the rate was computed over. It can be shorter
+	// than the requested one if not enough ingestion history is available.
+	Since float64 `json:"since"`
+	// LedgersIngested is the number of ledgers ingested over the period.
+	LedgersIngested uint32 `json:"ledgersIngested"`
+	// LedgersPerMinute is the ingestion rate over the period.
+	LedgersPerMinute float64 `json:"ledgersPerMinute"`
+}
+
+// TransactionStoreLagChecker reports how many ledgers the transaction store lags behind the ledger store.
+type TransactionStoreLagChecker interface {
+	Lag(ctx context.Context) (uint32, error)
+}
+
+// MaxDBSizeChecker reports whether the database exceeds its maximum size with no ledgers left to trim.
+type MaxDBSizeChecker interface {
+	Exceeded() (size uint64, maxSize uint64, exceeded bool)
+}
+
 type HealthCheckResult struct {
-	Status                string `json:"status"`
-	LatestLedger          uint32 `json:"latestLedger"`
-	OldestLedger          uint32 `json:"oldestLedger"`
-	LedgerRetentionWindow uint32 `json:"ledgerRetentionWindow"`
+	Status                string             `json:"status"`
+	LatestLedger          uint32             `json:"latestLedger"`
+	OldestLedger          uint32             `json:"oldestLedger"`
+	LedgerRetentionWindow uint32             `json:"ledgerRetentionWindow"`
+	IngestionRate         *IngestionRateInfo `json:"ingestionRate,omitempty"`
+	// DBCircuitBreaker is the state of the database read circuit breaker, when enabled.
+	DBCircuitBreaker string `json:"dbCircuitBreaker,omitempty"`
+	// TransactionStoreLag is the number of ledgers whose transactions are missing from the
+	// transaction store, when checked.
+	TransactionStoreLag *uint32 `json:"transactionStoreLag,omitempty"`
 }
 
-// NewHealthCheck returns a health check json rpc handler
+// NewHealthCheck returns a health check json rpc handler. If txStoreLagChecker is set, the
+// check fails when the transaction store lags more than maxTxStoreLag ledgers behind the ledger store.
+// If maxDBSizeChecker is set, the check fails when the database exceeds its maximum size.
 func NewHealthCheck(
 	retentionWindow uint32,
 	ledgerReader db.LedgerReader,
+	ingestionWindow *ingestionwindow.IngestionWindow,
 	maxHealthyLedgerLatency time.Duration,
+	clock util.Clock,
+	circuitBreaker *db.CircuitBreaker,
+	txStoreLagChecker TransactionStoreLagChecker,
+	maxTxStoreLag uint32,
+	maxDBSizeChecker MaxDBSizeChecker,
 ) jrpc2.Handler {
-	return NewHandler(func(ctx context.Context) (HealthCheckResult, error) {
+	return NewHandler(func(ctx context.Context, request HealthCheckRequest) (HealthCheckResult, error) {
+		if request.Since > maxIngestionRateSince {
+			return HealthCheckResult{}, jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("since must not exceed %d seconds", maxIngestionRateSince),
+			}
+		}
+
 		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if errors.Is(err, db.ErrCircuitBreakerOpen) {
+			return HealthCheckResult{}, jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
 		if err != nil || ledgerRange.LastLedger.Sequence < 1 {
 			extra := ""
 			if err != nil {
@@ -37,7 +97,8 @@ func NewHealthCheck(
 		}
 
 		lastKnownLedgerCloseTime := time.Unix(ledgerRange.LastLedger.CloseTime, 0)
-		lastKnownLedgerLatency := time.Since(lastKnownLedgerCloseTime)
+		now := clock.Now()
+		lastKnownLedgerLatency := now.Sub(lastKnownLedgerCloseTime)
 		if lastKnownLedgerLatency > maxHealthyLedgerLatency {
 			roundedLatency := lastKnownLedgerLatency.Round(time.Second)
 			msg := fmt.Sprintf("latency (%s) since last known ledger closed is too high (>%s)",
@@ -53,6 +114,47 @@ func NewHealthCheck(
 			OldestLedger:          ledgerRange.FirstLedger.Sequence,
 			LedgerRetentionWindow: retentionWindow,
 		}
+		if txStoreLagChecker != nil {
+			lag, err := txStoreLagChecker.Lag(ctx)
+			if err != nil {
+				return HealthCheckResult{}, jrpc2.Error{
+					Code:    jrpc2.InternalError,
+					Message: "could not check the transaction store: " + err.Error(),
+				}
+			}
+			if lag > maxTxStoreLag {
+				return HealthCheckResult{}, jrpc2.Error{
+					Code: jrpc2.InternalError,
+					Message: fmt.Sprintf("transaction store is lagging %d ledgers behind the ledger store (>%d)",
+						lag, maxTxStoreLag),
+				}
+			}
+			result.TransactionStoreLag = &lag
+		}
+		if maxDBSizeChecker != nil {
+			if size, maxSize, exceeded := maxDBSizeChecker.Exceeded(); exceeded {
+				return HealthCheckResult{}, jrpc2.Error{
+					Code: jrpc2.InternalError,
+					Message: fmt.Sprintf("database size (%d bytes) exceeds its maximum size (%d bytes) "+
+						"with its minimum retention window retained", size, maxSize),
+				}
+			}
+		}
+		if circuitBreaker != nil {
+			result.DBCircuitBreaker = circuitBreaker.State().String()
+		}
+		if ingestionWindow != nil {
+			since := request.Since
+			if since == 0 {
+				since = defaultIngestionRateSince
+			}
+			rate := ingestionWindow.Rate(time.Duration(since)*time.Second, now)
+			result.IngestionRate = &IngestionRateInfo{
+				Since:            rate.Window.Seconds(),
+				LedgersIngested:  rate.LedgerCount,
+				LedgersPerMinute: rate.LedgersPerMinute,
+			}
+		}
 		return result, nil
 	})
 }
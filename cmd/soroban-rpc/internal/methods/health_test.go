@@ -0,0 +1,131 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+func TestHealthCheck(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	ingestionWindow := ingestionwindow.NewIngestionWindow(ingestionwindow.DefaultRetention)
+	for i := 1; i <= 3; i++ {
+		ledger := createTestLedger(uint32(i))
+		require.NoError(t, store.InsertTransactions(ledger))
+		ingestionWindow.AppendLedger(uint32(i), ledger.LedgerCloseTime(), time.Unix(ledger.LedgerCloseTime(), 0))
+	}
+
+	// the latest ledger closed at 175
+	clock := util.NewManualClock(time.Unix(180, 0))
+	handler := NewHealthCheck(100, ledgerReader, ingestionWindow, 30*time.Second, clock, nil, nil, 0, nil)
+
+	resultI, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	result := resultI.(HealthCheckResult)
+	assert.Equal(t, "healthy", result.Status)
+	assert.Equal(t, uint32(1), result.OldestLedger)
+	assert.Equal(t, uint32(3), result.LatestLedger)
+	assert.Equal(t, uint32(100), result.LedgerRetentionWindow)
+	require.NotNil(t, result.IngestionRate)
+	assert.Equal(t, uint32(3), result.IngestionRate.LedgersIngested)
+
+	clock.Advance(time.Minute)
+	_, err = handler(context.Background(), &jrpc2.Request{})
+	require.EqualError(t, err, "[-32603] latency (1m5s) since last known ledger closed is too high (>30s)")
+}
+
+type failingLedgerReader struct {
+	db.LedgerReader
+	err error
+}
+
+func (r *failingLedgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
+	if r.err != nil {
+		return ledgerbucketwindow.LedgerRange{}, r.err
+	}
+	return r.LedgerReader.GetLedgerRange(ctx)
+}
+
+func TestHealthCheckCircuitBreaker(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	require.NoError(t, store.InsertTransactions(createTestLedger(1)))
+	clock := util.NewManualClock(time.Unix(30, 0))
+	breaker := db.NewCircuitBreaker(interfaces.MakeNoOpDeamon(), 1, time.Minute, clock)
+	reader := &failingLedgerReader{LedgerReader: db.NewMockLedgerReader(store), err: errors.New("disk I/O error")}
+	handler := NewHealthCheck(100, breaker.WrapLedgerReader(reader), nil, time.Hour, clock, breaker, nil, 0, nil)
+
+	_, err := handler(context.Background(), &jrpc2.Request{})
+	require.EqualError(t, err, "[-32603] data stores are not initialized: disk I/O error")
+	_, err = handler(context.Background(), &jrpc2.Request{})
+	require.ErrorContains(t, err, db.ErrCircuitBreakerOpen.Error())
+
+	// the database recovers and, after the cooldown, the breaker closes again
+	reader.err = nil
+	clock.Advance(time.Minute)
+	_, err = handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	resultI, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, "closed", resultI.(HealthCheckResult).DBCircuitBreaker)
+}
+
+type fixedTransactionStoreLag uint32
+
+func (l *fixedTransactionStoreLag) Lag(context.Context) (uint32, error) {
+	return uint32(*l), nil
+}
+
+func TestHealthCheckTransactionStoreLag(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	require.NoError(t, store.InsertTransactions(createTestLedger(1)))
+	clock := util.NewManualClock(time.Unix(120, 0))
+	lag := fixedTransactionStoreLag(3)
+	handler := NewHealthCheck(100, db.NewMockLedgerReader(store), nil, time.Hour, clock, nil, &lag, 5, nil)
+
+	resultI, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	result := resultI.(HealthCheckResult)
+	require.NotNil(t, result.TransactionStoreLag)
+	assert.Equal(t, uint32(3), *result.TransactionStoreLag)
+
+	lag = 6
+	_, err = handler(context.Background(), &jrpc2.Request{})
+	require.EqualError(t, err, "[-32603] transaction store is lagging 6 ledgers behind the ledger store (>5)")
+}
+
+type fixedMaxDBSizeStatus struct {
+	size     uint64
+	exceeded bool
+}
+
+func (s *fixedMaxDBSizeStatus) Exceeded() (uint64, uint64, bool) {
+	return s.size, 1000, s.exceeded
+}
+
+func TestHealthCheckMaxDBSize(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	require.NoError(t, store.InsertTransactions(createTestLedger(1)))
+	clock := util.NewManualClock(time.Unix(120, 0))
+	status := &fixedMaxDBSizeStatus{}
+	handler := NewHealthCheck(100, db.NewMockLedgerReader(store), nil, time.Hour, clock, nil, nil, 0, status)
+
+	_, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+
+	*status = fixedMaxDBSizeStatus{size: 1500, exceeded: true}
+	_, err = handler(context.Background(), &jrpc2.Request{})
+	require.EqualError(t, err,
+		"[-32603] database size (1500 bytes) exceeds its maximum size (1000 bytes) with its minimum retention window retained")
+}
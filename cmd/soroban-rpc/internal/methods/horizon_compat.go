@@ -0,0 +1,242 @@
+package methods
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stellar/go/toid"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// CompatHorizon adds a best-effort Horizon-shaped transaction object to the response,
+// easing the migration of tools built against Horizon's transaction resource.
+const CompatHorizon = "horizon"
+
+// IsValidCompat checks that compat is CompatHorizon (or empty, for the native shape only).
+func IsValidCompat(compat string) error {
+	switch compat {
+	case "", CompatHorizon:
+		return nil
+	default:
+		return fmt.Errorf("invalid compat %q (expected %q)", compat, CompatHorizon)
+	}
+}
+
+// HorizonTransaction mirrors the fields of Horizon's transaction resource
+// (https://developers.stellar.org/docs/data/horizon/api-reference/resources/transactions),
+// with the same names and encodings. The following Horizon fields are omitted, since
+// soroban-rpc doesn't store the data they require:
+//   - _links, as soroban-rpc doesn't serve the linked resources.
+//   - fee_meta_xdr, as the fee processing changes aren't stored.
+//
+// Additionally, the hash of the inner transaction of a fee-bump transaction is omitted if the
+// fee-bump transaction failed without processing its inner transaction.
+type HorizonTransaction struct {
+	ID                 string                           `json:"id"`
+	PagingToken        string                           `json:"paging_token"`
+	Successful         bool                             `json:"successful"`
+	Hash               string                           `json:"hash"`
+	Ledger             int32                            `json:"ledger"`
+	CreatedAt          string                           `json:"created_at"`
+	SourceAccount      string                           `json:"source_account"`
+	AccountMuxed       string                           `json:"account_muxed,omitempty"`
+	AccountMuxedID     uint64                           `json:"account_muxed_id,omitempty,string"`
+	SourceAccountSeq   int64                            `json:"source_account_sequence,string"`
+	FeeAccount         string                           `json:"fee_account"`
+	FeeAccountMuxed    string                           `json:"fee_account_muxed,omitempty"`
+	FeeAccountMuxedID  uint64                           `json:"fee_account_muxed_id,omitempty,string"`
+	FeeCharged         int64                            `json:"fee_charged,string"`
+	MaxFee             int64                            `json:"max_fee,string"`
+	OperationCount     int32                            `json:"operation_count"`
+	EnvelopeXDR        string                           `json:"envelope_xdr"`
+	ResultXDR          string                           `json:"result_xdr"`
+	ResultMetaXDR      string                           `json:"result_meta_xdr,omitempty"`
+	MemoType           string                           `json:"memo_type"`
+	MemoBytes          string                           `json:"memo_bytes,omitempty"`
+	Memo               string                           `json:"memo,omitempty"`
+	Signatures         []string                         `json:"signatures"`
+	ValidAfter         string                           `json:"valid_after,omitempty"`
+	ValidBefore        string                           `json:"valid_before,omitempty"`
+	Preconditions      *HorizonTransactionPreconditions `json:"preconditions,omitempty"`
+	FeeBumpTransaction *HorizonFeeBumpTransaction       `json:"fee_bump_transaction,omitempty"`
+	InnerTransaction   *HorizonInnerTransaction         `json:"inner_transaction,omitempty"`
+}
+
+// HorizonTransactionPreconditions mirrors the preconditions of Horizon's transaction resource.
+type HorizonTransactionPreconditions struct {
+	TimeBounds                  *HorizonTimeBounds   `json:"timebounds,omitempty"`
+	LedgerBounds                *HorizonLedgerBounds `json:"ledgerbounds,omitempty"`
+	MinAccountSequence          string               `json:"min_account_sequence,omitempty"`
+	MinAccountSequenceAge       string               `json:"min_account_sequence_age,omitempty"`
+	MinAccountSequenceLedgerGap uint32               `json:"min_account_sequence_ledger_gap,omitempty"`
+	ExtraSigners                []string             `json:"extra_signers,omitempty"`
+}
+
+// HorizonTimeBounds are expressed as (string) unix timestamps, like in Horizon.
+type HorizonTimeBounds struct {
+	MinTime string `json:"min_time,omitempty"`
+	MaxTime string `json:"max_time,omitempty"`
+}
+
+type HorizonLedgerBounds struct {
+	MinLedger uint32 `json:"min_ledger"`
+	MaxLedger uint32 `json:"max_ledger,omitempty"`
+}
+
+type HorizonFeeBumpTransaction struct {
+	Hash       string   `json:"hash"`
+	Signatures []string `json:"signatures"`
+}
+
+type HorizonInnerTransaction struct {
+	Hash       string   `json:"hash"`
+	Signatures []string `json:"signatures"`
+	MaxFee     int64    `json:"max_fee,string"`
+}
+
+// horizonTransaction builds the Horizon-shaped representation of a stored transaction.
+func horizonTransaction(tx db.Transaction, txHash xdr.Hash) (HorizonTransaction, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := envelope.UnmarshalBinary(tx.Envelope); err != nil {
+		return HorizonTransaction{}, err
+	}
+	var result xdr.TransactionResult
+	if err := result.UnmarshalBinary(tx.Result); err != nil {
+		return HorizonTransaction{}, err
+	}
+
+	hash := txHash.HexString()
+	htx := HorizonTransaction{
+		ID:               hash,
+		PagingToken:      toid.New(int32(tx.Ledger.Sequence), tx.ApplicationOrder, 0).String(),
+		Successful:       tx.Successful,
+		Hash:             hash,
+		Ledger:           int32(tx.Ledger.Sequence),
+		CreatedAt:        time.Unix(tx.Ledger.CloseTime, 0).UTC().Format(time.RFC3339),
+		SourceAccountSeq: envelope.SeqNum(),
+		FeeCharged:       int64(result.FeeCharged),
+		MaxFee:           int64(envelope.Fee()),
+		OperationCount:   int32(len(envelope.Operations())),
+		EnvelopeXDR:      base64.StdEncoding.EncodeToString(tx.Envelope),
+		ResultXDR:        base64.StdEncoding.EncodeToString(tx.Result),
+		ResultMetaXDR:    base64.StdEncoding.EncodeToString(tx.Meta),
+		Signatures:       horizonSignatures(envelope.Signatures()),
+		Preconditions:    horizonPreconditions(envelope),
+	}
+	sourceAccount := envelope.SourceAccount()
+	htx.SourceAccount, htx.AccountMuxed, htx.AccountMuxedID = splitMuxedAccount(sourceAccount)
+	htx.FeeAccount, htx.FeeAccountMuxed, htx.FeeAccountMuxedID = htx.SourceAccount, htx.AccountMuxed, htx.AccountMuxedID
+
+	if envelope.IsFeeBump() {
+		feeAccount := envelope.FeeBumpAccount()
+		htx.FeeAccount, htx.FeeAccountMuxed, htx.FeeAccountMuxedID = splitMuxedAccount(feeAccount)
+		htx.MaxFee = envelope.FeeBumpFee()
+		htx.Signatures = horizonSignatures(envelope.FeeBumpSignatures())
+		htx.FeeBumpTransaction = &HorizonFeeBumpTransaction{
+			Hash:       hash,
+			Signatures: htx.Signatures,
+		}
+		htx.InnerTransaction = &HorizonInnerTransaction{
+			Signatures: horizonSignatures(envelope.Signatures()),
+			MaxFee:     int64(envelope.Fee()),
+		}
+		if pair, ok := result.Result.GetInnerResultPair(); ok {
+			htx.InnerTransaction.Hash = pair.TransactionHash.HexString()
+		}
+	}
+
+	if err := setHorizonMemo(&htx, envelope.Memo()); err != nil {
+		return HorizonTransaction{}, err
+	}
+	if timeBounds := envelope.TimeBounds(); timeBounds != nil {
+		htx.ValidAfter = time.Unix(int64(timeBounds.MinTime), 0).UTC().Format(time.RFC3339)
+		if timeBounds.MaxTime != 0 {
+			htx.ValidBefore = time.Unix(int64(timeBounds.MaxTime), 0).UTC().Format(time.RFC3339)
+		}
+	}
+	return htx, nil
+}
+
+// splitMuxedAccount returns the (unmuxed) account address of a muxed account and, if the
+// account is muxed, its M-address and ID.
+func splitMuxedAccount(account xdr.MuxedAccount) (string, string, uint64) {
+	accountID := account.ToAccountId()
+	if account.Type != xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+		return accountID.Address(), "", 0
+	}
+	id, _ := account.GetId()
+	return accountID.Address(), account.Address(), id
+}
+
+func horizonSignatures(signatures []xdr.DecoratedSignature) []string {
+	encoded := make([]string, 0, len(signatures))
+	for _, signature := range signatures {
+		encoded = append(encoded, base64.StdEncoding.EncodeToString(signature.Signature))
+	}
+	return encoded
+}
+
+// setHorizonMemo fills in the memo fields following Horizon's encoding: text memos are
+// returned as a string (and their raw bytes base64-encoded in MemoBytes), ID memos as a
+// decimal string and hash memos base64-encoded.
+func setHorizonMemo(htx *HorizonTransaction, memo xdr.Memo) error {
+	switch memo.Type {
+	case xdr.MemoTypeMemoNone:
+		htx.MemoType = "none"
+	case xdr.MemoTypeMemoText:
+		htx.MemoType = "text"
+		htx.Memo = memo.MustText()
+		htx.MemoBytes = base64.StdEncoding.EncodeToString([]byte(memo.MustText()))
+	case xdr.MemoTypeMemoId:
+		htx.MemoType = "id"
+		htx.Memo = strconv.FormatUint(uint64(memo.MustId()), 10)
+	case xdr.MemoTypeMemoHash:
+		hash := memo.MustHash()
+		htx.MemoType = "hash"
+		htx.Memo = base64.StdEncoding.EncodeToString(hash[:])
+	case xdr.MemoTypeMemoReturn:
+		hash := memo.MustRetHash()
+		htx.MemoType = "return"
+		htx.Memo = base64.StdEncoding.EncodeToString(hash[:])
+	default:
+		return fmt.Errorf("unknown memo type %v", memo.Type)
+	}
+	return nil
+}
+
+// horizonPreconditions converts the preconditions of the transaction to Horizon's shape.
+func horizonPreconditions(envelope xdr.TransactionEnvelope) *HorizonTransactionPreconditions {
+	preconditions := transactionPreconditions(envelope)
+	if preconditions == nil {
+		return nil
+	}
+	converted := HorizonTransactionPreconditions{
+		MinAccountSequenceLedgerGap: preconditions.MinSeqLedgerGap,
+		ExtraSigners:                preconditions.ExtraSigners,
+	}
+	if preconditions.TimeBounds != nil {
+		converted.TimeBounds = &HorizonTimeBounds{
+			MinTime: strconv.FormatUint(preconditions.TimeBounds.MinTime, 10),
+		}
+		if preconditions.TimeBounds.MaxTime != 0 {
+			converted.TimeBounds.MaxTime = strconv.FormatUint(preconditions.TimeBounds.MaxTime, 10)
+		}
+	}
+	if preconditions.LedgerBounds != nil {
+		converted.LedgerBounds = &HorizonLedgerBounds{
+			MinLedger: preconditions.LedgerBounds.MinLedger,
+			MaxLedger: preconditions.LedgerBounds.MaxLedger,
+		}
+	}
+	if preconditions.MinSeqNum != nil {
+		converted.MinAccountSequence = strconv.FormatInt(*preconditions.MinSeqNum, 10)
+	}
+	if preconditions.MinSeqAge != 0 {
+		converted.MinAccountSequenceAge = strconv.FormatUint(preconditions.MinSeqAge, 10)
+	}
+	return &converted
+}
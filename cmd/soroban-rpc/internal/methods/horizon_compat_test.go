@@ -0,0 +1,108 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/toid"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// horizonTransactionFields are the top-level fields of Horizon's transaction resource
+// (see protocols/horizon.Transaction), excluding the omitted _links and fee_meta_xdr.
+var horizonTransactionFields = []string{
+	"id", "paging_token", "successful", "hash", "ledger", "created_at",
+	"source_account", "account_muxed", "account_muxed_id", "source_account_sequence",
+	"fee_account", "fee_account_muxed", "fee_account_muxed_id", "fee_charged", "max_fee",
+	"operation_count", "envelope_xdr", "result_xdr", "result_meta_xdr", "memo_type",
+	"memo_bytes", "memo", "signatures", "valid_after", "valid_before", "preconditions",
+	"fee_bump_transaction", "inner_transaction",
+}
+
+func TestGetTransaction_HorizonCompat(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	meta := txMeta(1, true)
+	require.NoError(t, store.InsertTransactions(meta))
+	hash := txHash(1)
+
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString()})
+	require.NoError(t, err)
+	require.Nil(t, tx.Horizon)
+
+	_, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString(), Compat: "sep"})
+	require.Error(t, err)
+
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString(), Compat: CompatHorizon})
+	require.NoError(t, err)
+	require.NotNil(t, tx.Horizon)
+	// the native shape is kept
+	require.NotEmpty(t, tx.EnvelopeXDR)
+
+	encoded, err := json.Marshal(tx.Horizon)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	for field := range fields {
+		require.Contains(t, horizonTransactionFields, field)
+	}
+	for _, field := range []string{
+		"id", "paging_token", "successful", "hash", "ledger", "created_at", "source_account",
+		"account_muxed", "account_muxed_id", "source_account_sequence", "fee_account",
+		"fee_charged", "max_fee", "operation_count", "envelope_xdr", "result_xdr",
+		"result_meta_xdr", "memo_type", "signatures",
+	} {
+		require.Contains(t, fields, field)
+	}
+
+	require.Equal(t, hash.HexString(), tx.Horizon.ID)
+	require.Equal(t, toid.New(int32(tx.Ledger), tx.ApplicationOrder, 0).String(), tx.Horizon.PagingToken)
+	require.True(t, tx.Horizon.Successful)
+	require.Equal(t, int32(tx.Ledger), tx.Horizon.Ledger)
+	require.Equal(t, time.Unix(tx.LedgerCloseTime, 0).UTC().Format(time.RFC3339), tx.Horizon.CreatedAt)
+	require.Equal(t, txSourceAccount, tx.Horizon.AccountMuxed)
+	sourceAccount := xdr.MustMuxedAddress(txSourceAccount).ToAccountId()
+	require.Equal(t, sourceAccount.Address(), tx.Horizon.SourceAccount)
+	require.Equal(t, tx.Horizon.SourceAccount, tx.Horizon.FeeAccount)
+	require.Equal(t, int64(1), tx.Horizon.SourceAccountSeq)
+	require.Equal(t, int64(100), tx.Horizon.FeeCharged)
+	require.Equal(t, int64(1), tx.Horizon.MaxFee)
+	require.Equal(t, "none", tx.Horizon.MemoType)
+	require.Equal(t, tx.EnvelopeXDR, tx.Horizon.EnvelopeXDR)
+	require.Equal(t, tx.ResultXDR, tx.Horizon.ResultXDR)
+	require.Equal(t, `[]`, string(fields["signatures"]))
+}
+
+func TestSetHorizonMemo(t *testing.T) {
+	text := "hello"
+	id := xdr.Uint64(42)
+	hash := xdr.Hash{0x1, 0x2}
+	for _, tc := range []struct {
+		memo         xdr.Memo
+		expectedType string
+		expected     string
+		expectedRaw  string
+	}{
+		{xdr.Memo{Type: xdr.MemoTypeMemoNone}, "none", "", ""},
+		{xdr.Memo{Type: xdr.MemoTypeMemoText, Text: &text}, "text", "hello", "aGVsbG8="},
+		{xdr.Memo{Type: xdr.MemoTypeMemoId, Id: &id}, "id", "42", ""},
+		{xdr.Memo{Type: xdr.MemoTypeMemoHash, Hash: &hash}, "hash", "AQIAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", ""},
+		{xdr.Memo{Type: xdr.MemoTypeMemoReturn, RetHash: &hash}, "return", "AQIAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", ""},
+	} {
+		var htx HorizonTransaction
+		require.NoError(t, setHorizonMemo(&htx, tc.memo))
+		require.Equal(t, tc.expectedType, htx.MemoType)
+		require.Equal(t, tc.expected, htx.Memo)
+		require.Equal(t, tc.expectedRaw, htx.MemoBytes)
+	}
+}
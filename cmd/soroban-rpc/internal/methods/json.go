@@ -1,9 +1,12 @@
 package methods
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/creachadair/jrpc2"
 	"github.com/pkg/errors"
 
 	"github.com/stellar/go/xdr"
@@ -32,6 +35,41 @@ func IsValidFormat(format string) error {
 	return nil
 }
 
+// WithDefaultFormat returns a handler applying the given format to the requests
+// which don't set xdrFormat. It returns the handler as is if format is empty.
+func WithDefaultFormat(handler jrpc2.Handler, format string) jrpc2.Handler {
+	if format == "" {
+		return handler
+	}
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		return handler(ctx, withDefaultFormat(request, format))
+	}
+}
+
+func withDefaultFormat(request *jrpc2.Request, format string) *jrpc2.Request {
+	params := map[string]json.RawMessage{}
+	if request.HasParams() {
+		// leave malformed params for the handler to reject
+		if err := json.Unmarshal([]byte(request.ParamString()), &params); err != nil || params == nil {
+			return request
+		}
+	}
+	if _, ok := params["xdrFormat"]; ok {
+		return request
+	}
+	encodedFormat, err := json.Marshal(format)
+	if err != nil {
+		return request
+	}
+	params["xdrFormat"] = encodedFormat
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return request
+	}
+	parsed := jrpc2.ParsedRequest{ID: request.ID(), Method: request.Method(), Params: encodedParams}
+	return parsed.ToRequest()
+}
+
 func transactionToJSON(tx db.Transaction) (
 	[]byte,
 	[]byte,
@@ -0,0 +1,93 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+// ErrJSONConversionTimeout is returned for the elements of a batch whose JSON conversion took
+// longer than the conversion timeout.
+var ErrJSONConversionTimeout = errors.New("JSON conversion timed out")
+
+// JSONConverter runs the XDR to JSON conversions of batch responses (e.g. the transactions of
+// getTransactions) on a bounded pool of workers, with a timeout per conversion, so that a
+// single huge element neither monopolizes the CPU nor blocks the rest of the batch.
+type JSONConverter struct {
+	workers chan struct{}
+	timeout time.Duration
+	clock   util.Clock
+}
+
+// NewJSONConverter returns a JSONConverter running up to workers conversions at a time.
+// A zero timeout disables the conversion timeout, which is measured by clock.
+func NewJSONConverter(workers uint, timeout time.Duration, clock util.Clock) *JSONConverter {
+	return &JSONConverter{
+		workers: make(chan struct{}, max(workers, 1)),
+		timeout: timeout,
+		clock:   clock,
+	}
+}
+
+type conversionResult[T any] struct {
+	value T
+	err   error
+}
+
+// convertOne runs a single conversion on a worker. Conversions can't be interrupted, so a
+// conversion which timed out keeps its worker until it completes, and its result is discarded.
+func convertOne[T any](ctx context.Context, c *JSONConverter, convert func() (T, error)) (T, error) {
+	var zero T
+	select {
+	case c.workers <- struct{}{}:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+	done := make(chan conversionResult[T], 1)
+	go func() {
+		defer func() { <-c.workers }()
+		value, err := convert()
+		done <- conversionResult[T]{value: value, err: err}
+	}()
+
+	var timeout <-chan time.Time
+	if c.timeout > 0 {
+		timer := c.clock.NewTimer(c.timeout)
+		defer timer.Stop()
+		timeout = timer.C()
+	}
+	select {
+	case result := <-done:
+		return result.value, result.err
+	case <-timeout:
+		return zero, ErrJSONConversionTimeout
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// convertAll converts the n elements of a batch, concurrently if c isn't nil, and returns
+// their values and errors (ErrJSONConversionTimeout for the elements which timed out).
+func convertAll[T any](ctx context.Context, c *JSONConverter, n int, convert func(i int) (T, error)) ([]T, []error) {
+	values := make([]T, n)
+	errs := make([]error, n)
+	if c == nil {
+		for i := range n {
+			values[i], errs[i] = convert(i)
+		}
+		return values, errs
+	}
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = convertOne(ctx, c, func() (T, error) { return convert(i) })
+		}()
+	}
+	wg.Wait()
+	return values, errs
+}
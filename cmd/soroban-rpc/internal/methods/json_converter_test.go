@@ -0,0 +1,74 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+func TestConvertOneTimesOutSlowConversions(t *testing.T) {
+	clock := util.NewManualClock(time.Unix(1000, 0))
+	converter := NewJSONConverter(2, 50*time.Millisecond, clock)
+	release := make(chan struct{})
+	defer close(release)
+
+	converted := make(chan error)
+	go func() {
+		_, err := convertOne(context.Background(), converter, func() (int, error) {
+			// an artificially slow conversion, which only completes at the end of the test
+			<-release
+			return 0, nil
+		})
+		converted <- err
+	}()
+	require.Eventually(t, func() bool { return clock.Timers() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(49 * time.Millisecond)
+	assert.Equal(t, 1, clock.Timers())
+	clock.Advance(time.Millisecond)
+	require.ErrorIs(t, <-converted, ErrJSONConversionTimeout)
+}
+
+func TestConvertAll(t *testing.T) {
+	clock := util.NewManualClock(time.Unix(1000, 0))
+	converter := NewJSONConverter(2, 50*time.Millisecond, clock)
+
+	errConversion := errors.New("conversion failed")
+	values, errs := convertAll(context.Background(), converter, 3, func(i int) (int, error) {
+		if i == 1 {
+			return 0, errConversion
+		}
+		return i * 10, nil
+	})
+	assert.Equal(t, []int{0, 0, 20}, values)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], errConversion)
+	require.NoError(t, errs[2])
+	// the timers of the completed conversions are stopped
+	assert.Zero(t, clock.Timers())
+}
+
+func TestConvertAllBoundsWorkers(t *testing.T) {
+	converter := NewJSONConverter(1, 0, util.RealClock{})
+	running := make(chan struct{}, 4)
+	_, errs := convertAll(context.Background(), converter, 4, func(int) (int, error) {
+		running <- struct{}{}
+		defer func() { <-running }()
+		assert.Len(t, running, 1)
+		time.Sleep(time.Millisecond)
+		return 0, nil
+	})
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, errs = convertAll(ctx, converter, 1, func(int) (int, error) { return 0, nil })
+	require.ErrorIs(t, errs[0], context.Canceled)
+}
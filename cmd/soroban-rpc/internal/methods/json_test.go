@@ -0,0 +1,42 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDefaultFormat(t *testing.T) {
+	type request struct {
+		Hash   string `json:"hash"`
+		Format string `json:"xdrFormat,omitempty"`
+	}
+	handler := NewHandler(func(_ context.Context, r request) (request, error) {
+		return r, nil
+	})
+
+	call := func(h jrpc2.Handler, params string) request {
+		msg := `{"jsonrpc":"2.0","id":1,"method":"test"`
+		if params != "" {
+			msg += `,"params":` + params
+		}
+		parsed, err := jrpc2.ParseRequests([]byte(msg + "}"))
+		require.NoError(t, err)
+		result, err := h(context.Background(), parsed[0].ToRequest())
+		require.NoError(t, err)
+		return result.(request) //nolint:forcetypeassert
+	}
+
+	// without a default format the requests are left untouched
+	assert.Equal(t, request{Hash: "abc"}, call(WithDefaultFormat(handler, ""), `{"hash":"abc"}`))
+
+	withDefault := WithDefaultFormat(handler, FormatJSON)
+	assert.Equal(t, request{Hash: "abc", Format: FormatJSON}, call(withDefault, `{"hash":"abc"}`))
+	assert.Equal(t, request{Format: FormatJSON}, call(withDefault, ""))
+	// requests can override the default
+	assert.Equal(t, request{Hash: "abc", Format: FormatBase64},
+		call(withDefault, `{"hash":"abc","xdrFormat":"base64"}`))
+}
@@ -0,0 +1,69 @@
+package methods
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionOperation is an operation of a transaction with its effective source account.
+type TransactionOperation struct {
+	// Index is the (zero-based) index of the operation in the transaction.
+	Index int `json:"index"`
+	// Type is the snake_case type of the operation, e.g. invoke_host_function.
+	Type string `json:"type"`
+	// SourceAccount is the (unmuxed) account address of the effective source of the operation:
+	// the source of the operation if set, otherwise the source of the transaction (of the inner
+	// transaction for fee-bump transactions).
+	SourceAccount string `json:"sourceAccount"`
+	// SourceAccountMuxed and SourceAccountMuxedID are only present if the source is a muxed account.
+	SourceAccountMuxed   string `json:"sourceAccountMuxed,omitempty"`
+	SourceAccountMuxedID uint64 `json:"sourceAccountMuxedId,omitempty,string"`
+	// InheritedSource indicates that the operation has no source of its own, so that its
+	// source is the source of the transaction.
+	InheritedSource bool `json:"inheritedSource,omitempty"`
+}
+
+// transactionOperations returns the operations of the transaction with their effective source accounts.
+func transactionOperations(envelope xdr.TransactionEnvelope) []TransactionOperation {
+	txSource := envelope.SourceAccount()
+	operations := envelope.Operations()
+	result := make([]TransactionOperation, 0, len(operations))
+	for i, op := range operations {
+		source := txSource
+		if op.SourceAccount != nil {
+			source = *op.SourceAccount
+		}
+		operation := TransactionOperation{
+			Index:           i,
+			Type:            operationTypeName(op.Body.Type),
+			InheritedSource: op.SourceAccount == nil,
+		}
+		operation.SourceAccount, operation.SourceAccountMuxed, operation.SourceAccountMuxedID = splitMuxedAccount(source)
+		result = append(result, operation)
+	}
+	return result
+}
+
+// operationTypeName converts the XDR name of an operation type to snake_case,
+// e.g. OperationTypeInvokeHostFunction to invoke_host_function.
+func operationTypeName(opType xdr.OperationType) string {
+	return toSnakeCase(strings.TrimPrefix(opType.String(), "OperationType"))
+}
+
+// toSnakeCase converts a CamelCase name to snake_case, e.g. InvokeHostFunction to
+// invoke_host_function.
+func toSnakeCase(name string) string {
+	var converted strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				converted.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		converted.WriteRune(r)
+	}
+	return converted.String()
+}
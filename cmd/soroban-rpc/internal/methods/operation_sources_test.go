@@ -0,0 +1,73 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestTransactionOperations(t *testing.T) {
+	txSource := keypair.MustRandom().Address()
+	opSource := keypair.MustRandom().Address()
+	muxedOpSource := xdr.MustMuxedAddress(txSourceAccount)
+	opSourceAccount := xdr.MustMuxedAddress(opSource)
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress(txSource),
+				Operations: []xdr.Operation{
+					{Body: xdr.OperationBody{Type: xdr.OperationTypeBumpSequence, BumpSequenceOp: &xdr.BumpSequenceOp{}}},
+					{
+						SourceAccount: &opSourceAccount,
+						Body:          xdr.OperationBody{Type: xdr.OperationTypeInflation},
+					},
+					{
+						SourceAccount: &muxedOpSource,
+						Body:          xdr.OperationBody{Type: xdr.OperationTypeInflation},
+					},
+				},
+			},
+		},
+	}
+
+	muxedAccountID := muxedOpSource.ToAccountId()
+	muxedID, err := muxedOpSource.GetId()
+	require.NoError(t, err)
+	expected := []TransactionOperation{
+		{Index: 0, Type: "bump_sequence", SourceAccount: txSource, InheritedSource: true},
+		{Index: 1, Type: "inflation", SourceAccount: opSource},
+		{
+			Index:                2,
+			Type:                 "inflation",
+			SourceAccount:        muxedAccountID.Address(),
+			SourceAccountMuxed:   txSourceAccount,
+			SourceAccountMuxedID: muxedID,
+		},
+	}
+	require.Equal(t, expected, transactionOperations(envelope))
+
+	// the operations of fee-bump transactions inherit the source of the inner transaction
+	feeBumpEnvelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   envelope.V1,
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, transactionOperations(feeBumpEnvelope))
+}
+
+func TestOperationTypeName(t *testing.T) {
+	require.Equal(t, "invoke_host_function", operationTypeName(xdr.OperationTypeInvokeHostFunction))
+	require.Equal(t, "create_account", operationTypeName(xdr.OperationTypeCreateAccount))
+	require.Equal(t, "payment", operationTypeName(xdr.OperationTypePayment))
+}
@@ -0,0 +1,81 @@
+package methods
+
+import (
+	"encoding/json"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
+)
+
+// ReadEntry is a ledger entry of the read-only footprint of a Soroban transaction.
+type ReadEntry struct {
+	// KeyXDR is the LedgerKey XDR value of the entry.
+	KeyXDR  string          `json:"keyXdr,omitempty"`
+	KeyJSON json.RawMessage `json:"keyJson,omitempty"`
+	// ValueXDR is the LedgerEntry XDR value of the entry when it was read. It is only present if
+	// the meta records it, i.e. if the transaction changed the entry (e.g. extended its TTL).
+	ValueXDR  string          `json:"valueXdr,omitempty"`
+	ValueJSON json.RawMessage `json:"valueJson,omitempty"`
+}
+
+// readEntries returns the entries of the read-only footprint of a transaction, with their values
+// before the transaction when the meta has them.
+func readEntries(footprint xdr.LedgerFootprint, meta xdr.TransactionMeta, format string) ([]ReadEntry, error) {
+	if len(footprint.ReadOnly) == 0 {
+		return nil, nil
+	}
+	changes, err := (&ingest.LedgerTransaction{UnsafeMeta: meta}).GetChanges()
+	if err != nil {
+		return nil, err
+	}
+	// the first recorded state of each entry is its value before the transaction
+	values := map[string]*xdr.LedgerEntry{}
+	for _, change := range changes {
+		if change.Pre == nil {
+			continue
+		}
+		key, err := change.Pre.LedgerKey()
+		if err != nil {
+			return nil, err
+		}
+		keyXDR, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := values[keyXDR]; !ok {
+			values[keyXDR] = change.Pre
+		}
+	}
+
+	entries := make([]ReadEntry, 0, len(footprint.ReadOnly))
+	for _, key := range footprint.ReadOnly {
+		keyXDR, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		value := values[keyXDR]
+		var entry ReadEntry
+		switch format {
+		case FormatJSON:
+			if entry.KeyJSON, err = xdr2json.ConvertInterface(key); err != nil {
+				return nil, err
+			}
+			if value != nil {
+				if entry.ValueJSON, err = xdr2json.ConvertInterface(*value); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			entry.KeyXDR = keyXDR
+			if value != nil {
+				if entry.ValueXDR, err = xdr.MarshalBase64(*value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
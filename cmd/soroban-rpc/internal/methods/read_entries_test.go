@@ -0,0 +1,53 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+func TestReadEntries(t *testing.T) {
+	instanceKey := xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}
+	changed := contractDataEntry(xdr.Hash{1}, instanceKey)
+	changedAfter := contractDataEntry(xdr.Hash{1}, instanceKey)
+	changedAfter.LastModifiedLedgerSeq = 10
+	unchanged := contractDataEntry(xdr.Hash{2}, instanceKey)
+	keys := make([]xdr.LedgerKey, 0, 2)
+	for _, entry := range []xdr.LedgerEntry{changed, unchanged} {
+		key, err := entry.LedgerKey()
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{{
+				Changes: xdr.LedgerEntryChanges{
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &changed},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &changedAfter},
+				},
+			}},
+		},
+	}
+	entries, err := readEntries(xdr.LedgerFootprint{ReadOnly: keys}, meta, FormatBase64)
+	require.NoError(t, err)
+	expected := make([]ReadEntry, 0, 2)
+	for _, key := range keys {
+		keyXDR, err := xdr.MarshalBase64(key)
+		require.NoError(t, err)
+		expected = append(expected, ReadEntry{KeyXDR: keyXDR})
+	}
+	// the value is the one before the transaction
+	expected[0].ValueXDR, err = xdr.MarshalBase64(changed)
+	require.NoError(t, err)
+	assert.Equal(t, expected, entries)
+
+	// transactions without a read-only footprint
+	entries, err = readEntries(xdr.LedgerFootprint{ReadWrite: keys}, meta, FormatBase64)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
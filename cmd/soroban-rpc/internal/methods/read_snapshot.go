@@ -0,0 +1,32 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// WithReadSnapshot returns a handler serving each request from a read snapshot of the
+// database, so that its reads see a consistent view even if ledgers are ingested or trimmed
+// meanwhile. It returns the handler as is if snapshotter is nil.
+func WithReadSnapshot(handler jrpc2.Handler, snapshotter db.ReadSnapshotter, logger *log.Entry) jrpc2.Handler {
+	if snapshotter == nil {
+		return handler
+	}
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		snapshotCtx, release, err := snapshotter.WithReadSnapshot(ctx)
+		if err != nil {
+			logger.WithError(err).WithField("method", request.Method()).Error("could not open read snapshot")
+			return nil, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not open read snapshot",
+			}
+		}
+		defer release()
+		return handler(snapshotCtx, request)
+	}
+}
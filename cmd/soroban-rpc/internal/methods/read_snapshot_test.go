@@ -0,0 +1,50 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+)
+
+type snapshotKey struct{}
+
+type mockReadSnapshotter struct {
+	err      error
+	released int
+}
+
+func (m *mockReadSnapshotter) WithReadSnapshot(ctx context.Context) (context.Context, func(), error) {
+	if m.err != nil {
+		return ctx, nil, m.err
+	}
+	return context.WithValue(ctx, snapshotKey{}, true), func() { m.released++ }, nil
+}
+
+func TestWithReadSnapshot(t *testing.T) {
+	snapshotter := &mockReadSnapshotter{}
+	inSnapshot := func(ctx context.Context, _ *jrpc2.Request) (interface{}, error) {
+		return ctx.Value(snapshotKey{}) != nil, nil
+	}
+	request := (&jrpc2.ParsedRequest{ID: "1", Method: "getTransaction"}).ToRequest()
+
+	result, err := WithReadSnapshot(inSnapshot, snapshotter, log.DefaultLogger)(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, true, result)
+	assert.Equal(t, 1, snapshotter.released)
+
+	result, err = WithReadSnapshot(inSnapshot, nil, log.DefaultLogger)(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, false, result)
+
+	snapshotter.err = errors.New("database is locked")
+	_, err = WithReadSnapshot(inSnapshot, snapshotter, log.DefaultLogger)(context.Background(), request)
+	var jrpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.InternalError, jrpcErr.Code)
+}
@@ -0,0 +1,60 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// CacheRefresher reloads the in-memory caches of the database, returning their state
+// before and after the refresh.
+type CacheRefresher interface {
+	RefreshCaches(ctx context.Context) (db.CacheState, db.CacheState, error)
+}
+
+type CacheState struct {
+	// LatestLedger and LatestLedgerCloseTime are the cached latest ledger, omitted if not cached.
+	LatestLedger          uint32 `json:"latestLedger,omitempty"`
+	LatestLedgerCloseTime int64  `json:"latestLedgerCloseTime,string,omitempty"`
+	// LedgerEntries is the number of cached ledger entries.
+	LedgerEntries int `json:"ledgerEntries"`
+}
+
+type RefreshCachesResponse struct {
+	Before CacheState `json:"before"`
+	After  CacheState `json:"after"`
+}
+
+func newCacheState(state db.CacheState) CacheState {
+	return CacheState{
+		LatestLedger:          state.LatestLedgerSequence,
+		LatestLedgerCloseTime: state.LatestLedgerCloseTime,
+		LedgerEntries:         state.LedgerEntries,
+	}
+}
+
+// NewRefreshCachesHandler returns an admin JSON RPC handler reloading the cached state of the
+// database, for when the caches are suspected to have drifted from the database.
+func NewRefreshCachesHandler(logger *log.Entry, refresher CacheRefresher) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context) (RefreshCachesResponse, error) {
+		before, after, err := refresher.RefreshCaches(ctx)
+		if err != nil {
+			logger.WithError(err).Error("could not refresh caches")
+			return RefreshCachesResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not refresh caches",
+			}
+		}
+		if before != after {
+			logger.WithField("before", before).WithField("after", after).Warn("refreshed stale caches")
+		}
+		return RefreshCachesResponse{
+			Before: newCacheState(before),
+			After:  newCacheState(after),
+		}, nil
+	})
+}
@@ -0,0 +1,40 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type fakeCacheRefresher struct {
+	before, after db.CacheState
+	err           error
+}
+
+func (r fakeCacheRefresher) RefreshCaches(_ context.Context) (db.CacheState, db.CacheState, error) {
+	return r.before, r.after, r.err
+}
+
+func TestRefreshCaches(t *testing.T) {
+	handler := NewRefreshCachesHandler(log.DefaultLogger, fakeCacheRefresher{
+		before: db.CacheState{LatestLedgerSequence: 10, LatestLedgerCloseTime: 100, LedgerEntries: 3},
+		after:  db.CacheState{LatestLedgerSequence: 12, LatestLedgerCloseTime: 110, LedgerEntries: 2},
+	})
+	resp, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	require.Equal(t, RefreshCachesResponse{
+		Before: CacheState{LatestLedger: 10, LatestLedgerCloseTime: 100, LedgerEntries: 3},
+		After:  CacheState{LatestLedger: 12, LatestLedgerCloseTime: 110, LedgerEntries: 2},
+	}, resp)
+
+	handler = NewRefreshCachesHandler(log.DefaultLogger, fakeCacheRefresher{err: errors.New("boom")})
+	_, err = handler(context.Background(), &jrpc2.Request{})
+	require.ErrorContains(t, err, "could not refresh caches")
+}
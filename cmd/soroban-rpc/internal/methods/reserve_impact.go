@@ -0,0 +1,106 @@
+package methods
+
+import (
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+// baseAccountReserves is the number of base reserves every account must hold.
+const baseAccountReserves = 2
+
+// ReserveImpact estimates how a transaction changed the minimum balance (reserve) of
+// the accounts it modified. The minimum balance of an account is
+//
+//	(2 + subentries + sponsoring entries - sponsored entries) * base reserve
+//
+// so, for instance, adding a trustline increases it by one base reserve. The estimate
+// is an approximation:
+//
+//   - It is computed from the subentry and sponsorship counts of the account entries
+//     before and after the transaction, rather than from the subentries themselves.
+//   - It uses the base reserve of the ledger which included the transaction, which
+//     may have changed since.
+//   - It ignores the selling liabilities of the accounts, which also lock part of
+//     their balance.
+type ReserveImpact struct {
+	// BaseReserve is the base reserve (in stroops) of the ledger which included the transaction.
+	BaseReserve int64 `json:"baseReserve,string"`
+	// TotalDelta is the sum (in stroops) of the minimum balance changes of all the accounts.
+	TotalDelta int64 `json:"totalDelta,string"`
+	// Accounts lists the accounts whose minimum balance changed, in order of appearance in
+	// the transaction meta.
+	Accounts []AccountReserveImpact `json:"accounts,omitempty"`
+}
+
+type AccountReserveImpact struct {
+	Account string `json:"account"`
+	// ReserveDelta is the change (in stroops) of the minimum balance of the account. Created
+	// and removed accounts count their two base reserves.
+	ReserveDelta int64 `json:"reserveDelta,string"`
+	// SubentriesDelta is the change in the number of subentries of the account.
+	SubentriesDelta int64 `json:"subentriesDelta"`
+}
+
+// accountReserveUnits returns the minimum balance of the account in base reserves,
+// together with its number of subentries. Missing accounts have no reserve.
+func accountReserveUnits(entry *xdr.LedgerEntry) (int64, int64) {
+	if entry == nil {
+		return 0, 0
+	}
+	account := entry.Data.MustAccount()
+	subentries := int64(account.NumSubEntries)
+	return baseAccountReserves + subentries + int64(account.NumSponsoring()) - int64(account.NumSponsored()), subentries
+}
+
+// reserveImpact computes the ReserveImpact of the transaction with the given (encoded) meta.
+func reserveImpact(encodedMeta []byte, baseReserve int64) (ReserveImpact, error) {
+	var meta xdr.TransactionMeta
+	if err := meta.UnmarshalBinary(encodedMeta); err != nil {
+		return ReserveImpact{}, err
+	}
+	changes, err := (&ingest.LedgerTransaction{UnsafeMeta: meta}).GetChanges()
+	if err != nil {
+		return ReserveImpact{}, err
+	}
+
+	type accountChange struct {
+		pre, post *xdr.LedgerEntry
+	}
+	var order []string
+	accounts := map[string]*accountChange{}
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeAccount {
+			continue
+		}
+		entry := change.Pre
+		if entry == nil {
+			entry = change.Post
+		}
+		address := entry.Data.MustAccount().AccountId.Address()
+		if existing, ok := accounts[address]; ok {
+			// keep the state before the first change and after the last one
+			existing.post = change.Post
+			continue
+		}
+		accounts[address] = &accountChange{pre: change.Pre, post: change.Post}
+		order = append(order, address)
+	}
+
+	impact := ReserveImpact{BaseReserve: baseReserve}
+	for _, address := range order {
+		change := accounts[address]
+		unitsBefore, subentriesBefore := accountReserveUnits(change.pre)
+		unitsAfter, subentriesAfter := accountReserveUnits(change.post)
+		if unitsBefore == unitsAfter && subentriesBefore == subentriesAfter {
+			continue
+		}
+		delta := (unitsAfter - unitsBefore) * baseReserve
+		impact.TotalDelta += delta
+		impact.Accounts = append(impact.Accounts, AccountReserveImpact{
+			Account:         address,
+			ReserveDelta:    delta,
+			SubentriesDelta: subentriesAfter - subentriesBefore,
+		})
+	}
+	return impact, nil
+}
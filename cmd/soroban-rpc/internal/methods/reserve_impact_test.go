@@ -0,0 +1,85 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func accountLedgerEntry(address string, balance int64, subentries uint32, sponsoring uint32) *xdr.LedgerEntry {
+	account := xdr.AccountEntry{
+		AccountId:     xdr.MustAddress(address),
+		Balance:       xdr.Int64(balance),
+		NumSubEntries: xdr.Uint32(subentries),
+	}
+	if sponsoring > 0 {
+		account.Ext = xdr.AccountEntryExt{
+			V: 1,
+			V1: &xdr.AccountEntryExtensionV1{
+				Ext: xdr.AccountEntryExtensionV1Ext{
+					V:  2,
+					V2: &xdr.AccountEntryExtensionV2{NumSponsoring: xdr.Uint32(sponsoring)},
+				},
+			},
+		}
+	}
+	return &xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &account},
+	}
+}
+
+func TestReserveImpact(t *testing.T) {
+	source := keypair.MustRandom().Address()
+	created := keypair.MustRandom().Address()
+	sponsor := keypair.MustRandom().Address()
+
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			// the sequence number bump doesn't change the reserve
+			TxChangesBefore: xdr.LedgerEntryChanges{
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: accountLedgerEntry(source, 1000, 1, 0)},
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: accountLedgerEntry(source, 900, 1, 0)},
+			},
+			Operations: []xdr.OperationMeta{
+				{Changes: xdr.LedgerEntryChanges{
+					// a trustline is added and the account funds a new account
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: accountLedgerEntry(source, 900, 1, 0)},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: accountLedgerEntry(source, 400, 2, 0)},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: accountLedgerEntry(created, 500, 0, 0)},
+				}},
+				{Changes: xdr.LedgerEntryChanges{
+					// two entries of the source account are sponsored
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: accountLedgerEntry(sponsor, 5000, 0, 0)},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: accountLedgerEntry(sponsor, 5000, 0, 2)},
+				}},
+			},
+		},
+	}
+	encodedMeta, err := meta.MarshalBinary()
+	require.NoError(t, err)
+
+	impact, err := reserveImpact(encodedMeta, 100)
+	require.NoError(t, err)
+	assert.Equal(t, ReserveImpact{
+		BaseReserve: 100,
+		TotalDelta:  500,
+		Accounts: []AccountReserveImpact{
+			{Account: source, ReserveDelta: 100, SubentriesDelta: 1},
+			{Account: created, ReserveDelta: 200},
+			{Account: sponsor, ReserveDelta: 200},
+		},
+	}, impact)
+
+	// transactions not changing any reserve
+	meta.V3.Operations = nil
+	encodedMeta, err = meta.MarshalBinary()
+	require.NoError(t, err)
+	impact, err = reserveImpact(encodedMeta, 100)
+	require.NoError(t, err)
+	assert.Equal(t, ReserveImpact{BaseReserve: 100}, impact)
+}
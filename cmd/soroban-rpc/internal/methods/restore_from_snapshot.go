@@ -0,0 +1,109 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// SnapshotRestorer restores the snapshot at the given path into the (empty) database,
+// returning the range of the restored ledgers (see db.SnapshotRestorer).
+type SnapshotRestorer interface {
+	RestoreFromSnapshot(ctx context.Context, path string) (db.LedgerSeqRange, error)
+}
+
+type RestoreFromSnapshotRequest struct {
+	// Path of the snapshot, a database backup. Relative paths are resolved against the allowed
+	// backup directory.
+	Path string `json:"path"`
+}
+
+type RestoreFromSnapshotResponse struct {
+	// FirstRestoredLedger and LastRestoredLedger delimit the ledgers restored from the snapshot.
+	FirstRestoredLedger uint32 `json:"firstRestoredLedger"`
+	LastRestoredLedger  uint32 `json:"lastRestoredLedger"`
+	// OldestLedger and LatestLedger delimit the ledgers stored after the restoration.
+	OldestLedger uint32 `json:"oldestLedger"`
+	LatestLedger uint32 `json:"latestLedger"`
+	// DurationMs is how long the restoration took, in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+}
+
+// resolveSnapshotPath returns the absolute path of the snapshot, making sure it lies within
+// allowedDir and exists.
+func resolveSnapshotPath(allowedDir string, path string) (string, error) {
+	if allowedDir == "" {
+		return "", errors.New("snapshot restoration is disabled")
+	}
+	resolved, err := resolveAdminPath(allowedDir, path)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.Mode().IsRegular() {
+		return "", errors.New("path must be an existing file")
+	}
+	return resolved, nil
+}
+
+// NewRestoreFromSnapshotHandler returns an admin JSON RPC handler bootstrapping the database
+// from snapshots (produced by backupDatabase) found in allowedDir. Restorations are disabled if
+// allowedDir is empty.
+func NewRestoreFromSnapshotHandler(logger *log.Entry, restorer SnapshotRestorer, ledgerReader db.LedgerReader,
+	allowedDir string,
+) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, request RestoreFromSnapshotRequest,
+	) (RestoreFromSnapshotResponse, error) {
+		path, err := resolveSnapshotPath(allowedDir, request.Path)
+		if err != nil {
+			return RestoreFromSnapshotResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+
+		startTime := time.Now()
+		restored, err := restorer.RestoreFromSnapshot(ctx, path)
+		if errors.Is(err, db.ErrInvalidSnapshot) {
+			return RestoreFromSnapshotResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		} else if errors.Is(err, db.ErrDatabaseNotEmpty) {
+			return RestoreFromSnapshotResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidRequest,
+				Message: err.Error(),
+			}
+		} else if err != nil {
+			logger.WithError(err).WithField("path", path).Error("could not restore snapshot")
+			return RestoreFromSnapshotResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not restore snapshot",
+			}
+		}
+		duration := time.Since(startTime)
+		logger.WithField("path", path).WithField("first", restored.First).WithField("last", restored.Last).
+			WithField("duration", duration).Info("snapshot restoration completed")
+
+		ledgerRange, err := ledgerReader.GetLedgerRange(ctx)
+		if err != nil {
+			return RestoreFromSnapshotResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get ledger range",
+			}
+		}
+		return RestoreFromSnapshotResponse{
+			FirstRestoredLedger: restored.First,
+			LastRestoredLedger:  restored.Last,
+			OldestLedger:        ledgerRange.FirstLedger.Sequence,
+			LatestLedger:        ledgerRange.LastLedger.Sequence,
+			DurationMs:          duration.Milliseconds(),
+		}, nil
+	})
+}
@@ -0,0 +1,79 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type mockSnapshotRestorer struct {
+	restored db.LedgerSeqRange
+	err      error
+	paths    []string
+}
+
+func (m *mockSnapshotRestorer) RestoreFromSnapshot(_ context.Context, path string) (db.LedgerSeqRange, error) {
+	m.paths = append(m.paths, path)
+	return m.restored, m.err
+}
+
+func TestRestoreFromSnapshot(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snapshot.sqlite"), nil, 0o600))
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	for i := uint32(1); i <= 3; i++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(i)))
+	}
+	restorer := &mockSnapshotRestorer{restored: db.LedgerSeqRange{First: 2, Last: 3}}
+	handler := NewRestoreFromSnapshotHandler(log.DefaultLogger, restorer, db.NewMockLedgerReader(store), dir)
+	restore := func(path string) (RestoreFromSnapshotResponse, error) {
+		params, err := json.Marshal(RestoreFromSnapshotRequest{Path: path})
+		require.NoError(t, err)
+		request := jrpc2.ParsedRequest{ID: "1", Method: "restoreFromSnapshot", Params: params}
+		response, err := handler(context.Background(), request.ToRequest())
+		if err != nil {
+			return RestoreFromSnapshotResponse{}, err
+		}
+		return response.(RestoreFromSnapshotResponse), nil
+	}
+
+	response, err := restore("snapshot.sqlite")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "snapshot.sqlite")}, restorer.paths)
+	assert.Equal(t, uint32(2), response.FirstRestoredLedger)
+	assert.Equal(t, uint32(3), response.LastRestoredLedger)
+	assert.Equal(t, uint32(1), response.OldestLedger)
+	assert.Equal(t, uint32(3), response.LatestLedger)
+
+	// missing snapshots and invalid snapshots are rejected
+	for _, path := range []string{"missing.sqlite", "../snapshot.sqlite", ""} {
+		_, err = restore(path)
+		var jrpcErr *jrpc2.Error
+		require.ErrorAs(t, err, &jrpcErr, path)
+		assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	}
+	restorer.err = fmt.Errorf("%w: gap", db.ErrInvalidSnapshot)
+	_, err = restore("snapshot.sqlite")
+	var jrpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+	restorer.err = db.ErrDatabaseNotEmpty
+	_, err = restore("snapshot.sqlite")
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.InvalidRequest, jrpcErr.Code)
+
+	_, err = resolveSnapshotPath("", "snapshot.sqlite")
+	require.ErrorContains(t, err, "disabled")
+}
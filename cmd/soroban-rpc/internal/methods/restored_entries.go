@@ -0,0 +1,114 @@
+package methods
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
+)
+
+// RestoredEntry is an archived ledger entry restored by a transaction (e.g. through RestoreFootprint).
+type RestoredEntry struct {
+	// KeyXDR is the LedgerKey XDR value of the entry.
+	KeyXDR  string          `json:"keyXdr,omitempty"`
+	KeyJSON json.RawMessage `json:"keyJson,omitempty"`
+	// LiveUntilLedgerSeq is the live-until ledger of the entry after the transaction, which
+	// includes the extensions made after the restoration by the same transaction.
+	LiveUntilLedgerSeq uint32 `json:"liveUntilLedgerSeq"`
+}
+
+// ExtendedTtlEntry is a live ledger entry whose TTL was extended by a transaction (e.g. through
+// ExtendFootprintTtl). The entries restored by the transaction are RestoredEntries instead.
+type ExtendedTtlEntry struct {
+	// KeyXDR is the LedgerKey XDR value of the entry.
+	KeyXDR  string          `json:"keyXdr,omitempty"`
+	KeyJSON json.RawMessage `json:"keyJson,omitempty"`
+	// PreviousLiveUntilLedgerSeq is the live-until ledger of the entry before the transaction.
+	PreviousLiveUntilLedgerSeq uint32 `json:"previousLiveUntilLedgerSeq"`
+	// LiveUntilLedgerSeq is the live-until ledger of the entry after the transaction.
+	LiveUntilLedgerSeq uint32 `json:"liveUntilLedgerSeq"`
+}
+
+// encodeLedgerKey encodes a ledger key in the given format, returning its XDR or JSON value.
+func encodeLedgerKey(key xdr.LedgerKey, format string) (string, json.RawMessage, error) {
+	if format == FormatJSON {
+		keyJSON, err := xdr2json.ConvertInterface(key)
+		return "", keyJSON, err
+	}
+	keyXDR, err := xdr.MarshalBase64(key)
+	return keyXDR, nil, err
+}
+
+// restoredAndExtendedEntries returns the entries of the footprint restored by the transaction
+// with the given meta, included in the given ledger, and the entries whose TTL it
+// extended. An entry was restored if its TTL went from expired (before the ledger) to live, and
+// extended if its TTL was live and increased. TTL entries only hold the hashes of the keys, so
+// the keys are looked up in the footprint.
+func restoredAndExtendedEntries(footprint xdr.LedgerFootprint, meta xdr.TransactionMeta, ledgerSeq uint32,
+	format string,
+) ([]RestoredEntry, []ExtendedTtlEntry, error) {
+	changes, err := (&ingest.LedgerTransaction{UnsafeMeta: meta}).GetChanges()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the TTL of each key before the transaction, and after it (its last change)
+	type ttlChange struct {
+		pre, post *xdr.TtlEntry
+	}
+	ttls := map[xdr.Hash]*ttlChange{}
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeTtl {
+			continue
+		}
+		entry := change.Post
+		if entry == nil {
+			entry = change.Pre
+		}
+		keyHash := entry.Data.MustTtl().KeyHash
+		ttl, ok := ttls[keyHash]
+		if !ok {
+			ttl = &ttlChange{}
+			if change.Pre != nil {
+				ttl.pre = change.Pre.Data.Ttl
+			}
+			ttls[keyHash] = ttl
+		}
+		ttl.post = nil
+		if change.Post != nil {
+			ttl.post = change.Post.Data.Ttl
+		}
+	}
+
+	var restored []RestoredEntry
+	var extended []ExtendedTtlEntry
+	for _, key := range append(footprint.ReadWrite, footprint.ReadOnly...) {
+		keyXDR, err := key.MarshalBinary()
+		if err != nil {
+			return nil, nil, err
+		}
+		ttl, ok := ttls[sha256.Sum256(keyXDR)]
+		if !ok || ttl.pre == nil || ttl.post == nil {
+			continue
+		}
+		pre, post := uint32(ttl.pre.LiveUntilLedgerSeq), uint32(ttl.post.LiveUntilLedgerSeq)
+		switch {
+		case pre < ledgerSeq && post >= ledgerSeq:
+			entry := RestoredEntry{LiveUntilLedgerSeq: post}
+			if entry.KeyXDR, entry.KeyJSON, err = encodeLedgerKey(key, format); err != nil {
+				return nil, nil, err
+			}
+			restored = append(restored, entry)
+		case pre >= ledgerSeq && post > pre:
+			entry := ExtendedTtlEntry{PreviousLiveUntilLedgerSeq: pre, LiveUntilLedgerSeq: post}
+			if entry.KeyXDR, entry.KeyJSON, err = encodeLedgerKey(key, format); err != nil {
+				return nil, nil, err
+			}
+			extended = append(extended, entry)
+		}
+	}
+	return restored, extended, nil
+}
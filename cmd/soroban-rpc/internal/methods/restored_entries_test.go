@@ -0,0 +1,92 @@
+package methods
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+func ttlEntry(t *testing.T, key xdr.LedgerKey, liveUntil uint32) xdr.LedgerEntry {
+	keyXDR, err := key.MarshalBinary()
+	require.NoError(t, err)
+	return xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTtl,
+			Ttl: &xdr.TtlEntry{
+				KeyHash:            sha256.Sum256(keyXDR),
+				LiveUntilLedgerSeq: xdr.Uint32(liveUntil),
+			},
+		},
+	}
+}
+
+func TestRestoredAndExtendedEntries(t *testing.T) {
+	const ledgerSeq = 100
+	keys := make([]xdr.LedgerKey, 4)
+	for i := range keys {
+		entry := contractDataEntry(xdr.Hash{byte(i + 1)}, xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance})
+		key, err := entry.LedgerKey()
+		require.NoError(t, err)
+		keys[i] = key
+	}
+	restored := ttlEntry(t, keys[0], 50)
+	restoredAfter := ttlEntry(t, keys[0], 200)
+	restoredThenBumped := ttlEntry(t, keys[1], 60)
+	restoredThenBumpedAfter := ttlEntry(t, keys[1], 200)
+	restoredThenBumpedFinal := ttlEntry(t, keys[1], 300)
+	bumped := ttlEntry(t, keys[2], 150)
+	bumpedAfter := ttlEntry(t, keys[2], 250)
+
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{
+				{
+					Changes: xdr.LedgerEntryChanges{
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &restored},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &restoredAfter},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &restoredThenBumped},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &restoredThenBumpedAfter},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &bumped},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &bumpedAfter},
+					},
+				},
+				{
+					Changes: xdr.LedgerEntryChanges{
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &restoredThenBumpedAfter},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &restoredThenBumpedFinal},
+					},
+				},
+			},
+		},
+	}
+	footprint := xdr.LedgerFootprint{ReadWrite: keys}
+
+	// the transaction both restores entries and extends the TTL of a live entry
+	entries, extended, err := restoredAndExtendedEntries(footprint, meta, ledgerSeq, FormatBase64)
+	require.NoError(t, err)
+	expectedKeys := make([]string, 0, 3)
+	for _, key := range keys[:3] {
+		keyXDR, err := xdr.MarshalBase64(key)
+		require.NoError(t, err)
+		expectedKeys = append(expectedKeys, keyXDR)
+	}
+	assert.Equal(t, []RestoredEntry{
+		{KeyXDR: expectedKeys[0], LiveUntilLedgerSeq: 200},
+		{KeyXDR: expectedKeys[1], LiveUntilLedgerSeq: 300},
+	}, entries)
+	assert.Equal(t, []ExtendedTtlEntry{
+		{KeyXDR: expectedKeys[2], PreviousLiveUntilLedgerSeq: 150, LiveUntilLedgerSeq: 250},
+	}, extended)
+
+	// transactions which didn't restore entries
+	meta = txMeta(1, true).V1.TxProcessing[0].TxApplyProcessing
+	entries, extended, err = restoredAndExtendedEntries(footprint, meta, ledgerSeq, FormatBase64)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Empty(t, extended)
+}
@@ -0,0 +1,150 @@
+package methods
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+// maxResultCacheEntries bounds the number of results cached for a method, so that requests
+// with distinct parameters can't grow the cache without bounds.
+const maxResultCacheEntries = 1024
+
+// ResultCacheConfig configures the result cache of a method (see WithResultCache).
+type ResultCacheConfig struct {
+	// TTL is the time to live of the cached results.
+	TTL time.Duration
+	// LatestLedger, if set, returns the latest ingested ledger (and false if none was ingested
+	// yet). The results are then dropped as soon as a later ledger is ingested, for the methods
+	// whose results follow the latest ledger.
+	LatestLedger func() (uint32, bool)
+	// Key derives the cache key of the requests, ParamsCacheKey if nil. Requests with the same
+	// key are served the same result.
+	Key func(request *jrpc2.Request) (string, error)
+	// Clock defaults to the real clock if nil.
+	Clock util.Clock
+	// Hits and Misses, if set, count the requests served from the cache and the other ones.
+	Hits, Misses prometheus.Counter
+}
+
+// ParamsCacheKey derives the cache key of a request from its (compacted) parameters.
+func ParamsCacheKey(request *jrpc2.Request) (string, error) {
+	params := request.ParamString()
+	if params == "" {
+		return "", nil
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(params)); err != nil {
+		return "", err
+	}
+	return compacted.String(), nil
+}
+
+type resultCacheEntry struct {
+	result    interface{}
+	expiresAt time.Time
+	// ledger is the latest ingested ledger when the result was computed, if ingested is set.
+	ledger   uint32
+	ingested bool
+}
+
+type resultCache struct {
+	config  ResultCacheConfig
+	lock    sync.Mutex
+	entries map[string]resultCacheEntry
+}
+
+// WithResultCache returns a handler serving the results of handler from a cache for the
+// configured TTL. Errors aren't cached, and requests whose cache key can't be derived are
+// served by handler as is.
+func WithResultCache(handler jrpc2.Handler, config ResultCacheConfig) jrpc2.Handler {
+	if config.Key == nil {
+		config.Key = ParamsCacheKey
+	}
+	if config.Clock == nil {
+		config.Clock = util.RealClock{}
+	}
+	cache := &resultCache{config: config, entries: map[string]resultCacheEntry{}}
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		key, err := config.Key(request)
+		if err != nil {
+			return handler(ctx, request)
+		}
+		ledger, ingested := cache.latestLedger()
+		if result, ok := cache.get(key, ledger, ingested); ok {
+			if config.Hits != nil {
+				config.Hits.Inc()
+			}
+			return result, nil
+		}
+		if config.Misses != nil {
+			config.Misses.Inc()
+		}
+		result, err := handler(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		// the ledger is read before computing the result, so that the result is dropped
+		// if a ledger was ingested meanwhile
+		cache.put(key, resultCacheEntry{
+			result:    result,
+			expiresAt: config.Clock.Now().Add(config.TTL),
+			ledger:    ledger,
+			ingested:  ingested,
+		})
+		return result, nil
+	}
+}
+
+func (c *resultCache) latestLedger() (uint32, bool) {
+	if c.config.LatestLedger == nil {
+		return 0, false
+	}
+	return c.config.LatestLedger()
+}
+
+func (c *resultCache) isFresh(entry resultCacheEntry, now time.Time, ledger uint32, ingested bool) bool {
+	if !now.Before(entry.expiresAt) {
+		return false
+	}
+	return c.config.LatestLedger == nil || (entry.ingested == ingested && entry.ledger == ledger)
+}
+
+func (c *resultCache) get(key string, ledger uint32, ingested bool) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.isFresh(entry, c.config.Clock.Now(), ledger, ingested) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (c *resultCache) put(key string, entry resultCacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxResultCacheEntries {
+		ledger, ingested := c.latestLedger()
+		now := c.config.Clock.Now()
+		for existingKey, existing := range c.entries {
+			if !c.isFresh(existing, now, ledger, ingested) {
+				delete(c.entries, existingKey)
+			}
+		}
+		if len(c.entries) >= maxResultCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = entry
+}
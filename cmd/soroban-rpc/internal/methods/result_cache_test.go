@@ -0,0 +1,109 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingestionwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+// countingHandler returns the number of calls it served.
+type countingHandler struct {
+	calls int
+	err   error
+}
+
+func (h *countingHandler) handle(context.Context, *jrpc2.Request) (interface{}, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	h.calls++
+	return h.calls, nil
+}
+
+func callWithParams(t *testing.T, handler jrpc2.Handler, params string) (interface{}, error) {
+	requests, err := jrpc2.ParseRequests([]byte(
+		`{"jsonrpc":"2.0","id":1,"method":"getFeeStats","params":` + params + `}`))
+	require.NoError(t, err)
+	return handler(context.Background(), requests[0].ToRequest())
+}
+
+func TestResultCache(t *testing.T) {
+	clock := util.NewManualClock(time.Unix(1_000_000, 0))
+	hits := prometheus.NewCounter(prometheus.CounterOpts{Name: "hits"})
+	misses := prometheus.NewCounter(prometheus.CounterOpts{Name: "misses"})
+	underlying := &countingHandler{}
+	handler := WithResultCache(underlying.handle, ResultCacheConfig{
+		TTL:    time.Minute,
+		Clock:  clock,
+		Hits:   hits,
+		Misses: misses,
+	})
+	call := func(params string) interface{} {
+		result, err := callWithParams(t, handler, params)
+		require.NoError(t, err)
+		return result
+	}
+
+	require.Equal(t, 1, call(`{"a":1}`))
+	// hit, including with differently formatted params
+	require.Equal(t, 1, call(`{"a":1}`))
+	require.Equal(t, 1, call(`{ "a": 1 }`))
+	// other params are cached apart
+	require.Equal(t, 2, call(`{"a":2}`))
+	require.InDelta(t, 2, testutil.ToFloat64(hits), 0)
+	require.InDelta(t, 2, testutil.ToFloat64(misses), 0)
+
+	// expiry
+	clock.Advance(59 * time.Second)
+	require.Equal(t, 1, call(`{"a":1}`))
+	clock.Advance(time.Second)
+	require.Equal(t, 3, call(`{"a":1}`))
+	require.Equal(t, 3, call(`{"a":1}`))
+	require.InDelta(t, 4, testutil.ToFloat64(hits), 0)
+	require.InDelta(t, 3, testutil.ToFloat64(misses), 0)
+}
+
+func TestResultCacheErrors(t *testing.T) {
+	underlying := &countingHandler{err: errors.New("failed")}
+	handler := WithResultCache(underlying.handle, ResultCacheConfig{TTL: time.Minute})
+	_, err := callWithParams(t, handler, `{}`)
+	require.Error(t, err)
+
+	// errors aren't cached
+	underlying.err = nil
+	result, err := callWithParams(t, handler, `{}`)
+	require.NoError(t, err)
+	require.Equal(t, 1, result)
+}
+
+func TestResultCacheLedgerIngestion(t *testing.T) {
+	window := ingestionwindow.NewIngestionWindow(10)
+	underlying := &countingHandler{}
+	handler := WithResultCache(underlying.handle, ResultCacheConfig{
+		TTL:          time.Hour,
+		LatestLedger: window.LatestLedger,
+	})
+	call := func() interface{} {
+		result, err := callWithParams(t, handler, `{}`)
+		require.NoError(t, err)
+		return result
+	}
+
+	require.Equal(t, 1, call())
+	require.Equal(t, 1, call())
+	// the results are dropped whenever a ledger is ingested
+	window.AppendLedger(10, 0, time.Now())
+	require.Equal(t, 2, call())
+	require.Equal(t, 2, call())
+	window.AppendLedger(11, 0, time.Now())
+	require.Equal(t, 3, call())
+}
@@ -0,0 +1,69 @@
+package methods
+
+import (
+	"encoding/json"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdr2json"
+)
+
+// ResultingEntry is the state of a ledger entry written by a transaction, after the transaction.
+type ResultingEntry struct {
+	// KeyJSON is the LedgerKey of the entry, only present in the JSON format (the entries are
+	// keyed by their LedgerKey XDR value).
+	KeyJSON json.RawMessage `json:"keyJson,omitempty"`
+	// EntryXDR is the LedgerEntry XDR value of the entry after the transaction, absent when
+	// the transaction removed the entry.
+	EntryXDR  string          `json:"entryXdr,omitempty"`
+	EntryJSON json.RawMessage `json:"entryJson,omitempty"`
+	// Deleted is true when the transaction removed the entry.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// resultingState returns the final state of the ledger entries written by the transaction with
+// the given meta, keyed by their base64 LedgerKey XDR value. The final state of an
+// entry is the post-state of its last change, so the intermediate values set by earlier
+// operations of the transaction are left out.
+func resultingState(meta xdr.TransactionMeta, format string) (map[string]ResultingEntry, error) {
+	changes, err := (&ingest.LedgerTransaction{UnsafeMeta: meta}).GetChanges()
+	if err != nil {
+		return nil, err
+	}
+	state := make(map[string]ResultingEntry, len(changes))
+	for _, change := range changes {
+		entry := change.Post
+		if entry == nil {
+			entry = change.Pre
+		}
+		key, err := entry.LedgerKey()
+		if err != nil {
+			return nil, err
+		}
+		keyXDR, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, err
+		}
+		var resulting ResultingEntry
+		if format == FormatJSON {
+			if resulting.KeyJSON, err = xdr2json.ConvertInterface(key); err != nil {
+				return nil, err
+			}
+		}
+		switch {
+		case change.Post == nil:
+			resulting.Deleted = true
+		case format == FormatJSON:
+			if resulting.EntryJSON, err = xdr2json.ConvertInterface(*change.Post); err != nil {
+				return nil, err
+			}
+		default:
+			if resulting.EntryXDR, err = xdr.MarshalBase64(*change.Post); err != nil {
+				return nil, err
+			}
+		}
+		state[keyXDR] = resulting
+	}
+	return state, nil
+}
@@ -0,0 +1,71 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+func TestResultingState(t *testing.T) {
+	symbol := xdr.ScSymbol("balance")
+	key := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &symbol}
+	updated := contractDataEntry(xdr.Hash{1}, key)
+	intermediate := contractDataEntry(xdr.Hash{1}, key)
+	intermediate.Data.ContractData.Val = xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &[]bool{true}[0]}
+	final := contractDataEntry(xdr.Hash{1}, key)
+	final.Data.ContractData.Val = xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &[]bool{false}[0]}
+	created := contractDataEntry(xdr.Hash{2}, key)
+	removed := contractDataEntry(xdr.Hash{3}, key)
+	removedKey, err := removed.LedgerKey()
+	require.NoError(t, err)
+
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{
+				{
+					Changes: xdr.LedgerEntryChanges{
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &updated},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &intermediate},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &created},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &removed},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &removedKey},
+					},
+				},
+				{
+					Changes: xdr.LedgerEntryChanges{
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &intermediate},
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &final},
+					},
+				},
+			},
+		},
+	}
+	encode := func(value interface{}) string {
+		encoded, err := xdr.MarshalBase64(value)
+		require.NoError(t, err)
+		return encoded
+	}
+	keyXDR := func(entry xdr.LedgerEntry) string {
+		key, err := entry.LedgerKey()
+		require.NoError(t, err)
+		return encode(key)
+	}
+
+	// the entries map to the values they have after the last operation of the transaction
+	state, err := resultingState(meta, FormatBase64)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ResultingEntry{
+		keyXDR(final):   {EntryXDR: encode(final)},
+		keyXDR(created): {EntryXDR: encode(created)},
+		keyXDR(removed): {Deleted: true},
+	}, state)
+
+	// transactions which didn't write entries
+	state, err = resultingState(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}}, FormatBase64)
+	require.NoError(t, err)
+	assert.Empty(t, state)
+}
@@ -44,6 +44,25 @@ type SendTransactionResponse struct {
 	// LatestLedgerCloseTime is the unix timestamp of the close time of the latest ledger known to
 	// Soroban-RPC at the time it handled the transaction submission request.
 	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+
+	// emptySlices is how the empty diagnostic event lists are rendered (see WithEmptySlices).
+	emptySlices string
+}
+
+func (r SendTransactionResponse) withEmptySlices(mode string) interface{} {
+	r.emptySlices = mode
+	return r
+}
+
+func (r SendTransactionResponse) MarshalJSON() ([]byte, error) {
+	type sendTransactionResponse SendTransactionResponse
+	encoded, err := json.Marshal(sendTransactionResponse(r))
+	if err != nil {
+		return nil, err
+	}
+	return appendEmptyLists(encoded, r.emptySlices,
+		listField("diagnosticEventsXdr", r.DiagnosticEventsXDR),
+		listField("diagnosticEventsJson", r.DiagnosticEventsJSON)), nil
 }
 
 // SendTransactionRequest is the Soroban-RPC request to submit a transaction.
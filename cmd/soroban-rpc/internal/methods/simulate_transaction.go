@@ -224,6 +224,25 @@ type SimulateTransactionResponse struct {
 	// If present, it indicates how the state (ledger entries) will change as a result of the transaction execution.
 	StateChanges []LedgerEntryChange `json:"stateChanges,omitempty"`
 	LatestLedger uint32              `json:"latestLedger"`
+
+	// emptySlices is how the empty diagnostic event lists are rendered (see WithEmptySlices).
+	emptySlices string
+}
+
+func (r SimulateTransactionResponse) withEmptySlices(mode string) interface{} {
+	r.emptySlices = mode
+	return r
+}
+
+func (r SimulateTransactionResponse) MarshalJSON() ([]byte, error) {
+	type simulateTransactionResponse SimulateTransactionResponse
+	encoded, err := json.Marshal(simulateTransactionResponse(r))
+	if err != nil {
+		return nil, err
+	}
+	return appendEmptyLists(encoded, r.emptySlices,
+		listField("events", r.EventsXDR),
+		listField("eventsJson", r.EventsJSON)), nil
 }
 
 type PreflightGetter interface {
@@ -0,0 +1,148 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+// errStreamStopped interrupts the streams stopped by StreamRegistry.Drain.
+var errStreamStopped = errors.New("stream stopped by the server shutdown")
+
+// StreamRegistry tracks the active streams (e.g. of NewTransactionHashesHTTPHandler), to cap
+// their number and to drain them on shutdown: the streams are signaled to stop after their
+// current record, so that clients never get a partial record, before the database is closed.
+// Each stream can also be throttled to a maximum number of ledgers per second, so that full
+// history scans don't saturate the disk I/O needed by the ingestion.
+// A nil registry doesn't limit nor track the streams.
+type StreamRegistry struct {
+	lock                sync.Mutex
+	maxStreams          uint
+	maxLedgersPerSecond uint
+	clock               util.Clock
+	active              uint
+	draining            bool
+	// stopping is closed when draining starts
+	stopping chan struct{}
+	done     sync.WaitGroup
+}
+
+// NewStreamRegistry returns a registry allowing up to maxStreams concurrent streams, each
+// emitting at most maxLedgersPerSecond ledgers per second (0 meaning unlimited) as measured
+// by clock.
+func NewStreamRegistry(maxStreams, maxLedgersPerSecond uint, clock util.Clock) *StreamRegistry {
+	return &StreamRegistry{
+		maxStreams:          maxStreams,
+		maxLedgersPerSecond: maxLedgersPerSecond,
+		clock:               clock,
+		stopping:            make(chan struct{}),
+	}
+}
+
+// start registers a new stream. It returns false if there are too many streams or if the
+// registry is draining. Otherwise, the stream must stop once the returned channel is closed,
+// and call the returned function when it ends.
+func (r *StreamRegistry) start() (<-chan struct{}, func(), bool) {
+	if r == nil {
+		return nil, func() {}, true
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.draining || r.active >= r.maxStreams {
+		return nil, nil, false
+	}
+	r.active++
+	r.done.Add(1)
+	return r.stopping, func() {
+		r.lock.Lock()
+		r.active--
+		r.lock.Unlock()
+		r.done.Done()
+	}, true
+}
+
+// throttle returns the throttle of a new stream, nil if the streams aren't throttled.
+func (r *StreamRegistry) throttle() *streamThrottle {
+	if r == nil || r.maxLedgersPerSecond == 0 {
+		return nil
+	}
+	return newStreamThrottle(float64(r.maxLedgersPerSecond), r.clock)
+}
+
+// Drain rejects new streams, signals the active streams to stop after their current record
+// and waits for them to end, until the context is done.
+func (r *StreamRegistry) Drain(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	r.lock.Lock()
+	if !r.draining {
+		r.draining = true
+		close(r.stopping)
+	}
+	r.lock.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		r.done.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// streamThrottle is the token bucket limiting the ledgers emitted by a stream. It holds up to
+// one second of tokens, so that streams can burst after pausing (e.g. for a slow client).
+// It belongs to a single stream, so it isn't synchronized.
+type streamThrottle struct {
+	ratePerSecond float64
+	clock         util.Clock
+	tokens        float64
+	last          time.Time
+}
+
+func newStreamThrottle(ratePerSecond float64, clock util.Clock) *streamThrottle {
+	return &streamThrottle{
+		ratePerSecond: ratePerSecond,
+		clock:         clock,
+		tokens:        ratePerSecond,
+		last:          clock.Now(),
+	}
+}
+
+// wait takes a token, sleeping until one is available unless the context is done or the
+// stream is stopped (in which case it returns errStreamStopped). A nil throttle never waits.
+func (t *streamThrottle) wait(ctx context.Context, stopping <-chan struct{}) error {
+	if t == nil {
+		return nil
+	}
+	now := t.clock.Now()
+	t.tokens = min(t.ratePerSecond, t.tokens+now.Sub(t.last).Seconds()*t.ratePerSecond)
+	t.last = now
+	if t.tokens >= 1 {
+		t.tokens--
+		return nil
+	}
+
+	delay := time.Duration((1 - t.tokens) / t.ratePerSecond * float64(time.Second))
+	timer := t.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		// the token accrued while sleeping is taken
+		t.tokens = 0
+		t.last = now.Add(delay)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-stopping:
+		return errStreamStopped
+	}
+}
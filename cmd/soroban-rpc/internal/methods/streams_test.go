@@ -0,0 +1,132 @@
+package methods
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+// endlessTransactionHashes is a db.TransactionHashReader streaming hashes until f errors.
+type endlessTransactionHashes struct{}
+
+func (endlessTransactionHashes) StreamTransactionHashes(ctx context.Context, startLedger, _ uint32,
+	f db.StreamTransactionHashFn,
+) error {
+	for order := int32(1); ctx.Err() == nil; order++ {
+		hash := db.TransactionHash{
+			TransactionLocation: db.TransactionLocation{Ledger: startLedger, ApplicationOrder: order},
+			Hash:                xdr.Hash{byte(order)},
+		}
+		if err := f(hash); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func TestStreamRegistryLimit(t *testing.T) {
+	registry := NewStreamRegistry(1, 0, util.RealClock{})
+	_, done, ok := registry.start()
+	require.True(t, ok)
+	_, _, ok = registry.start()
+	require.False(t, ok)
+	done()
+	_, done, ok = registry.start()
+	require.True(t, ok)
+	done()
+}
+
+func TestStreamRegistryDrain(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	require.NoError(t, store.InsertTransactions(createTestLedger(1)))
+	registry := NewStreamRegistry(10, 0, util.RealClock{})
+	handler := NewTransactionHashesHTTPHandler(log.DefaultLogger, db.NewMockLedgerReader(store),
+		endlessTransactionHashes{}, 10, registry)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + TransactionHashesPath + "?startLedger=1")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	reader := bufio.NewReader(response.Body)
+	_, err = reader.ReadBytes('\n')
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, registry.Drain(ctx))
+
+	// the stream ends after a complete line, flagged as incomplete
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			assert.Empty(t, line)
+			break
+		}
+		require.NoError(t, err)
+		var hashLine TransactionHashLine
+		require.NoError(t, json.Unmarshal(line, &hashLine))
+	}
+	assert.Equal(t, "false", response.Trailer.Get(StreamCompleteTrailer))
+
+	// no streams are accepted after draining
+	rejected, err := http.Get(server.URL + TransactionHashesPath + "?startLedger=1")
+	require.NoError(t, err)
+	rejected.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, rejected.StatusCode)
+}
+
+func TestStreamThrottle(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := util.NewManualClock(start)
+	// the bucket starts with a second of tokens, the next ones are emitted at the rate
+	throttle := newStreamThrottle(50, clock)
+	for range 50 {
+		require.NoError(t, throttle.wait(context.Background(), nil))
+	}
+	require.Zero(t, clock.Timers())
+	waited := make(chan error)
+	go func() { waited <- throttle.wait(context.Background(), nil) }()
+	require.Eventually(t, func() bool { return clock.Timers() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(10 * time.Millisecond)
+	assert.Equal(t, 1, clock.Timers())
+	clock.Advance(10 * time.Millisecond)
+	require.NoError(t, <-waited)
+
+	// the tokens accrue while the stream pauses, up to a second of them
+	clock.Advance(time.Hour)
+	for range 50 {
+		require.NoError(t, throttle.wait(context.Background(), nil))
+	}
+	require.Zero(t, clock.Timers())
+
+	// waiting stops with the context and the stream
+	throttle = newStreamThrottle(1, clock)
+	require.NoError(t, throttle.wait(context.Background(), nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, throttle.wait(ctx, nil), context.Canceled)
+	stopping := make(chan struct{})
+	close(stopping)
+	require.ErrorIs(t, throttle.wait(context.Background(), stopping), errStreamStopped)
+	require.Zero(t, clock.Timers())
+
+	var disabled *streamThrottle
+	require.NoError(t, disabled.wait(context.Background(), nil))
+	assert.Nil(t, NewStreamRegistry(1, 0, clock).throttle())
+}
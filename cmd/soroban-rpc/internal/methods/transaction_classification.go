@@ -0,0 +1,41 @@
+package methods
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionClassification classifies a transaction by the kinds of Soroban activity of its
+// operations, letting indexers bucket transactions without decoding them. All the applicable
+// flags are set, and none of them for transactions without Soroban operations.
+type TransactionClassification struct {
+	// IsRestore indicates that the transaction restores archived entries (restore_footprint).
+	IsRestore bool `json:"isRestore,omitempty"`
+	// IsExtendTtl indicates that the transaction extends the TTL of entries (extend_footprint_ttl).
+	IsExtendTtl bool `json:"isExtendTtl,omitempty"`
+	// IsCreateContract indicates that the transaction creates a contract.
+	IsCreateContract bool `json:"isCreateContract,omitempty"`
+	// IsInvoke indicates that the transaction invokes a contract function.
+	IsInvoke bool `json:"isInvoke,omitempty"`
+}
+
+// classifyTransaction derives the classification of a transaction from its operation and host
+// function types. The operations of fee-bump transactions are those of the inner transaction.
+func classifyTransaction(envelope xdr.TransactionEnvelope) TransactionClassification {
+	var classification TransactionClassification
+	for _, op := range envelope.Operations() {
+		switch op.Body.Type {
+		case xdr.OperationTypeRestoreFootprint:
+			classification.IsRestore = true
+		case xdr.OperationTypeExtendFootprintTtl:
+			classification.IsExtendTtl = true
+		case xdr.OperationTypeInvokeHostFunction:
+			switch op.Body.MustInvokeHostFunctionOp().HostFunction.Type {
+			case xdr.HostFunctionTypeHostFunctionTypeCreateContract:
+				classification.IsCreateContract = true
+			case xdr.HostFunctionTypeHostFunctionTypeInvokeContract:
+				classification.IsInvoke = true
+			}
+		}
+	}
+	return classification
+}
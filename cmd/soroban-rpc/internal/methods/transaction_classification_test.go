@@ -0,0 +1,104 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestClassifyTransaction(t *testing.T) {
+	hostFunctionOp := func(hostFunctionType xdr.HostFunctionType) xdr.Operation {
+		return xdr.Operation{Body: xdr.OperationBody{
+			Type: xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+				HostFunction: xdr.HostFunction{Type: hostFunctionType},
+			},
+		}}
+	}
+	restoreOp := xdr.Operation{Body: xdr.OperationBody{
+		Type:               xdr.OperationTypeRestoreFootprint,
+		RestoreFootprintOp: &xdr.RestoreFootprintOp{},
+	}}
+	extendTTLOp := xdr.Operation{Body: xdr.OperationBody{
+		Type:                 xdr.OperationTypeExtendFootprintTtl,
+		ExtendFootprintTtlOp: &xdr.ExtendFootprintTtlOp{},
+	}}
+	envelope := func(operations ...xdr.Operation) xdr.TransactionEnvelope {
+		return xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+					Operations:    operations,
+				},
+			},
+		}
+	}
+
+	for _, testCase := range []struct {
+		name       string
+		operations []xdr.Operation
+		expected   TransactionClassification
+	}{
+		{
+			name:       "classic",
+			operations: []xdr.Operation{{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}}},
+		},
+		{
+			name:       "restore",
+			operations: []xdr.Operation{restoreOp},
+			expected:   TransactionClassification{IsRestore: true},
+		},
+		{
+			name:       "extend ttl",
+			operations: []xdr.Operation{extendTTLOp},
+			expected:   TransactionClassification{IsExtendTtl: true},
+		},
+		{
+			name:       "create contract",
+			operations: []xdr.Operation{hostFunctionOp(xdr.HostFunctionTypeHostFunctionTypeCreateContract)},
+			expected:   TransactionClassification{IsCreateContract: true},
+		},
+		{
+			name:       "invoke",
+			operations: []xdr.Operation{hostFunctionOp(xdr.HostFunctionTypeHostFunctionTypeInvokeContract)},
+			expected:   TransactionClassification{IsInvoke: true},
+		},
+		{
+			name:       "upload wasm",
+			operations: []xdr.Operation{hostFunctionOp(xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm)},
+		},
+		{
+			name: "multiple",
+			operations: []xdr.Operation{
+				restoreOp,
+				extendTTLOp,
+				hostFunctionOp(xdr.HostFunctionTypeHostFunctionTypeInvokeContract),
+			},
+			expected: TransactionClassification{IsRestore: true, IsExtendTtl: true, IsInvoke: true},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, classifyTransaction(envelope(testCase.operations...)))
+		})
+	}
+
+	// fee-bump transactions are classified by their inner transaction
+	inner := envelope(restoreOp)
+	feeBumpEnvelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   inner.V1,
+				},
+			},
+		},
+	}
+	require.Equal(t, TransactionClassification{IsRestore: true}, classifyTransaction(feeBumpEnvelope))
+}
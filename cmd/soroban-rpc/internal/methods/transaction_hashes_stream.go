@@ -0,0 +1,144 @@
+package methods
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// TransactionHashesPath is the HTTP path streaming the hashes of the transactions of a range of
+// ledgers, as NDJSON (see NewTransactionHashesHTTPHandler).
+const TransactionHashesPath = "/transactions/hashes"
+
+const (
+	// transactionHashesFlushPeriod is the number of lines after which the stream is flushed.
+	transactionHashesFlushPeriod = 1000
+	// StreamCompleteTrailer is the HTTP trailer telling whether a stream is complete ("true") or was
+	// interrupted ("false"), e.g. by the server shutdown.
+	StreamCompleteTrailer = "X-Stream-Complete"
+)
+
+// TransactionHashLine is a line of the transaction hashes stream.
+type TransactionHashLine struct {
+	Ledger           uint32 `json:"ledger"`
+	ApplicationOrder int32  `json:"applicationOrder"`
+	// Hash is the hex-encoded transaction hash. Fee-bump transactions have a line for both
+	// their outer and inner hashes.
+	Hash string `json:"hash"`
+}
+
+// NewTransactionHashesHTTPHandler returns a plain HTTP handler streaming the hashes of the
+// transactions of the ledgers from the startLedger to the endLedger query parameters (inclusive,
+// endLedger defaults to the latest ledger), as NDJSON: one TransactionHashLine per line.
+// It only reads the transactions table, so it's much cheaper than getTransactions, which decodes
+// the ledgers. The range can't exceed maxLedgers ledgers, and the streams are tracked by the
+// registry, which rejects them (with a 503 status) if there are too many and throttles the
+// ledgers they emit.
+func NewTransactionHashesHTTPHandler(logger *log.Entry, ledgerReader db.LedgerReader,
+	hashReader db.TransactionHashReader, maxLedgers uint32, streams *StreamRegistry,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parseLedger := func(name string) (uint32, error) {
+			value := r.URL.Query().Get(name)
+			if value == "" {
+				return 0, nil
+			}
+			ledger, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("incorrect %s: %w", name, err)
+			}
+			return uint32(ledger), nil
+		}
+		startLedger, err := parseLedger("startLedger")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		endLedger, err := parseLedger("endLedger")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ledgerRange, err := ledgerReader.GetLedgerRange(r.Context())
+		if err != nil {
+			logger.WithError(err).Error("failed to get the ledger range")
+			http.Error(w, "failed to get the ledger range", http.StatusInternalServerError)
+			return
+		}
+		if endLedger == 0 {
+			endLedger = ledgerRange.LastLedger.Sequence
+		}
+		switch {
+		case startLedger < ledgerRange.FirstLedger.Sequence || endLedger > ledgerRange.LastLedger.Sequence:
+			http.Error(w, fmt.Sprintf("startLedger and endLedger must be between the oldest ledger: %d and the "+
+				"latest ledger: %d", ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence),
+				http.StatusBadRequest)
+			return
+		case startLedger > endLedger:
+			http.Error(w, "endLedger must not be lower than startLedger", http.StatusBadRequest)
+			return
+		case endLedger-startLedger >= maxLedgers:
+			http.Error(w, fmt.Sprintf("the range must not exceed %d ledgers", maxLedgers), http.StatusBadRequest)
+			return
+		}
+
+		stopping, done, ok := streams.start()
+		if !ok {
+			http.Error(w, "too many streams", http.StatusServiceUnavailable)
+			return
+		}
+		defer done()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Trailer", StreamCompleteTrailer)
+		flusher, _ := w.(http.Flusher)
+		encoder := json.NewEncoder(w)
+		throttle := streams.throttle()
+		lines := 0
+		var lastLedger uint32
+		err = hashReader.StreamTransactionHashes(r.Context(), startLedger, endLedger,
+			func(hash db.TransactionHash) error {
+				select {
+				case <-stopping:
+					return errStreamStopped
+				default:
+				}
+				if hash.Ledger != lastLedger {
+					if err := throttle.wait(r.Context(), stopping); err != nil {
+						return err
+					}
+					lastLedger = hash.Ledger
+				}
+				if err := encoder.Encode(TransactionHashLine{
+					Ledger:           hash.Ledger,
+					ApplicationOrder: hash.ApplicationOrder,
+					Hash:             hash.Hash.HexString(),
+				}); err != nil {
+					return err
+				}
+				if lines++; lines%transactionHashesFlushPeriod == 0 && flusher != nil {
+					flusher.Flush()
+				}
+				return nil
+			})
+		switch {
+		case errors.Is(err, errStreamStopped):
+			logger.Debug("transaction hashes stream stopped by the shutdown")
+		case err != nil && lines == 0:
+			logger.WithError(err).Error("failed to stream transaction hashes")
+			http.Error(w, "failed to stream transaction hashes", http.StatusInternalServerError)
+			return
+		case err != nil:
+			// the status was sent already, the client sees a truncated stream
+			logger.WithError(err).Warn("transaction hashes stream interrupted")
+		}
+		w.Header().Set(StreamCompleteTrailer, strconv.FormatBool(err == nil))
+	})
+}
@@ -0,0 +1,81 @@
+package methods
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// transactionHashes is a db.TransactionHashReader over a fixed list of hashes.
+type transactionHashes []db.TransactionHash
+
+func (hashes transactionHashes) StreamTransactionHashes(_ context.Context, startLedger, endLedger uint32,
+	f db.StreamTransactionHashFn,
+) error {
+	for _, hash := range hashes {
+		if hash.Ledger >= startLedger && hash.Ledger <= endLedger {
+			if err := f(hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestTransactionHashesHTTPHandler(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	var hashes transactionHashes
+	for ledger := uint32(1); ledger <= 5; ledger++ {
+		require.NoError(t, store.InsertTransactions(createTestLedger(ledger)))
+		for order := int32(1); order <= 2; order++ {
+			hashes = append(hashes, db.TransactionHash{
+				TransactionLocation: db.TransactionLocation{Ledger: ledger, ApplicationOrder: order},
+				Hash:                xdr.Hash{byte(ledger), byte(order)},
+			})
+		}
+	}
+	handler := NewTransactionHashesHTTPHandler(log.DefaultLogger, db.NewMockLedgerReader(store), hashes, 3, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, TransactionHashesPath+"?"+query, nil))
+		return recorder
+	}
+
+	recorder := get("startLedger=3")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "true", recorder.Result().Trailer.Get(StreamCompleteTrailer))
+	var lines []TransactionHashLine
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		var line TransactionHashLine
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 6)
+	assert.Equal(t, TransactionHashLine{Ledger: 3, ApplicationOrder: 1, Hash: xdr.Hash{3, 1}.HexString()}, lines[0])
+	assert.Equal(t, TransactionHashLine{Ledger: 5, ApplicationOrder: 2, Hash: xdr.Hash{5, 2}.HexString()}, lines[5])
+
+	recorder = get("startLedger=2&endLedger=2")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 2, bytes.Count(recorder.Body.Bytes(), []byte("\n")))
+
+	// range guards
+	for _, query := range []string{"startLedger=1", "startLedger=0", "startLedger=2&endLedger=6", "startLedger=3&endLedger=2",
+		"startLedger=abc"} {
+		assert.Equal(t, http.StatusBadRequest, get(query).Code, query)
+	}
+}
@@ -0,0 +1,122 @@
+package methods
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionOutcome summarizes whether a transaction succeeded and, if not, why, so that
+// clients don't need to decode its result.
+type TransactionOutcome struct {
+	Success bool `json:"success"`
+	// ErrorCode is the code of the failure: the code of the first failed operation for the
+	// transactions whose operations failed (e.g. PaymentUnderfunded or InvokeHostFunctionTrapped),
+	// the code of the (inner) transaction result otherwise (e.g. TxBadSeq).
+	ErrorCode string `json:"errorCode,omitempty"`
+	// ErrorMessage describes the failure, with the contract error reported in the diagnostic
+	// events of failed Soroban transactions when there is one.
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// resultCodeName returns the name of a result code without the name of its type,
+// e.g. TxBadSeq for xdr.TransactionResultCodeTxBadSeq.
+func resultCodeName(code fmt.Stringer) string {
+	return strings.TrimPrefix(code.String(), reflect.TypeOf(code).Name())
+}
+
+// failedOperation returns the index and the code of the first failed operation of the
+// given operation results.
+func failedOperation(opResults []xdr.OperationResult) (int, string, bool) {
+	for i, opResult := range opResults {
+		if opResult.Code != xdr.OperationResultCodeOpInner {
+			return i, resultCodeName(opResult.Code), true
+		}
+		if opResult.Tr == nil {
+			continue
+		}
+		// every operation result (e.g. PaymentResult) has a Code, which is 0 on success
+		arm, ok := opResult.Tr.ArmForSwitch(int32(opResult.Tr.Type))
+		if !ok {
+			continue
+		}
+		result := reflect.ValueOf(*opResult.Tr).FieldByName(arm)
+		if !result.IsValid() || result.IsNil() {
+			continue
+		}
+		code := result.Elem().FieldByName("Code")
+		if !code.IsValid() || code.Int() == 0 {
+			continue
+		}
+		if stringer, ok := code.Interface().(fmt.Stringer); ok {
+			return i, resultCodeName(stringer), true
+		}
+	}
+	return 0, "", false
+}
+
+// contractErrorMessage returns a description of the first error reported by the given
+// diagnostic events, preferring contract errors to host errors.
+func contractErrorMessage(events []xdr.DiagnosticEvent) string {
+	var message string
+	for _, event := range events {
+		body, ok := event.Event.Body.GetV0()
+		if !ok || len(body.Topics) < 2 {
+			continue
+		}
+		if sym, ok := body.Topics[0].GetSym(); !ok || sym != "error" {
+			continue
+		}
+		scError, ok := body.Topics[1].GetError()
+		if !ok {
+			continue
+		}
+		var description string
+		if contractCode, ok := scError.GetContractCode(); ok {
+			description = fmt.Sprintf("contract error #%d", contractCode)
+		} else if errorCode, ok := scError.GetCode(); ok {
+			description = fmt.Sprintf("%s error (%s)", resultCodeName(scError.Type), resultCodeName(errorCode))
+		} else {
+			continue
+		}
+		if str, ok := body.Data.GetStr(); ok {
+			description += ": " + string(str)
+		}
+		if scError.Type == xdr.ScErrorTypeSceContract {
+			return description
+		}
+		if message == "" {
+			message = description
+		}
+	}
+	return message
+}
+
+// transactionOutcome derives the outcome of a transaction from its result and diagnostic events.
+func transactionOutcome(result xdr.TransactionResult, events []xdr.DiagnosticEvent) TransactionOutcome {
+	if result.Successful() {
+		return TransactionOutcome{Success: true}
+	}
+
+	outcome := TransactionOutcome{ErrorCode: resultCodeName(result.Result.Code)}
+	code := result.Result.Code
+	if pair, ok := result.Result.GetInnerResultPair(); ok {
+		code = pair.Result.Result.Code
+		outcome.ErrorCode = resultCodeName(code)
+		outcome.ErrorMessage = "the inner transaction failed"
+	}
+	if code == xdr.TransactionResultCodeTxFailed {
+		opResults, _ := result.OperationResults()
+		if index, opCode, ok := failedOperation(opResults); ok {
+			outcome.ErrorCode = opCode
+			outcome.ErrorMessage = fmt.Sprintf("operation %d failed", index)
+		}
+	}
+
+	if contractError := contractErrorMessage(events); contractError != "" {
+		outcome.ErrorMessage = contractError
+	}
+	return outcome
+}
@@ -0,0 +1,116 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/xdr"
+)
+
+func errorEvent(scError xdr.ScError, message string) xdr.DiagnosticEvent {
+	symbol := xdr.ScSymbol("error")
+	str := xdr.ScString(message)
+	return xdr.DiagnosticEvent{
+		Event: xdr.ContractEvent{
+			Type: xdr.ContractEventTypeDiagnostic,
+			Body: xdr.ContractEventBody{
+				V: 0,
+				V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{
+						{Type: xdr.ScValTypeScvSymbol, Sym: &symbol},
+						{Type: xdr.ScValTypeScvError, Error: &scError},
+					},
+					Data: xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str},
+				},
+			},
+		},
+	}
+}
+
+func failedResult(opResults ...xdr.OperationResult) xdr.TransactionResult {
+	return xdr.TransactionResult{
+		FeeCharged: 100,
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &opResults,
+		},
+	}
+}
+
+func TestTransactionOutcome(t *testing.T) {
+	outcome := transactionOutcome(transactionResult(true), nil)
+	assert.Equal(t, TransactionOutcome{Success: true}, outcome)
+
+	outcome = transactionOutcome(transactionResult(false), nil)
+	assert.Equal(t, TransactionOutcome{ErrorCode: "TxBadSeq"}, outcome)
+
+	// classic failure: the first failed operation is reported
+	paymentSuccess := xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type:          xdr.OperationTypePayment,
+			PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentSuccess},
+		},
+	}
+	underfunded := xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type:          xdr.OperationTypePayment,
+			PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentUnderfunded},
+		},
+	}
+	outcome = transactionOutcome(failedResult(paymentSuccess, underfunded), nil)
+	assert.Equal(t, TransactionOutcome{ErrorCode: "PaymentUnderfunded", ErrorMessage: "operation 1 failed"}, outcome)
+
+	noAccount := xdr.OperationResult{Code: xdr.OperationResultCodeOpNoAccount}
+	outcome = transactionOutcome(failedResult(noAccount), nil)
+	assert.Equal(t, TransactionOutcome{ErrorCode: "OpNoAccount", ErrorMessage: "operation 0 failed"}, outcome)
+
+	// fee-bump transactions report the failure of their inner transaction
+	innerResults := []xdr.OperationResult{underfunded}
+	feeBump := xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFeeBumpInnerFailed,
+			InnerResultPair: &xdr.InnerTransactionResultPair{
+				Result: xdr.InnerTransactionResult{
+					Result: xdr.InnerTransactionResultResult{
+						Code:    xdr.TransactionResultCodeTxFailed,
+						Results: &innerResults,
+					},
+				},
+			},
+		},
+	}
+	outcome = transactionOutcome(feeBump, nil)
+	assert.Equal(t, TransactionOutcome{ErrorCode: "PaymentUnderfunded", ErrorMessage: "operation 0 failed"}, outcome)
+
+	// contract trap: the contract error of the diagnostic events is preferred to host errors
+	trapped := xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type: xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{
+				Code: xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped,
+			},
+		},
+	}
+	contractCode := xdr.Uint32(3)
+	hostCode := xdr.ScErrorCodeScecInvalidAction
+	events := []xdr.DiagnosticEvent{
+		errorEvent(xdr.ScError{Type: xdr.ScErrorTypeSceWasmVm, Code: &hostCode}, "unreachable"),
+		errorEvent(xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &contractCode}, "insufficient balance"),
+	}
+	outcome = transactionOutcome(failedResult(trapped), events)
+	assert.Equal(t, TransactionOutcome{
+		ErrorCode:    "InvokeHostFunctionTrapped",
+		ErrorMessage: "contract error #3: insufficient balance",
+	}, outcome)
+
+	outcome = transactionOutcome(failedResult(trapped), events[:1])
+	assert.Equal(t, TransactionOutcome{
+		ErrorCode:    "InvokeHostFunctionTrapped",
+		ErrorMessage: "SceWasmVm error (ScecInvalidAction): unreachable",
+	}, outcome)
+}
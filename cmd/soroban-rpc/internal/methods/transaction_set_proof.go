@@ -0,0 +1,109 @@
+package methods
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionSetProof allows verifying that a transaction was included in a ledger
+// without trusting Soroban-RPC. The ledger header commits to the results of all
+// the transactions of the ledger (in application order) through its txSetResultHash,
+// and every result includes the hash of its transaction. To verify the proof:
+//
+//  1. Decode LedgerHeaderXDR and check that the SHA-256 hash of its XDR is the hash
+//     of the ledger, as obtained from a trusted source (e.g. the history archives).
+//  2. Check that the SHA-256 hash of ResultSetXDR is the txSetResultHash of the
+//     ledger header.
+//  3. Decode ResultSetXDR and check that the result at Index carries the hash of
+//     the transaction.
+//
+// VerifyTransactionSetProof implements these steps.
+type TransactionSetProof struct {
+	// Index is the (zero-based) position of the transaction in the ledger, in application order.
+	Index uint32 `json:"index"`
+	// TransactionHashes are the hex-encoded hashes of all the transactions of the ledger,
+	// in application order. They include the transactions of the denylist, since the
+	// ledger header commits to them.
+	TransactionHashes []string `json:"transactionHashes"`
+	// LedgerHeaderXDR is the base64-encoded LedgerHeader XDR of the ledger.
+	LedgerHeaderXDR string `json:"ledgerHeaderXdr"`
+	// ResultSetXDR is the base64-encoded TransactionResultSet XDR of the ledger.
+	ResultSetXDR string `json:"resultSetXdr"`
+}
+
+// newTransactionSetProof builds the proof of inclusion of a transaction in the given ledger.
+func newTransactionSetProof(ledger xdr.LedgerCloseMeta, txHash xdr.Hash) (TransactionSetProof, error) {
+	count := ledger.CountTransactions()
+	resultSet := xdr.TransactionResultSet{Results: make([]xdr.TransactionResultPair, 0, count)}
+	proof := TransactionSetProof{TransactionHashes: make([]string, 0, count)}
+	found := false
+	for i := range count {
+		result := ledger.TransactionResultPair(i)
+		if result.TransactionHash == txHash {
+			proof.Index = uint32(i)
+			found = true
+		}
+		resultSet.Results = append(resultSet.Results, result)
+		proof.TransactionHashes = append(proof.TransactionHashes, hex.EncodeToString(result.TransactionHash[:]))
+	}
+	if !found {
+		return TransactionSetProof{}, fmt.Errorf("transaction %x not found in ledger %d", txHash, ledger.LedgerSequence())
+	}
+
+	var err error
+	if proof.LedgerHeaderXDR, err = xdr.MarshalBase64(ledger.LedgerHeaderHistoryEntry().Header); err != nil {
+		return TransactionSetProof{}, err
+	}
+	if proof.ResultSetXDR, err = xdr.MarshalBase64(resultSet); err != nil {
+		return TransactionSetProof{}, err
+	}
+	return proof, nil
+}
+
+// VerifyTransactionSetProof checks that the proof shows that the transaction with the
+// given hash was included in the ledger with the given (trusted) hash.
+func VerifyTransactionSetProof(proof TransactionSetProof, ledgerHash xdr.Hash, txHash xdr.Hash) error {
+	var header xdr.LedgerHeader
+	headerBytes, err := decodeBase64XDR(proof.LedgerHeaderXDR, &header)
+	if err != nil {
+		return fmt.Errorf("invalid ledger header: %w", err)
+	}
+	if sha256.Sum256(headerBytes) != ledgerHash {
+		return errors.New("ledger header doesn't match the ledger hash")
+	}
+
+	var resultSet xdr.TransactionResultSet
+	resultSetBytes, err := decodeBase64XDR(proof.ResultSetXDR, &resultSet)
+	if err != nil {
+		return fmt.Errorf("invalid result set: %w", err)
+	}
+	if sha256.Sum256(resultSetBytes) != header.TxSetResultHash {
+		return errors.New("result set doesn't match the ledger header")
+	}
+
+	if int(proof.Index) >= len(resultSet.Results) {
+		return fmt.Errorf("index %d is out of range (the ledger has %d transactions)",
+			proof.Index, len(resultSet.Results))
+	}
+	if resultSet.Results[proof.Index].TransactionHash != txHash {
+		return fmt.Errorf("transaction at index %d doesn't match the transaction hash", proof.Index)
+	}
+	return nil
+}
+
+// decodeBase64XDR decodes the base64-encoded XDR value into dest, returning the raw XDR.
+func decodeBase64XDR(encoded string, dest interface{}) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if err := xdr.SafeUnmarshal(raw, dest); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
@@ -0,0 +1,95 @@
+package methods
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// ledgerWithResults returns a ledger including the given transaction results, with
+// a header committing to them as stellar-core does.
+func ledgerWithResults(t *testing.T, sequence uint32, results []xdr.TransactionResultPair) (xdr.LedgerCloseMeta, xdr.Hash) {
+	resultSetBytes, err := xdr.TransactionResultSet{Results: results}.MarshalBinary()
+	require.NoError(t, err)
+	header := xdr.LedgerHeader{
+		LedgerSeq:       xdr.Uint32(sequence),
+		TxSetResultHash: sha256.Sum256(resultSetBytes),
+	}
+	headerBytes, err := header.MarshalBinary()
+	require.NoError(t, err)
+	ledgerHash := xdr.Hash(sha256.Sum256(headerBytes))
+
+	txProcessing := make([]xdr.TransactionResultMeta, 0, len(results))
+	for _, result := range results {
+		txProcessing = append(txProcessing, xdr.TransactionResultMeta{Result: result})
+	}
+	return xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{Hash: ledgerHash, Header: header},
+			TxSet:        xdr.GeneralizedTransactionSet{V: 1, V1TxSet: &xdr.TransactionSetV1{}},
+			TxProcessing: txProcessing,
+		},
+	}, ledgerHash
+}
+
+func TestTransactionSetProof_RoundTrip(t *testing.T) {
+	results := []xdr.TransactionResultPair{
+		{TransactionHash: xdr.Hash{1}, Result: transactionResult(true)},
+		{TransactionHash: xdr.Hash{2}, Result: transactionResult(false)},
+		{TransactionHash: xdr.Hash{3}, Result: transactionResult(true)},
+	}
+	ledger, ledgerHash := ledgerWithResults(t, 10, results)
+
+	proof, err := newTransactionSetProof(ledger, xdr.Hash{2})
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), proof.Index)
+	require.Equal(t, []string{
+		xdr.Hash{1}.HexString(), xdr.Hash{2}.HexString(), xdr.Hash{3}.HexString(),
+	}, proof.TransactionHashes)
+	require.NoError(t, VerifyTransactionSetProof(proof, ledgerHash, xdr.Hash{2}))
+
+	// the proof doesn't hold for other transactions or ledgers
+	require.Error(t, VerifyTransactionSetProof(proof, ledgerHash, xdr.Hash{3}))
+	require.Error(t, VerifyTransactionSetProof(proof, xdr.Hash{0xff}, xdr.Hash{2}))
+
+	// nor if the result set is tampered with
+	tampered := proof
+	tampered.ResultSetXDR, err = xdr.MarshalBase64(xdr.TransactionResultSet{Results: results[1:]})
+	require.NoError(t, err)
+	tampered.Index = 0
+	require.Error(t, VerifyTransactionSetProof(tampered, ledgerHash, xdr.Hash{2}))
+
+	_, err = newTransactionSetProof(ledger, xdr.Hash{4})
+	require.Error(t, err)
+}
+
+func TestGetTransaction_TransactionSetProof(t *testing.T) {
+	store := db.NewMockTransactionStore(NetworkPassphrase)
+	ledgerReader := db.NewMockLedgerReader(store)
+	meta := txMeta(1, true)
+	require.NoError(t, store.InsertTransactions(meta))
+	hash := txHash(1)
+
+	tx, err := GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString()})
+	require.NoError(t, err)
+	require.Nil(t, tx.TransactionSetProof)
+
+	tx, err = GetTransaction(context.TODO(), log.DefaultLogger, store, ledgerReader, 0,
+		GetTransactionRequest{Hash: hash.HexString(), IncludeTransactionSetProof: true})
+	require.NoError(t, err)
+	require.NotNil(t, tx.TransactionSetProof)
+	require.Equal(t, uint32(0), tx.TransactionSetProof.Index)
+	require.Equal(t, []string{hash.HexString()}, tx.TransactionSetProof.TransactionHashes)
+	expectedHeader, err := xdr.MarshalBase64(meta.LedgerHeaderHistoryEntry().Header)
+	require.NoError(t, err)
+	require.Equal(t, expectedHeader, tx.TransactionSetProof.LedgerHeaderXDR)
+}
@@ -0,0 +1,326 @@
+package txdenylist
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// Denylist is a set of transaction hashes which must not be served.
+//
+// The hashes are read from a file containing one hex-encoded hash per line.
+// Empty lines and lines starting with '#' are ignored.
+type Denylist struct {
+	path   string
+	logger *log.Entry
+	lock   sync.RWMutex
+	hashes map[string]struct{}
+}
+
+// NewDenylist loads the denylist from the given file.
+func NewDenylist(path string, logger *log.Entry) (*Denylist, error) {
+	d := &Denylist{
+		path:   path,
+		logger: logger,
+	}
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reload re-reads the denylist file. The current list is kept if the file
+// cannot be read or parsed.
+func (d *Denylist) Reload() error {
+	hashes, err := readHashes(d.path)
+	if err != nil {
+		return err
+	}
+	d.lock.Lock()
+	d.hashes = hashes
+	d.lock.Unlock()
+	return nil
+}
+
+// Len returns the number of hashes in the denylist.
+func (d *Denylist) Len() int {
+	if d == nil {
+		return 0
+	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return len(d.hashes)
+}
+
+// IsDenied tells whether the transaction with the given (hex-encoded) hash
+// must not be served. Denials are logged for auditing.
+// A nil Denylist denies nothing.
+func (d *Denylist) IsDenied(hash string) bool {
+	if d == nil {
+		return false
+	}
+	hash = strings.ToLower(hash)
+	d.lock.RLock()
+	_, denied := d.hashes[hash]
+	d.lock.RUnlock()
+	if denied {
+		d.logger.WithField("hash", hash).Info("denied access to transaction in denylist")
+	}
+	return denied
+}
+
+// TransactionReader wraps the given reader so that denied transactions
+// are reported as not found.
+func (d *Denylist) TransactionReader(reader db.TransactionReader) db.TransactionReader {
+	if d == nil {
+		return reader
+	}
+	return denylistTransactionReader{reader: reader, denylist: d}
+}
+
+type denylistTransactionReader struct {
+	reader   db.TransactionReader
+	denylist *Denylist
+}
+
+func (r denylistTransactionReader) GetTransaction(ctx context.Context, hash xdr.Hash) (db.Transaction, error) {
+	if r.denylist.IsDenied(hex.EncodeToString(hash[:])) {
+		return db.Transaction{}, db.ErrNoTransaction
+	}
+	return r.reader.GetTransaction(ctx, hash)
+}
+
+// GetTransactionByResultHash reports denied transactions as not found, like GetTransaction.
+func (r denylistTransactionReader) GetTransactionByResultHash(ctx context.Context, resultHash xdr.Hash) (
+	db.Transaction, error,
+) {
+	tx, err := r.reader.GetTransactionByResultHash(ctx, resultHash)
+	if err == nil && r.denylist.IsDenied(tx.TransactionHash) {
+		return db.Transaction{}, db.ErrNoTransaction
+	}
+	return tx, err
+}
+
+// CountTransactions counts the denied transactions too, since they are still stored.
+func (r denylistTransactionReader) CountTransactions(ctx context.Context, startLedger uint32,
+	endLedger uint32,
+) (uint64, error) {
+	return r.reader.CountTransactions(ctx, startLedger, endLedger)
+}
+
+// TransactionHashReader wraps the given reader so that the hashes of denied transactions are
+// omitted from the streams. Both hashes of denied fee-bump transactions are omitted, whichever
+// of them is listed.
+func (d *Denylist) TransactionHashReader(reader db.TransactionHashReader) db.TransactionHashReader {
+	if d == nil {
+		return reader
+	}
+	return denylistTransactionHashReader{reader: reader, denylist: d}
+}
+
+type denylistTransactionHashReader struct {
+	reader   db.TransactionHashReader
+	denylist *Denylist
+}
+
+func (r denylistTransactionHashReader) StreamTransactionHashes(ctx context.Context, startLedger, endLedger uint32,
+	f db.StreamTransactionHashFn,
+) error {
+	// the hashes of a transaction (two for fee bumps) share its location, so they are streamed
+	// once all of them are known
+	var pending []db.TransactionHash
+	denied := false
+	flush := func() error {
+		if !denied {
+			for _, hash := range pending {
+				if err := f(hash); err != nil {
+					return err
+				}
+			}
+		}
+		pending = pending[:0]
+		denied = false
+		return nil
+	}
+	err := r.reader.StreamTransactionHashes(ctx, startLedger, endLedger, func(hash db.TransactionHash) error {
+		if len(pending) > 0 && pending[0].TransactionLocation != hash.TransactionLocation {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		pending = append(pending, hash)
+		denied = denied || r.denylist.IsDenied(hex.EncodeToString(hash.Hash[:]))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// LedgerReader wraps the given reader so that denied transactions are stripped from the
+// ledgers: their envelopes are removed from the transaction sets, and their results and meta
+// from the transaction processing.
+func (d *Denylist) LedgerReader(reader db.LedgerReader, passphrase string) db.LedgerReader {
+	if d == nil {
+		return reader
+	}
+	return denylistLedgerReader{LedgerReader: reader, denylist: d, passphrase: passphrase}
+}
+
+type denylistLedgerReader struct {
+	db.LedgerReader
+	denylist   *Denylist
+	passphrase string
+}
+
+func (r denylistLedgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
+	ledger, found, err := r.LedgerReader.GetLedger(ctx, sequence)
+	if err != nil || !found {
+		return ledger, found, err
+	}
+	ledger, err = r.denylist.stripLedger(ledger, r.passphrase)
+	return ledger, err == nil, err
+}
+
+func (r denylistLedgerReader) StreamAllLedgers(ctx context.Context, f db.StreamLedgerFn) error {
+	return r.LedgerReader.StreamAllLedgers(ctx, r.strippingLedgers(f))
+}
+
+func (r denylistLedgerReader) StreamLedgerRange(ctx context.Context, startLedger uint32, endLedger uint32,
+	f db.StreamLedgerFn,
+) error {
+	return r.LedgerReader.StreamLedgerRange(ctx, startLedger, endLedger, r.strippingLedgers(f))
+}
+
+func (r denylistLedgerReader) strippingLedgers(f db.StreamLedgerFn) db.StreamLedgerFn {
+	return func(ledger xdr.LedgerCloseMeta) error {
+		ledger, err := r.denylist.stripLedger(ledger, r.passphrase)
+		if err != nil {
+			return err
+		}
+		return f(ledger)
+	}
+}
+
+// stripLedger returns a copy of the ledger without the denied transactions. The ledger is
+// returned as is if none of its transactions is denied.
+func (d *Denylist) stripLedger(ledger xdr.LedgerCloseMeta, passphrase string) (xdr.LedgerCloseMeta, error) {
+	if d.Len() == 0 {
+		return ledger, nil
+	}
+	var processing []xdr.TransactionResultMeta
+	switch ledger.V {
+	case 0:
+		processing = ledger.V0.TxProcessing
+	case 1:
+		processing = ledger.V1.TxProcessing
+	default:
+		return xdr.LedgerCloseMeta{}, fmt.Errorf("unsupported LedgerCloseMeta.V: %d", ledger.V)
+	}
+	denied := map[xdr.Hash]struct{}{}
+	for _, tx := range processing {
+		if d.IsDenied(hex.EncodeToString(tx.Result.TransactionHash[:])) {
+			denied[tx.Result.TransactionHash] = struct{}{}
+		}
+	}
+	if len(denied) == 0 {
+		return ledger, nil
+	}
+
+	var strippedProcessing []xdr.TransactionResultMeta
+	for _, tx := range processing {
+		if _, ok := denied[tx.Result.TransactionHash]; !ok {
+			strippedProcessing = append(strippedProcessing, tx)
+		}
+	}
+	stripEnvelopes := func(envelopes []xdr.TransactionEnvelope) ([]xdr.TransactionEnvelope, error) {
+		var stripped []xdr.TransactionEnvelope
+		for _, envelope := range envelopes {
+			hash, err := network.HashTransactionInEnvelope(envelope, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := denied[hash]; !ok {
+				stripped = append(stripped, envelope)
+			}
+		}
+		return stripped, nil
+	}
+
+	var err error
+	if ledger.V == 0 {
+		v0 := *ledger.V0
+		v0.TxProcessing = strippedProcessing
+		if v0.TxSet.Txs, err = stripEnvelopes(v0.TxSet.Txs); err != nil {
+			return xdr.LedgerCloseMeta{}, err
+		}
+		ledger.V0 = &v0
+		return ledger, nil
+	}
+	v1 := *ledger.V1
+	v1.TxProcessing = strippedProcessing
+	if v1.TxSet.V1TxSet != nil {
+		txSet := *v1.TxSet.V1TxSet
+		txSet.Phases = make([]xdr.TransactionPhase, len(v1.TxSet.V1TxSet.Phases))
+		for i, phase := range v1.TxSet.V1TxSet.Phases {
+			if phase.V0Components != nil {
+				components := make([]xdr.TxSetComponent, len(*phase.V0Components))
+				for j, component := range *phase.V0Components {
+					if component.TxsMaybeDiscountedFee != nil {
+						txs := *component.TxsMaybeDiscountedFee
+						if txs.Txs, err = stripEnvelopes(txs.Txs); err != nil {
+							return xdr.LedgerCloseMeta{}, err
+						}
+						component.TxsMaybeDiscountedFee = &txs
+					}
+					components[j] = component
+				}
+				phase.V0Components = &components
+			}
+			txSet.Phases[i] = phase
+		}
+		v1.TxSet.V1TxSet = &txSet
+	}
+	ledger.V1 = &v1
+	return ledger, nil
+}
+
+func readHashes(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open transaction denylist: %w", err)
+	}
+	defer f.Close()
+
+	hashes := map[string]struct{}{}
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var hash xdr.Hash
+		if hex.DecodedLen(len(line)) != len(hash) {
+			return nil, fmt.Errorf("invalid transaction hash in denylist (line %d): unexpected length", lineNumber)
+		}
+		if _, err := hex.Decode(hash[:], []byte(line)); err != nil {
+			return nil, fmt.Errorf("invalid transaction hash in denylist (line %d): %w", lineNumber, err)
+		}
+		hashes[hex.EncodeToString(hash[:])] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read transaction denylist: %w", err)
+	}
+	return hashes, nil
+}
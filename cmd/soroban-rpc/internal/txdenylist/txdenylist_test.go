@@ -0,0 +1,205 @@
+package txdenylist
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type mockTransactionReader struct{}
+
+func (mockTransactionReader) GetTransaction(context.Context, xdr.Hash) (db.Transaction, error) {
+	return db.Transaction{Successful: true}, nil
+}
+
+func (mockTransactionReader) GetTransactionByResultHash(context.Context, xdr.Hash) (db.Transaction, error) {
+	return db.Transaction{Successful: true}, nil
+}
+
+func (mockTransactionReader) CountTransactions(context.Context, uint32, uint32) (uint64, error) {
+	return 0, nil
+}
+
+type mockLedgerReader struct {
+	db.LedgerReader
+	ledger xdr.LedgerCloseMeta
+}
+
+func (r mockLedgerReader) GetLedger(context.Context, uint32) (xdr.LedgerCloseMeta, bool, error) {
+	return r.ledger, true, nil
+}
+
+func (r mockLedgerReader) StreamLedgerRange(_ context.Context, _ uint32, _ uint32, f db.StreamLedgerFn) error {
+	return f(r.ledger)
+}
+
+type mockTransactionHashReader []db.TransactionHash
+
+func (r mockTransactionHashReader) StreamTransactionHashes(_ context.Context, _, _ uint32,
+	f db.StreamTransactionHashFn,
+) error {
+	for _, hash := range r {
+		if err := f(hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func transactionEnvelope(seqNum int64) xdr.TransactionEnvelope {
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+				SeqNum:        xdr.SequenceNumber(seqNum),
+			},
+		},
+	}
+}
+
+// ledgerWithTransactions returns a ledger with the given transactions, and their hashes.
+func ledgerWithTransactions(t *testing.T, envelopes ...xdr.TransactionEnvelope) (xdr.LedgerCloseMeta, []xdr.Hash) {
+	var hashes []xdr.Hash
+	var processing []xdr.TransactionResultMeta
+	for _, envelope := range envelopes {
+		hash, err := network.HashTransactionInEnvelope(envelope, network.TestNetworkPassphrase)
+		require.NoError(t, err)
+		hashes = append(hashes, hash)
+		processing = append(processing, xdr.TransactionResultMeta{
+			Result: xdr.TransactionResultPair{TransactionHash: hash},
+		})
+	}
+	components := []xdr.TxSetComponent{{
+		Type:                  xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+		TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{Txs: envelopes},
+	}}
+	return xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			TxProcessing: processing,
+			TxSet: xdr.GeneralizedTransactionSet{
+				V: 1,
+				V1TxSet: &xdr.TransactionSetV1{
+					Phases: []xdr.TransactionPhase{{V: 0, V0Components: &components}},
+				},
+			},
+		},
+	}, hashes
+}
+
+func TestDenylist(t *testing.T) {
+	denied := strings.Repeat("ab", 32)
+	allowed := strings.Repeat("cd", 32)
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\n\n"+strings.ToUpper(denied)+"\n"), 0o600))
+
+	denylist, err := NewDenylist(path, log.DefaultLogger)
+	require.NoError(t, err)
+	require.Equal(t, 1, denylist.Len())
+	require.True(t, denylist.IsDenied(denied))
+	require.False(t, denylist.IsDenied(allowed))
+
+	reader := denylist.TransactionReader(mockTransactionReader{})
+	var hash xdr.Hash
+	_, err = hex.Decode(hash[:], []byte(denied))
+	require.NoError(t, err)
+	_, err = reader.GetTransaction(context.Background(), hash)
+	require.ErrorIs(t, err, db.ErrNoTransaction)
+
+	// reloading picks up the changes
+	require.NoError(t, os.WriteFile(path, []byte(allowed+"\n"), 0o600))
+	require.NoError(t, denylist.Reload())
+	require.False(t, denylist.IsDenied(denied))
+	require.True(t, denylist.IsDenied(allowed))
+	tx, err := reader.GetTransaction(context.Background(), hash)
+	require.NoError(t, err)
+	require.True(t, tx.Successful)
+
+	// an invalid file keeps the current list
+	require.NoError(t, os.WriteFile(path, []byte("not a hash\n"), 0o600))
+	require.ErrorContains(t, denylist.Reload(), "line 1")
+	require.True(t, denylist.IsDenied(allowed))
+}
+
+func TestNilDenylist(t *testing.T) {
+	var denylist *Denylist
+	require.False(t, denylist.IsDenied(strings.Repeat("ab", 32)))
+	require.Zero(t, denylist.Len())
+	reader := mockTransactionReader{}
+	require.Equal(t, db.TransactionReader(reader), denylist.TransactionReader(reader))
+	ledgerReader := mockLedgerReader{}
+	require.Equal(t, db.LedgerReader(ledgerReader), denylist.LedgerReader(ledgerReader, network.TestNetworkPassphrase))
+}
+
+func TestDenylistLedgerReader(t *testing.T) {
+	ledger, hashes := ledgerWithTransactions(t, transactionEnvelope(1), transactionEnvelope(2))
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte(hex.EncodeToString(hashes[0][:])+"\n"), 0o600))
+	denylist, err := NewDenylist(path, log.DefaultLogger)
+	require.NoError(t, err)
+	reader := denylist.LedgerReader(mockLedgerReader{ledger: ledger}, network.TestNetworkPassphrase)
+
+	assertStripped := func(stripped xdr.LedgerCloseMeta) {
+		require.Equal(t, 1, stripped.CountTransactions())
+		require.Equal(t, hashes[1], stripped.TransactionHash(0))
+		require.Equal(t, []xdr.TransactionEnvelope{transactionEnvelope(2)}, stripped.TransactionEnvelopes())
+	}
+	stripped, found, err := reader.GetLedger(context.Background(), 1)
+	require.NoError(t, err)
+	require.True(t, found)
+	assertStripped(stripped)
+	require.NoError(t, reader.StreamLedgerRange(context.Background(), 1, 1, func(stripped xdr.LedgerCloseMeta) error {
+		assertStripped(stripped)
+		return nil
+	}))
+	// the stored ledger isn't modified
+	require.Equal(t, 2, ledger.CountTransactions())
+	require.Len(t, ledger.TransactionEnvelopes(), 2)
+
+	// ledgers without denied transactions are served as is
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("ab", 32)+"\n"), 0o600))
+	require.NoError(t, denylist.Reload())
+	unchanged, _, err := reader.GetLedger(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, ledger, unchanged)
+}
+
+func TestDenylistTransactionHashReader(t *testing.T) {
+	hash := func(ledger uint32, order int32, b byte) db.TransactionHash {
+		return db.TransactionHash{
+			TransactionLocation: db.TransactionLocation{Ledger: ledger, ApplicationOrder: order},
+			Hash:                xdr.Hash{b},
+		}
+	}
+	// the second transaction is a fee bump, stored under its inner and outer hashes
+	hashes := mockTransactionHashReader{hash(1, 1, 1), hash(1, 2, 2), hash(1, 2, 3), hash(2, 1, 4), hash(2, 2, 5)}
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	denied := []xdr.Hash{hashes[2].Hash, hashes[3].Hash}
+	require.NoError(t, os.WriteFile(path, []byte(denied[0].HexString()+"\n"+denied[1].HexString()+"\n"), 0o600))
+	denylist, err := NewDenylist(path, log.DefaultLogger)
+	require.NoError(t, err)
+
+	var streamed []db.TransactionHash
+	err = denylist.TransactionHashReader(hashes).StreamTransactionHashes(context.Background(), 1, 2,
+		func(hash db.TransactionHash) error {
+			streamed = append(streamed, hash)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, []db.TransactionHash{hashes[0], hashes[4]}, streamed)
+
+	var nilDenylist *Denylist
+	require.Equal(t, db.TransactionHashReader(hashes), nilDenylist.TransactionHashReader(hashes))
+}
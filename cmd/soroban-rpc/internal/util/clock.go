@@ -0,0 +1,135 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current wall-clock time and timers. Components whose behavior
+// depends on wall-clock time take a Clock so that tests can control it.
+//
+// Durations measured for metrics and logs don't need to go through a Clock.
+type Clock interface {
+	Now() time.Time
+	// NewTimer returns a timer firing once the duration has elapsed on the clock.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-shot timer created by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is sent when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false if the timer already fired
+	// or was stopped.
+	Stop() bool
+}
+
+// RealClock is a Clock backed by time.Now().
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// ManualClock is a Clock which only moves when told to. Its timers fire when the clock is
+// moved past their deadline.
+type ManualClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers map[*manualTimer]struct{}
+}
+
+// NewManualClock returns a ManualClock set to the given time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	timer := &manualTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+		return timer
+	}
+	if c.timers == nil {
+		c.timers = map[*manualTimer]struct{}{}
+	}
+	c.timers[timer] = struct{}{}
+	return timer
+}
+
+// Timers returns the number of timers which are pending (neither fired nor stopped), e.g.
+// for tests to wait for a component to start waiting on the clock.
+func (c *ManualClock) Timers() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.timers)
+}
+
+// Set sets the time of the clock.
+func (c *ManualClock) Set(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = now
+	c.fireTimers()
+}
+
+// Advance moves the clock forward by the given duration.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	c.fireTimers()
+}
+
+func (c *ManualClock) fireTimers() {
+	for timer := range c.timers {
+		if !timer.deadline.After(c.now) {
+			timer.c <- c.now
+			delete(c.timers, timer)
+		}
+	}
+}
+
+type manualTimer struct {
+	clock    *ManualClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	if _, ok := t.clock.timers[t]; !ok {
+		return false
+	}
+	delete(t.clock.timers, t)
+	return true
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewManualClock(start)
+	require.Equal(t, start, clock.Now())
+
+	clock.Advance(time.Minute)
+	require.Equal(t, start.Add(time.Minute), clock.Now())
+
+	clock.Set(start)
+	require.Equal(t, start, clock.Now())
+}
+
+func TestManualClockTimers(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewManualClock(start)
+
+	timer := clock.NewTimer(time.Minute)
+	stopped := clock.NewTimer(time.Minute)
+	require.Equal(t, 2, clock.Timers())
+	require.True(t, stopped.Stop())
+	require.False(t, stopped.Stop())
+	require.Equal(t, 1, clock.Timers())
+
+	clock.Advance(time.Second)
+	require.Empty(t, timer.C())
+
+	clock.Advance(time.Minute)
+	require.Equal(t, start.Add(time.Minute+time.Second), <-timer.C())
+	require.False(t, timer.Stop())
+	require.Empty(t, stopped.C())
+	require.Zero(t, clock.Timers())
+
+	// timers with no duration fire right away
+	require.Equal(t, start.Add(time.Minute+time.Second), <-clock.NewTimer(0).C())
+}
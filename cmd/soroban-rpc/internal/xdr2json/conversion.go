@@ -21,6 +21,8 @@ import (
 	"unsafe"
 
 	"github.com/pkg/errors"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrfailures"
 )
 
 // ConvertBytes takes an XDR object (`xdr`) and its serialized bytes (`field`)
@@ -52,6 +54,7 @@ func ConvertInterface(xdr encoding.BinaryMarshaler) (json.RawMessage, error) {
 	xdrTypeName := reflect.TypeOf(xdr).Name()
 	data, err := xdr.MarshalBinary()
 	if err != nil {
+		xdrfailures.Record(xdrfailures.JSONConversion, xdrTypeName)
 		return []byte(""), errors.Wrapf(err, "failed to serialize XDR type '%s'", xdrTypeName)
 	}
 
@@ -75,6 +78,7 @@ func convertAnyBytes(xdrTypeName string, field []byte) (json.RawMessage, error)
 	}
 
 	if errStr != "" {
+		xdrfailures.Record(xdrfailures.JSONConversion, xdrTypeName)
 		return json.RawMessage(jsonStr), errors.New(errStr)
 	}
 
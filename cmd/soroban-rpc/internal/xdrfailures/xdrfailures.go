@@ -0,0 +1,39 @@
+// Package xdrfailures counts the failures to decode, encode and convert XDR values, which
+// signal corrupted data or unsupported protocol changes otherwise only surfaced as internal
+// errors of the requests.
+package xdrfailures
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Operations which can fail on XDR values.
+const (
+	// LedgerMetaDecode is the decoding of the stored ledgers.
+	LedgerMetaDecode = "ledger_meta_decode"
+	// LedgerEntryDecode is the decoding of the stored ledger entries.
+	LedgerEntryDecode = "ledger_entry_decode"
+	// EventDecode is the decoding of the stored events.
+	EventDecode = "event_decode"
+	// TransactionEncode is the encoding of the parts of the transactions read from the ledgers.
+	TransactionEncode = "transaction_encode"
+	// JSONConversion is the conversion of XDR values to JSON (see xdr2json).
+	JSONConversion = "json_conversion"
+)
+
+var failures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "soroban_rpc",
+	Subsystem: "xdr",
+	Name:      "failures_total",
+	Help:      "number of failures to decode, encode or convert XDR values, by operation and XDR type",
+}, []string{"operation", "xdr_type"})
+
+// Record counts a failure of the given operation on a value of the given XDR type.
+func Record(operation string, xdrType string) {
+	failures.WithLabelValues(operation, xdrType).Inc()
+}
+
+// Collector returns the failure counter, to be registered in the metrics registry.
+func Collector() prometheus.Collector {
+	return failures
+}